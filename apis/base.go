@@ -38,14 +38,22 @@ func NewRouter(app core.App) (*router.Router[*core.RequestEvent], error) {
 	bindSettingsApi(app, apiGroup)
 	bindCollectionApi(app, apiGroup)
 	bindRecordCrudApi(app, apiGroup)
+	bindRecordVersionsApi(app, apiGroup)
+	bindRecordSoftDeleteApi(app, apiGroup)
 	bindRecordAuthApi(app, apiGroup)
 	bindLogsApi(app, apiGroup)
+	bindMailLogsApi(app, apiGroup)
+	bindMailInboundApi(app, apiGroup)
+	bindJobsApi(app, apiGroup)
+	bindWebhooksApi(app, apiGroup)
+	bindPushApi(app, apiGroup)
 	bindBackupApi(app, apiGroup)
 	bindCronApi(app, apiGroup)
 	bindFileApi(app, apiGroup)
 	bindBatchApi(app, apiGroup)
 	bindRealtimeApi(app, apiGroup)
 	bindHealthApi(app, apiGroup)
+	bindSCIMApi(app, apiGroup)
 
 	return pbRouter, nil
 }
@@ -4,11 +4,14 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/router"
 	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/search"
 )
 
 // bindCronApi registers the crons api endpoint.
@@ -16,6 +19,18 @@ func bindCronApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
 	subGroup := rg.Group("/crons").Bind(RequireSuperuserAuth())
 	subGroup.GET("", cronsList)
 	subGroup.POST("/{id}", cronRun)
+	subGroup.GET("/{id}/runs", cronRunsList)
+}
+
+// cronsListItem defines a single cron job item returned by the cronsList endpoint.
+type cronsListItem struct {
+	LastRun    *core.CronRun `json:"lastRun"`
+	Id         string        `json:"id"`
+	Expression string        `json:"expression"`
+	Timeout    int64         `json:"timeout"`
+	Jitter     int64         `json:"jitter"`
+	NoOverlap  bool          `json:"noOverlap"`
+	Running    bool          `json:"running"`
 }
 
 func cronsList(e *core.RequestEvent) error {
@@ -31,7 +46,24 @@ func cronsList(e *core.RequestEvent) error {
 		return strings.Compare(a.Id(), b.Id())
 	})
 
-	return e.JSON(http.StatusOK, jobs)
+	result := make([]cronsListItem, len(jobs))
+	for i, j := range jobs {
+		options := j.Options()
+
+		result[i] = cronsListItem{
+			Id:         j.Id(),
+			Expression: j.Expression(),
+			Timeout:    int64(options.Timeout / time.Second),
+			Jitter:     int64(options.Jitter / time.Second),
+			NoOverlap:  options.NoOverlap,
+			Running:    j.IsRunning(),
+		}
+
+		// the job may not have been run yet
+		result[i].LastRun, _ = e.App.FindLatestCronRun(j.Id())
+	}
+
+	return e.JSON(http.StatusOK, result)
 }
 
 func cronRun(e *core.RequestEvent) error {
@@ -57,3 +89,21 @@ func cronRun(e *core.RequestEvent) error {
 
 	return e.NoContent(http.StatusNoContent)
 }
+
+var cronRunFilterFields = []string{
+	"id", "status", "error", "started", "finished", "duration", "created",
+}
+
+func cronRunsList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(cronRunFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.AuxModelQuery(&core.CronRun{}).AndWhere(dbx.HashExp{"jobId": e.Request.PathValue("id")})).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.CronRun{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,81 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindJobsApi registers the background job queue api endpoints.
+func bindJobsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/jobs").Bind(RequireSuperuserAuth())
+	sub.GET("", jobsList).Bind(SkipSuccessActivityLog())
+	sub.GET("/{id}", jobsView).Bind(SkipSuccessActivityLog())
+	sub.POST("/{id}/retry", jobsRetry)
+	sub.POST("/{id}/cancel", jobsCancel)
+}
+
+var jobFilterFields = []string{
+	"id", "name", "status", "priority", "uniqueKey", "attempts",
+	"maxAttempts", "runAt", "lastError", "created", "updated",
+}
+
+func jobsList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(jobFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.AuxModelQuery(&core.Job{})).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.Job{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func jobsView(e *core.RequestEvent) error {
+	id := e.Request.PathValue("id")
+	if id == "" {
+		return e.NotFoundError("", nil)
+	}
+
+	job, err := e.App.FindJobById(id)
+	if err != nil || job == nil {
+		return e.NotFoundError("", err)
+	}
+
+	return e.JSON(http.StatusOK, job)
+}
+
+func jobsRetry(e *core.RequestEvent) error {
+	job, err := e.App.FindJobById(e.Request.PathValue("id"))
+	if err != nil || job == nil {
+		return e.NotFoundError("", err)
+	}
+
+	if job.Status == core.JobStatusRunning {
+		return e.BadRequestError("The job is already running.", nil)
+	}
+
+	if err := e.App.RetryJob(job); err != nil {
+		return e.BadRequestError("Failed to retry the job.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+func jobsCancel(e *core.RequestEvent) error {
+	job, err := e.App.FindJobById(e.Request.PathValue("id"))
+	if err != nil || job == nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.CancelJob(job); err != nil {
+		return e.BadRequestError("Failed to cancel the job.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,58 @@
+package apis
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// defaultInboundMailMaxSize is the default max allowed raw inbound message size.
+const defaultInboundMailMaxSize int64 = 25 << 20
+
+// bindMailInboundApi registers the inbound mail webhook api endpoint.
+func bindMailInboundApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.POST("/mails/inbound", mailInbound).Unbind(DefaultBodyLimitMiddlewareId) // the body limit is inlined
+}
+
+// mailInbound accepts a single raw RFC 5322 message as request body
+// and triggers the OnMailReceived app hook.
+func mailInbound(e *core.RequestEvent) error {
+	config := e.App.Settings().InboundMail
+	if !config.Enabled {
+		return e.ForbiddenError("Inbound mail processing is not allowed.", nil)
+	}
+
+	// note: compare the hashes to avoid leaking the secret length
+	token, ok := strings.CutPrefix(e.Request.Header.Get("Authorization"), "Bearer ")
+	if !ok || config.Secret == "" ||
+		subtle.ConstantTimeCompare([]byte(security.SHA256(token)), []byte(security.SHA256(config.Secret))) != 1 {
+		return e.UnauthorizedError("Missing or invalid inbound mail secret.", nil)
+	}
+
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultInboundMailMaxSize
+	}
+
+	err := applyBodyLimit(e, maxSize)
+	if err != nil {
+		return err
+	}
+
+	message, err := mailer.ParseMessage(e.Request.Body)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to parse the submitted message.", err))
+	}
+
+	_, err = e.App.ReceiveMail(message)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to process the submitted message.", err))
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindMailLogsApi registers the mail queue delivery logs api endpoints.
+func bindMailLogsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/mails/logs").Bind(RequireSuperuserAuth(), SkipSuccessActivityLog())
+	sub.GET("", mailLogsList)
+	sub.GET("/{id}", mailLogsView)
+}
+
+var mailLogFilterFields = []string{
+	"id", "created", "mailRef", "to", "subject", "status", "attempt", "error",
+}
+
+func mailLogsList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(mailLogFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.AuxModelQuery(&core.MailLog{})).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.MailLog{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func mailLogsView(e *core.RequestEvent) error {
+	id := e.Request.PathValue("id")
+	if id == "" {
+		return e.NotFoundError("", nil)
+	}
+
+	log, err := e.App.FindMailLogById(id)
+	if err != nil || log == nil {
+		return e.NotFoundError("", err)
+	}
+
+	return e.JSON(http.StatusOK, log)
+}
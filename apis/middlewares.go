@@ -11,14 +11,22 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/router"
 	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
 
+// APIKeyHeader is the name of the request header used to authorize requests with an auth record API key.
+const APIKeyHeader = "X-Api-Key"
+
+// apiKeyLastUsedThreshold is the min interval between 2 consecutive API key lastUsed updates.
+const apiKeyLastUsedThreshold = 1 * time.Minute
+
 // Common request event store keys used by the middlewares and api handlers.
 const (
 	RequestEventKeyLogMeta = "pbLogMeta" // extra data to store with the request activity log
@@ -168,10 +176,11 @@ func RequireSameCollectionContextAuth(collectionPathParam string) *hook.Handler[
 	}
 }
 
-// loadAuthToken attempts to load the auth context based on the "Authorization: TOKEN" header value.
+// loadAuthToken attempts to load the auth context based on the "Authorization: TOKEN"
+// or the "X-Api-Key: KEY" header value (the Authorization header has precedence).
 //
 // This middleware does nothing in case of:
-//   - missing, invalid or expired token
+//   - missing, invalid or expired token or API key
 //   - e.Auth is already loaded by another middleware
 //
 // This middleware is registered by default for all routes.
@@ -190,6 +199,7 @@ func loadAuthToken() *hook.Handler[*core.RequestEvent] {
 
 			token := getAuthTokenFromRequest(e)
 			if token == "" {
+				loadAPIKey(e)
 				return e.Next()
 			}
 
@@ -205,6 +215,37 @@ func loadAuthToken() *hook.Handler[*core.RequestEvent] {
 	}
 }
 
+// loadAPIKey loads the auth context based on the "X-Api-Key: KEY" header value (if any).
+func loadAPIKey(e *core.RequestEvent) {
+	key := e.Request.Header.Get(APIKeyHeader)
+	if key == "" {
+		return
+	}
+
+	record, apiKey, err := e.App.FindAuthRecordByAPIKey(key)
+	if err != nil {
+		e.App.Logger().Debug("loadAPIKey failure", "error", err)
+		return
+	}
+
+	e.Auth = record
+	e.Set(core.RequestEventKeyAPIKey, apiKey)
+
+	// throttle the lastUsed updates to minimize the db writes
+	if time.Since(apiKey.LastUsed().Time()) < apiKeyLastUsedThreshold {
+		return
+	}
+
+	_, err = e.App.NonconcurrentDB().Update(
+		core.CollectionNameAPIKeys,
+		dbx.Params{"lastUsed": types.NowDateTime().String()},
+		dbx.HashExp{"id": apiKey.Id},
+	).Execute()
+	if err != nil {
+		e.App.Logger().Warn("Failed to update the API key lastUsed date", "error", err, "apiKeyId", apiKey.Id)
+	}
+}
+
 func getAuthTokenFromRequest(e *core.RequestEvent) string {
 	token := e.Request.Header.Get("Authorization")
 	if token != "" {
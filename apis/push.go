@@ -0,0 +1,27 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindPushApi registers the web push api endpoints.
+func bindPushApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.GET("/push/public-key", pushPublicKey)
+}
+
+// pushPublicKey returns the VAPID public key that the clients should use
+// as "applicationServerKey" when subscribing via the browser PushManager
+// (the subscription itself is stored in the _pushSubscriptions collection).
+func pushPublicKey(e *core.RequestEvent) error {
+	config := e.App.Settings().WebPush
+	if !config.Enabled || config.PublicKey == "" {
+		return e.NotFoundError("Web push notifications are not enabled.", nil)
+	}
+
+	return e.JSON(http.StatusOK, map[string]string{
+		"publicKey": config.PublicKey,
+	})
+}
@@ -517,6 +517,66 @@ func TestRealtimeAuthRecordUpdateEvent(t *testing.T) {
 	}
 }
 
+func TestRealtimeRecordUpdatePositionalModifiers(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	// init realtime handlers
+	apis.NewRouter(testApp)
+
+	superuser, err := testApp.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := subscriptions.NewDefaultClient()
+	client.Set(apis.RealtimeClientAuthKey, superuser)
+	client.Subscribe("demo1/al1h9ijdeojtsjy")
+	testApp.SubscriptionsBroker().Register(client)
+
+	record, err := testApp.FindRecordById("demo1", "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record.Load(record.ReplaceModifiers(map[string]any{
+		"rel_many@move":      map[string]any{"keys": map[string]any{"oap640cot4yru2s": -1, "bgs820n361vj1qd": 1.5}},
+		"select_many@insert": map[string]any{"value": "optionA", "index": 0},
+	}))
+
+	if err := testApp.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	var data struct {
+		Action string
+		Record map[string]any
+	}
+
+	select {
+	case msg := <-client.Channel():
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(250 * time.Millisecond):
+		t.Fatal("Expected realtime update message")
+	}
+
+	if data.Action != "update" {
+		t.Fatalf("Expected update action, got %q", data.Action)
+	}
+
+	expectedRelMany := `["oap640cot4yru2s","4q1xlclmfloku33","bgs820n361vj1qd"]`
+	if raw, _ := json.Marshal(data.Record["rel_many"]); string(raw) != expectedRelMany {
+		t.Fatalf("Expected rel_many %s, got %s", expectedRelMany, raw)
+	}
+
+	expectedSelectMany := `["optionA","optionB"]`
+	if raw, _ := json.Marshal(data.Record["select_many"]); string(raw) != expectedSelectMany {
+		t.Fatalf("Expected select_many %s, got %s", expectedSelectMany, raw)
+	}
+}
+
 // Custom auth record model struct
 // -------------------------------------------------------------------
 var _ core.Model = (*CustomUser)(nil)
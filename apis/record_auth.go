@@ -36,6 +36,21 @@ func bindRecordAuthApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 		collectionPathRateLimit("", "authWithOAuth2", "auth"),
 	)
 
+	sub.GET("/saml/metadata", recordSAMLMetadata).Bind(
+		collectionPathRateLimit("", "samlMetadata"),
+	)
+	sub.GET("/saml/login", recordSAMLLogin).Bind(
+		collectionPathRateLimit("", "samlLogin"),
+	)
+	sub.POST("/saml/acs", recordSAMLACS).Bind(
+		collectionPathRateLimit("", "authWithSAML", "auth"),
+		SkipSuccessActivityLog(), // skip success log as it contains the assertion data
+	)
+	sub.POST("/auth-with-saml", recordAuthWithSAML).Bind(
+		collectionPathRateLimit("", "authWithSAML", "auth"),
+		SkipSuccessActivityLog(), // skip success log as it contains the assertion data
+	)
+
 	sub.POST("/request-otp", recordRequestOTP).Bind(
 		collectionPathRateLimit("", "requestOTP"),
 	)
@@ -66,6 +81,7 @@ func bindRecordAuthApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 	)
 
 	sub.POST("/impersonate/{id}", recordAuthImpersonate).Bind(RequireSuperuserAuth())
+	sub.POST("/unlock/{id}", recordAuthUnlock).Bind(RequireSuperuserAuth())
 }
 
 func findAuthCollection(e *core.RequestEvent) (*core.Collection, error) {
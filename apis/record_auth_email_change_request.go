@@ -24,6 +24,10 @@ func recordRequestEmailChange(e *core.RequestEvent) error {
 		return e.UnauthorizedError("The request requires valid auth record.", nil)
 	}
 
+	if e.HasAPIKeyAuth() {
+		return e.ForbiddenError("The email change is not allowed with an API key authorization.", nil)
+	}
+
 	form := newEmailChangeRequestForm(e.App, record)
 	if err = e.BindBody(form); err != nil {
 		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
@@ -39,7 +43,7 @@ func recordRequestEmailChange(e *core.RequestEvent) error {
 	event.NewEmail = form.NewEmail
 
 	return e.App.OnRecordRequestEmailChangeRequest().Trigger(event, func(e *core.RecordRequestEmailChangeRequestEvent) error {
-		if err := mails.SendRecordChangeEmail(e.App, e.Record, e.NewEmail); err != nil {
+		if err := mails.SendRecordChangeEmail(e.App, e.Record, e.NewEmail, requestLocales(e.Request)...); err != nil {
 			return firstApiError(err, e.BadRequestError("Failed to request email change.", err))
 		}
 
@@ -0,0 +1,133 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+)
+
+// note: for now allow only superusers but it may change in the future to allow access
+// also to users with "Manage API" rule access depending on the use cases that will arise
+func recordAuthUnlock(e *core.RequestEvent) error {
+	if !e.HasSuperuserAuth() {
+		return e.ForbiddenError("", nil)
+	}
+
+	collection, err := findAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	err = e.App.DeleteAuthLockoutsByRecord(record)
+	if err != nil {
+		return e.BadRequestError("Failed to unlock the auth record.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// -------------------------------------------------------------------
+
+// checkAuthLockout returns a 429 API error if the auth lockout ref
+// (the auth record id or core.AuthLockoutIdentityRef for unknown identities)
+// is temporary locked because of too many failed auth attempts.
+func checkAuthLockout(e *core.RequestEvent, collection *core.Collection, ref string) error {
+	if collection.PasswordAuth.LockoutThreshold <= 0 {
+		return nil // disabled
+	}
+
+	lockout, err := e.App.FindAuthLockout(collection.Id, ref)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return e.InternalServerError("Failed to check the auth record lockout state.", err)
+	}
+
+	if lockout.IsLocked() {
+		return e.TooManyRequestsError("Too many failed auth attempts, please try again later.", nil)
+	}
+
+	return nil
+}
+
+// registerAuthFailure atomically increments the failed auth attempts counter
+// of the auth lockout ref and locks it if the collection LockoutThreshold is reached.
+//
+// The OnRecordAuthLockout hook is triggered only for existing auth records
+// (authRecord could be nil in case of an unknown identity).
+//
+// Errors are only logged to avoid changing the original failed auth response.
+func registerAuthFailure(e *core.RequestEvent, collection *core.Collection, ref string, authRecord *core.Record, authMethod string) {
+	if collection.PasswordAuth.LockoutThreshold <= 0 {
+		return // disabled
+	}
+
+	lockout, err := e.App.IncrementAuthLockoutFailures(collection.Id, ref)
+	if err != nil {
+		e.App.Logger().Error("Failed to register failed auth attempt", "error", err, "ref", ref)
+		return
+	}
+
+	if !lockout.Lock(collection.PasswordAuth) {
+		return // below the threshold
+	}
+
+	if authRecord == nil {
+		_, err = e.App.ApplyAuthLockout(lockout)
+	} else {
+		event := new(core.RecordAuthLockoutEvent)
+		event.RequestEvent = e
+		event.Collection = collection
+		event.Record = authRecord
+		event.Lockout = lockout
+		event.AuthMethod = authMethod
+
+		err = e.App.OnRecordAuthLockout().Trigger(event, func(e *core.RecordAuthLockoutEvent) error {
+			applied, err := e.App.ApplyAuthLockout(e.Lockout)
+			if err != nil {
+				return err
+			}
+
+			if !applied {
+				return nil // already locked by a concurrent failed attempt
+			}
+
+			if e.Collection.AuthAlert.Enabled && e.Record.Email() != "" {
+				if err := mails.SendRecordAuthLockoutAlert(e.App, e.Record, e.Lockout, requestLocales(e.Request)...); err != nil {
+					e.App.Logger().Error("Failed to send auth lockout alert", "error", err, "recordId", e.Record.Id)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if err != nil {
+		e.App.Logger().Error("Failed to apply the auth lockout", "error", err, "ref", ref)
+	}
+}
+
+// resetAuthFailures clears the failed auth attempts state of the auth record
+// (usually called after a successful auth).
+func resetAuthFailures(e *core.RequestEvent, authRecord *core.Record) {
+	if authRecord.Collection().PasswordAuth.LockoutThreshold <= 0 {
+		return // disabled
+	}
+
+	if _, err := e.App.FindAuthLockoutByRecord(authRecord); err != nil {
+		return // no failed attempts or load error
+	}
+
+	if err := e.App.DeleteAuthLockoutsByRecord(authRecord); err != nil {
+		e.App.Logger().Error("Failed to reset the auth record failed attempts", "error", err, "recordId", authRecord.Id)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/auth"
@@ -31,6 +32,12 @@ type oauth2Response struct {
 	Enabled   bool           `json:"enabled"`
 }
 
+type samlResponse struct {
+	// AuthURL is the SP-initiated login url that redirects to the IdP.
+	AuthURL string `json:"authURL"`
+	Enabled bool   `json:"enabled"`
+}
+
 type providerInfo struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName"`
@@ -52,6 +59,7 @@ type providerInfo struct {
 type authMethodsResponse struct {
 	Password passwordResponse `json:"password"`
 	OAuth2   oauth2Response   `json:"oauth2"`
+	SAML     samlResponse     `json:"saml"`
 	MFA      mfaResponse      `json:"mfa"`
 	OTP      otpResponse      `json:"otp"`
 
@@ -106,6 +114,11 @@ func recordAuthMethods(e *core.RequestEvent) error {
 		result.MFA.Duration = collection.MFA.Duration
 	}
 
+	if collection.SAML.Enabled {
+		result.SAML.Enabled = true
+		result.SAML.AuthURL = strings.TrimRight(e.App.Settings().Meta.AppURL, "/") + "/api/collections/" + collection.Id + "/saml/login"
+	}
+
 	if !collection.OAuth2.Enabled {
 		result.fillLegacyFields()
 
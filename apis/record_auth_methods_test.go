@@ -1,6 +1,8 @@
 package apis_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"net/http"
 	"testing"
 
@@ -36,6 +38,7 @@ func TestRecordAuthMethodsList(t *testing.T) {
 			ExpectedContent: []string{
 				`"password":{"identityFields":[],"enabled":false}`,
 				`"oauth2":{"providers":[],"enabled":false}`,
+				`"saml":{"authURL":"","enabled":false}`,
 				`"mfa":{"enabled":false,"duration":0}`,
 				`"otp":{"enabled":false,"duration":0}`,
 			},
@@ -64,6 +67,23 @@ func TestRecordAuthMethodsList(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{"*": 0},
 		},
+		{
+			Name:   "auth collection with SAML auth allowed",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/auth-methods",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				if err != nil {
+					t.Fatal(err)
+				}
+				enableTestSAML(t, app, newTestSAMLCertificate(t, key), nil)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"saml":{"authURL":"` + testSAMLSPBaseURL + `/login","enabled":true}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
 
 		// rate limit checks
 		// -----------------------------------------------------------
@@ -100,8 +100,9 @@ func recordRequestOTP(e *core.RequestEvent) error {
 			// send OTP email
 			// (in the background as a very basic timing attacks and emails enumeration protection)
 			// ---
+			locales := requestLocales(e.Request)
 			routine.FireAndForget(func() {
-				err = mails.SendRecordOTP(originalApp, e.Record, otp.Id, e.Password)
+				err = mails.SendRecordOTP(originalApp, e.Record, otp.Id, e.Password, locales...)
 				if err != nil {
 					originalApp.Logger().Error("Failed to send OTP email", "error", errors.Join(err, originalApp.Delete(otp)))
 				}
@@ -12,6 +12,11 @@ func recordAuthRefresh(e *core.RequestEvent) error {
 		return e.NotFoundError("Missing auth record context.", nil)
 	}
 
+	// API keys have their own lifetime and shouldn't be exchanged for a regular auth token
+	if e.HasAPIKeyAuth() {
+		return e.ForbiddenError("The auth refresh is not allowed with an API key authorization.", nil)
+	}
+
 	event := new(core.RecordAuthRefreshRequestEvent)
 	event.RequestEvent = e
 	event.Collection = record.Collection()
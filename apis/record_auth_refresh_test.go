@@ -53,6 +53,20 @@ func TestRecordAuthRefresh(t *testing.T) {
 			ExpectedContent: []string{`"data":{}`},
 			ExpectedEvents:  map[string]int{"*": 0},
 		},
+		{
+			Name:   "auth record + API key authorization",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-refresh",
+			Headers: map[string]string{
+				"X-Api-Key": testAPIKey,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createTestAPIKey(t, app)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
 		{
 			Name:   "auth record + same auth collection as the token",
 			Method: http.MethodPost,
@@ -59,9 +59,16 @@ func recordAuthWithOTP(e *core.RequestEvent) error {
 		return e.TooManyRequestsError("Too many attempts, please try again later with a new OTP.", nil)
 	}
 
+	if err = checkAuthLockout(e, collection, event.Record.Id); err != nil {
+		return err
+	}
+
 	if !event.OTP.ValidatePassword(form.Password) {
+		registerAuthFailure(e, collection, event.Record.Id, event.Record, core.MFAMethodOTP)
 		return e.BadRequestError("Invalid or expired OTP", errors.New("incorrect password"))
 	}
+
+	resetAuthFailures(e, event.Record)
 	// ---
 
 	return e.App.OnRecordAuthWithOTPRequest().Trigger(event, func(e *core.RecordAuthWithOTPRequestEvent) error {
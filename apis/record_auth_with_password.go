@@ -75,10 +75,40 @@ func recordAuthWithPassword(e *core.RequestEvent) error {
 	event.IdentityField = form.IdentityField
 
 	return e.App.OnRecordAuthWithPasswordRequest().Trigger(event, func(e *core.RecordAuthWithPasswordRequestEvent) error {
-		if e.Record == nil || !e.Record.ValidatePassword(e.Password) {
+		if e.Record == nil {
+			// track the unknown identities failed attempts too so that
+			// the lockout response can't be used for accounts enumeration
+			ref := core.AuthLockoutIdentityRef(e.Identity)
+			if err := checkAuthLockout(e.RequestEvent, e.Collection, ref); err != nil {
+				return err
+			}
+
+			registerAuthFailure(e.RequestEvent, e.Collection, ref, nil, core.MFAMethodPassword)
+
+			return e.BadRequestError("Failed to authenticate.", errors.New("invalid login credentials"))
+		}
+
+		if err := checkAuthLockout(e.RequestEvent, e.Collection, e.Record.Id); err != nil {
+			return err
+		}
+
+		if !e.Record.ValidatePassword(e.Password) {
+			registerAuthFailure(e.RequestEvent, e.Collection, e.Record.Id, e.Record, core.MFAMethodPassword)
 			return e.BadRequestError("Failed to authenticate.", errors.New("invalid login credentials"))
 		}
 
+		resetAuthFailures(e.RequestEvent, e.Record)
+
+		expired, err := e.App.HasPasswordExpired(e.Record)
+		if err != nil {
+			return e.InternalServerError("Failed to check the password age.", err)
+		}
+		if expired {
+			return e.BadRequestError("The password has expired and must be reset.", validation.Errors{
+				"password": validation.NewError("validation_password_expired", "The password has expired and must be reset."),
+			})
+		}
+
 		return RecordAuthResponse(e.RequestEvent, e.Record, core.MFAMethodPassword, nil)
 	})
 }
@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/dbutils"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestRecordAuthWithPassword(t *testing.T) {
@@ -639,6 +641,273 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 		},
 
+		{
+			Name:   "valid identity field and valid password with expired password",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				collection, err := app.FindCollectionByNameOrId("clients")
+				if err != nil {
+					t.Fatal(err)
+				}
+				collection.Fields.GetByName(core.FieldNamePassword).(*core.PasswordField).MaxAge = 3600
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+
+				record, err := app.FindAuthRecordByEmail(collection, "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				entry := core.NewPasswordHistory(app)
+				entry.SetCollectionRef(collection.Id)
+				entry.SetRecordRef(record.Id)
+				entry.SetField(core.FieldNamePassword)
+				entry.SetHash(record.GetString(core.FieldNamePassword + ":hash"))
+				entry.SetRaw("created", types.NowDateTime().Add(-2*time.Hour))
+				if err := app.SaveNoValidate(entry); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"password":{"code":"validation_password_expired"`,
+			},
+			NotExpectedContent: []string{
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "valid identity field and invalid password (below the lockout threshold)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"invalid"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setLockoutThreshold(t, app, "clients", 3)
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindAuthRecordByEmail("clients", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				lockout, err := app.FindAuthLockoutByRecord(record)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if lockout.Failures != 1 || lockout.IsLocked() {
+					t.Fatalf("Expected 1 failure and unlocked state, got %d failures and locked until %v", lockout.Failures, lockout.LockedUntil)
+				}
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "valid identity field and invalid password (reaching the lockout threshold)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"invalid"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				record := setLockoutThreshold(t, app, "clients", 3)
+
+				lockout := core.NewAuthLockout(record)
+				lockout.Failures = 2
+				if err := app.AuxSave(lockout); err != nil {
+					t.Fatal(err)
+				}
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindAuthRecordByEmail("clients", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				lockout, err := app.FindAuthLockoutByRecord(record)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !lockout.IsLocked() || lockout.Lockouts != 1 {
+					t.Fatalf("Expected locked state with 1 lockout, got %d lockouts and locked until %v", lockout.Lockouts, lockout.LockedUntil)
+				}
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+				"OnRecordAuthLockout":             1,
+				"OnMailerSend":                    1,
+				"OnMailerRecordAuthAlertSend":     1,
+			},
+		},
+		{
+			Name:   "valid identity field and valid password (locked auth record)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				record := setLockoutThreshold(t, app, "clients", 3)
+
+				lockout := core.NewAuthLockout(record)
+				lockout.LockedUntil = types.NowDateTime().Add(time.Minute)
+				if err := app.AuxSave(lockout); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 429,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			NotExpectedContent: []string{
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "valid identity field and valid password (expired lock)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				record := setLockoutThreshold(t, app, "clients", 3)
+
+				lockout := core.NewAuthLockout(record)
+				lockout.Failures = 2
+				lockout.Lockouts = 1
+				lockout.LockedUntil = types.NowDateTime().Add(-time.Minute)
+				if err := app.AuxSave(lockout); err != nil {
+					t.Fatal(err)
+				}
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindAuthRecordByEmail("clients", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := app.FindAuthLockoutByRecord(record); err == nil {
+					t.Fatal("Expected the auth record failures to be reset")
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"email":"test@example.com"`,
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+				"OnRecordAuthRequest":             1,
+				"OnRecordEnrich":                  1,
+				// authOrigin track
+				"OnModelCreate":               1,
+				"OnModelCreateExecute":        1,
+				"OnModelAfterCreateSuccess":   1,
+				"OnModelValidate":             1,
+				"OnRecordCreate":              1,
+				"OnRecordCreateExecute":       1,
+				"OnRecordAfterCreateSuccess":  1,
+				"OnRecordValidate":            1,
+				"OnMailerSend":                1,
+				"OnMailerRecordAuthAlertSend": 1,
+			},
+		},
+		{
+			Name:   "unknown identity (reaching the lockout threshold)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"missing@example.com",
+				"password":"invalid"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				record := setLockoutThreshold(t, app, "clients", 2)
+
+				if _, err := app.IncrementAuthLockoutFailures(record.Collection().Id, core.AuthLockoutIdentityRef("missing@example.com")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				collection, err := app.FindCollectionByNameOrId("clients")
+				if err != nil {
+					t.Fatal(err)
+				}
+				lockout, err := app.FindAuthLockout(collection.Id, core.AuthLockoutIdentityRef("MISSING@example.com"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !lockout.IsLocked() || lockout.Lockouts != 1 {
+					t.Fatalf("Expected locked state with 1 lockout, got %d lockouts and locked until %v", lockout.Lockouts, lockout.LockedUntil)
+				}
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "unknown identity (locked)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"missing@example.com",
+				"password":"invalid"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				record := setLockoutThreshold(t, app, "clients", 3)
+
+				lockout, err := app.IncrementAuthLockoutFailures(record.Collection().Id, core.AuthLockoutIdentityRef("missing@example.com"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				lockout.LockedUntil = types.NowDateTime().Add(time.Minute)
+				if _, err := app.ApplyAuthLockout(lockout); err != nil {
+					t.Fatal(err)
+				}
+			},
+			// same as the existing locked auth record response
+			ExpectedStatus: 429,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
 		// rate limit checks
 		// -----------------------------------------------------------
 		{
@@ -711,3 +980,23 @@ func TestRecordAuthWithPassword(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+func setLockoutThreshold(t testing.TB, app *tests.TestApp, collectionName string, threshold int) *core.Record {
+	collection, err := app.FindCollectionByNameOrId(collectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.PasswordAuth.LockoutThreshold = threshold
+	collection.PasswordAuth.LockoutDuration = 60
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.FindAuthRecordByEmail(collection, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return record
+}
@@ -0,0 +1,504 @@
+package apis
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/saml"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+const (
+	samlSubscriptionTopic      string = "@saml"
+	samlRedirectFailurePath    string = "../../../../_/#/auth/oauth2-redirect-failure"
+	samlRedirectSuccessPath    string = "../../../../_/#/auth/oauth2-redirect-success"
+	samlUsedAssertionsStoreKey string = "@saml_used_assertions"
+
+	// samlRequestExpiration is the max time between the AuthnRequest
+	// redirect and the IdP response submission.
+	samlRequestExpiration = 10 * time.Minute
+)
+
+// samlServiceProvider initializes a new SAML service provider for the specified auth collection.
+//
+// The default SP entity id is the collection metadata url and the ACS
+// url is always derived from the application url.
+func samlServiceProvider(app core.App, collection *core.Collection) (*saml.ServiceProvider, error) {
+	baseURL := strings.TrimRight(app.Settings().Meta.AppURL, "/") + "/api/collections/" + collection.Id + "/saml"
+
+	return collection.SAML.InitServiceProvider(baseURL+"/metadata", baseURL+"/acs")
+}
+
+func findSAMLAuthCollection(e *core.RequestEvent) (*core.Collection, error) {
+	collection, err := findAuthCollection(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if !collection.SAML.Enabled {
+		return nil, e.ForbiddenError("The collection is not configured to allow SAML authentication.", nil)
+	}
+
+	return collection, nil
+}
+
+// recordSAMLMetadata returns the collection service provider metadata XML.
+func recordSAMLMetadata(e *core.RequestEvent) error {
+	collection, err := findSAMLAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	sp, err := samlServiceProvider(e.App, collection)
+	if err != nil {
+		return e.InternalServerError("Failed to init the SAML service provider.", err)
+	}
+
+	metadata, err := sp.Metadata()
+	if err != nil {
+		return e.InternalServerError("Failed to generate the SAML metadata.", err)
+	}
+
+	return e.Blob(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// recordSAMLLogin redirects to the IdP single sign-on service
+// with a new AuthnRequest.
+func recordSAMLLogin(e *core.RequestEvent) error {
+	collection, err := findSAMLAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	sp, err := samlServiceProvider(e.App, collection)
+	if err != nil {
+		return e.InternalServerError("Failed to init the SAML service provider.", err)
+	}
+
+	// the request state is kept in a signed RelayState token
+	// (optionally with the realtime client id to forward the IdP response to)
+	relayState, requestId, err := saml.NewRelayState(
+		collection.AuthToken.Secret,
+		e.Request.URL.Query().Get("clientId"),
+		samlRequestExpiration,
+	)
+	if err != nil {
+		return e.BadRequestError("Invalid clientId.", err)
+	}
+
+	redirectURL, _, err := sp.AuthnRequestURL(relayState, requestId)
+	if err != nil {
+		return e.InternalServerError("Failed to build the SAML AuthnRequest.", err)
+	}
+
+	return e.Redirect(http.StatusTemporaryRedirect, redirectURL)
+}
+
+// recordSAMLACS handles the IdP HTTP-POST binding response
+// (aka. the Assertion Consumer Service endpoint).
+//
+// If the login was initiated with a realtime client id, the IdP
+// response is forwarded to the "@saml" subscribed client and the browser
+// is redirected to the Dashboard success/failure page (similar to the
+// OAuth2 redirect) so that the client could complete the authentication
+// with the auth-with-saml endpoint. Otherwise the response is directly
+// authenticated and the auth JSON response is returned.
+func recordSAMLACS(e *core.RequestEvent) error {
+	collection, err := findSAMLAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	form, err := bindSAMLLoginForm(e)
+	if err != nil {
+		return err
+	}
+
+	_, clientId, stateErr := saml.ParseRelayState(collection.AuthToken.Secret, form.RelayState, time.Now())
+	if stateErr == nil && clientId != "" {
+		return samlSubscriptionRedirect(e, clientId, form)
+	}
+
+	return authWithSAML(e, collection, form)
+}
+
+// recordAuthWithSAML authenticates with the IdP response submitted
+// as JSON or form data (e.g. the one forwarded by the ACS endpoint to
+// the "@saml" realtime subscription).
+func recordAuthWithSAML(e *core.RequestEvent) error {
+	collection, err := findSAMLAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	form, err := bindSAMLLoginForm(e)
+	if err != nil {
+		return err
+	}
+
+	return authWithSAML(e, collection, form)
+}
+
+type samlRedirectData struct {
+	State        string `json:"state"`
+	SAMLResponse string `json:"SAMLResponse"`
+	RelayState   string `json:"RelayState"`
+}
+
+func samlSubscriptionRedirect(e *core.RequestEvent, clientId string, form *recordSAMLLoginForm) error {
+	client, err := e.App.SubscriptionsBroker().ClientById(clientId)
+	if err != nil || client.IsDiscarded() || !client.HasSubscription(samlSubscriptionTopic) {
+		e.App.Logger().Debug("Missing or invalid SAML subscription client", "error", err, "clientId", clientId)
+		return e.Redirect(http.StatusSeeOther, samlRedirectFailurePath)
+	}
+	defer client.Unsubscribe(samlSubscriptionTopic)
+
+	encodedData, err := json.Marshal(samlRedirectData{
+		State:        clientId,
+		SAMLResponse: form.SAMLResponse,
+		RelayState:   form.RelayState,
+	})
+	if err != nil {
+		e.App.Logger().Debug("Failed to marshalize SAML redirect data", "error", err)
+		return e.Redirect(http.StatusSeeOther, samlRedirectFailurePath)
+	}
+
+	client.Send(subscriptions.Message{
+		Name: samlSubscriptionTopic,
+		Data: encodedData,
+	})
+
+	return e.Redirect(http.StatusSeeOther, samlRedirectSuccessPath)
+}
+
+func bindSAMLLoginForm(e *core.RequestEvent) (*recordSAMLLoginForm, error) {
+	form := new(recordSAMLLoginForm)
+	if err := e.BindBody(form); err != nil {
+		return nil, firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+
+	if err := form.validate(); err != nil {
+		return nil, firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+
+	return form, nil
+}
+
+func authWithSAML(e *core.RequestEvent, collection *core.Collection, form *recordSAMLLoginForm) error {
+	var fallbackAuthRecord *core.Record
+	if e.Auth != nil && e.Auth.Collection().Id == collection.Id {
+		fallbackAuthRecord = e.Auth
+	}
+
+	e.Set(core.RequestEventKeyInfoContext, core.RequestInfoContextSAML)
+
+	sp, err := samlServiceProvider(e.App, collection)
+	if err != nil {
+		return e.InternalServerError("Failed to init the SAML service provider.", err)
+	}
+
+	now := time.Now()
+
+	// SP-initiated response -> ensure that it was issued for a valid
+	// and non-expired request of the current collection
+	var requestId string
+	if form.RelayState != "" {
+		requestId, _, _ = saml.ParseRelayState(collection.AuthToken.Secret, form.RelayState, now)
+	}
+
+	var assertion *saml.Assertion
+	if requestId != "" {
+		assertion, err = sp.ParseResponse(form.SAMLResponse, now, requestId)
+	} else {
+		assertion, err = sp.ParseResponse(form.SAMLResponse, now)
+	}
+	if err != nil {
+		return e.BadRequestError("Invalid SAML response.", err)
+	}
+
+	if assertion.InResponseTo != "" && requestId == "" {
+		return e.BadRequestError("Invalid or expired SAML request.", nil)
+	}
+
+	// replay protection
+	// (the assertion id is kept until the end of its validity period)
+	usedAssertions := e.App.Store().GetOrSet(samlUsedAssertionsStoreKey, func() any {
+		return newSAMLUsedAssertions()
+	}).(*samlUsedAssertions)
+	if !usedAssertions.use(collection.Id+"_"+assertion.Id, assertion.NotOnOrAfter.Add(saml.DefaultClockSkew), now) {
+		return e.BadRequestError("The SAML assertion has already been used.", nil)
+	}
+
+	// locate existing ExternalAuth rel
+	// ---------------------------------------------------------------
+
+	var authRecord *core.Record
+
+	externalAuthRel, err := e.App.FindFirstExternalAuthByExpr(dbx.HashExp{
+		"collectionRef": collection.Id,
+		"provider":      saml.ProviderName,
+		"providerId":    assertion.NameId,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return e.InternalServerError("Failed SAML relation check.", err)
+	}
+
+	email := samlAssertionEmail(collection, assertion)
+
+	switch {
+	case err == nil && externalAuthRel != nil:
+		authRecord, err = e.App.FindRecordById(collection, externalAuthRel.RecordRef())
+		if err != nil {
+			return err
+		}
+	case fallbackAuthRecord != nil:
+		// fallback to the logged auth record (if any)
+		authRecord = fallbackAuthRecord
+	case email != "":
+		// look for an existing auth record by the assertion email
+		authRecord, err = e.App.FindAuthRecordByEmail(collection.Id, email)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return e.InternalServerError("Failed SAML auth record check.", err)
+		}
+	}
+
+	// ---------------------------------------------------------------
+
+	event := new(core.RecordAuthWithSAMLRequestEvent)
+	event.RequestEvent = e
+	event.Collection = collection
+	event.ServiceProvider = sp
+	event.Assertion = assertion
+	event.CreateData = form.CreateData
+	event.Record = authRecord
+	event.IsNewRecord = authRecord == nil
+
+	return e.App.OnRecordAuthWithSAMLRequest().Trigger(event, func(e *core.RecordAuthWithSAMLRequestEvent) error {
+		if err := samlSubmit(e, externalAuthRel); err != nil {
+			return firstApiError(err, e.BadRequestError("Failed to authenticate.", err))
+		}
+
+		meta := struct {
+			*saml.Assertion
+			RelayState string `json:"relayState"`
+			IsNew      bool   `json:"isNew"`
+		}{
+			Assertion:  e.Assertion,
+			RelayState: form.RelayState,
+			IsNew:      e.IsNewRecord,
+		}
+
+		return RecordAuthResponse(e.RequestEvent, e.Record, core.MFAMethodSAML, meta)
+	})
+}
+
+// -------------------------------------------------------------------
+
+// samlUsedAssertions keeps track of the already used assertion ids
+// until their expiration.
+//
+// The expired entries are lazily removed on use so that no timers are
+// needed (the used ids are bounded by the assertions validity period).
+type samlUsedAssertions struct {
+	items map[string]time.Time
+	mu    sync.Mutex
+}
+
+func newSAMLUsedAssertions() *samlUsedAssertions {
+	return &samlUsedAssertions{items: map[string]time.Time{}}
+}
+
+// use marks the specified assertion key as used until the expires time.
+//
+// It returns false if the key was already used and it hasn't expired yet.
+func (s *samlUsedAssertions) use(key string, expires time.Time, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, exp := range s.items {
+		if now.After(exp) {
+			delete(s.items, k)
+		}
+	}
+
+	if _, ok := s.items[key]; ok {
+		return false
+	}
+
+	s.items[key] = expires
+
+	return true
+}
+
+// -------------------------------------------------------------------
+
+type recordSAMLLoginForm struct {
+	// Additional data that will be used for creating a new auth record
+	// if an existing SAML linked account doesn't exist.
+	CreateData map[string]any `form:"createData" json:"createData"`
+
+	// The base64 encoded IdP response.
+	SAMLResponse string `form:"SAMLResponse" json:"SAMLResponse"`
+
+	// The relay state token sent with the initial request
+	// (or an arbitrary IdP value for IdP-initiated responses).
+	RelayState string `form:"RelayState" json:"RelayState"`
+}
+
+func (form *recordSAMLLoginForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.SAMLResponse, validation.Required),
+		validation.Field(&form.RelayState, validation.Length(0, saml.MaxRelayStateLength)),
+	)
+}
+
+// samlAssertionEmail returns the assertion email based on the collection
+// SAML.EmailAttribute option, with fallback to the NameID if its format is emailAddress.
+func samlAssertionEmail(collection *core.Collection, assertion *saml.Assertion) string {
+	if collection.SAML.EmailAttribute != "" {
+		return assertion.Attribute(collection.SAML.EmailAttribute)
+	}
+
+	if assertion.NameIdFormat == saml.NameIDFormatEmailAddress {
+		return assertion.NameId
+	}
+
+	return ""
+}
+
+func samlSubmit(e *core.RecordAuthWithSAMLRequestEvent, optExternalAuth *core.ExternalAuth) error {
+	email := samlAssertionEmail(e.Collection, e.Assertion)
+
+	return e.App.RunInTransaction(func(txApp core.App) error {
+		if e.Record == nil {
+			// extra check to prevent creating a superuser record via
+			// SAML in case the method is used by another action
+			if e.Collection.Name == core.CollectionNameSuperusers {
+				return errors.New("superusers are not allowed to sign-up with SAML")
+			}
+
+			payload := maps.Clone(e.CreateData)
+			if payload == nil {
+				payload = map[string]any{}
+			}
+
+			// assign the assertion email only if the user hasn't submitted one
+			if v, _ := payload[core.FieldNameEmail].(string); v == "" {
+				payload[core.FieldNameEmail] = email
+			}
+
+			// map the assertion attributes (unless the field was explicitly submitted as part of CreateData)
+			for attr, fieldName := range e.Collection.SAML.MappedFields {
+				if _, ok := payload[fieldName]; ok {
+					continue
+				}
+
+				values := e.Assertion.Attributes[attr]
+				switch len(values) {
+				case 0:
+					// nothing to map
+				case 1:
+					payload[fieldName] = values[0]
+				default:
+					payload[fieldName] = values
+				}
+			}
+
+			createdRecord, err := sendSAMLRecordCreateRequest(txApp, e, payload)
+			if err != nil {
+				return err
+			}
+
+			e.Record = createdRecord
+
+			if e.Record.Email() == email && !e.Record.Verified() {
+				// mark as verified as long as it matches the assertion data (even if the email is empty)
+				e.Record.SetVerified(true)
+				if err := txApp.Save(e.Record); err != nil {
+					return err
+				}
+			}
+		} else {
+			var needUpdate bool
+
+			isLoggedAuthRecord := e.Auth != nil &&
+				e.Auth.Id == e.Record.Id &&
+				e.Auth.Collection().Id == e.Record.Collection().Id
+
+			// set random password for users with unverified email
+			// (this is in case a malicious actor has registered previously with the user email)
+			if !isLoggedAuthRecord && e.Record.Email() != "" && !e.Record.Verified() {
+				e.Record.SetRandomPassword()
+				needUpdate = true
+			}
+
+			// update the existing auth record empty email if the assertion has one
+			if e.Record.Email() == "" && email != "" {
+				e.Record.SetEmail(email)
+				needUpdate = true
+			}
+
+			// update the existing auth record verified state
+			// (only if the auth record doesn't have an email or the auth record email match with the assertion one)
+			if !e.Record.Verified() && (e.Record.Email() == "" || e.Record.Email() == email) {
+				e.Record.SetVerified(true)
+				needUpdate = true
+			}
+
+			if needUpdate {
+				if err := txApp.Save(e.Record); err != nil {
+					return err
+				}
+			}
+		}
+
+		// create ExternalAuth relation if missing
+		if optExternalAuth == nil {
+			optExternalAuth = core.NewExternalAuth(txApp)
+			optExternalAuth.SetCollectionRef(e.Record.Collection().Id)
+			optExternalAuth.SetRecordRef(e.Record.Id)
+			optExternalAuth.SetProvider(saml.ProviderName)
+			optExternalAuth.SetProviderId(e.Assertion.NameId)
+
+			if err := txApp.Save(optExternalAuth); err != nil {
+				return fmt.Errorf("failed to save linked rel: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func sendSAMLRecordCreateRequest(txApp core.App, e *core.RecordAuthWithSAMLRequestEvent, payload map[string]any) (*core.Record, error) {
+	ir := &core.InternalRequest{
+		Method: http.MethodPost,
+		URL:    "/api/collections/" + e.Collection.Name + "/records",
+		Body:   payload,
+	}
+
+	var createdRecord *core.Record
+	response, err := processInternalRequest(txApp, e.RequestEvent, ir, core.RequestInfoContextSAML, func(data any) error {
+		createdRecord, _ = data.(*core.Record)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != http.StatusOK || createdRecord == nil {
+		return nil, errors.New("failed to create SAML auth record")
+	}
+
+	return createdRecord, nil
+}
@@ -0,0 +1,768 @@
+package apis_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/saml"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+const (
+	testSAMLAppURL      = "https://example.com"
+	testSAMLIdPEntityId = "https://idp.example.com"
+	testSAMLIdPSSOURL   = "https://idp.example.com/sso"
+	testSAMLSPBaseURL   = testSAMLAppURL + "/api/collections/_pb_users_auth_/saml"
+
+	// used to sign the RelayState tokens
+	testSAMLAuthTokenSecret = "test_saml_auth_token_secret_1234567890"
+)
+
+type testSAMLAssertion struct {
+	Id           string
+	NameId       string
+	NameIdFormat string
+	InResponseTo string
+	Attributes   map[string][]string
+}
+
+// newTestSAMLResponse creates a new base64 encoded SAML response with
+// an assertion signed with the provided key.
+//
+// Note that the generated xml is already in its exclusive canonical form
+// so that it could be signed without the need of a c14n implementation.
+func newTestSAMLResponse(t testing.TB, key *rsa.PrivateKey, data testSAMLAssertion) string {
+	now := time.Now().UTC()
+	issueInstant := now.Format(time.RFC3339)
+	notBefore := now.Add(-1 * time.Minute).Format(time.RFC3339)
+	notOnOrAfter := now.Add(5 * time.Minute).Format(time.RFC3339)
+
+	inResponseTo := ""
+	if data.InResponseTo != "" {
+		inResponseTo = ` InResponseTo="` + data.InResponseTo + `"`
+	}
+
+	var attrs strings.Builder
+	names := make([]string, 0, len(data.Attributes))
+	for name := range data.Attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		attrs.WriteString(`<saml:Attribute Name="` + name + `">`)
+		for _, v := range data.Attributes[name] {
+			attrs.WriteString(`<saml:AttributeValue>` + v + `</saml:AttributeValue>`)
+		}
+		attrs.WriteString(`</saml:Attribute>`)
+	}
+
+	assertionStart := `<saml:Assertion xmlns:saml="` + saml.NamespaceAssertion + `" ID="` + data.Id + `" IssueInstant="` + issueInstant + `" Version="2.0">` +
+		`<saml:Issuer>` + testSAMLIdPEntityId + `</saml:Issuer>`
+	assertionEnd := `<saml:Subject>` +
+		`<saml:NameID Format="` + data.NameIdFormat + `">` + data.NameId + `</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">` +
+		`<saml:SubjectConfirmationData` + inResponseTo + ` NotOnOrAfter="` + notOnOrAfter + `" Recipient="` + testSAMLSPBaseURL + `/acs"></saml:SubjectConfirmationData>` +
+		`</saml:SubjectConfirmation>` +
+		`</saml:Subject>` +
+		`<saml:Conditions NotBefore="` + notBefore + `" NotOnOrAfter="` + notOnOrAfter + `">` +
+		`<saml:AudienceRestriction><saml:Audience>` + testSAMLSPBaseURL + `/metadata</saml:Audience></saml:AudienceRestriction>` +
+		`</saml:Conditions>` +
+		`<saml:AuthnStatement AuthnInstant="` + issueInstant + `" SessionIndex="test_session"></saml:AuthnStatement>` +
+		`<saml:AttributeStatement>` + attrs.String() + `</saml:AttributeStatement>` +
+		`</saml:Assertion>`
+
+	digest := sha256.Sum256([]byte(assertionStart + assertionEnd))
+
+	signedInfo := `<ds:SignedInfo xmlns:ds="` + saml.NamespaceDSig + `">` +
+		`<ds:CanonicalizationMethod Algorithm="` + saml.AlgorithmExcC14N + `"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="` + saml.AlgorithmSignatureRSASHA256 + `"></ds:SignatureMethod>` +
+		`<ds:Reference URI="#` + data.Id + `">` +
+		`<ds:Transforms>` +
+		`<ds:Transform Algorithm="` + saml.AlgorithmEnvelopedSignature + `"></ds:Transform>` +
+		`<ds:Transform Algorithm="` + saml.AlgorithmExcC14N + `"></ds:Transform>` +
+		`</ds:Transforms>` +
+		`<ds:DigestMethod Algorithm="` + saml.AlgorithmDigestSHA256 + `"></ds:DigestMethod>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest[:]) + `</ds:DigestValue>` +
+		`</ds:Reference>` +
+		`</ds:SignedInfo>`
+
+	signedInfoHash := sha256.Sum256([]byte(signedInfo))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, signedInfoHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := `<samlp:Response xmlns:samlp="` + saml.NamespaceProtocol + `" ID="_response" Version="2.0" Destination="` + testSAMLSPBaseURL + `/acs"` + inResponseTo + `>` +
+		`<samlp:Status><samlp:StatusCode Value="` + saml.StatusSuccess + `"/></samlp:Status>` +
+		assertionStart +
+		`<ds:Signature xmlns:ds="` + saml.NamespaceDSig + `">` + signedInfo +
+		`<ds:SignatureValue>` + base64.StdEncoding.EncodeToString(signature) + `</ds:SignatureValue>` +
+		`</ds:Signature>` +
+		assertionEnd +
+		`</samlp:Response>`
+
+	return base64.StdEncoding.EncodeToString([]byte(response))
+}
+
+// newTestSAMLCertificate generates a new self-signed PEM encoded certificate for the specified key.
+func newTestSAMLCertificate(t testing.TB, key *rsa.PrivateKey) string {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test idp"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}))
+}
+
+func enableTestSAML(t testing.TB, app *tests.TestApp, cert string, mappedFields map[string]string) *core.Collection {
+	app.Settings().Meta.AppURL = testSAMLAppURL
+
+	usersCol, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usersCol.MFA.Enabled = false
+	usersCol.AuthToken.Secret = testSAMLAuthTokenSecret
+	usersCol.SAML = core.SAMLConfig{
+		Enabled:        true,
+		IdPEntityId:    testSAMLIdPEntityId,
+		IdPSSOURL:      testSAMLIdPSSOURL,
+		IdPCertificate: cert,
+		EmailAttribute: "email",
+		MappedFields:   mappedFields,
+	}
+	if err := app.Save(usersCol); err != nil {
+		t.Fatal(err)
+	}
+
+	return usersCol
+}
+
+func samlFormBody(encodedResponse string, optRelayState ...string) *strings.Reader {
+	data := url.Values{"SAMLResponse": {encodedResponse}}
+	if len(optRelayState) > 0 {
+		data.Set("RelayState", optRelayState[0])
+	}
+
+	return strings.NewReader(data.Encode())
+}
+
+func newTestSAMLRelayState(t testing.TB, secret string, clientId string, duration time.Duration) (string, string) {
+	state, requestId, err := saml.NewRelayState(secret, clientId, duration)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state, requestId
+}
+
+var samlFormHeaders = map[string]string{
+	"content-type": "application/x-www-form-urlencoded",
+}
+
+func TestRecordSAMLMetadata(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestSAMLCertificate(t, key)
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "missing collection",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/missing/saml/metadata",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "disabled SAML auth",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/users/saml/metadata",
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "enabled SAML auth",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/saml/metadata",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`entityID="` + testSAMLSPBaseURL + `/metadata"`,
+				`Location="` + testSAMLSPBaseURL + `/acs"`,
+				`WantAssertionsSigned="true"`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordSAMLLogin(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestSAMLCertificate(t, key)
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "disabled SAML auth",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/users/saml/login",
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "too long clientId",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/saml/login?clientId=" + strings.Repeat("a", 81),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "redirect to the IdP",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/saml/login?clientId=test",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				location, err := url.Parse(res.Header.Get("Location"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if location.Scheme+"://"+location.Host+location.Path != testSAMLIdPSSOURL {
+					t.Fatalf("Expected redirect to %q, got %q", testSAMLIdPSSOURL, location.String())
+				}
+
+				if location.Query().Get("SAMLRequest") == "" {
+					t.Fatal("Expected non-empty SAMLRequest")
+				}
+
+				_, clientId, err := saml.ParseRelayState(testSAMLAuthTokenSecret, location.Query().Get("RelayState"), time.Now())
+				if err != nil {
+					t.Fatalf("Expected valid signed RelayState, got %v", err)
+				}
+
+				if clientId != "test" {
+					t.Fatalf("Expected RelayState clientId %q, got %q", "test", clientId)
+				}
+
+				for k := range app.Store().GetAll() {
+					if strings.HasPrefix(k, "@saml") {
+						t.Fatalf("Expected no stored SAML request state, got %q", k)
+					}
+				}
+			},
+			ExpectedStatus: 307,
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthWithSAML(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestSAMLCertificate(t, key)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relayState, requestId := newTestSAMLRelayState(t, testSAMLAuthTokenSecret, "", 1*time.Minute)
+	expiredRelayState, expiredRequestId := newTestSAMLRelayState(t, testSAMLAuthTokenSecret, "", -1*time.Minute)
+	otherRelayState, otherRequestId := newTestSAMLRelayState(t, "other_secret", "", 1*time.Minute)
+
+	usedResponse := newTestSAMLResponse(t, key, testSAMLAssertion{
+		Id:           "_a1",
+		NameId:       "test_id",
+		NameIdFormat: saml.NameIDFormatPersistent,
+	})
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "disabled SAML auth",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+			})),
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "missing SAMLResponse",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body:   samlFormBody(""),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"SAMLResponse":{"code":"validation_required"`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "untrusted signature",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, otherKey, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+			})),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "unknown InResponseTo request",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+				InResponseTo: "_missing",
+			})),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "RelayState signed for a different collection",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+				InResponseTo: otherRequestId,
+			}), otherRelayState),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "expired RelayState",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+				InResponseTo: expiredRequestId,
+			}), expiredRelayState),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "InResponseTo not matching the RelayState request",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+				InResponseTo: otherRequestId,
+			}), relayState),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "already used assertion",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body:   samlFormBody(usedResponse),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+
+				// submit the same assertion in advance
+				mux, err := e.Router.BuildMux()
+				if err != nil {
+					t.Fatal(err)
+				}
+				req := httptest.NewRequest(http.MethodPost, "/api/collections/users/saml/acs", samlFormBody(usedResponse))
+				req.Header.Set("content-type", "application/x-www-form-urlencoded")
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Expected the first assertion submit to succeed, got %d: %s", rec.Code, rec.Body.String())
+				}
+			},
+			Headers:         samlFormHeaders,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "existing linked SAML user",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+				InResponseTo: requestId,
+			}), relayState),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol := enableTestSAML(t, app, cert, nil)
+
+				user, err := app.FindAuthRecordByEmail(usersCol, "test2@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				ea := core.NewExternalAuth(app)
+				ea.SetCollectionRef(usersCol.Id)
+				ea.SetRecordRef(user.Id)
+				ea.SetProvider(saml.ProviderName)
+				ea.SetProviderId("test_id")
+				if err := app.Save(ea); err != nil {
+					t.Fatal(err)
+				}
+			},
+			Headers:        samlFormHeaders,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"meta":{`,
+				`"isNew":false`,
+				`"nameId":"test_id"`,
+				`"sessionIndex":"test_session"`,
+				`"id":"oap640cot4yru2s"`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                           0,
+				"OnRecordAuthWithSAMLRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordEnrich":              1,
+				// authOrigin track
+				"OnModelCreate":              1,
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				"OnModelValidate":            1,
+				"OnRecordValidate":           1,
+			},
+		},
+		{
+			Name:   "link by email (unverified user) with the auth-with-saml endpoint",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-saml",
+			Body: strings.NewReader(`{"SAMLResponse":"` + newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "test_id",
+				NameIdFormat: saml.NameIDFormatPersistent,
+				InResponseTo: requestId,
+				Attributes: map[string][]string{
+					"email": {"test@example.com"},
+				},
+			}) + `","RelayState":"` + relayState + `"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !user.Verified() {
+					t.Fatal("Expected the user to be marked as verified")
+				}
+
+				if user.ValidatePassword("1234567890") {
+					t.Fatal("Expected the user password to be changed")
+				}
+
+				ea, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{
+					"recordRef":  user.Id,
+					"provider":   saml.ProviderName,
+					"providerId": "test_id",
+				})
+				if err != nil || ea == nil {
+					t.Fatalf("Expected SAML external auth to be created, got %v", err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":false`,
+				`"id":"4q1xlclmfloku33"`,
+				`"verified":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                           0,
+				"OnRecordAuthWithSAMLRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordEnrich":              1,
+				// ---
+				"OnModelCreate":              2, // authOrigins + externalAuths
+				"OnModelCreateExecute":       2,
+				"OnModelAfterCreateSuccess":  2,
+				"OnRecordCreate":             2,
+				"OnRecordCreateExecute":      2,
+				"OnRecordAfterCreateSuccess": 2,
+				// ---
+				"OnModelUpdate":              1, // record password and verified states
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  3,
+				"OnRecordValidate": 3,
+			},
+		},
+		{
+			Name:   "creating user with mapped attributes",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body: samlFormBody(newTestSAMLResponse(t, key, testSAMLAssertion{
+				Id:           "_a1",
+				NameId:       "new@example.com",
+				NameIdFormat: saml.NameIDFormatEmailAddress,
+				Attributes: map[string][]string{
+					"displayName": {"test_name"},
+				},
+			})),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol := enableTestSAML(t, app, cert, map[string]string{"displayName": "name"})
+
+				// fallback to the NameID
+				usersCol.SAML.EmailAttribute = ""
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			Headers:        samlFormHeaders,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"new@example.com"`,
+				`"name":"test_name"`,
+				`"verified":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                           0,
+				"OnRecordAuthWithSAMLRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordCreateRequest":       1,
+				"OnRecordEnrich":              2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  4,
+				"OnRecordValidate": 4,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordSAMLACSSubscriptionRedirect(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestSAMLCertificate(t, key)
+
+	encodedResponse := newTestSAMLResponse(t, key, testSAMLAssertion{
+		Id:           "_a1",
+		NameId:       "test_id",
+		NameIdFormat: saml.NameIDFormatPersistent,
+	})
+
+	checkRedirect := func(expected string) func(testing.TB, *tests.TestApp, *http.Response) {
+		return func(t testing.TB, app *tests.TestApp, res *http.Response) {
+			loc := res.Header.Get("Location")
+			if !strings.HasSuffix(loc, expected) {
+				t.Fatalf("Expected %q redirect, got %q", expected, loc)
+			}
+		}
+	}
+
+	t.Run("missing or unsubscribed client", func(t *testing.T) {
+		client := subscriptions.NewDefaultClient()
+		client.Subscribe("test")
+
+		relayState, _ := newTestSAMLRelayState(t, testSAMLAuthTokenSecret, client.Id(), 1*time.Minute)
+
+		scenario := tests.ApiScenario{
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body:   samlFormBody(encodedResponse, relayState),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+				app.SubscriptionsBroker().Register(client)
+			},
+			Headers:        samlFormHeaders,
+			AfterTestFunc:  checkRedirect("/_/#/auth/oauth2-redirect-failure"),
+			ExpectedStatus: 303,
+			ExpectedEvents: map[string]int{"*": 0},
+		}
+		scenario.Test(t)
+	})
+
+	t.Run("subscribed client", func(t *testing.T) {
+		client := subscriptions.NewDefaultClient()
+		client.Subscribe("@saml")
+
+		relayState, _ := newTestSAMLRelayState(t, testSAMLAuthTokenSecret, client.Id(), 1*time.Minute)
+
+		messages := make(chan subscriptions.Message, 1)
+
+		scenario := tests.ApiScenario{
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/saml/acs",
+			Body:   samlFormBody(encodedResponse, relayState),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableTestSAML(t, app, cert, nil)
+				app.SubscriptionsBroker().Register(client)
+
+				go func() {
+					messages <- <-client.Channel()
+				}()
+			},
+			Headers: samlFormHeaders,
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				checkRedirect("/_/#/auth/oauth2-redirect-success")(t, app, res)
+
+				var msg subscriptions.Message
+				select {
+				case msg = <-messages:
+				case <-time.After(1 * time.Second):
+					t.Fatal("Expected the SAML response to be forwarded to the subscribed client")
+				}
+
+				if msg.Name != "@saml" {
+					t.Fatalf("Expected @saml message, got %q", msg.Name)
+				}
+
+				expectedParts := []string{
+					`"state":"` + client.Id() + `"`,
+					`"SAMLResponse":"` + encodedResponse + `"`,
+					`"RelayState":"` + relayState + `"`,
+				}
+				for _, part := range expectedParts {
+					if !strings.Contains(string(msg.Data), part) {
+						t.Fatalf("Missing %q in\n%s", part, msg.Data)
+					}
+				}
+
+				if client.HasSubscription("@saml") {
+					t.Fatal("Expected the client to be unsubscribed from @saml")
+				}
+			},
+			ExpectedStatus: 303,
+			ExpectedEvents: map[string]int{"*": 0},
+		}
+		scenario.Test(t)
+	})
+}
@@ -61,6 +61,10 @@ func recordsList(e *core.RequestEvent) error {
 
 	query := e.App.RecordQuery(collection)
 
+	if isWithDeletedRequest(requestInfo) {
+		core.WithDeletedRecords(query)
+	}
+
 	fieldsResolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
 
 	if !requestInfo.HasSuperuserAuth() && collection.ListRule != nil && *collection.ListRule != "" {
@@ -167,14 +171,20 @@ func recordView(e *core.RequestEvent) error {
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
-		if !requestInfo.HasSuperuserAuth() && collection.ViewRule != nil && *collection.ViewRule != "" {
+		if isWithDeletedRequest(requestInfo) {
+			core.WithDeletedRecords(q)
+		}
+
+		if !requestInfo.HasSuperuserAuth() && collection.ViewRule != nil {
 			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
-			if err != nil {
-				return err
+			if *collection.ViewRule != "" {
+				expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				q.AndWhere(expr)
 			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
+			resolver.UpdateQuery(q) // attaches the rule joins and the tenant scope (if any)
 		}
 		return nil
 	}
@@ -233,9 +243,9 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return firstApiError(err, e.BadRequestError("Failed to read the submitted data.", err))
 		}
 
-		// set a random password for the OAuth2 ignoring its plain password validators
+		// set a random password for the OAuth2 and SAML sign-ups ignoring its plain password validators
 		var skipPlainPasswordRecordValidators bool
-		if requestInfo.Context == core.RequestInfoContextOAuth2 {
+		if requestInfo.Context == core.RequestInfoContextOAuth2 || requestInfo.Context == core.RequestInfoContextSAML {
 			if _, ok := data[core.FieldNamePassword]; !ok {
 				data[core.FieldNamePassword] = security.RandomString(30)
 				data[core.FieldNamePassword+"Confirm"] = data[core.FieldNamePassword]
@@ -253,6 +263,11 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		}
 		form.Load(data)
 
+		// always assign the requester tenant (if any)
+		if !stampRecordTenant(requestInfo, record) {
+			return e.ForbiddenError("The request is not associated with a tenant.", nil)
+		}
+
 		if skipPlainPasswordRecordValidators {
 			// unset the plain value to skip the plain password field validators
 			if raw, ok := record.GetRaw(core.FieldNamePassword).(*core.PasswordFieldValue); ok {
@@ -428,14 +443,16 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		requestInfo.Body = data
 
 		ruleFunc := func(q *dbx.SelectQuery) error {
-			if !hasSuperuserAuth && collection.UpdateRule != nil && *collection.UpdateRule != "" {
+			if !hasSuperuserAuth && collection.UpdateRule != nil {
 				resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-				expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
-				if err != nil {
-					return err
+				if *collection.UpdateRule != "" {
+					expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+					if err != nil {
+						return err
+					}
+					q.AndWhere(expr)
 				}
-				resolver.UpdateQuery(q)
-				q.AndWhere(expr)
+				resolver.UpdateQuery(q) // attaches the rule joins and the tenant scope (if any)
 			}
 			return nil
 		}
@@ -447,11 +464,17 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		}
 
 		form := forms.NewRecordUpsert(e.App, record)
+		form.SetContext(core.WithActor(e.Request.Context(), requestInfo.Auth))
 		if hasSuperuserAuth {
 			form.GrantSuperuserAccess()
 		}
 		form.Load(data)
 
+		// always assign the requester tenant (if any)
+		if !stampRecordTenant(requestInfo, record) {
+			return e.ForbiddenError("The request is not associated with a tenant.", nil)
+		}
+
 		manageRuleQuery := e.App.ConcurrentDB().Select("(1)").From(collection.Name).AndWhere(dbx.HashExp{
 			collection.Name + ".id": record.Id,
 		})
@@ -544,14 +567,16 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		}
 
 		ruleFunc := func(q *dbx.SelectQuery) error {
-			if !requestInfo.HasSuperuserAuth() && collection.DeleteRule != nil && *collection.DeleteRule != "" {
+			if !requestInfo.HasSuperuserAuth() && collection.DeleteRule != nil {
 				resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-				expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
-				if err != nil {
-					return err
+				if *collection.DeleteRule != "" {
+					expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+					if err != nil {
+						return err
+					}
+					q.AndWhere(expr)
 				}
-				resolver.UpdateQuery(q)
-				q.AndWhere(expr)
+				resolver.UpdateQuery(q) // attaches the rule joins and the tenant scope (if any)
 			}
 			return nil
 		}
@@ -569,7 +594,7 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		event.Record = record
 
 		hookErr := e.App.OnRecordDeleteRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
-			if err := e.App.Delete(e.Record); err != nil {
+			if err := e.App.DeleteWithContext(core.WithActor(e.Request.Context(), e.Auth), e.Record); err != nil {
 				return firstApiError(err, e.BadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err))
 			}
 
@@ -705,6 +730,25 @@ func extractUploadedFiles(re *core.RequestEvent, collection *core.Collection, pr
 	return result, nil
 }
 
+// stampRecordTenant sets the record tenant field to the tenant of the
+// requestInfo auth record (if the record collection is tenant scoped).
+//
+// Returns false if the request is tenant scoped but doesn't have a tenant (eg. guest).
+func stampRecordTenant(requestInfo *core.RequestInfo, record *core.Record) bool {
+	tenant, ok := core.RequestTenant(record.Collection(), requestInfo)
+	if !ok {
+		return true
+	}
+
+	if tenant == "" {
+		return false
+	}
+
+	record.Set(record.Collection().TenantField, tenant)
+
+	return true
+}
+
 // hasAuthManageAccess checks whether the client is allowed to have
 // [forms.RecordUpsert] auth management permissions
 // (e.g. allowing to change system auth fields without oldPassword).
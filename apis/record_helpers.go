@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"golang.org/x/text/language"
 )
 
 const (
@@ -47,7 +49,15 @@ func recordAuthResponse(e *core.RequestEvent, authRecord *core.Record, token str
 		return err
 	}
 
-	ok, err := e.App.CanAccessRecord(authRecord, originalRequestInfo, authRecord.Collection().AuthRule)
+	authRuleRequestInfo := originalRequestInfo
+	if authRecord.Collection().TenantField != "" && originalRequestInfo.Auth == nil {
+		// evaluate the auth rule within the authenticated record tenant
+		// (otherwise the guest request would be denied by the tenant scope)
+		authRuleRequestInfo = originalRequestInfo.Clone()
+		authRuleRequestInfo.Auth = authRecord
+	}
+
+	ok, err := e.App.CanAccessRecord(authRecord, authRuleRequestInfo, authRecord.Collection().AuthRule)
 	if !ok {
 		return firstApiError(err, e.ForbiddenError("The request doesn't satisfy the collection requirements to authenticate.", err))
 	}
@@ -346,6 +356,10 @@ func defaultEnrichRecords(app core.App, requestInfo *core.RequestInfo, records [
 		return fmt.Errorf("failed to resolve records flags: %w", err)
 	}
 
+	if backRels := autoExpandBackRelations(app, records[0].Collection(), requestInfo); len(backRels) > 0 {
+		expands = append(slices.Clip(expands), backRels...)
+	}
+
 	if len(expands) > 0 {
 		expandErrs := app.ExpandRecords(records, expands, expandFetch(app, requestInfo))
 		if len(expandErrs) > 0 {
@@ -360,6 +374,42 @@ func defaultEnrichRecords(app core.App, requestInfo *core.RequestInfo, records [
 	return nil
 }
 
+// autoExpandBackRelations returns the names of the collection back relation
+// fields that should be automatically expanded for the current request.
+//
+// Hidden fields and back relations to superusers only collections are
+// skipped for non-superusers since they will be never expanded.
+func autoExpandBackRelations(app core.App, collection *core.Collection, requestInfo *core.RequestInfo) []string {
+	isSuperuser := requestInfo.HasSuperuserAuth()
+
+	result := []string{}
+
+	for _, field := range collection.Fields {
+		backRelField, ok := field.(*core.BackRelationField)
+		if !ok {
+			continue
+		}
+
+		if isSuperuser {
+			result = append(result, backRelField.Name)
+			continue
+		}
+
+		if backRelField.Hidden {
+			continue
+		}
+
+		backCollection, err := app.FindCachedCollectionByNameOrId(backRelField.CollectionId)
+		if err != nil || backCollection.ViewRule == nil {
+			continue
+		}
+
+		result = append(result, backRelField.Name)
+	}
+
+	return result
+}
+
 // expandFetch is the records fetch function that is used to expand related records.
 func expandFetch(app core.App, originalRequestInfo *core.RequestInfo) core.ExpandFetchFunc {
 	// shallow clone the provided request info to set an "expand" context
@@ -377,15 +427,15 @@ func expandFetch(app core.App, originalRequestInfo *core.RequestInfo) core.Expan
 				return fmt.Errorf("only superusers can view collection %q records", relCollection.Name)
 			}
 
+			resolver := core.NewRecordFieldResolver(app, relCollection, requestInfoPtr, true)
 			if *relCollection.ViewRule != "" {
-				resolver := core.NewRecordFieldResolver(app, relCollection, requestInfoPtr, true)
 				expr, err := search.FilterData(*(relCollection.ViewRule)).BuildExpr(resolver)
 				if err != nil {
 					return err
 				}
-				resolver.UpdateQuery(q)
 				q.AndWhere(expr)
 			}
+			resolver.UpdateQuery(q) // attaches the rule joins and the tenant scope (if any)
 
 			return nil
 		})
@@ -608,8 +658,10 @@ func authAlert(e *core.RequestEvent, authRecord *core.Record) error {
 			mailSent <- errors.New("auth alert mail send wait timeout reached")
 		})
 
+		locales := requestLocales(e.Request)
+
 		routine.FireAndForget(func() {
-			err := mails.SendRecordAuthAlert(e.App, authRecord)
+			err := mails.SendRecordAuthAlert(e.App, authRecord, locales...)
 			timer.Stop()
 			mailSent <- err
 		})
@@ -634,3 +686,26 @@ func authAlert(e *core.RequestEvent, authRecord *core.Record) error {
 	// create/update the origin fingerprint
 	return e.App.Save(currentOrigin)
 }
+
+// requestLocales returns the Accept-Language locales of the request
+// ordered by their preference (used for the localized email templates).
+func requestLocales(r *http.Request) []string {
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return nil
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !tag.IsRoot() {
+			locales = append(locales, tag.String())
+		}
+	}
+
+	return locales
+}
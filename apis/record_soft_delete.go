@@ -0,0 +1,80 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// withDeletedQueryParam is the name of the query parameter that
+// superusers could use to include the soft deleted records
+// in the records list and view responses.
+const withDeletedQueryParam = "withDeleted"
+
+// bindRecordSoftDeleteApi registers the soft deleted records api endpoints
+// (available only for superusers and collections with enabled soft delete).
+func bindRecordSoftDeleteApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}")
+	sub.POST("/restore", recordRestore).Bind(RequireSuperuserAuth())
+	sub.DELETE("/purge", recordPurge).Bind(RequireSuperuserAuth())
+}
+
+func recordRestore(e *core.RequestEvent) error {
+	record, err := findSoftDeletedRecord(e)
+	if err != nil {
+		return err
+	}
+
+	record.SetRaw(core.FieldNameDeleted, types.DateTime{})
+
+	err = e.App.SaveWithContext(core.WithActor(context.Background(), e.Auth), record)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to restore the record.", err))
+	}
+
+	if err := EnrichRecord(e, record); err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+	}
+
+	return e.JSON(http.StatusOK, record)
+}
+
+func recordPurge(e *core.RequestEvent) error {
+	record, err := findSoftDeletedRecord(e)
+	if err != nil {
+		return err
+	}
+
+	// the record is already soft deleted so it will be permanently deleted
+	err = e.App.DeleteWithContext(core.WithActor(context.Background(), e.Auth), record)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to purge the record.", err))
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// findSoftDeletedRecord loads the request soft deleted collection record.
+func findSoftDeletedRecord(e *core.RequestEvent) (*core.Record, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || !collection.SoftDelete.Enabled || collection.IsView() {
+		return nil, e.NotFoundError("Missing collection context or the collection doesn't have soft delete.", err)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"), core.WithDeletedRecords)
+	if err != nil || !record.IsDeleted() {
+		return nil, e.NotFoundError("Missing soft deleted record.", err)
+	}
+
+	return record, nil
+}
+
+// isWithDeletedRequest checks whether the soft deleted records
+// should be included in the current records list or view request.
+func isWithDeletedRequest(requestInfo *core.RequestInfo) bool {
+	return requestInfo.HasSuperuserAuth() && cast.ToBool(requestInfo.Query[withDeletedQueryParam])
+}
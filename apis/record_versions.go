@@ -0,0 +1,321 @@
+package apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// bindRecordVersionsApi registers the record versions api endpoints
+// (available only for collections with enabled versioning).
+func bindRecordVersionsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}/versions")
+	sub.GET("", recordVersionsList)
+	sub.GET("/diff", recordVersionsDiff)
+	sub.POST("/{version}/restore", recordVersionRestore).Bind(dynamicCollectionBodyLimit(""))
+}
+
+var recordVersionFilterFields = []string{
+	"id", "version", "action", "actorCollectionRef", "actorRef", "created",
+}
+
+// recordVersionResponse defines the API representation of a single record version.
+type recordVersionResponse struct {
+	Id                 string         `json:"id"`
+	Version            int            `json:"version"`
+	Action             string         `json:"action"`
+	Data               map[string]any `json:"data"`
+	ActorCollectionRef string         `json:"actorCollectionRef"`
+	ActorRef           string         `json:"actorRef"`
+	Created            types.DateTime `json:"created"`
+}
+
+// recordFieldChange defines a single field change between two record versions.
+type recordFieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+func recordVersionsList(e *core.RequestEvent) error {
+	record, requestInfo, err := findVersionedRecord(e)
+	if err != nil {
+		return err
+	}
+
+	query := e.App.RecordQuery(core.CollectionNameRecordVersions).AndWhere(dbx.HashExp{
+		"collectionRef": record.Collection().Id,
+		"recordRef":     record.Id,
+	})
+
+	provider := search.NewProvider(search.NewSimpleFieldResolver(recordVersionFilterFields...)).Query(query)
+
+	urlQuery := e.Request.URL.Query()
+	if urlQuery.Get(search.SortQueryParam) == "" {
+		provider.AddSort(search.SortField{Name: "version", Direction: search.SortDesc})
+	}
+
+	versions := []*core.Record{}
+
+	result, err := provider.ParseAndExec(urlQuery.Encode(), &versions)
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	items := make([]*recordVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		version := &core.RecordVersion{Record: v}
+
+		data, err := version.FieldsData(e.App, record.Collection())
+		if err != nil {
+			return e.InternalServerError("Failed to load the record version data.", err)
+		}
+
+		items = append(items, &recordVersionResponse{
+			Id:                 version.Id,
+			Version:            version.Version(),
+			Action:             version.Action(),
+			Data:               visibleVersionData(record.Collection(), requestInfo, data),
+			ActorCollectionRef: version.ActorCollectionRef(),
+			ActorRef:           version.ActorRef(),
+			Created:            version.Created(),
+		})
+	}
+	result.Items = items
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func recordVersionsDiff(e *core.RequestEvent) error {
+	record, requestInfo, err := findVersionedRecord(e)
+	if err != nil {
+		return err
+	}
+
+	from, err := strconv.Atoi(e.Request.URL.Query().Get("from"))
+	if err != nil || from <= 0 {
+		return e.BadRequestError("Missing or invalid from version.", err)
+	}
+
+	// if not set, compare with the current record state
+	var to int
+	if raw := e.Request.URL.Query().Get("to"); raw != "" {
+		to, err = strconv.Atoi(raw)
+		if err != nil || to < 0 {
+			return e.BadRequestError("Invalid to version.", err)
+		}
+	}
+
+	fromState, err := core.RecordVersionState(e.App, record, from)
+	if err != nil {
+		return e.NotFoundError("Missing from version.", err)
+	}
+
+	toState, err := core.RecordVersionState(e.App, record, to)
+	if err != nil {
+		return e.NotFoundError("Missing to version.", err)
+	}
+
+	fromState = visibleVersionData(record.Collection(), requestInfo, fromState)
+	toState = visibleVersionData(record.Collection(), requestInfo, toState)
+
+	changes := map[string]*recordFieldChange{}
+	for name, fromValue := range fromState {
+		toValue := toState[name]
+		if !isSameJSONValue(fromValue, toValue) {
+			changes[name] = &recordFieldChange{From: fromValue, To: toValue}
+		}
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"from":    from,
+		"to":      to,
+		"changes": changes,
+	})
+}
+
+func recordVersionRestore(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || !collection.Versioning {
+		return e.NotFoundError("Missing or not versioned collection context.", err)
+	}
+
+	err = checkCollectionRateLimit(e, collection, "update")
+	if err != nil {
+		return err
+	}
+
+	version, err := strconv.Atoi(e.Request.PathValue("version"))
+	if err != nil || version <= 0 {
+		return e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	hasSuperuserAuth := requestInfo.HasSuperuserAuth()
+
+	if !hasSuperuserAuth && collection.UpdateRule == nil {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	// eager fetch the record so that the version state can be resolved
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	data, err := core.RecordVersionState(e.App, record, version)
+	if err != nil {
+		return e.NotFoundError("Missing record version.", err)
+	}
+
+	// the restored data is treated as regular update request body
+	requestInfo.Body = data
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if !hasSuperuserAuth && collection.UpdateRule != nil {
+			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+			if *collection.UpdateRule != "" {
+				expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				q.AndWhere(expr)
+			}
+			resolver.UpdateQuery(q) // attaches the rule joins and the tenant scope (if any)
+		}
+		return nil
+	}
+
+	// refetch with access checks
+	record, err = e.App.FindRecordById(collection, record.Id, ruleFunc)
+	if err != nil {
+		return firstApiError(err, e.NotFoundError("", err))
+	}
+
+	form := forms.NewRecordUpsert(e.App, record)
+	form.SetContext(core.WithActor(context.Background(), requestInfo.Auth))
+	if hasSuperuserAuth {
+		form.GrantSuperuserAccess()
+	}
+	form.Load(data)
+
+	// always assign the requester tenant (if any)
+	if !stampRecordTenant(requestInfo, record) {
+		return e.ForbiddenError("The request is not associated with a tenant.", nil)
+	}
+
+	manageRuleQuery := e.App.ConcurrentDB().Select("(1)").From(collection.Name).AndWhere(dbx.HashExp{
+		collection.Name + ".id": record.Id,
+	})
+	if !form.HasManageAccess() &&
+		hasAuthManageAccess(e.App, requestInfo, collection, manageRuleQuery) {
+		form.GrantManagerAccess()
+	}
+
+	event := new(core.RecordRequestEvent)
+	event.RequestEvent = e
+	event.Collection = collection
+	event.Record = record
+
+	// note: the restore is a regular record update and triggers the same request hook
+	return e.App.OnRecordUpdateRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
+		form.SetApp(e.App)
+		form.SetRecord(e.Record)
+
+		if err := form.Submit(); err != nil {
+			return firstApiError(err, e.BadRequestError("Failed to restore record version.", err))
+		}
+
+		if err := EnrichRecord(e.RequestEvent, e.Record); err != nil {
+			return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+		}
+
+		return execAfterSuccessTx(true, e.App, func() error {
+			return e.JSON(http.StatusOK, e.Record)
+		})
+	})
+}
+
+// findVersionedRecord loads the request versioned collection record
+// applying the collection ViewRule for non-superusers.
+func findVersionedRecord(e *core.RequestEvent) (*core.Record, *core.RequestInfo, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || !collection.Versioning {
+		return nil, nil, e.NotFoundError("Missing or not versioned collection context.", err)
+	}
+
+	err = checkCollectionRateLimit(e, collection, "view")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return nil, nil, firstApiError(err, e.BadRequestError("", err))
+	}
+
+	rule := collection.ViewRule
+
+	if rule == nil && !requestInfo.HasSuperuserAuth() {
+		return nil, nil, e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if !requestInfo.HasSuperuserAuth() && rule != nil {
+			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+			if *rule != "" {
+				expr, err := search.FilterData(*rule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				q.AndWhere(expr)
+			}
+			resolver.UpdateQuery(q) // attaches the rule joins and the tenant scope (if any)
+		}
+		return nil
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"), ruleFunc)
+	if err != nil || record == nil {
+		return nil, nil, e.NotFoundError("", err)
+	}
+
+	return record, requestInfo, nil
+}
+
+// visibleVersionData returns a copy of the version data without
+// the hidden collection fields (unless requested by a superuser).
+func visibleVersionData(collection *core.Collection, requestInfo *core.RequestInfo, data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+
+	for name, value := range data {
+		field := collection.Fields.GetByName(name)
+		if field != nil && field.GetHidden() && !requestInfo.HasSuperuserAuth() {
+			continue
+		}
+
+		result[name] = value
+	}
+
+	return result
+}
+
+// isSameJSONValue checks whether a and b have the same JSON serialization.
+func isSameJSONValue(a any, b any) bool {
+	aRaw, errA := json.Marshal(a)
+	bRaw, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(aRaw, bRaw)
+}
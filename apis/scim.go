@@ -0,0 +1,925 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/scim"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/spf13/cast"
+)
+
+const (
+	scimDefaultCount = 100
+	scimMaxCount     = 500
+)
+
+// bindSCIMApi registers the SCIM 2.0 provisioning api endpoints.
+//
+// The endpoints are available only if enabled from the app settings and
+// are authorized with the superuser issued static bearer token.
+func bindSCIMApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/scim/v2").BindFunc(requireSCIMToken)
+
+	sub.GET("/ServiceProviderConfig", scimServiceProviderConfig)
+
+	sub.GET("/Users", scimUsersList)
+	sub.POST("/Users", scimUserCreate)
+	sub.GET("/Users/{id}", scimUserView)
+	sub.PUT("/Users/{id}", scimUserReplace)
+	sub.PATCH("/Users/{id}", scimUserPatch)
+	sub.DELETE("/Users/{id}", scimResourceDelete(false))
+
+	sub.GET("/Groups", scimGroupsList)
+	sub.POST("/Groups", scimGroupCreate)
+	sub.GET("/Groups/{id}", scimGroupView)
+	sub.PUT("/Groups/{id}", scimGroupReplace)
+	sub.PATCH("/Groups/{id}", scimGroupPatch)
+	sub.DELETE("/Groups/{id}", scimResourceDelete(true))
+}
+
+// scimError writes a SCIM formatted error response and returns
+// a regular ApiError so that the request could be properly logged.
+func scimError(e *core.RequestEvent, status int, scimType string, detail string, err error) error {
+	e.Response.Header().Set("Content-Type", scim.ContentType)
+
+	writeErr := e.JSON(status, scim.Error{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+
+	return router.NewApiError(status, detail, err)
+}
+
+func scimJSON(e *core.RequestEvent, status int, data any) error {
+	e.Response.Header().Set("Content-Type", scim.ContentType)
+
+	return e.JSON(status, data)
+}
+
+func requireSCIMToken(e *core.RequestEvent) error {
+	config := e.App.Settings().SCIM
+
+	if !config.Enabled {
+		return scimError(e, http.StatusNotFound, "", "SCIM provisioning is not enabled.", nil)
+	}
+
+	token, ok := strings.CutPrefix(e.Request.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || config.TokenHash == "" || !security.Equal(security.SHA256(token), config.TokenHash) {
+		return scimError(e, http.StatusUnauthorized, "", "Missing or invalid SCIM bearer token.", nil)
+	}
+
+	return e.Next()
+}
+
+func scimServiceProviderConfig(e *core.RequestEvent) error {
+	return scimJSON(e, http.StatusOK, map[string]any{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          map[string]any{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": scimMaxCount},
+		"changePassword": map[string]any{"supported": true},
+		"sort":           map[string]any{"supported": false},
+		"etag":           map[string]any{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Superuser issued static bearer token.",
+			"primary":     true,
+		}},
+	})
+}
+
+// -------------------------------------------------------------------
+
+// scimContext holds the resolved SCIM settings and mapped collections.
+type scimContext struct {
+	users  *core.Collection
+	groups *core.Collection
+	config core.SCIMConfig
+}
+
+func newSCIMContext(e *core.RequestEvent, requireGroups bool) (*scimContext, error) {
+	sc := &scimContext{config: e.App.Settings().SCIM}
+
+	if sc.config.UserNameField == "" {
+		sc.config.UserNameField = core.FieldNameEmail
+	}
+
+	var err error
+
+	sc.users, err = e.App.FindCachedCollectionByNameOrId(sc.config.UsersCollection)
+	if err != nil || !sc.users.IsAuth() {
+		return nil, scimError(e, http.StatusInternalServerError, "", "Missing or invalid SCIM users collection.", err)
+	}
+
+	if requireGroups {
+		if sc.config.GroupsCollection == "" {
+			return nil, scimError(e, http.StatusNotFound, "", "SCIM groups provisioning is not configured.", nil)
+		}
+
+		sc.groups, err = e.App.FindCachedCollectionByNameOrId(sc.config.GroupsCollection)
+		if err != nil {
+			return nil, scimError(e, http.StatusInternalServerError, "", "Missing or invalid SCIM groups collection.", err)
+		}
+	}
+
+	return sc, nil
+}
+
+func (sc *scimContext) location(e *core.RequestEvent, resourceType string, id string) string {
+	return strings.TrimRight(e.App.Settings().Meta.AppURL, "/") + "/api/scim/v2/" + resourceType + "/" + id
+}
+
+func (sc *scimContext) meta(e *core.RequestEvent, resourceType string, record *core.Record) scim.Meta {
+	meta := scim.Meta{
+		ResourceType: strings.TrimSuffix(resourceType, "s"),
+		Location:     sc.location(e, resourceType, record.Id),
+	}
+
+	if created := record.GetDateTime("created"); !created.IsZero() {
+		meta.Created = created.Time().UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	if updated := record.GetDateTime("updated"); !updated.IsZero() {
+		meta.LastModified = updated.Time().UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	return meta
+}
+
+// list executes a SCIM list query for the specified collection.
+func (sc *scimContext) list(
+	e *core.RequestEvent,
+	collection *core.Collection,
+	resolveAttr func(attr string) (string, error),
+	toResource func(record *core.Record) (map[string]any, error),
+) error {
+	query := e.Request.URL.Query()
+
+	startIndex := 1
+	if v := query.Get("startIndex"); v != "" {
+		startIndex, _ = strconv.Atoi(v)
+		startIndex = max(startIndex, 1)
+	}
+
+	count := scimDefaultCount
+	if v := query.Get("count"); v != "" {
+		count, _ = strconv.Atoi(v)
+		count = min(max(count, 0), scimMaxCount)
+	}
+
+	var filterExpr dbx.Expression
+	if rawFilter := query.Get("filter"); rawFilter != "" {
+		expr, err := scim.ParseFilter(rawFilter)
+		if err != nil {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidFilter, err.Error(), err)
+		}
+
+		filter, params, err := expr.Build(resolveAttr)
+		if err != nil {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidFilter, err.Error(), err)
+		}
+
+		resolver := core.NewRecordFieldResolver(e.App, collection, nil, true)
+		filterExpr, err = search.FilterData(filter).BuildExpr(resolver, params)
+		if err != nil {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidFilter, "Failed to build the filter expression.", err)
+		}
+	}
+
+	total, err := e.App.CountRecords(collection, filterExpr)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to count the resources.", err)
+	}
+
+	records := []*core.Record{}
+	if count > 0 {
+		q := e.App.RecordQuery(collection).
+			OrderBy("rowid ASC").
+			Offset(int64(startIndex - 1)).
+			Limit(int64(count))
+		if filterExpr != nil {
+			q.AndWhere(filterExpr)
+		}
+
+		if err := q.All(&records); err != nil {
+			return scimError(e, http.StatusInternalServerError, "", "Failed to fetch the resources.", err)
+		}
+	}
+
+	resources := make([]any, 0, len(records))
+	for _, record := range records {
+		resource, err := toResource(record)
+		if err != nil {
+			return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+		}
+		resources = append(resources, resource)
+	}
+
+	return scimJSON(e, http.StatusOK, scim.NewListResponse(resources, int(total), startIndex))
+}
+
+// save persists the record and maps the save errors to SCIM errors.
+func (sc *scimContext) save(e *core.RequestEvent, record *core.Record) error {
+	err := e.App.Save(record)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		for name, fieldErr := range validationErrs {
+			var vErr validation.Error
+			if errors.As(fieldErr, &vErr) && vErr.Code() == "validation_not_unique" {
+				return scimError(e, http.StatusConflict, scim.ErrorTypeUniqueness, "The "+name+" value is already in use.", err)
+			}
+		}
+
+		return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidValue, err.Error(), err)
+	}
+
+	return scimError(e, http.StatusInternalServerError, "", "Failed to save the resource.", err)
+}
+
+func findSCIMRecord(e *core.RequestEvent, collection *core.Collection) (*core.Record, error) {
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, scimError(e, http.StatusNotFound, "", "Resource not found.", err)
+		}
+		return nil, scimError(e, http.StatusInternalServerError, "", "Failed to load the resource.", err)
+	}
+
+	return record, nil
+}
+
+func scimReadBody(e *core.RequestEvent) (map[string]any, error) {
+	body := map[string]any{}
+
+	if err := e.BindBody(&body); err != nil {
+		return nil, scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid request body.", err)
+	}
+
+	return body, nil
+}
+
+// scimNormalizeBody lowercases the root and nested object keys
+// since the SCIM attribute names are case-insensitive.
+func scimNormalizeBody(body map[string]any) map[string]any {
+	normalized := make(map[string]any, len(body))
+
+	for k, v := range body {
+		if nested, ok := v.(map[string]any); ok {
+			v = scimNormalizeBody(nested)
+		}
+		normalized[strings.ToLower(k)] = v
+	}
+
+	return normalized
+}
+
+func scimResourceDelete(groups bool) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		sc, err := newSCIMContext(e, groups)
+		if err != nil {
+			return err
+		}
+
+		collection := sc.users
+		if groups {
+			collection = sc.groups
+		}
+
+		record, err := findSCIMRecord(e, collection)
+		if err != nil {
+			return err
+		}
+
+		if err := e.App.Delete(record); err != nil {
+			return scimError(e, http.StatusBadRequest, "", "Failed to delete the resource.", err)
+		}
+
+		return e.NoContent(http.StatusNoContent)
+	}
+}
+
+// scimPatch applies the PATCH request operations by calling setAttr
+// for each attribute change (value is nil for the remove operations).
+func scimPatch(e *core.RequestEvent, setAttr func(op string, path *scim.Path, value any) error) error {
+	req := &scim.PatchRequest{}
+	if err := e.BindBody(req); err != nil {
+		return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid request body.", err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, err.Error(), err)
+	}
+
+	for _, op := range req.Operations {
+		// no path -> the value is an object with the attributes to modify
+		if op.Path == "" {
+			values, ok := op.Value.(map[string]any)
+			if !ok {
+				return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The operation value must be an object when path is not set.", nil)
+			}
+
+			// apply the attributes in deterministic order so that
+			// the parent attributes are set before their sub-attributes
+			// (eg. "name" before "name.formatted")
+			keys := slices.SortedFunc(maps.Keys(values), func(a, b string) int {
+				if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+					return c
+				}
+				return strings.Compare(a, b)
+			})
+
+			for _, k := range keys {
+				path, err := scim.ParsePath(k)
+				if err != nil {
+					return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidPath, err.Error(), err)
+				}
+
+				if err := setAttr(op.Op, path, values[k]); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		path, err := scim.ParsePath(op.Path)
+		if err != nil {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidPath, err.Error(), err)
+		}
+
+		value := op.Value
+		if op.Op == scim.PatchOpRemove && path.Attr != "members" {
+			value = nil
+		}
+
+		if err := setAttr(op.Op, path, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+// Users
+// -------------------------------------------------------------------
+
+func (sc *scimContext) resolveUserAttr(attr string) (string, error) {
+	var field string
+
+	switch attr {
+	case "id":
+		field = "id"
+	case "username":
+		field = sc.config.UserNameField
+	case "emails", "emails.value":
+		field = core.FieldNameEmail
+	case "externalid":
+		field = sc.config.ExternalIdField
+	case "displayname", "name.formatted":
+		field = sc.config.DisplayNameField
+	case "active":
+		field = sc.config.ActiveField
+	case "meta.created":
+		field = "created"
+	case "meta.lastmodified":
+		field = "updated"
+	}
+
+	if field == "" || (field != "id" && sc.users.Fields.GetByName(field) == nil) {
+		return "", errors.New("unsupported filter attribute " + attr)
+	}
+
+	return field, nil
+}
+
+func (sc *scimContext) userResource(e *core.RequestEvent, record *core.Record) (map[string]any, error) {
+	resource := map[string]any{
+		"schemas":  []string{scim.SchemaUser},
+		"id":       record.Id,
+		"userName": record.GetString(sc.config.UserNameField),
+		"active":   true,
+		"meta":     sc.meta(e, "Users", record),
+	}
+
+	if email := record.Email(); email != "" {
+		resource["emails"] = []map[string]any{{"value": email, "type": "work", "primary": true}}
+	}
+
+	if sc.config.ExternalIdField != "" {
+		if v := record.GetString(sc.config.ExternalIdField); v != "" {
+			resource["externalId"] = v
+		}
+	}
+
+	if sc.config.DisplayNameField != "" {
+		if v := record.GetString(sc.config.DisplayNameField); v != "" {
+			resource["displayName"] = v
+			resource["name"] = map[string]any{"formatted": v}
+		}
+	}
+
+	if sc.config.ActiveField != "" {
+		resource["active"] = record.GetBool(sc.config.ActiveField)
+	}
+
+	return resource, nil
+}
+
+// setUserAttr assigns a single normalized SCIM attribute value to the user record.
+func (sc *scimContext) setUserAttr(e *core.RequestEvent, record *core.Record, path *scim.Path, value any) error {
+	attr := path.FullAttr()
+
+	switch {
+	case attr == "username":
+		record.Set(sc.config.UserNameField, cast.ToString(value))
+	case attr == "password":
+		if value == nil {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeMutability, "The password cannot be removed.", nil)
+		}
+		record.SetPassword(cast.ToString(value))
+	case attr == "externalid":
+		if sc.config.ExternalIdField != "" {
+			record.Set(sc.config.ExternalIdField, cast.ToString(value))
+		}
+	case attr == "displayname" || attr == "name.formatted":
+		if sc.config.DisplayNameField != "" {
+			record.Set(sc.config.DisplayNameField, cast.ToString(value))
+		}
+	case attr == "name":
+		name, _ := value.(map[string]any)
+		name = scimNormalizeBody(name)
+		formatted := cast.ToString(name["formatted"])
+		if formatted == "" {
+			formatted = strings.TrimSpace(cast.ToString(name["givenname"]) + " " + cast.ToString(name["familyname"]))
+		}
+		if sc.config.DisplayNameField != "" && formatted != "" {
+			record.Set(sc.config.DisplayNameField, formatted)
+		}
+	case attr == "active":
+		active, err := cast.ToBoolE(value)
+		if err != nil {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidValue, "Invalid active value.", err)
+		}
+		if sc.config.ActiveField != "" {
+			record.Set(sc.config.ActiveField, active)
+		} else if !active {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeMutability, "Users deactivation is not supported (missing active field mapping).", nil)
+		}
+	case path.Attr == "emails":
+		email := scimPrimaryValue(value)
+		if path.SubAttr == "value" {
+			email = cast.ToString(value)
+		}
+		record.SetEmail(email)
+	default:
+		// silently ignore the unmapped attributes (eg. locale, phoneNumbers, etc.)
+	}
+
+	return nil
+}
+
+// scimPrimaryValue returns the "value" of the primary (or the first)
+// item from a SCIM multi-valued attribute (eg. emails).
+func scimPrimaryValue(value any) string {
+	items, _ := value.([]any)
+
+	var result string
+	for i, item := range items {
+		obj, _ := item.(map[string]any)
+		if obj == nil {
+			continue
+		}
+		if i == 0 || cast.ToBool(obj["primary"]) {
+			result = cast.ToString(obj["value"])
+		}
+		if cast.ToBool(obj["primary"]) {
+			break
+		}
+	}
+
+	return result
+}
+
+// loadUser populates the user record with the full SCIM resource body (aka. POST and PUT).
+func (sc *scimContext) loadUser(e *core.RequestEvent, record *core.Record, body map[string]any) error {
+	body = scimNormalizeBody(body)
+
+	userName := cast.ToString(body["username"])
+	if userName == "" {
+		return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The userName attribute is required.", nil)
+	}
+
+	// note: iterate in a predefined order so that the more specific
+	// attributes (eg. displayName) take precedence over the generic ones (eg. name)
+	for _, attr := range []string{"username", "emails", "externalid", "name", "displayname", "active", "password"} {
+		value, ok := body[attr]
+		if !ok {
+			continue
+		}
+
+		if err := sc.setUserAttr(e, record, &scim.Path{Attr: attr}, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scimUsersList(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, false)
+	if err != nil {
+		return err
+	}
+
+	return sc.list(e, sc.users, sc.resolveUserAttr, func(record *core.Record) (map[string]any, error) {
+		return sc.userResource(e, record)
+	})
+}
+
+func scimUserView(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, false)
+	if err != nil {
+		return err
+	}
+
+	record, err := findSCIMRecord(e, sc.users)
+	if err != nil {
+		return err
+	}
+
+	resource, err := sc.userResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	return scimJSON(e, http.StatusOK, resource)
+}
+
+func scimUserCreate(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, false)
+	if err != nil {
+		return err
+	}
+
+	body, err := scimReadBody(e)
+	if err != nil {
+		return err
+	}
+
+	record := core.NewRecord(sc.users)
+	record.SetRandomPassword()
+
+	// the IdP is trusted to have already verified its users email
+	record.SetVerified(true)
+
+	if err := sc.loadUser(e, record, body); err != nil {
+		return err
+	}
+
+	if err := sc.save(e, record); err != nil {
+		return err
+	}
+
+	resource, err := sc.userResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	e.Response.Header().Set("Location", sc.location(e, "Users", record.Id))
+
+	return scimJSON(e, http.StatusCreated, resource)
+}
+
+func scimUserReplace(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, false)
+	if err != nil {
+		return err
+	}
+
+	record, err := findSCIMRecord(e, sc.users)
+	if err != nil {
+		return err
+	}
+
+	body, err := scimReadBody(e)
+	if err != nil {
+		return err
+	}
+
+	if err := sc.loadUser(e, record, body); err != nil {
+		return err
+	}
+
+	if err := sc.save(e, record); err != nil {
+		return err
+	}
+
+	resource, err := sc.userResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	return scimJSON(e, http.StatusOK, resource)
+}
+
+func scimUserPatch(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, false)
+	if err != nil {
+		return err
+	}
+
+	record, err := findSCIMRecord(e, sc.users)
+	if err != nil {
+		return err
+	}
+
+	err = scimPatch(e, func(op string, path *scim.Path, value any) error {
+		return sc.setUserAttr(e, record, path, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sc.save(e, record); err != nil {
+		return err
+	}
+
+	resource, err := sc.userResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	return scimJSON(e, http.StatusOK, resource)
+}
+
+// -------------------------------------------------------------------
+// Groups
+// -------------------------------------------------------------------
+
+func (sc *scimContext) resolveGroupAttr(attr string) (string, error) {
+	switch attr {
+	case "id":
+		return "id", nil
+	case "displayname":
+		return sc.config.GroupsNameField, nil
+	case "meta.created":
+		if sc.groups.Fields.GetByName("created") != nil {
+			return "created", nil
+		}
+	case "meta.lastmodified":
+		if sc.groups.Fields.GetByName("updated") != nil {
+			return "updated", nil
+		}
+	}
+
+	return "", errors.New("unsupported filter attribute " + attr)
+}
+
+func (sc *scimContext) groupResource(e *core.RequestEvent, record *core.Record) (map[string]any, error) {
+	memberIds := record.GetStringSlice(sc.config.GroupsMembersField)
+
+	members := make([]map[string]any, 0, len(memberIds))
+	for _, id := range memberIds {
+		members = append(members, map[string]any{
+			"value": id,
+			"$ref":  sc.location(e, "Users", id),
+		})
+	}
+
+	return map[string]any{
+		"schemas":     []string{scim.SchemaGroup},
+		"id":          record.Id,
+		"displayName": record.GetString(sc.config.GroupsNameField),
+		"members":     members,
+		"meta":        sc.meta(e, "Groups", record),
+	}, nil
+}
+
+// scimMemberIds extracts the member ids from a SCIM members value.
+func scimMemberIds(value any) []string {
+	items, _ := value.([]any)
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		obj, _ := item.(map[string]any)
+		if id := cast.ToString(obj["value"]); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// setGroupAttr applies a single group attribute operation.
+func (sc *scimContext) setGroupAttr(e *core.RequestEvent, record *core.Record, op string, path *scim.Path, value any) error {
+	switch path.Attr {
+	case "displayname":
+		if op == scim.PatchOpRemove {
+			return scimError(e, http.StatusBadRequest, scim.ErrorTypeMutability, "The displayName cannot be removed.", nil)
+		}
+		record.Set(sc.config.GroupsNameField, cast.ToString(value))
+	case "members":
+		current := record.GetStringSlice(sc.config.GroupsMembersField)
+
+		switch op {
+		case scim.PatchOpReplace:
+			record.Set(sc.config.GroupsMembersField, scimMemberIds(value))
+		case scim.PatchOpAdd:
+			record.Set(sc.config.GroupsMembersField, list.ToUniqueStringSlice(append(current, scimMemberIds(value)...)))
+		case scim.PatchOpRemove:
+			var remaining []string
+
+			switch {
+			case path.Filter != nil:
+				// eg. members[value eq "abc"]
+				for _, id := range current {
+					matched := path.Filter.Match(func(attr string) any {
+						if attr == "members.value" {
+							return id
+						}
+						return nil
+					})
+					if !matched {
+						remaining = append(remaining, id)
+					}
+				}
+			case value != nil:
+				// eg. {"path": "members", "value": [{"value": "abc"}]}
+				toRemove := scimMemberIds(value)
+				for _, id := range current {
+					if !slices.Contains(toRemove, id) {
+						remaining = append(remaining, id)
+					}
+				}
+			default:
+				// remove all members
+			}
+
+			record.Set(sc.config.GroupsMembersField, remaining)
+		}
+	default:
+		// silently ignore the unmapped attributes (eg. externalId)
+	}
+
+	return nil
+}
+
+func (sc *scimContext) loadGroup(e *core.RequestEvent, record *core.Record, body map[string]any) error {
+	body = scimNormalizeBody(body)
+
+	name := cast.ToString(body["displayname"])
+	if name == "" {
+		return scimError(e, http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The displayName attribute is required.", nil)
+	}
+
+	record.Set(sc.config.GroupsNameField, name)
+	record.Set(sc.config.GroupsMembersField, scimMemberIds(body["members"]))
+
+	return nil
+}
+
+func scimGroupsList(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, true)
+	if err != nil {
+		return err
+	}
+
+	return sc.list(e, sc.groups, sc.resolveGroupAttr, func(record *core.Record) (map[string]any, error) {
+		return sc.groupResource(e, record)
+	})
+}
+
+func scimGroupView(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, true)
+	if err != nil {
+		return err
+	}
+
+	record, err := findSCIMRecord(e, sc.groups)
+	if err != nil {
+		return err
+	}
+
+	resource, err := sc.groupResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	return scimJSON(e, http.StatusOK, resource)
+}
+
+func scimGroupCreate(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, true)
+	if err != nil {
+		return err
+	}
+
+	body, err := scimReadBody(e)
+	if err != nil {
+		return err
+	}
+
+	record := core.NewRecord(sc.groups)
+
+	if err := sc.loadGroup(e, record, body); err != nil {
+		return err
+	}
+
+	if err := sc.save(e, record); err != nil {
+		return err
+	}
+
+	resource, err := sc.groupResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	e.Response.Header().Set("Location", sc.location(e, "Groups", record.Id))
+
+	return scimJSON(e, http.StatusCreated, resource)
+}
+
+func scimGroupReplace(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, true)
+	if err != nil {
+		return err
+	}
+
+	record, err := findSCIMRecord(e, sc.groups)
+	if err != nil {
+		return err
+	}
+
+	body, err := scimReadBody(e)
+	if err != nil {
+		return err
+	}
+
+	if err := sc.loadGroup(e, record, body); err != nil {
+		return err
+	}
+
+	if err := sc.save(e, record); err != nil {
+		return err
+	}
+
+	resource, err := sc.groupResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	return scimJSON(e, http.StatusOK, resource)
+}
+
+func scimGroupPatch(e *core.RequestEvent) error {
+	sc, err := newSCIMContext(e, true)
+	if err != nil {
+		return err
+	}
+
+	record, err := findSCIMRecord(e, sc.groups)
+	if err != nil {
+		return err
+	}
+
+	err = scimPatch(e, func(op string, path *scim.Path, value any) error {
+		return sc.setGroupAttr(e, record, op, path, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sc.save(e, record); err != nil {
+		return err
+	}
+
+	resource, err := sc.groupResource(e, record)
+	if err != nil {
+		return scimError(e, http.StatusInternalServerError, "", "Failed to serialize the resource.", err)
+	}
+
+	return scimJSON(e, http.StatusOK, resource)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/webpush"
 )
 
 // bindSettingsApi registers the settings api endpoints.
@@ -17,6 +19,8 @@ func bindSettingsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
 	subGroup.POST("/test/s3", settingsTestS3)
 	subGroup.POST("/test/email", settingsTestEmail)
 	subGroup.POST("/apple/generate-client-secret", settingsGenerateAppleClientSecret)
+	subGroup.POST("/scim/token", settingsGenerateSCIMToken)
+	subGroup.POST("/webpush/keys", settingsGenerateVAPIDKeys)
 }
 
 func settingsList(e *core.RequestEvent) error {
@@ -141,3 +145,53 @@ func settingsGenerateAppleClientSecret(e *core.RequestEvent) error {
 		"secret": secret,
 	})
 }
+
+// settingsGenerateSCIMToken generates and stores a new SCIM bearer token.
+//
+// Only the token hash is persisted in the settings so the plain token
+// is returned just once and any previously generated token is invalidated.
+func settingsGenerateSCIMToken(e *core.RequestEvent) error {
+	settings, err := e.App.Settings().Clone()
+	if err != nil {
+		return e.InternalServerError("Failed to clone app settings.", err)
+	}
+
+	token := security.RandomString(50)
+
+	settings.SCIM.TokenHash = security.SHA256(token)
+
+	if err := e.App.Save(settings); err != nil {
+		return e.BadRequestError("An error occurred while saving the new SCIM token.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]string{
+		"token": token,
+	})
+}
+
+// settingsGenerateVAPIDKeys generates and stores a new Web Push VAPID key pair.
+//
+// Note that the existing push subscriptions are bound to the previous
+// public key and the clients will have to subscribe again.
+func settingsGenerateVAPIDKeys(e *core.RequestEvent) error {
+	settings, err := e.App.Settings().Clone()
+	if err != nil {
+		return e.InternalServerError("Failed to clone app settings.", err)
+	}
+
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		return e.InternalServerError("Failed to generate VAPID keys.", err)
+	}
+
+	settings.WebPush.PublicKey = publicKey
+	settings.WebPush.PrivateKey = privateKey
+
+	if err := e.App.Save(settings); err != nil {
+		return e.BadRequestError("An error occurred while saving the new VAPID keys.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]string{
+		"publicKey": publicKey,
+	})
+}
@@ -0,0 +1,51 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindWebhooksApi registers the webhook delivery logs api endpoints.
+//
+// The webhooks itself are managed through the regular records api of the _webhooks collection.
+func bindWebhooksApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/webhooks/logs").Bind(RequireSuperuserAuth(), SkipSuccessActivityLog())
+	sub.GET("", webhookLogsList)
+	sub.GET("/{id}", webhookLogsView)
+}
+
+var webhookLogFilterFields = []string{
+	"id", "created", "webhookRef", "deliveryId", "event", "collection",
+	"recordRef", "url", "status", "error", "attempt", "duration",
+}
+
+func webhookLogsList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(webhookLogFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.AuxModelQuery(&core.WebhookLog{})).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.WebhookLog{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func webhookLogsView(e *core.RequestEvent) error {
+	id := e.Request.PathValue("id")
+	if id == "" {
+		return e.NotFoundError("", nil)
+	}
+
+	log, err := e.App.FindWebhookLogById(id)
+	if err != nil || log == nil {
+		return e.NotFoundError("", err)
+	}
+
+	return e.JSON(http.StatusOK, log)
+}
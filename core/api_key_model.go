@@ -0,0 +1,272 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const CollectionNameAPIKeys = "_apiKeys"
+
+// APIKeyPrefix is the prefix of all generated plain API keys
+// (it is mostly for easier identification, eg. by secret scanners).
+const APIKeyPrefix = "pbk_"
+
+// apiKeyScopeRegex defines the allowed API key scope characters (eg. "posts:write").
+var apiKeyScopeRegex = regexp.MustCompile(`^[\w\-\.\:\*]+$`)
+
+var (
+	_ Model        = (*APIKey)(nil)
+	_ PreValidator = (*APIKey)(nil)
+	_ RecordProxy  = (*APIKey)(nil)
+)
+
+// APIKey defines a Record proxy for working with the apiKeys collection.
+type APIKey struct {
+	*Record
+}
+
+// NewAPIKey instantiates and returns a new blank *APIKey model.
+//
+// Example usage:
+//
+//	apiKey := core.NewAPIKey(app)
+//	apiKey.SetRecordRef(user.Id)
+//	apiKey.SetCollectionRef(user.Collection().Id)
+//	apiKey.SetName("ci")
+//	apiKey.SetScopes([]string{"posts:write"})
+//	plainKey := apiKey.RefreshKey()
+//	app.Save(apiKey)
+func NewAPIKey(app App) *APIKey {
+	m := &APIKey{}
+
+	c, err := app.FindCachedCollectionByNameOrId(CollectionNameAPIKeys)
+	if err != nil {
+		// this is just to make tests easier since apiKeys is a system collection and it is expected to be always accessible
+		// (note: the loaded record is further checked on APIKey.PreValidate())
+		c = NewBaseCollection("@__invalid__")
+	}
+
+	m.Record = NewRecord(c)
+
+	return m
+}
+
+// PreValidate implements the [PreValidator] interface and checks
+// whether the proxy is properly loaded.
+func (m *APIKey) PreValidate(ctx context.Context, app App) error {
+	if m.Record == nil || m.Record.Collection().Name != CollectionNameAPIKeys {
+		return errors.New("missing or invalid APIKey ProxyRecord")
+	}
+
+	return nil
+}
+
+// ProxyRecord returns the proxied Record model.
+func (m *APIKey) ProxyRecord() *Record {
+	return m.Record
+}
+
+// SetProxyRecord loads the specified record model into the current proxy.
+func (m *APIKey) SetProxyRecord(record *Record) {
+	m.Record = record
+}
+
+// CollectionRef returns the "collectionRef" field value.
+func (m *APIKey) CollectionRef() string {
+	return m.GetString("collectionRef")
+}
+
+// SetCollectionRef updates the "collectionRef" record field value.
+func (m *APIKey) SetCollectionRef(collectionId string) {
+	m.Set("collectionRef", collectionId)
+}
+
+// RecordRef returns the "recordRef" record field value.
+func (m *APIKey) RecordRef() string {
+	return m.GetString("recordRef")
+}
+
+// SetRecordRef updates the "recordRef" record field value.
+func (m *APIKey) SetRecordRef(recordId string) {
+	m.Set("recordRef", recordId)
+}
+
+// Name returns the "name" record field value.
+func (m *APIKey) Name() string {
+	return m.GetString("name")
+}
+
+// SetName updates the "name" record field value.
+func (m *APIKey) SetName(name string) {
+	m.Set("name", name)
+}
+
+// KeyHash returns the "keyHash" record field value.
+func (m *APIKey) KeyHash() string {
+	return m.GetString("keyHash")
+}
+
+// SetKey hashes and stores the provided plain key in the "keyHash" record field.
+func (m *APIKey) SetKey(plainKey string) {
+	m.Set("keyHash", security.SHA256(plainKey))
+}
+
+// RefreshKey generates and sets a new random API key and returns its plain value.
+//
+// Note that only the key hash is persisted so the returned
+// plain key value cannot be retrieved later.
+func (m *APIKey) RefreshKey() string {
+	plainKey := APIKeyPrefix + security.RandomString(40)
+
+	m.SetKey(plainKey)
+
+	return plainKey
+}
+
+// Scopes returns the "scopes" record field value.
+func (m *APIKey) Scopes() []string {
+	return m.GetStringSlice("scopes")
+}
+
+// SetScopes updates the "scopes" record field value.
+func (m *APIKey) SetScopes(scopes []string) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	m.Set("scopes", scopes)
+}
+
+// HasScope checks whether the API key has the specified scope.
+func (m *APIKey) HasScope(scope string) bool {
+	return slices.Contains(m.Scopes(), scope)
+}
+
+// Expires returns the "expires" record field value.
+func (m *APIKey) Expires() types.DateTime {
+	return m.GetDateTime("expires")
+}
+
+// SetExpires updates the "expires" record field value.
+func (m *APIKey) SetExpires(date types.DateTime) {
+	m.Set("expires", date)
+}
+
+// HasExpired checks whether the API key has an expiration date that has already passed.
+func (m *APIKey) HasExpired() bool {
+	expires := m.Expires()
+
+	return !expires.IsZero() && expires.Time().Before(time.Now())
+}
+
+// LastUsed returns the "lastUsed" record field value.
+func (m *APIKey) LastUsed() types.DateTime {
+	return m.GetDateTime("lastUsed")
+}
+
+// SetLastUsed updates the "lastUsed" record field value.
+func (m *APIKey) SetLastUsed(date types.DateTime) {
+	m.Set("lastUsed", date)
+}
+
+// Created returns the "created" record field value.
+func (m *APIKey) Created() types.DateTime {
+	return m.GetDateTime("created")
+}
+
+// Updated returns the "updated" record field value.
+func (m *APIKey) Updated() types.DateTime {
+	return m.GetDateTime("updated")
+}
+
+func (app *BaseApp) registerAPIKeyHooks() {
+	recordRefHooks[*APIKey](app, CollectionNameAPIKeys, CollectionTypeAuth)
+
+	app.OnRecordValidate(CollectionNameAPIKeys).Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			// superusers bypass all collection rules so scoped keys wouldn't have any effect
+			collectionRef := e.Record.GetString("collectionRef")
+			if collectionRef != "" {
+				c, _ := e.App.FindCachedCollectionByNameOrId(collectionRef)
+				if c != nil && c.Name == CollectionNameSuperusers {
+					return validation.Errors{"collectionRef": validation.NewError(
+						"validation_superusers_api_keys_not_allowed",
+						"API keys are not allowed for superusers.",
+					)}
+				}
+			}
+
+			var scopes []string
+			if raw, _ := e.Record.Get("scopes").(types.JSONRaw); len(raw) > 0 && string(raw) != "null" {
+				if err := json.Unmarshal(raw, &scopes); err != nil {
+					return validation.Errors{"scopes": validation.NewError(
+						"validation_invalid_api_key_scopes",
+						"Scopes must be an array of strings.",
+					)}
+				}
+			}
+
+			for _, scope := range scopes {
+				if !apiKeyScopeRegex.MatchString(scope) {
+					return validation.Errors{"scopes": validation.NewError(
+						"validation_invalid_api_key_scope",
+						"Invalid scope {{.scope}}.",
+					).SetParams(map[string]any{"scope": scope})}
+				}
+			}
+
+			return e.Next()
+		},
+		Priority: 99,
+	})
+
+	// generate a new key for the records created through the records api
+	// and return its plain value as part of the response
+	app.OnRecordCreateRequest(CollectionNameAPIKeys).Bind(&hook.Handler[*RecordRequestEvent]{
+		Func: func(e *RecordRequestEvent) error {
+			if e.HasAPIKeyAuth() {
+				return e.ForbiddenError("API keys can't be created with an API key authorization.", nil)
+			}
+
+			apiKey := &APIKey{Record: e.Record}
+			plainKey := apiKey.RefreshKey()
+
+			e.Record.WithCustomData(true)
+			e.Record.Set("key", plainKey)
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnRecordUpdateRequest(CollectionNameAPIKeys).Bind(&hook.Handler[*RecordRequestEvent]{
+		Func: func(e *RecordRequestEvent) error {
+			if e.HasAPIKeyAuth() {
+				return e.ForbiddenError("API keys can't be updated with an API key authorization.", nil)
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnRecordDeleteRequest(CollectionNameAPIKeys).Bind(&hook.Handler[*RecordRequestEvent]{
+		Func: func(e *RecordRequestEvent) error {
+			if e.HasAPIKeyAuth() {
+				return e.ForbiddenError("API keys can't be deleted with an API key authorization.", nil)
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+}
@@ -0,0 +1,384 @@
+package core_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := core.NewAPIKey(app)
+
+	if apiKey.Collection().Name != core.CollectionNameAPIKeys {
+		t.Fatalf("Expected record with %q collection, got %q", core.CollectionNameAPIKeys, apiKey.Collection().Name)
+	}
+}
+
+func TestAPIKeyProxyRecord(t *testing.T) {
+	t.Parallel()
+
+	record := core.NewRecord(core.NewBaseCollection("test"))
+	record.Id = "test_id"
+
+	apiKey := core.APIKey{}
+	apiKey.SetProxyRecord(record)
+
+	if apiKey.ProxyRecord() == nil || apiKey.ProxyRecord().Id != record.Id {
+		t.Fatalf("Expected proxy record with id %q, got %v", record.Id, apiKey.ProxyRecord())
+	}
+}
+
+func TestAPIKeyStringFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := core.NewAPIKey(app)
+
+	scenarios := []struct {
+		field  string
+		setter func(string)
+		getter func() string
+	}{
+		{"recordRef", apiKey.SetRecordRef, apiKey.RecordRef},
+		{"collectionRef", apiKey.SetCollectionRef, apiKey.CollectionRef},
+		{"name", apiKey.SetName, apiKey.Name},
+	}
+
+	for _, s := range scenarios {
+		for i, testValue := range []string{"test_1", "test2", ""} {
+			t.Run(fmt.Sprintf("%s_%d_%q", s.field, i, testValue), func(t *testing.T) {
+				s.setter(testValue)
+
+				if v := s.getter(); v != testValue {
+					t.Fatalf("Expected getter %q, got %q", testValue, v)
+				}
+
+				if v := apiKey.GetString(s.field); v != testValue {
+					t.Fatalf("Expected field value %q, got %q", testValue, v)
+				}
+			})
+		}
+	}
+}
+
+func TestAPIKeySetKeyAndRefreshKey(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := core.NewAPIKey(app)
+
+	apiKey.SetKey("test")
+	if v := apiKey.KeyHash(); v != security.SHA256("test") {
+		t.Fatalf("Expected the key hash to be stored, got %q", v)
+	}
+
+	plainKey := apiKey.RefreshKey()
+
+	if !strings.HasPrefix(plainKey, core.APIKeyPrefix) || len(plainKey) != len(core.APIKeyPrefix)+40 {
+		t.Fatalf("Invalid plain key %q", plainKey)
+	}
+
+	if v := apiKey.KeyHash(); v != security.SHA256(plainKey) {
+		t.Fatalf("Expected the refreshed key hash to be stored, got %q", v)
+	}
+
+	if plainKey2 := apiKey.RefreshKey(); plainKey2 == plainKey {
+		t.Fatal("Expected a different plain key to be generated")
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := core.NewAPIKey(app)
+
+	if v := apiKey.Scopes(); len(v) != 0 {
+		t.Fatalf("Expected no scopes, got %v", v)
+	}
+
+	apiKey.SetScopes(nil)
+	if v := apiKey.GetRaw("scopes"); v == nil {
+		t.Fatal("Expected nil scopes to be normalized to an empty slice")
+	}
+
+	apiKey.SetScopes([]string{"posts:read", "posts:write"})
+
+	if v := apiKey.Scopes(); !slices.Equal(v, []string{"posts:read", "posts:write"}) {
+		t.Fatalf("Expected the set scopes, got %v", v)
+	}
+
+	if !apiKey.HasScope("posts:write") {
+		t.Fatal("Expected HasScope(posts:write) to be true")
+	}
+
+	if apiKey.HasScope("posts") {
+		t.Fatal("Expected HasScope(posts) to be false")
+	}
+}
+
+func TestAPIKeyExpires(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := core.NewAPIKey(app)
+
+	if !apiKey.Expires().IsZero() || apiKey.HasExpired() {
+		t.Fatal("Expected a non-expiring key by default")
+	}
+
+	past, _ := types.ParseDateTime(time.Now().Add(-1 * time.Minute))
+	apiKey.SetExpires(past)
+
+	if v := apiKey.Expires().String(); v != past.String() {
+		t.Fatalf("Expected expires %q, got %q", past.String(), v)
+	}
+
+	if !apiKey.HasExpired() {
+		t.Fatal("Expected the key to have expired")
+	}
+
+	future, _ := types.ParseDateTime(time.Now().Add(1 * time.Minute))
+	apiKey.SetExpires(future)
+
+	if apiKey.HasExpired() {
+		t.Fatal("Expected the key to not have expired")
+	}
+}
+
+func TestAPIKeyDateFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := core.NewAPIKey(app)
+
+	scenarios := []struct {
+		field  string
+		getter func() types.DateTime
+	}{
+		{"lastUsed", apiKey.LastUsed},
+		{"created", apiKey.Created},
+		{"updated", apiKey.Updated},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.field, func(t *testing.T) {
+			if v := s.getter().String(); v != "" {
+				t.Fatalf("Expected empty %s, got %q", s.field, v)
+			}
+
+			now := types.NowDateTime()
+			apiKey.SetRaw(s.field, now)
+
+			if v := s.getter().String(); v != now.String() {
+				t.Fatalf("Expected %q %s, got %q", now.String(), s.field, v)
+			}
+		})
+	}
+
+	now := types.NowDateTime()
+	apiKey.SetLastUsed(now)
+	if v := apiKey.LastUsed().String(); v != now.String() {
+		t.Fatalf("Expected %q lastUsed, got %q", now.String(), v)
+	}
+}
+
+func TestAPIKeyPreValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKeysCol, err := app.FindCollectionByNameOrId(core.CollectionNameAPIKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no proxy record", func(t *testing.T) {
+		apiKey := &core.APIKey{}
+
+		if err := app.Validate(apiKey); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("non-APIKey collection", func(t *testing.T) {
+		apiKey := &core.APIKey{}
+		apiKey.SetProxyRecord(core.NewRecord(core.NewBaseCollection("invalid")))
+		apiKey.SetRecordRef(user.Id)
+		apiKey.SetCollectionRef(user.Collection().Id)
+		apiKey.RefreshKey()
+
+		if err := app.Validate(apiKey); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("APIKey collection", func(t *testing.T) {
+		apiKey := &core.APIKey{}
+		apiKey.SetProxyRecord(core.NewRecord(apiKeysCol))
+		apiKey.SetRecordRef(user.Id)
+		apiKey.SetCollectionRef(user.Collection().Id)
+		apiKey.RefreshKey()
+
+		if err := app.Validate(apiKey); err != nil {
+			t.Fatalf("Expected nil validation error, got %v", err)
+		}
+	})
+}
+
+func TestAPIKeyValidateHook(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		apiKey       func() *core.APIKey
+		expectErrors []string
+	}{
+		{
+			"empty",
+			func() *core.APIKey {
+				return core.NewAPIKey(app)
+			},
+			[]string{"collectionRef", "recordRef", "keyHash"},
+		},
+		{
+			"non-auth collection",
+			func() *core.APIKey {
+				apiKey := core.NewAPIKey(app)
+				apiKey.SetCollectionRef(demo1.Collection().Id)
+				apiKey.SetRecordRef(demo1.Id)
+				apiKey.RefreshKey()
+				return apiKey
+			},
+			[]string{"collectionRef"},
+		},
+		{
+			"superusers collection",
+			func() *core.APIKey {
+				apiKey := core.NewAPIKey(app)
+				apiKey.SetCollectionRef(superuser.Collection().Id)
+				apiKey.SetRecordRef(superuser.Id)
+				apiKey.RefreshKey()
+				return apiKey
+			},
+			[]string{"collectionRef"},
+		},
+		{
+			"invalid scopes type",
+			func() *core.APIKey {
+				apiKey := core.NewAPIKey(app)
+				apiKey.SetCollectionRef(user.Collection().Id)
+				apiKey.SetRecordRef(user.Id)
+				apiKey.Set("scopes", map[string]any{"a": 1})
+				apiKey.RefreshKey()
+				return apiKey
+			},
+			[]string{"scopes"},
+		},
+		{
+			"invalid scope characters",
+			func() *core.APIKey {
+				apiKey := core.NewAPIKey(app)
+				apiKey.SetCollectionRef(user.Collection().Id)
+				apiKey.SetRecordRef(user.Id)
+				apiKey.SetScopes([]string{"posts:write", "posts write"})
+				apiKey.RefreshKey()
+				return apiKey
+			},
+			[]string{"scopes"},
+		},
+		{
+			"valid key",
+			func() *core.APIKey {
+				apiKey := core.NewAPIKey(app)
+				apiKey.SetCollectionRef(user.Collection().Id)
+				apiKey.SetRecordRef(user.Id)
+				apiKey.SetScopes([]string{"posts:write", "posts.*", "admin-read"})
+				apiKey.RefreshKey()
+				return apiKey
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := app.Validate(s.apiKey())
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestAPIKeyRecordRefDeletion(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey := core.NewAPIKey(app)
+	apiKey.SetCollectionRef(user.Collection().Id)
+	apiKey.SetRecordRef(user.Id)
+	apiKey.RefreshKey()
+	if err := app.Save(apiKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindAPIKeyById(apiKey.Id); err == nil {
+		t.Fatal("Expected the API key to be deleted together with its auth record")
+	}
+}
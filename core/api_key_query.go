@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// FindAllAPIKeysByRecord returns all APIKey models linked to the provided auth record (in DESC order).
+func (app *BaseApp) FindAllAPIKeysByRecord(authRecord *Record) ([]*APIKey, error) {
+	result := []*APIKey{}
+
+	err := app.RecordQuery(CollectionNameAPIKeys).
+		AndWhere(dbx.HashExp{
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+		}).
+		OrderBy("created DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAPIKeyById returns a single APIKey model by its id.
+func (app *BaseApp) FindAPIKeyById(id string) (*APIKey, error) {
+	result := &APIKey{}
+
+	err := app.RecordQuery(CollectionNameAPIKeys).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAuthRecordByAPIKey returns the non-expired APIKey model matching
+// the provided plain key together with its linked auth record.
+func (app *BaseApp) FindAuthRecordByAPIKey(plainKey string) (*Record, *APIKey, error) {
+	if plainKey == "" {
+		return nil, nil, errors.New("missing API key")
+	}
+
+	apiKey := &APIKey{}
+
+	err := app.RecordQuery(CollectionNameAPIKeys).
+		AndWhere(dbx.HashExp{"keyHash": security.SHA256(plainKey)}).
+		Limit(1).
+		One(apiKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if apiKey.HasExpired() {
+		return nil, nil, errors.New("the API key has expired")
+	}
+
+	record, err := app.FindRecordById(apiKey.CollectionRef(), apiKey.RecordRef())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !record.Collection().IsAuth() {
+		return nil, nil, errors.New("the API key is not linked to an auth record")
+	}
+
+	return record, apiKey, nil
+}
+
+// DeleteAllAPIKeysByRecord deletes all APIKey models associated with the provided record.
+//
+// Returns a combined error with the failed deletes.
+func (app *BaseApp) DeleteAllAPIKeysByRecord(authRecord *Record) error {
+	models, err := app.FindAllAPIKeysByRecord(authRecord)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, m := range models {
+		if err := app.Delete(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
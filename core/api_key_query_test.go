@@ -0,0 +1,192 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// createTestAPIKey creates and persists a new API key for the provided
+// auth record and returns it together with its plain key value.
+func createTestAPIKey(t testing.TB, app core.App, authRecord *core.Record, expires time.Duration) (*core.APIKey, string) {
+	apiKey := core.NewAPIKey(app)
+	apiKey.SetCollectionRef(authRecord.Collection().Id)
+	apiKey.SetRecordRef(authRecord.Id)
+	apiKey.SetScopes([]string{"test"})
+	plainKey := apiKey.RefreshKey()
+
+	if expires != 0 {
+		date, _ := types.ParseDateTime(time.Now().Add(expires))
+		apiKey.SetExpires(date)
+	}
+
+	if err := app.Save(apiKey); err != nil {
+		t.Fatal(err)
+	}
+
+	return apiKey, plainKey
+}
+
+func TestFindAllAPIKeysByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key1, _ := createTestAPIKey(t, app, user1, 0)
+	key2, _ := createTestAPIKey(t, app, user1, 0)
+	createTestAPIKey(t, app, user2, 0)
+
+	result, err := app.FindAllAPIKeysByRecord(user1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 API keys, got %d", len(result))
+	}
+
+	for _, apiKey := range result {
+		if apiKey.Id != key1.Id && apiKey.Id != key2.Id {
+			t.Fatalf("Unexpected API key %q", apiKey.Id)
+		}
+	}
+}
+
+func TestFindAPIKeyById(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey, _ := createTestAPIKey(t, app, user, 0)
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{apiKey.Id, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.id, func(t *testing.T) {
+			result, err := app.FindAPIKeyById(s.id)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v", s.expectError, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if result.Id != s.id {
+				t.Fatalf("Expected API key %q, got %q", s.id, result.Id)
+			}
+		})
+	}
+}
+
+func TestFindAuthRecordByAPIKey(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validKey, validPlainKey := createTestAPIKey(t, app, user, 1*time.Hour)
+	_, expiredPlainKey := createTestAPIKey(t, app, user, -1*time.Hour)
+
+	scenarios := []struct {
+		name        string
+		plainKey    string
+		expectError bool
+	}{
+		{"empty key", "", true},
+		{"missing key", "pbk_missing", true},
+		{"key hash", validKey.KeyHash(), true},
+		{"expired key", expiredPlainKey, true},
+		{"valid key", validPlainKey, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			record, apiKey, err := app.FindAuthRecordByAPIKey(s.plainKey)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v", s.expectError, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if record.Id != user.Id {
+				t.Fatalf("Expected auth record %q, got %q", user.Id, record.Id)
+			}
+
+			if apiKey.Id != validKey.Id {
+				t.Fatalf("Expected API key %q, got %q", validKey.Id, apiKey.Id)
+			}
+		})
+	}
+}
+
+func TestDeleteAllAPIKeysByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createTestAPIKey(t, app, user1, 0)
+	createTestAPIKey(t, app, user1, 0)
+	createTestAPIKey(t, app, user2, 0)
+
+	if err := app.DeleteAllAPIKeysByRecord(user1); err != nil {
+		t.Fatal(err)
+	}
+
+	if keys, _ := app.FindAllAPIKeysByRecord(user1); len(keys) != 0 {
+		t.Fatalf("Expected all user1 API keys to be deleted, got %d", len(keys))
+	}
+
+	if keys, _ := app.FindAllAPIKeysByRecord(user2); len(keys) != 1 {
+		t.Fatalf("Expected the user2 API key to remain, got %d", len(keys))
+	}
+}
@@ -85,10 +85,24 @@ type App interface {
 	// SubscriptionsBroker returns the app realtime subscriptions broker instance.
 	SubscriptionsBroker() *subscriptions.Broker
 
-	// NewMailClient creates and returns a new SMTP or Sendmail client
+	// NewMailClient creates and returns a new HTTP-API, SMTP or Sendmail client
 	// based on the current app settings.
+	//
+	// If the MailQueue setting is enabled, the returned client enqueues
+	// the messages in the persistent mail queue instead of sending them inline.
 	NewMailClient() mailer.Mailer
 
+	// ReceiveMail triggers the OnMailReceived hook for the provided
+	// inbound message (usually parsed with [mailer.ParseMessage]).
+	ReceiveMail(message *mailer.Message) (*Record, error)
+
+	// SendPush encrypts and delivers the payload as Web Push notification
+	// to all push subscriptions of the provided auth record.
+	//
+	// Subscriptions for which the push service responded with 404 or 410
+	// are considered expired and are automatically deleted.
+	SendPush(authRecord *Record, payload []byte) error
+
 	// NewFilesystem creates a new local or S3 filesystem instance
 	// for managing regular app files (ex. record uploads)
 	// based on the current app settings.
@@ -371,6 +385,72 @@ type App interface {
 
 	// ---------------------------------------------------------------
 
+	// EnqueueMail stores the provided message in the persistent mail queue
+	// for later delivery by the mail queue worker.
+	EnqueueMail(message *mailer.Message) error
+
+	// ProcessMailQueue delivers all queued mails that are due for
+	// a delivery attempt using the app SMTP or Sendmail client.
+	//
+	// Successfully delivered mails are removed from the queue.
+	// Failed mails are rescheduled with exponential backoff until
+	// the MailQueue.MaxAttempts setting is reached.
+	// Each delivery attempt is recorded in the mail log.
+	ProcessMailQueue() error
+
+	// RegisterJob registers (or replaces) the handler of the named background job.
+	RegisterJob(name string, handler JobHandler)
+
+	// EnqueueJob stores a new job with the provided payload in the persistent job queue
+	// for later processing by the job queue workers.
+	//
+	// If opts.UniqueKey is set and there is already a pending or running job
+	// with the same key, no new job is created and the existing one is returned.
+	EnqueueJob(name string, payload any, opts *JobOptions) (*Job, error)
+
+	// RetryJob reschedules the provided pending or dead job to run
+	// as soon as possible with reset attempts counter.
+	RetryJob(job *Job) error
+
+	// CancelJob removes the provided job from the job queue.
+	//
+	// If the job is currently running, its handler context is also canceled
+	// and the handler result is discarded.
+	CancelJob(job *Job) error
+
+	// ProcessJobs runs all queued jobs that are due using a pool of
+	// Jobs.Concurrency workers and returns once there are no more due jobs.
+	//
+	// Failed jobs are rescheduled with exponential backoff until their
+	// MaxAttempts is reached, after which they are moved to the dead-letter state.
+	ProcessJobs() error
+
+	// FindJobById finds a single background job by its id.
+	FindJobById(id string) (*Job, error)
+
+	// FindCronRunById finds a single CronRun entry by its id.
+	FindCronRunById(id string) (*CronRun, error)
+
+	// FindLatestCronRun finds the most recently started run of the specified cron job.
+	FindLatestCronRun(jobId string) (*CronRun, error)
+
+	// DeleteOldCronRuns deletes all cron run entries that are created before createdBefore.
+	DeleteOldCronRuns(createdBefore time.Time) error
+
+	// FindMailLogById finds a single MailLog entry by its id.
+	FindMailLogById(id string) (*MailLog, error)
+
+	// DeleteOldMailLogs deletes all mail log entries that are created before createdBefore.
+	DeleteOldMailLogs(createdBefore time.Time) error
+
+	// FindWebhookLogById finds a single WebhookLog entry by its id.
+	FindWebhookLogById(id string) (*WebhookLog, error)
+
+	// DeleteOldWebhookLogs deletes all webhook log entries that are created before createdBefore.
+	DeleteOldWebhookLogs(createdBefore time.Time) error
+
+	// ---------------------------------------------------------------
+
 	// CollectionQuery returns a new Collection select query.
 	CollectionQuery() *dbx.SelectQuery
 
@@ -550,11 +630,102 @@ type App interface {
 
 	// ---------------------------------------------------------------
 
+	// FindAllAPIKeysByRecord returns all APIKey models linked to the provided auth record (in DESC order).
+	FindAllAPIKeysByRecord(authRecord *Record) ([]*APIKey, error)
+
+	// FindAPIKeyById returns a single APIKey model by its id.
+	FindAPIKeyById(id string) (*APIKey, error)
+
+	// FindAuthRecordByAPIKey returns the non-expired APIKey model matching
+	// the provided plain key together with its linked auth record.
+	FindAuthRecordByAPIKey(plainKey string) (*Record, *APIKey, error)
+
+	// DeleteAllAPIKeysByRecord deletes all APIKey models associated with the provided record.
+	//
+	// Returns a combined error with the failed deletes.
+	DeleteAllAPIKeysByRecord(authRecord *Record) error
+
+	// ---------------------------------------------------------------
+
+	// FindAllPushSubscriptionsByRecord returns all PushSubscription models linked to the provided auth record (in DESC order).
+	FindAllPushSubscriptionsByRecord(authRecord *Record) ([]*PushSubscription, error)
+
+	// FindPushSubscriptionById returns a single PushSubscription model by its id.
+	FindPushSubscriptionById(id string) (*PushSubscription, error)
+
+	// DeleteAllPushSubscriptionsByRecord deletes all PushSubscription models associated with the provided record.
+	//
+	// Returns a combined error with the failed deletes.
+	DeleteAllPushSubscriptionsByRecord(authRecord *Record) error
+
+	// ---------------------------------------------------------------
+
+	// FindAllPasswordHistoryByRecord returns all PasswordHistory models linked
+	// to the provided auth record and password field name (in DESC order).
+	FindAllPasswordHistoryByRecord(authRecord *Record, fieldName string) ([]*PasswordHistory, error)
+
+	// HasPasswordExpired checks whether the password of the provided
+	// auth record is older than the password field MaxAge option.
+	//
+	// Records without a tracked password change (eg. created before
+	// enabling the MaxAge option) are not considered expired.
+	HasPasswordExpired(authRecord *Record) (bool, error)
+
+	// DeleteAllPasswordHistoryByRecord deletes all PasswordHistory models associated with the provided record.
+	//
+	// Returns a combined error with the failed deletes.
+	DeleteAllPasswordHistoryByRecord(authRecord *Record) error
+
+	// ---------------------------------------------------------------
+
+	// FindAllRecordVersions returns all RecordVersion models
+	// linked to the provided record (in DESC version order).
+	FindAllRecordVersions(record *Record) ([]*RecordVersion, error)
+
+	// FindRecordVersion returns a single RecordVersion model
+	// linked to the provided record by its version number.
+	FindRecordVersion(record *Record, version int) (*RecordVersion, error)
+
+	// DeleteAllRecordVersionsByCollection deletes all RecordVersion models
+	// associated with the provided collection.
+	//
+	// For better performance the delete is executed as plain SQL statement,
+	// aka. no delete model hook events will be fired.
+	DeleteAllRecordVersionsByCollection(collection *Collection) error
+
+	// FindAuthLockoutByRecord returns the AuthLockout model associated with the provided auth record.
+	FindAuthLockoutByRecord(authRecord *Record) (*AuthLockout, error)
+
+	// FindAuthLockout returns the AuthLockout model associated with the
+	// provided collection id and lockout ref (the auth record id or
+	// the result of AuthLockoutIdentityRef for unknown identities).
+	FindAuthLockout(collectionId string, ref string) (*AuthLockout, error)
+
+	// IncrementAuthLockoutFailures atomically increments the failed auth
+	// attempts counter of the provided collection id and lockout ref
+	// (creating the AuthLockout entry if missing) and returns the updated model.
+	IncrementAuthLockoutFailures(collectionId string, ref string) (*AuthLockout, error)
+
+	// ApplyAuthLockout persists the lockout state of the provided AuthLockout model
+	// only if the stored lockout is not already active.
+	//
+	// Returns true if the lockout was applied.
+	ApplyAuthLockout(lockout *AuthLockout) (bool, error)
+
+	// DeleteAuthLockoutsByRecord deletes all AuthLockout models associated
+	// with the provided auth record (aka. unlocks the record and resets its failed auth attempts).
+	DeleteAuthLockoutsByRecord(authRecord *Record) error
+
+	// ---------------------------------------------------------------
+
 	// RecordQuery returns a new Record select query from a collection model, id or name.
 	//
 	// In case a collection id or name is provided and that collection doesn't
 	// actually exists, the generated query will be created with a cancelled context
 	// and will fail once an executor (Row(), One(), All(), etc.) is called.
+	//
+	// For collections with enabled soft delete, the soft deleted records are
+	// excluded from the query unless [WithDeletedRecords] is applied to it.
 	RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuery
 
 	// FindRecordById finds the Record model by its id.
@@ -639,6 +810,14 @@ type App interface {
 	// CountRecords returns the total number of records in a collection.
 	CountRecords(collectionModelOrIdentifier any, exprs ...dbx.Expression) (int64, error)
 
+	// CountRequestRecords is similar to CountRecords but counts only the
+	// collection records of the requestInfo tenant (if the collection is tenant scoped).
+	CountRequestRecords(requestInfo *RequestInfo, collectionModelOrIdentifier any, exprs ...dbx.Expression) (int64, error)
+
+	// PurgeExpiredDeletedRecords permanently deletes the soft deleted records
+	// (including their files) that are older than their collection soft delete retention.
+	PurgeExpiredDeletedRecords() error
+
 	// FindAuthRecordByToken finds the auth record associated with the provided JWT
 	// (auth, file, verifyEmail, changeEmail, passwordReset types).
 	//
@@ -1200,6 +1379,14 @@ type App interface {
 	// It allows intercepting the email message or to use a custom mailer client.
 	OnMailerSend() *hook.Hook[*MailerEvent]
 
+	// OnMailReceived hook is triggered every time when a new inbound
+	// email is received (ex. via the inbound mail webhook endpoint or [App.ReceiveMail]).
+	//
+	// It allows routing the message to a custom collection. By default,
+	// if InboundMail.Collection setting is set, a new record is created
+	// from the message when e.Next() is called.
+	OnMailReceived() *hook.Hook[*MailReceivedEvent]
+
 	// OnMailerRecordAuthAlertSend hook is triggered when
 	// sending a new device login auth alert email, allowing you to
 	// intercept and customize the email message that is being sent.
@@ -1340,6 +1527,21 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*RecordAuthWithOAuth2RequestEvent]
 
+	// OnRecordAuthWithSAMLRequest hook is triggered on each Record
+	// SAML sign-in/sign-up API request (after the assertion verification
+	// and before the external auth linking).
+	//
+	// If [RecordAuthWithSAMLRequestEvent.Record] is not set, then the SAML
+	// request will try to create a new auth Record.
+	//
+	// To assign or link a different existing record model you can
+	// change the [RecordAuthWithSAMLRequestEvent.Record] field.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAuthWithSAMLRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithSAMLRequestEvent]
+
 	// OnRecordAuthRefreshRequest hook is triggered on each Record
 	// auth refresh API request (right before generating a new auth token).
 	//
@@ -1436,6 +1638,19 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPRequestEvent]
 
+	// OnRecordAuthLockout hook is triggered when an auth record reaches
+	// the configured number of consecutive failed password or OTP
+	// auth attempts and it is about to be temporary locked.
+	//
+	// Could be used to adjust or prevent the lockout (by not calling e.Next()),
+	// or to implement a different alert behavior
+	// (by default an email alert is sent if the collection AuthAlert option is enabled).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAuthLockout(tags ...string) *hook.TaggedHook[*RecordAuthLockoutEvent]
+
 	// ---------------------------------------------------------------
 	// Record CRUD API event hooks
 	// ---------------------------------------------------------------
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	_ Model = (*AuthLockout)(nil)
+)
+
+const AuthLockoutsTableName = "_authLockouts"
+
+// AuthLockout defines the failed auth attempts state of a single auth record.
+//
+// The lockouts are stored in the auxiliary database to avoid
+// locking the main one on each failed auth attempt.
+type AuthLockout struct {
+	BaseModel
+
+	CollectionRef string `db:"collectionRef" json:"collectionRef"`
+	RecordRef     string `db:"recordRef" json:"recordRef"`
+
+	// Failures is the number of the consecutive failed auth attempts since the last lockout.
+	Failures int `db:"failures" json:"failures"`
+
+	// Lockouts is the number of the consecutive lockouts since the last successful auth.
+	Lockouts int `db:"lockouts" json:"lockouts"`
+
+	LockedUntil types.DateTime `db:"lockedUntil" json:"lockedUntil"`
+	Created     types.DateTime `db:"created" json:"created"`
+	Updated     types.DateTime `db:"updated" json:"updated"`
+}
+
+// NewAuthLockout instantiates and returns a new blank *AuthLockout model
+// associated with the provided auth record.
+func NewAuthLockout(authRecord *Record) *AuthLockout {
+	now := types.NowDateTime()
+
+	m := &AuthLockout{
+		CollectionRef: authRecord.Collection().Id,
+		RecordRef:     authRecord.Id,
+		Created:       now,
+		Updated:       now,
+	}
+	m.Id = GenerateDefaultRandomId()
+
+	return m
+}
+
+func (m *AuthLockout) TableName() string {
+	return AuthLockoutsTableName
+}
+
+// IsLocked reports whether the lockout is still active.
+func (m *AuthLockout) IsLocked() bool {
+	return !m.LockedUntil.IsZero() && m.LockedUntil.Time().After(time.Now())
+}
+
+// RegisterFailure increments the failed auth attempts counter and
+// locks the auth record once the config LockoutThreshold is reached.
+//
+// Returns true if the registered failure resulted in a new lockout.
+//
+// Note that the changes are not persisted
+// (see also [App.IncrementAuthLockoutFailures] for the atomic db counterpart).
+func (m *AuthLockout) RegisterFailure(config PasswordAuthConfig) bool {
+	m.Failures++
+	m.Updated = types.NowDateTime()
+
+	return m.Lock(config)
+}
+
+// Lock locks the auth record (resetting the failures counter) if the
+// config LockoutThreshold is reached.
+//
+// Returns true if the lockout was applied.
+//
+// Note that the changes are not persisted.
+func (m *AuthLockout) Lock(config PasswordAuthConfig) bool {
+	if config.LockoutThreshold <= 0 || m.Failures < config.LockoutThreshold {
+		return false
+	}
+
+	m.Updated = types.NowDateTime()
+	m.LockedUntil = m.Updated.Add(config.LockoutDurationTime(m.Lockouts))
+	m.Failures = 0
+	m.Lockouts++
+
+	return true
+}
+
+// AuthLockoutIdentityRef returns the AuthLockout ref used to track
+// the failed auth attempts of an identity that doesn't match any auth record.
+//
+// This allows nonexistent identities to be locked the same way as the
+// existing ones so that the lockout response can't be used for accounts enumeration.
+func AuthLockoutIdentityRef(identity string) string {
+	return "@identity_" + security.SHA256(strings.ToLower(strings.TrimSpace(identity)))
+}
+
+func (app *BaseApp) registerAuthLockoutHooks() {
+	// delete the lockouts of the deleted auth records
+	// (the auxiliary db tables don't have foreign keys to the main db)
+	app.OnRecordAfterDeleteSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbAuthLockoutsCleanup__",
+		Func: func(e *RecordEvent) error {
+			if e.Record.Collection().IsAuth() {
+				if err := e.App.DeleteAuthLockoutsByRecord(e.Record); err != nil {
+					e.App.Logger().Warn(
+						"Failed to delete the auth record lockouts",
+						"error", err,
+						"recordId", e.Record.Id,
+						"collectionId", e.Record.Collection().Id,
+					)
+				}
+			}
+
+			return e.Next()
+		},
+		Priority: 99,
+	})
+}
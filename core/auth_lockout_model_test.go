@@ -0,0 +1,153 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewAuthLockout(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockout := core.NewAuthLockout(user)
+
+	if lockout.Id == "" {
+		t.Fatal("Expected non-empty id")
+	}
+
+	if !lockout.IsNew() {
+		t.Fatal("Expected new model")
+	}
+
+	if lockout.TableName() != core.AuthLockoutsTableName {
+		t.Fatalf("Expected table %q, got %q", core.AuthLockoutsTableName, lockout.TableName())
+	}
+
+	if lockout.CollectionRef != user.Collection().Id {
+		t.Fatalf("Expected collectionRef %q, got %q", user.Collection().Id, lockout.CollectionRef)
+	}
+
+	if lockout.RecordRef != user.Id {
+		t.Fatalf("Expected recordRef %q, got %q", user.Id, lockout.RecordRef)
+	}
+
+	if lockout.Created.IsZero() || lockout.Updated.IsZero() {
+		t.Fatal("Expected non-zero created and updated dates")
+	}
+}
+
+func TestAuthLockoutIsLocked(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		lockedUntil types.DateTime
+		expected    bool
+	}{
+		{"zero lockedUntil", types.DateTime{}, false},
+		{"past lockedUntil", types.NowDateTime().Add(-1 * time.Minute), false},
+		{"future lockedUntil", types.NowDateTime().Add(1 * time.Minute), true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			lockout := &core.AuthLockout{LockedUntil: s.lockedUntil}
+
+			if v := lockout.IsLocked(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestAuthLockoutRegisterFailure(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled lockout", func(t *testing.T) {
+		lockout := &core.AuthLockout{}
+
+		for i := 0; i < 10; i++ {
+			if lockout.RegisterFailure(core.PasswordAuthConfig{}) {
+				t.Fatal("Expected no lockout")
+			}
+		}
+
+		if lockout.Failures != 10 {
+			t.Fatalf("Expected %d failures, got %d", 10, lockout.Failures)
+		}
+
+		if lockout.IsLocked() {
+			t.Fatal("Expected unlocked state")
+		}
+	})
+
+	t.Run("progressive lockout", func(t *testing.T) {
+		config := core.PasswordAuthConfig{
+			LockoutThreshold:   3,
+			LockoutDuration:    60,
+			LockoutMaxDuration: 600,
+		}
+
+		lockout := &core.AuthLockout{}
+
+		for _, expectedDuration := range []time.Duration{60 * time.Second, 120 * time.Second} {
+			for i := 1; i < config.LockoutThreshold; i++ {
+				if lockout.RegisterFailure(config) {
+					t.Fatalf("Expected no lockout after %d failures", i)
+				}
+			}
+
+			if !lockout.RegisterFailure(config) {
+				t.Fatal("Expected new lockout")
+			}
+
+			if !lockout.IsLocked() {
+				t.Fatal("Expected locked state")
+			}
+
+			if lockout.Failures != 0 {
+				t.Fatalf("Expected the failures to be reset, got %d", lockout.Failures)
+			}
+
+			if d := lockout.LockedUntil.Sub(lockout.Updated); d != expectedDuration {
+				t.Fatalf("Expected lockout duration %v, got %v", expectedDuration, d)
+			}
+		}
+
+		if lockout.Lockouts != 2 {
+			t.Fatalf("Expected %d lockouts, got %d", 2, lockout.Lockouts)
+		}
+	})
+}
+
+func TestAuthLockoutIdentityRef(t *testing.T) {
+	t.Parallel()
+
+	a := core.AuthLockoutIdentityRef("test@example.com")
+	b := core.AuthLockoutIdentityRef(" TEST@example.com ")
+	c := core.AuthLockoutIdentityRef("test2@example.com")
+
+	if a != b {
+		t.Fatalf("Expected the identity ref to be case and space insensitive, got %q and %q", a, b)
+	}
+
+	if a == c {
+		t.Fatalf("Expected different refs for different identities, got %q", a)
+	}
+
+	if strings.Contains(a, "test") {
+		t.Fatalf("Expected the identity to be hashed, got %q", a)
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindAuthLockoutByRecord returns the AuthLockout model associated with the provided auth record.
+func (app *BaseApp) FindAuthLockoutByRecord(authRecord *Record) (*AuthLockout, error) {
+	return app.FindAuthLockout(authRecord.Collection().Id, authRecord.Id)
+}
+
+// FindAuthLockout returns the AuthLockout model associated with the
+// provided collection id and lockout ref (the auth record id or
+// the result of AuthLockoutIdentityRef for unknown identities).
+func (app *BaseApp) FindAuthLockout(collectionId string, ref string) (*AuthLockout, error) {
+	model := &AuthLockout{}
+
+	err := app.AuxModelQuery(model).
+		AndWhere(dbx.HashExp{
+			"collectionRef": collectionId,
+			"recordRef":     ref,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// IncrementAuthLockoutFailures atomically increments the failed auth
+// attempts counter of the provided collection id and lockout ref
+// (creating the AuthLockout entry if missing) and returns the updated model.
+//
+// The increment is executed as a single plain SQL upsert statement,
+// aka. concurrent failed attempts can't overwrite each other counts
+// and no model hook events will be fired.
+func (app *BaseApp) IncrementAuthLockoutFailures(collectionId string, ref string) (*AuthLockout, error) {
+	model := &AuthLockout{}
+
+	now := types.NowDateTime().String()
+
+	err := app.AuxNonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + AuthLockoutsTableName + "}} ([[id]], [[collectionRef]], [[recordRef]], [[failures]], [[created]], [[updated]]) " +
+			"VALUES ({:id}, {:collectionRef}, {:recordRef}, 1, {:now}, {:now}) " +
+			"ON CONFLICT ([[collectionRef]], [[recordRef]]) DO UPDATE SET [[failures]] = [[failures]] + 1, [[updated]] = {:now} " +
+			"RETURNING *",
+	).Bind(dbx.Params{
+		"id":            GenerateDefaultRandomId(),
+		"collectionRef": collectionId,
+		"recordRef":     ref,
+		"now":           now,
+	}).One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// ApplyAuthLockout persists the failures, lockouts and lockedUntil state
+// of the provided AuthLockout model (usually after calling [AuthLockout.Lock]).
+//
+// The update is executed only if the stored lockout is not already active,
+// aka. when multiple concurrent failed attempts reach the lockout threshold
+// only the first one is applied and reported with true.
+//
+// For better performance the update is executed as plain SQL statement,
+// aka. no update model hook events will be fired.
+func (app *BaseApp) ApplyAuthLockout(lockout *AuthLockout) (bool, error) {
+	lockout.Updated = types.NowDateTime()
+
+	result, err := app.AuxNonconcurrentDB().Update(
+		AuthLockoutsTableName,
+		dbx.Params{
+			"failures":    lockout.Failures,
+			"lockouts":    lockout.Lockouts,
+			"lockedUntil": lockout.LockedUntil.String(),
+			"updated":     lockout.Updated.String(),
+		},
+		dbx.And(
+			dbx.HashExp{"id": lockout.Id},
+			dbx.NewExp("([[lockedUntil]] = '' OR [[lockedUntil]] <= {:now})", dbx.Params{"now": lockout.Updated.String()}),
+		),
+	).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteAuthLockoutsByRecord deletes all AuthLockout models associated
+// with the provided auth record (aka. unlocks the record and resets its failed auth attempts).
+//
+// For better performance the delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteAuthLockoutsByRecord(authRecord *Record) error {
+	_, err := app.AuxNonconcurrentDB().Delete(AuthLockoutsTableName, dbx.HashExp{
+		"collectionRef": authRecord.Collection().Id,
+		"recordRef":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
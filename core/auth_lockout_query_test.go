@@ -0,0 +1,196 @@
+package core_test
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestFindAuthLockoutByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockout := core.NewAuthLockout(user1)
+	lockout.Failures = 2
+	if err := app.AuxSave(lockout); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := app.FindAuthLockoutByRecord(user1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != lockout.Id || found.Failures != 2 {
+		t.Fatalf("Expected lockout %q with %d failures, got %q with %d", lockout.Id, 2, found.Id, found.Failures)
+	}
+
+	_, err = app.FindAuthLockoutByRecord(user2)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestDeleteAuthLockoutsByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, user := range []*core.Record{user1, user2} {
+		if err := app.AuxSave(core.NewAuthLockout(user)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.DeleteAuthLockoutsByRecord(user1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindAuthLockoutByRecord(user1); err == nil {
+		t.Fatal("Expected the user1 lockout to be deleted")
+	}
+
+	if _, err := app.FindAuthLockoutByRecord(user2); err != nil {
+		t.Fatalf("Expected the user2 lockout to remain, got %v", err)
+	}
+
+	// deleting the auth record should also delete its lockouts
+	if err := app.Delete(user2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindAuthLockoutByRecord(user2); err == nil {
+		t.Fatal("Expected the user2 lockout to be deleted")
+	}
+}
+
+func TestIncrementAuthLockoutFailures(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// concurrent increments shouldn't overwrite each other
+	total := 20
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := app.IncrementAuthLockoutFailures(user.Collection().Id, user.Id); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lockout, err := app.FindAuthLockoutByRecord(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockout.Failures != total {
+		t.Fatalf("Expected %d failures, got %d", total, lockout.Failures)
+	}
+
+	incremented, err := app.IncrementAuthLockoutFailures(user.Collection().Id, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if incremented.Id != lockout.Id || incremented.Failures != total+1 {
+		t.Fatalf("Expected lockout %q with %d failures, got %q with %d", lockout.Id, total+1, incremented.Id, incremented.Failures)
+	}
+
+	// unknown identity ref
+	ref := core.AuthLockoutIdentityRef("missing@example.com")
+	identityLockout, err := app.IncrementAuthLockoutFailures(user.Collection().Id, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identityLockout.RecordRef != ref || identityLockout.Failures != 1 {
+		t.Fatalf("Expected identity lockout with 1 failure, got %q with %d", identityLockout.RecordRef, identityLockout.Failures)
+	}
+}
+
+func TestApplyAuthLockout(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := core.PasswordAuthConfig{LockoutThreshold: 1, LockoutDuration: 60}
+
+	// simulate 2 concurrent failed attempts reaching the threshold
+	lockoutA, err := app.IncrementAuthLockoutFailures(user.Collection().Id, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockoutB, err := app.IncrementAuthLockoutFailures(user.Collection().Id, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !lockoutA.Lock(config) || !lockoutB.Lock(config) {
+		t.Fatal("Expected both lockouts to reach the threshold")
+	}
+
+	applied, err := app.ApplyAuthLockout(lockoutA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied {
+		t.Fatal("Expected the first lockout to be applied")
+	}
+
+	applied, err = app.ApplyAuthLockout(lockoutB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied {
+		t.Fatal("Expected the second lockout to be skipped")
+	}
+
+	stored, err := app.FindAuthLockoutByRecord(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.IsLocked() || stored.Lockouts != 1 || stored.Failures != 0 {
+		t.Fatalf("Expected locked state with 1 lockout and 0 failures, got %d lockouts and %d failures", stored.Lockouts, stored.Failures)
+	}
+
+}
@@ -199,9 +199,10 @@ func recordRefHooks[T RecordProxy](app App, collectionName string, optCollection
 	})
 
 	// delete on record ref delete
+	// (the references of the soft deleted records are preserved)
 	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
 		Func: func(e *RecordEvent) error {
-			if e.Record.Collection().Name == collectionName ||
+			if e.Record.Collection().Name == collectionName || e.Record.isSoftDelete() ||
 				(len(optCollectionTypes) > 0 && !slices.Contains(optCollectionTypes, e.Record.Collection().Type)) {
 				return e.Next()
 			}
@@ -83,6 +83,8 @@ type BaseApp struct {
 	nonconcurrentDB     dbx.Builder
 	auxConcurrentDB     dbx.Builder
 	auxNonconcurrentDB  dbx.Builder
+	mailQueue           *queueWorker
+	jobQueue            *jobQueue
 
 	// app event hooks
 	onBootstrap     *hook.Hook[*BootstrapEvent]
@@ -139,6 +141,7 @@ type BaseApp struct {
 
 	// mailer event hooks
 	onMailerSend                    *hook.Hook[*MailerEvent]
+	onMailReceived                  *hook.Hook[*MailReceivedEvent]
 	onMailerRecordPasswordResetSend *hook.Hook[*MailerRecordEvent]
 	onMailerRecordVerificationSend  *hook.Hook[*MailerRecordEvent]
 	onMailerRecordEmailChangeSend   *hook.Hook[*MailerRecordEvent]
@@ -163,6 +166,7 @@ type BaseApp struct {
 	onRecordAuthRequest                 *hook.Hook[*RecordAuthRequestEvent]
 	onRecordAuthWithPasswordRequest     *hook.Hook[*RecordAuthWithPasswordRequestEvent]
 	onRecordAuthWithOAuth2Request       *hook.Hook[*RecordAuthWithOAuth2RequestEvent]
+	onRecordAuthWithSAMLRequest         *hook.Hook[*RecordAuthWithSAMLRequestEvent]
 	onRecordAuthRefreshRequest          *hook.Hook[*RecordAuthRefreshRequestEvent]
 	onRecordRequestPasswordResetRequest *hook.Hook[*RecordRequestPasswordResetRequestEvent]
 	onRecordConfirmPasswordResetRequest *hook.Hook[*RecordConfirmPasswordResetRequestEvent]
@@ -172,6 +176,7 @@ type BaseApp struct {
 	onRecordConfirmEmailChangeRequest   *hook.Hook[*RecordConfirmEmailChangeRequestEvent]
 	onRecordRequestOTPRequest           *hook.Hook[*RecordCreateOTPRequestEvent]
 	onRecordAuthWithOTPRequest          *hook.Hook[*RecordAuthWithOTPRequestEvent]
+	onRecordAuthLockout                 *hook.Hook[*RecordAuthLockoutEvent]
 
 	// record crud API event hooks
 	onRecordsListRequest  *hook.Hook[*RecordsListRequestEvent]
@@ -200,6 +205,8 @@ func NewBaseApp(config BaseAppConfig) *BaseApp {
 		settings:            newDefaultSettings(),
 		store:               store.New[string, any](nil),
 		cron:                cron.New(),
+		mailQueue:           &queueWorker{},
+		jobQueue:            newJobQueue(),
 		subscriptionsBroker: subscriptions.NewBroker(),
 		config:              &config,
 	}
@@ -287,6 +294,7 @@ func (app *BaseApp) initHooks() {
 
 	// mailer event hooks
 	app.onMailerSend = &hook.Hook[*MailerEvent]{}
+	app.onMailReceived = &hook.Hook[*MailReceivedEvent]{}
 	app.onMailerRecordPasswordResetSend = &hook.Hook[*MailerRecordEvent]{}
 	app.onMailerRecordVerificationSend = &hook.Hook[*MailerRecordEvent]{}
 	app.onMailerRecordEmailChangeSend = &hook.Hook[*MailerRecordEvent]{}
@@ -311,6 +319,7 @@ func (app *BaseApp) initHooks() {
 	app.onRecordAuthRequest = &hook.Hook[*RecordAuthRequestEvent]{}
 	app.onRecordAuthWithPasswordRequest = &hook.Hook[*RecordAuthWithPasswordRequestEvent]{}
 	app.onRecordAuthWithOAuth2Request = &hook.Hook[*RecordAuthWithOAuth2RequestEvent]{}
+	app.onRecordAuthWithSAMLRequest = &hook.Hook[*RecordAuthWithSAMLRequestEvent]{}
 	app.onRecordAuthRefreshRequest = &hook.Hook[*RecordAuthRefreshRequestEvent]{}
 	app.onRecordRequestPasswordResetRequest = &hook.Hook[*RecordRequestPasswordResetRequestEvent]{}
 	app.onRecordConfirmPasswordResetRequest = &hook.Hook[*RecordConfirmPasswordResetRequestEvent]{}
@@ -320,6 +329,7 @@ func (app *BaseApp) initHooks() {
 	app.onRecordConfirmEmailChangeRequest = &hook.Hook[*RecordConfirmEmailChangeRequestEvent]{}
 	app.onRecordRequestOTPRequest = &hook.Hook[*RecordCreateOTPRequestEvent]{}
 	app.onRecordAuthWithOTPRequest = &hook.Hook[*RecordAuthWithOTPRequestEvent]{}
+	app.onRecordAuthLockout = &hook.Hook[*RecordAuthLockoutEvent]{}
 
 	// record crud API event hooks
 	app.onRecordsListRequest = &hook.Hook[*RecordsListRequestEvent]{}
@@ -614,13 +624,46 @@ func (app *BaseApp) SubscriptionsBroker() *subscriptions.Broker {
 	return app.subscriptionsBroker
 }
 
-// NewMailClient creates and returns a new SMTP or Sendmail client
+// NewMailClient creates and returns a new HTTP-API, SMTP or Sendmail client
 // based on the current app settings.
+//
+// If the MailQueue setting is enabled, the returned client enqueues
+// the messages in the persistent mail queue instead of sending them inline.
 func (app *BaseApp) NewMailClient() mailer.Mailer {
+	if app.Settings().MailQueue.Enabled {
+		return &mailer.QueueMailer{Queue: &appMailQueue{app: app}}
+	}
+
+	return app.newDirectMailClient()
+}
+
+// newDirectMailClient creates and returns a new HTTP-API, SMTP or Sendmail client
+// based on the current app settings (ignoring the MailQueue setting).
+func (app *BaseApp) newDirectMailClient() mailer.Mailer {
 	var client mailer.Mailer
 
 	// init mailer client
-	if app.Settings().SMTP.Enabled {
+	driver := app.Settings().MailDriver
+	switch {
+	case driver.Driver == MailDriverSES:
+		client = &mailer.SESClient{
+			Endpoint:  driver.Endpoint,
+			Region:    driver.Region,
+			AccessKey: driver.AccessKey,
+			SecretKey: driver.Secret,
+		}
+	case driver.Driver == MailDriverMailgun:
+		client = &mailer.MailgunClient{
+			Endpoint: driver.Endpoint,
+			Domain:   driver.Domain,
+			APIKey:   driver.Secret,
+		}
+	case driver.Driver == MailDriverPostmark:
+		client = &mailer.PostmarkClient{
+			Endpoint:    driver.Endpoint,
+			ServerToken: driver.Secret,
+		}
+	case app.Settings().SMTP.Enabled:
 		client = &mailer.SMTPClient{
 			Host:       app.Settings().SMTP.Host,
 			Port:       app.Settings().SMTP.Port,
@@ -630,7 +673,7 @@ func (app *BaseApp) NewMailClient() mailer.Mailer {
 			AuthMethod: app.Settings().SMTP.AuthMethod,
 			LocalName:  app.Settings().SMTP.LocalName,
 		}
-	} else {
+	default:
 		client = &mailer.Sendmail{}
 	}
 
@@ -995,6 +1038,10 @@ func (app *BaseApp) OnMailerSend() *hook.Hook[*MailerEvent] {
 	return app.onMailerSend
 }
 
+func (app *BaseApp) OnMailReceived() *hook.Hook[*MailReceivedEvent] {
+	return app.onMailReceived
+}
+
 func (app *BaseApp) OnMailerRecordPasswordResetSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent] {
 	return hook.NewTaggedHook(app.onMailerRecordPasswordResetSend, tags...)
 }
@@ -1075,6 +1122,10 @@ func (app *BaseApp) OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHo
 	return hook.NewTaggedHook(app.onRecordAuthWithOAuth2Request, tags...)
 }
 
+func (app *BaseApp) OnRecordAuthWithSAMLRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithSAMLRequestEvent] {
+	return hook.NewTaggedHook(app.onRecordAuthWithSAMLRequest, tags...)
+}
+
 func (app *BaseApp) OnRecordAuthRefreshRequest(tags ...string) *hook.TaggedHook[*RecordAuthRefreshRequestEvent] {
 	return hook.NewTaggedHook(app.onRecordAuthRefreshRequest, tags...)
 }
@@ -1111,6 +1162,10 @@ func (app *BaseApp) OnRecordAuthWithOTPRequest(tags ...string) *hook.TaggedHook[
 	return hook.NewTaggedHook(app.onRecordAuthWithOTPRequest, tags...)
 }
 
+func (app *BaseApp) OnRecordAuthLockout(tags ...string) *hook.TaggedHook[*RecordAuthLockoutEvent] {
+	return hook.NewTaggedHook(app.onRecordAuthLockout, tags...)
+}
+
 // -------------------------------------------------------------------
 // Record CRUD API event hooks
 // -------------------------------------------------------------------
@@ -1312,7 +1367,7 @@ func (app *BaseApp) registerBaseHooks() {
 	app.OnModelAfterDeleteSuccess().Bind(&hook.Handler[*ModelEvent]{
 		Id: "__pbFilesManagerDelete__",
 		Func: func(e *ModelEvent) error {
-			if m, ok := e.Model.(FilesManager); ok && m.BaseFilesPath() != "" && supportFiles(e.Model) {
+			if m, ok := e.Model.(FilesManager); ok && m.BaseFilesPath() != "" && supportFiles(e.Model) && !isSoftDeleteModel(e.Model) {
 				// ensure that there is a trailing slash so that the list iterator could start walking from the prefix dir
 				// (https://github.com/pocketbase/pocketbase/discussions/5246#discussioncomment-10128955)
 				prefix := strings.TrimRight(m.BaseFilesPath(), "/") + "/"
@@ -1382,6 +1437,16 @@ func (app *BaseApp) registerBaseHooks() {
 	app.registerMFAHooks()
 	app.registerOTPHooks()
 	app.registerAuthOriginHooks()
+	app.registerAPIKeyHooks()
+	app.registerPushSubscriptionHooks()
+	app.registerPasswordHistoryHooks()
+	app.registerAuthLockoutHooks()
+	app.registerMailQueueHooks()
+	app.registerJobQueueHooks()
+	app.registerCronRunsHooks()
+	app.registerWebhookHooks()
+	app.registerRecordVersionHooks()
+	app.registerSoftDeleteHooks()
 }
 
 // getLoggerMinLevel returns the logger min level based on the
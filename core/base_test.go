@@ -205,6 +205,38 @@ func TestBaseAppNewMailClient(t *testing.T) {
 	if m2.OnSend() == nil || m2.OnSend().Length() == 0 {
 		t.Fatal("Expected OnSend hook to be registered")
 	}
+
+	// HTTP-API drivers (have priority over the SMTP settings)
+	// ---
+	app.Settings().MailDriver.Driver = core.MailDriverSES
+	app.Settings().MailDriver.Region = "eu-west-1"
+	m3, ok := app.NewMailClient().(*mailer.SESClient)
+	if !ok || m3.Region != "eu-west-1" {
+		t.Fatalf("Expected mailer.SESClient instance, got %v", m3)
+	}
+	if m3.OnSend() == nil || m3.OnSend().Length() == 0 {
+		t.Fatal("Expected OnSend hook to be registered")
+	}
+
+	app.Settings().MailDriver.Driver = core.MailDriverMailgun
+	app.Settings().MailDriver.Domain = "mg.example.com"
+	m4, ok := app.NewMailClient().(*mailer.MailgunClient)
+	if !ok || m4.Domain != "mg.example.com" {
+		t.Fatalf("Expected mailer.MailgunClient instance, got %v", m4)
+	}
+	if m4.OnSend() == nil || m4.OnSend().Length() == 0 {
+		t.Fatal("Expected OnSend hook to be registered")
+	}
+
+	app.Settings().MailDriver.Driver = core.MailDriverPostmark
+	app.Settings().MailDriver.Secret = "test_token"
+	m5, ok := app.NewMailClient().(*mailer.PostmarkClient)
+	if !ok || m5.ServerToken != "test_token" {
+		t.Fatalf("Expected mailer.PostmarkClient instance, got %v", m5)
+	}
+	if m5.OnSend() == nil || m5.OnSend().Length() == 0 {
+		t.Fatal("Expected OnSend hook to be registered")
+	}
 }
 
 func TestBaseAppNewFilesystem(t *testing.T) {
@@ -0,0 +1,335 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const (
+	CollectionConstraintTypeUnique = "unique"
+	CollectionConstraintTypeCheck  = "check"
+)
+
+// checkConstraintErrorPrefix is the prefix of the RAISE error message
+// of the triggers that enforce the collection "check" constraints.
+const checkConstraintErrorPrefix = "pb_check_constraint_failed:"
+
+var checkConstraintErrorRegex = regexp.MustCompile(regexp.QuoteMeta(checkConstraintErrorPrefix) + `(\w+)`)
+
+// CollectionConstraint defines a single collection records table constraint.
+//
+// The "unique" constraints are created as (optionally partial) unique indexes.
+//
+// Because SQLite doesn't support adding CHECK constraints to existing tables,
+// the "check" constraints are enforced with BEFORE INSERT and BEFORE UPDATE
+// triggers (existing records are verified when the constraint is created).
+type CollectionConstraint struct {
+	// Name is the collection constraint identifier.
+	Name string `form:"name" json:"name"`
+
+	// Type is the constraint type ("unique" or "check").
+	Type string `form:"type" json:"type"`
+
+	// Fields is the list of field names which values must be
+	// unique together (applicable only for the "unique" constraints).
+	Fields []string `form:"fields" json:"fields"`
+
+	// Where is an optional filter expression that limits the "unique"
+	// constraint only to the matching records (aka. partial unique index).
+	//
+	// For example: "deleted = '' && status = 'active'".
+	Where string `form:"where" json:"where"`
+
+	// Expression is the filter expression that each record must satisfy
+	// (applicable only for the "check" constraints).
+	//
+	// It follows the [search.FormulaData] syntax but can reference only
+	// the collection regular fields. For example: "start < end".
+	Expression string `form:"expression" json:"expression"`
+
+	// Message is an optional custom validation error message
+	// that is returned when the constraint is violated.
+	Message string `form:"message" json:"message"`
+}
+
+// dbName returns the name of the database object(s) associated with the constraint.
+func (c CollectionConstraint) dbName(collection *Collection) string {
+	return "_" + collection.Id + "_" + c.Name
+}
+
+// buildConstraintSQL compiles the provided constraint filter expression
+// into a standalone SQL expression with inlined literal values.
+//
+// If tableAlias is set, the column identifiers are prefixed with it (eg. "NEW").
+//
+// The decimal field identifiers are resolved to their numeric values.
+func buildConstraintSQL(collection *Collection, expression string, tableAlias string) (string, error) {
+	return search.FormulaData(expression).BuildSQL(func(name string) (string, error) {
+		identifier := "[[" + name + "]]"
+		if tableAlias != "" {
+			identifier = tableAlias + "." + identifier
+		}
+
+		if _, ok := collection.Fields.GetByName(name).(*DecimalField); ok {
+			return decimalSQLValue(identifier), nil
+		}
+
+		return identifier, nil
+	})
+}
+
+// fieldNames returns the names of all fields referenced by the constraint.
+func (c CollectionConstraint) fieldNames() []string {
+	names := slices.Clone(c.Fields)
+
+	for _, expr := range []string{c.Where, c.Expression} {
+		if expr == "" {
+			continue
+		}
+
+		identifiers, _ := search.FormulaData(expr).Identifiers()
+		names = append(names, identifiers...)
+	}
+
+	return names
+}
+
+// hasConstraintFieldsChanges checks whether any of the fields referenced
+// by the old collection constraints was deleted, renamed or has a different
+// column definition (aka. the constraints need to be rebuilt).
+func hasConstraintFieldsChanges(app App, newCollection *Collection, oldCollection *Collection) bool {
+	for _, c := range oldCollection.Constraints {
+		for _, name := range c.fieldNames() {
+			oldField := oldCollection.Fields.GetByName(name)
+			if oldField == nil {
+				continue
+			}
+
+			newField := newCollection.Fields.GetById(oldField.GetId())
+			if newField == nil ||
+				newField.GetName() != oldField.GetName() ||
+				newField.ColumnType(app) != oldField.ColumnType(app) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func dropCollectionConstraints(app App, collection *Collection) error {
+	if collection.IsView() {
+		return nil // views don't have constraints
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		for _, c := range collection.Constraints {
+			name := c.dbName(collection)
+
+			var queries []string
+			switch c.Type {
+			case CollectionConstraintTypeUnique:
+				queries = []string{fmt.Sprintf("DROP INDEX IF EXISTS [[%s]]", name)}
+			case CollectionConstraintTypeCheck:
+				queries = []string{
+					fmt.Sprintf("DROP TRIGGER IF EXISTS [[%s_insert]]", name),
+					fmt.Sprintf("DROP TRIGGER IF EXISTS [[%s_update]]", name),
+				}
+			}
+
+			for _, q := range queries {
+				if _, err := txApp.DB().NewQuery(q).Execute(); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func createCollectionConstraints(app App, collection *Collection) error {
+	if collection.IsView() {
+		return nil // views don't have constraints
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		// note: similar to the indexes we are returning validation errors
+		// because the constraints cannot be fully validated before
+		// persisting the related collection record table changes
+		errs := validation.Errors{}
+		for i, c := range collection.Constraints {
+			var err error
+			switch c.Type {
+			case CollectionConstraintTypeUnique:
+				err = createUniqueConstraint(txApp, collection, c)
+			case CollectionConstraintTypeCheck:
+				err = createCheckConstraint(txApp, collection, c)
+			default:
+				err = fmt.Errorf("unknown constraint type %q", c.Type)
+			}
+
+			if err != nil {
+				errs[strconv.Itoa(i)] = validation.NewError(
+					"validation_invalid_constraint",
+					fmt.Sprintf("Failed to create constraint %s - %v.", c.Name, err.Error()),
+				)
+			}
+		}
+
+		if len(errs) > 0 {
+			return validation.Errors{"constraints": errs}
+		}
+
+		return nil
+	})
+}
+
+func createUniqueConstraint(app App, collection *Collection, c CollectionConstraint) error {
+	columns := make([]string, len(c.Fields))
+	for i, name := range c.Fields {
+		columns[i] = "[[" + name + "]]"
+	}
+
+	sql := fmt.Sprintf(
+		"CREATE UNIQUE INDEX [[%s]] ON {{%s}} (%s)",
+		c.dbName(collection),
+		collection.Name,
+		strings.Join(columns, ", "),
+	)
+
+	if c.Where != "" {
+		where, err := buildConstraintSQL(collection, c.Where, "")
+		if err != nil {
+			return err
+		}
+		sql += " WHERE " + where
+	}
+
+	_, err := app.DB().NewQuery(sql).Execute()
+
+	return err
+}
+
+func createCheckConstraint(app App, collection *Collection, c CollectionConstraint) error {
+	expr, err := buildConstraintSQL(collection, c.Expression, "")
+	if err != nil {
+		return err
+	}
+
+	// verify the existing records
+	var total int
+	err = app.DB().Select("count(*)").
+		From(collection.Name).
+		AndWhere(dbx.NewExp("NOT (" + expr + ")")).
+		Row(&total)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return fmt.Errorf("%d existing record(s) don't satisfy the expression", total)
+	}
+
+	newExpr, err := buildConstraintSQL(collection, c.Expression, "NEW")
+	if err != nil {
+		return err
+	}
+
+	// note: similar to the CHECK constraints, NULL expression result is considered valid
+	for _, event := range []string{"INSERT", "UPDATE"} {
+		_, err := app.DB().NewQuery(fmt.Sprintf(
+			"CREATE TRIGGER [[%s_%s]] BEFORE %s ON {{%s}} WHEN NOT (%s) BEGIN SELECT RAISE(ABORT, '%s'); END",
+			c.dbName(collection),
+			strings.ToLower(event),
+			event,
+			collection.Name,
+			newExpr,
+			checkConstraintErrorPrefix+c.Name,
+		)).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeConstraintError attempts to convert a collection constraint
+// violation db error into a user friendly validation error.
+//
+// Returns nil if err is not a collection constraint violation.
+func normalizeConstraintError(err error, collection *Collection) error {
+	if err == nil || len(collection.Constraints) == 0 {
+		return nil
+	}
+
+	if _, ok := err.(validation.Errors); ok {
+		return nil
+	}
+
+	msg := err.Error()
+
+	// check constraint failure
+	if match := checkConstraintErrorRegex.FindStringSubmatch(msg); len(match) == 2 {
+		for _, c := range collection.Constraints {
+			if c.Type != CollectionConstraintTypeCheck || c.Name != match[1] {
+				continue
+			}
+
+			message := c.Message
+			if message == "" {
+				message = "The value doesn't satisfy the " + c.Name + " constraint."
+			}
+
+			validationErr := validation.NewError("validation_check_constraint_failure", message)
+
+			fields, _ := search.FormulaData(c.Expression).Identifiers()
+			if len(fields) == 0 {
+				return validationErr
+			}
+
+			errs := make(validation.Errors, len(fields))
+			for _, name := range fields {
+				errs[name] = validationErr
+			}
+
+			return errs
+		}
+	}
+
+	// unique constraint failure
+	lowerMsg := strings.ToLower(msg)
+	if strings.Contains(lowerMsg, "unique constraint failed") {
+		// note: extra space to unify multi-columns lookup
+		lowerMsg = strings.ReplaceAll(strings.TrimSpace(lowerMsg), ",", " ") + " "
+
+	CONSTRAINTS_LOOP:
+		for _, c := range collection.Constraints {
+			if c.Type != CollectionConstraintTypeUnique || c.Message == "" || len(c.Fields) == 0 {
+				continue // fallback to the default unique index error normalization
+			}
+
+			for _, name := range c.Fields {
+				if !strings.Contains(lowerMsg, strings.ToLower(" "+collection.Name+"."+name+" ")) {
+					continue CONSTRAINTS_LOOP
+				}
+			}
+
+			errs := make(validation.Errors, len(c.Fields))
+			for _, name := range c.Fields {
+				errs[name] = validation.NewError("validation_not_unique", c.Message)
+			}
+
+			return errs
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,424 @@
+package core_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func createConstraintsCollection(t *testing.T, app core.App, constraints ...core.CollectionConstraint) *core.Collection {
+	collection := core.NewBaseCollection("test_constraints")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "status"},
+		&core.NumberField{Name: "start"},
+		&core.NumberField{Name: "end"},
+	)
+	collection.Constraints = constraints
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func TestCollectionConstraintsValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name           string
+		constraints    []core.CollectionConstraint
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			nil,
+			nil,
+		},
+		{
+			"missing required fields",
+			[]core.CollectionConstraint{{}},
+			[]string{"name", "type"},
+		},
+		{
+			"invalid name and type",
+			[]core.CollectionConstraint{{Name: "a b", Type: "missing"}},
+			[]string{"name", "type"},
+		},
+		{
+			"duplicated names",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeUnique, Fields: []string{"title"}},
+				{Name: "A", Type: core.CollectionConstraintTypeUnique, Fields: []string{"status"}},
+			},
+			[]string{"name"},
+		},
+		{
+			"unique with missing, duplicated and check only fields",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeUnique, Expression: "start < end"},
+				{Name: "b", Type: core.CollectionConstraintTypeUnique, Fields: []string{"title", "missing"}},
+				{Name: "c", Type: core.CollectionConstraintTypeUnique, Fields: []string{"title", "title"}},
+			},
+			[]string{"fields", "expression"},
+		},
+		{
+			"unique with invalid where",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeUnique, Fields: []string{"title"}, Where: "missing = 1"},
+			},
+			[]string{"where"},
+		},
+		{
+			"check with missing expression and unique only fields",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeCheck, Fields: []string{"title"}, Where: "status = ''"},
+			},
+			[]string{"fields", "where", "expression"},
+		},
+		{
+			"check with invalid expression",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeCheck, Expression: "start <"},
+				{Name: "b", Type: core.CollectionConstraintTypeCheck, Expression: "missing > 0"},
+			},
+			[]string{"expression"},
+		},
+		{
+			"too long message",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeCheck, Expression: "start < end", Message: strings.Repeat("a", 256)},
+			},
+			[]string{"message"},
+		},
+		{
+			"valid constraints",
+			[]core.CollectionConstraint{
+				{Name: "a", Type: core.CollectionConstraintTypeUnique, Fields: []string{"title", "status"}, Where: "status = 'active'"},
+				{Name: "b", Type: core.CollectionConstraintTypeCheck, Expression: "start < end && length(title) > 0", Message: "test"},
+			},
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection := core.NewBaseCollection("new_test")
+			collection.Fields.Add(
+				&core.TextField{Name: "title"},
+				&core.TextField{Name: "status"},
+				&core.NumberField{Name: "start"},
+				&core.NumberField{Name: "end"},
+			)
+			collection.Constraints = s.constraints
+
+			err := app.Validate(collection)
+
+			var constraintsErrs map[string]any
+			if errs, ok := err.(validation.Errors); ok && errs["constraints"] != nil {
+				raw, _ := json.Marshal(errs["constraints"])
+				json.Unmarshal(raw, &constraintsErrs)
+			}
+
+			if len(s.expectedErrors) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no errors, got %v", err)
+				}
+				return
+			}
+
+			if len(constraintsErrs) == 0 {
+				t.Fatalf("Expected constraints errors, got %v", err)
+			}
+
+			// collect the unique keys of the individual constraint errors
+			found := map[string]struct{}{}
+			for _, itemErrs := range constraintsErrs {
+				if m, ok := itemErrs.(map[string]any); ok {
+					for k := range m {
+						found[k] = struct{}{}
+					}
+				}
+			}
+
+			if len(found) != len(s.expectedErrors) {
+				t.Fatalf("Expected error keys %v, got %v", s.expectedErrors, found)
+			}
+			for _, k := range s.expectedErrors {
+				if _, ok := found[k]; !ok {
+					t.Fatalf("Missing expected error key %q in %v", k, found)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectionConstraintsUnique(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createConstraintsCollection(t, app, core.CollectionConstraint{
+		Name:    "active_title",
+		Type:    core.CollectionConstraintTypeUnique,
+		Fields:  []string{"title", "status"},
+		Where:   "status = 'active'",
+		Message: "The title is already used.",
+	})
+
+	save := func(title, status string) error {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		record.Set("status", status)
+		return app.Save(record)
+	}
+
+	if err := save("a", "active"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the partial constraint doesn't apply to the non-matching records
+	for i := 0; i < 2; i++ {
+		if err := save("a", "inactive"); err != nil {
+			t.Fatalf("Expected the non-active duplicate to be saved, got %v", err)
+		}
+	}
+
+	err := save("a", "active")
+
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		t.Fatalf("Expected validation.Errors, got %v", err)
+	}
+
+	for _, name := range []string{"title", "status"} {
+		fieldErr, ok := errs[name].(validation.Error)
+		if !ok {
+			t.Fatalf("Expected %q field error, got %v", name, errs)
+		}
+
+		if fieldErr.Code() != "validation_not_unique" || fieldErr.Message() != "The title is already used." {
+			t.Fatalf("Unexpected %q field error %q: %q", name, fieldErr.Code(), fieldErr.Message())
+		}
+	}
+
+	// remove the constraint
+	collection.Constraints = nil
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := save("a", "active"); err != nil {
+		t.Fatalf("Expected the duplicate to be saved after removing the constraint, got %v", err)
+	}
+
+	// try to readd the constraint with existing duplicates
+	collection.Constraints = []core.CollectionConstraint{{
+		Name:   "active_title",
+		Type:   core.CollectionConstraintTypeUnique,
+		Fields: []string{"title", "status"},
+		Where:  "status = 'active'",
+	}}
+	err = app.Save(collection)
+	if err == nil || !strings.Contains(err.Error(), "constraints") {
+		t.Fatalf("Expected constraints error, got %v", err)
+	}
+}
+
+func TestCollectionConstraintsCheck(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createConstraintsCollection(t, app, core.CollectionConstraint{
+		Name:       "valid_range",
+		Type:       core.CollectionConstraintTypeCheck,
+		Expression: "start < end",
+	})
+
+	record := core.NewRecord(collection)
+	record.Set("start", 1)
+	record.Set("end", 2)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	assertCheckErr := func(err error) {
+		t.Helper()
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		if len(errs) != 2 {
+			t.Fatalf("Expected start and end errors, got %v", errs)
+		}
+
+		for _, name := range []string{"start", "end"} {
+			fieldErr, ok := errs[name].(validation.Error)
+			if !ok || fieldErr.Code() != "validation_check_constraint_failure" {
+				t.Fatalf("Expected %q check constraint error, got %v", name, errs[name])
+			}
+		}
+	}
+
+	// update
+	record.Set("end", 0)
+	assertCheckErr(app.Save(record))
+
+	// create
+	invalid := core.NewRecord(collection)
+	invalid.Set("start", 5)
+	invalid.Set("end", 5)
+	assertCheckErr(app.Save(invalid))
+
+	// rename the collection and ensure that the constraint is still applied
+	collection.Name = "test_constraints_new"
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid = core.NewRecord(collection)
+	invalid.Set("start", 5)
+	invalid.Set("end", 5)
+	assertCheckErr(app.Save(invalid))
+}
+
+func TestCollectionConstraintsCheckExistingRecords(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createConstraintsCollection(t, app)
+
+	record := core.NewRecord(collection)
+	record.Set("start", 10)
+	record.Set("end", 1)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Constraints = []core.CollectionConstraint{{
+		Name:       "valid_range",
+		Type:       core.CollectionConstraintTypeCheck,
+		Expression: "start < end",
+	}}
+
+	err := app.Save(collection)
+	if err == nil || !strings.Contains(err.Error(), "existing record(s)") {
+		t.Fatalf("Expected existing records error, got %v", err)
+	}
+
+	// fix the existing record and retry
+	record.Set("end", 20)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Save(collection); err != nil {
+		t.Fatalf("Expected the constraint to be added, got %v", err)
+	}
+}
+
+func TestCollectionConstraintsFieldChanges(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createConstraintsCollection(t, app,
+		core.CollectionConstraint{
+			Name:   "unique_title",
+			Type:   core.CollectionConstraintTypeUnique,
+			Fields: []string{"title"},
+		},
+		core.CollectionConstraint{
+			Name:       "valid_range",
+			Type:       core.CollectionConstraintTypeCheck,
+			Expression: "start < end",
+		},
+	)
+
+	assertFieldsErr := func(err error) {
+		t.Helper()
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		fieldsErr, ok := errs["fields"].(validation.Error)
+		if !ok || fieldsErr.Code() != "validation_constraint_field_change" {
+			t.Fatalf("Expected fields constraint change error, got %v", errs)
+		}
+	}
+
+	// delete referenced field
+	{
+		clone, _ := app.FindCollectionByNameOrId(collection.Id)
+		clone.Fields.RemoveByName("end")
+		assertFieldsErr(app.Save(clone))
+	}
+
+	// rename referenced field without updating the constraint
+	{
+		clone, _ := app.FindCollectionByNameOrId(collection.Id)
+		clone.Fields.GetByName("end").SetName("finish")
+		assertFieldsErr(app.Save(clone))
+	}
+
+	// rename referenced fields together with the constraints
+	collection.Fields.GetByName("end").SetName("finish")
+	collection.Fields.GetByName("title").SetName("label")
+	collection.Constraints[0].Fields = []string{"label"}
+	collection.Constraints[1].Expression = "start < finish"
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	save := func(label string, start, finish int) error {
+		record := core.NewRecord(collection)
+		record.Set("label", label)
+		record.Set("start", start)
+		record.Set("finish", finish)
+		return app.Save(record)
+	}
+
+	if err := save("a", 1, 2); err != nil {
+		t.Fatalf("Expected the valid record to be saved, got %v", err)
+	}
+
+	err := save("b", 2, 1)
+	if errs, ok := err.(validation.Errors); !ok || errs["finish"] == nil {
+		t.Fatalf("Expected the renamed field check constraint error, got %v", err)
+	}
+
+	err = save("a", 1, 2)
+	if errs, ok := err.(validation.Errors); !ok || errs["label"] == nil {
+		t.Fatalf("Expected the renamed field unique constraint error, got %v", err)
+	}
+
+	// unrelated field changes
+	collection.Fields.Add(&core.TextField{Name: "extra"})
+	collection.Fields.RemoveByName("status")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	err = save("b", 2, 1)
+	if errs, ok := err.(validation.Errors); !ok || errs["finish"] == nil {
+		t.Fatalf("Expected the check constraint to be still applied, got %v", err)
+	}
+}
@@ -380,6 +380,7 @@ type baseCollection struct {
 // Collection defines the table, fields and various options related to a set of records.
 type Collection struct {
 	baseCollection
+	collectionBaseOptions
 	collectionAuthOptions
 	collectionViewOptions
 }
@@ -510,10 +511,13 @@ func (m *Collection) unmarshalRawOptions() error {
 	case CollectionTypeView:
 		return json.Unmarshal(raw, &m.collectionViewOptions)
 	case CollectionTypeAuth:
+		if err := json.Unmarshal(raw, &m.collectionBaseOptions); err != nil {
+			return err
+		}
 		return json.Unmarshal(raw, &m.collectionAuthOptions)
+	default:
+		return json.Unmarshal(raw, &m.collectionBaseOptions)
 	}
-
-	return nil
 }
 
 // UnmarshalJSON implements the [json.Unmarshaler] interface.
@@ -556,8 +560,9 @@ func (m Collection) MarshalJSON() ([]byte, error) {
 	case CollectionTypeAuth:
 		alias := struct {
 			baseCollection
+			collectionBaseOptions
 			collectionAuthOptions
-		}{m.baseCollection, m.collectionAuthOptions}
+		}{m.baseCollection, m.collectionBaseOptions, m.collectionAuthOptions}
 
 		// ensure that it is always returned as array
 		if alias.OAuth2.Providers == nil {
@@ -576,7 +581,10 @@ func (m Collection) MarshalJSON() ([]byte, error) {
 
 		return json.Marshal(alias)
 	default:
-		return json.Marshal(m.baseCollection)
+		return json.Marshal(struct {
+			baseCollection
+			collectionBaseOptions
+		}{m.baseCollection, m.collectionBaseOptions})
 	}
 }
 
@@ -613,7 +621,17 @@ func (m *Collection) DBExport(app App) (map[string]any, error) {
 			return nil, err
 		}
 	case CollectionTypeAuth:
-		if raw, err := types.ParseJSONRaw(m.collectionAuthOptions); err == nil {
+		options := struct {
+			collectionBaseOptions
+			collectionAuthOptions
+		}{m.collectionBaseOptions, m.collectionAuthOptions}
+		if raw, err := types.ParseJSONRaw(options); err == nil {
+			result["options"] = raw
+		} else {
+			return nil, err
+		}
+	default:
+		if raw, err := types.ParseJSONRaw(m.collectionBaseOptions); err == nil {
 			result["options"] = raw
 		} else {
 			return nil, err
@@ -809,6 +827,7 @@ func onCollectionSave(e *CollectionEvent) error {
 
 	if e.Collection.IsAuth() {
 		e.Collection.unsetMissingOAuth2MappedFields()
+		e.Collection.unsetMissingSAMLMappedFields()
 	}
 
 	e.Collection.updateGeneratedIdIfExists(e.App)
@@ -918,6 +937,26 @@ func (c *Collection) initDefaultFields() {
 	case CollectionTypeView:
 		// view fields are autogenerated
 	}
+
+	if c.SoftDelete.Enabled && !c.IsView() {
+		c.initDeletedField()
+	}
+}
+
+func (c *Collection) initDeletedField() {
+	field, _ := c.Fields.GetByName(FieldNameDeleted).(*AutodateField)
+	if field == nil {
+		// load default field
+		c.Fields.Add(&AutodateField{
+			Name:     FieldNameDeleted,
+			System:   true,
+			OnDelete: true,
+		})
+	} else {
+		// enforce system defaults
+		field.System = true
+		field.OnDelete = true
+	}
 }
 
 func (c *Collection) initIdField() {
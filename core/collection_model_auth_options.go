@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 	"time"
@@ -9,9 +10,11 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/tools/auth"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/saml"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
+	"golang.org/x/text/language"
 )
 
 func (m *Collection) unsetMissingOAuth2MappedFields() {
@@ -44,6 +47,18 @@ func (m *Collection) unsetMissingOAuth2MappedFields() {
 	}
 }
 
+func (m *Collection) unsetMissingSAMLMappedFields() {
+	if !m.IsAuth() {
+		return
+	}
+
+	for attr, name := range m.SAML.MappedFields {
+		if m.Fields.GetByName(name) == nil {
+			delete(m.SAML.MappedFields, attr)
+		}
+	}
+}
+
 func (m *Collection) setDefaultAuthOptions() {
 	m.collectionAuthOptions = collectionAuthOptions{
 		VerificationTemplate:       defaultVerificationTemplate,
@@ -51,12 +66,15 @@ func (m *Collection) setDefaultAuthOptions() {
 		ConfirmEmailChangeTemplate: defaultConfirmEmailChangeTemplate,
 		AuthRule:                   types.Pointer(""),
 		AuthAlert: AuthAlertConfig{
-			Enabled:       true,
-			EmailTemplate: defaultAuthAlertTemplate,
+			Enabled:              true,
+			EmailTemplate:        defaultAuthAlertTemplate,
+			LockoutEmailTemplate: defaultAuthLockoutTemplate,
 		},
 		PasswordAuth: PasswordAuthConfig{
-			Enabled:        true,
-			IdentityFields: []string{FieldNameEmail},
+			Enabled:          true,
+			IdentityFields:   []string{FieldNameEmail},
+			LockoutThreshold: 0,   // disabled
+			LockoutDuration:  900, // 15min
 		},
 		MFA: MFAConfig{
 			Enabled:  false,
@@ -122,6 +140,9 @@ type collectionAuthOptions struct {
 	// and which OAuth2 providers are allowed.
 	OAuth2 OAuth2Config `form:"oauth2" json:"oauth2"`
 
+	// SAML defines options related to the SAML 2.0 SSO authentication.
+	SAML SAMLConfig `form:"saml" json:"saml"`
+
 	// PasswordAuth defines options related to the collection password authentication.
 	PasswordAuth PasswordAuthConfig `form:"passwordAuth" json:"passwordAuth"`
 
@@ -144,6 +165,9 @@ type collectionAuthOptions struct {
 	VerificationTemplate       EmailTemplate `form:"verificationTemplate" json:"verificationTemplate"`
 	ResetPasswordTemplate      EmailTemplate `form:"resetPasswordTemplate" json:"resetPasswordTemplate"`
 	ConfirmEmailChangeTemplate EmailTemplate `form:"confirmEmailChangeTemplate" json:"confirmEmailChangeTemplate"`
+
+	// EmailLocale defines how to pick the localized email template variants.
+	EmailLocale EmailLocaleConfig `form:"emailLocale" json:"emailLocale"`
 }
 
 func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
@@ -162,6 +186,7 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 		validation.Field(&o.AuthAlert),
 		validation.Field(&o.PasswordAuth),
 		validation.Field(&o.OAuth2),
+		validation.Field(&o.SAML),
 		validation.Field(&o.OTP),
 		validation.Field(&o.MFA),
 		validation.Field(&o.AuthToken),
@@ -172,6 +197,7 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 		validation.Field(&o.VerificationTemplate, validation.Required),
 		validation.Field(&o.ResetPasswordTemplate, validation.Required),
 		validation.Field(&o.ConfirmEmailChangeTemplate, validation.Required),
+		validation.Field(&o.EmailLocale),
 	)
 	if err != nil {
 		return err
@@ -189,6 +215,9 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 		if o.OAuth2.Enabled {
 			authsEnabled++
 		}
+		if o.SAML.Enabled {
+			authsEnabled++
+		}
 		if o.OTP.Enabled {
 			authsEnabled++
 		}
@@ -231,6 +260,54 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 		}
 	}
 
+	// extra check to ensure that the SAML mapped fields exist
+	if o.SAML.Enabled {
+		err = validation.Validate(o.SAML.MappedFields, validation.By(cv.checkSAMLMappedFields))
+		if err != nil {
+			return validation.Errors{
+				"saml": validation.Errors{
+					"mappedFields": err,
+				},
+			}
+		}
+	}
+
+	// extra check to ensure that the locale field exists
+	if o.EmailLocale.Field != "" {
+		err = validation.Validate(o.EmailLocale.Field, validation.By(cv.checkEmailLocaleField))
+		if err != nil {
+			return validation.Errors{
+				"emailLocale": validation.Errors{
+					"field": err,
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkEmailLocaleField(value any) error {
+	name, _ := value.(string)
+
+	switch cv.new.Fields.GetByName(name).(type) {
+	case *TextField, *SelectField:
+		return nil
+	default:
+		return validation.NewError("validation_invalid_locale_field", "The locale field must be an existing text or select field.")
+	}
+}
+
+func (cv *collectionValidator) checkSAMLMappedFields(value any) error {
+	mappedFields, _ := value.(map[string]string)
+
+	for attr, name := range mappedFields {
+		if cv.new.Fields.GetByName(name) == nil {
+			return validation.NewError("validation_missing_field", "Invalid or missing field {{.fieldName}} for attribute {{.attr}}.").
+				SetParams(map[string]any{"fieldName": name, "attr": attr})
+		}
+	}
+
 	return nil
 }
 
@@ -239,6 +316,10 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 type EmailTemplate struct {
 	Subject string `form:"subject" json:"subject"`
 	Body    string `form:"body" json:"body"`
+
+	// Locales is an optional list with localized template variants
+	// keyed by their BCP 47 language tag (ex. "de", "pt-BR").
+	Locales map[string]LocalizedEmailTemplate `form:"locales" json:"locales,omitempty"`
 }
 
 // Validate makes EmailTemplate validatable by implementing [validation.Validatable] interface.
@@ -246,9 +327,75 @@ func (t EmailTemplate) Validate() error {
 	return validation.ValidateStruct(&t,
 		validation.Field(&t.Subject, validation.Required),
 		validation.Field(&t.Body, validation.Required),
+		validation.Field(&t.Locales, validation.By(checkEmailTemplateLocales)),
 	)
 }
 
+func checkEmailTemplateLocales(value any) error {
+	locales, _ := value.(map[string]LocalizedEmailTemplate)
+
+	errs := validation.Errors{}
+
+	for locale, variant := range locales {
+		if err := checkLocale(locale); err != nil {
+			errs[locale] = err
+			continue
+		}
+
+		if err := variant.Validate(); err != nil {
+			errs[locale] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Localize returns a copy of the current template with the subject and body
+// of the first matching localized variant from the provided locales
+// (ordered by preference).
+//
+// Each locale is matched first by its exact tag and then by its parent tags
+// (ex. "pt-BR" -> "pt"). If none of the locales match, the default
+// non-localized subject and body are returned.
+func (t EmailTemplate) Localize(locales ...string) EmailTemplate {
+	result := EmailTemplate{Subject: t.Subject, Body: t.Body}
+
+	if len(t.Locales) == 0 {
+		return result
+	}
+
+	// normalize the variant keys
+	variants := make(map[string]LocalizedEmailTemplate, len(t.Locales))
+	for locale, variant := range t.Locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+		variants[tag.String()] = variant
+	}
+
+	for _, locale := range locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+
+		for ; !tag.IsRoot(); tag = tag.Parent() {
+			if variant, ok := variants[tag.String()]; ok {
+				result.Subject = variant.Subject
+				result.Body = variant.Body
+				return result
+			}
+		}
+	}
+
+	return result
+}
+
 // Resolve replaces the placeholder parameters in the current email
 // template and returns its components as ready-to-use strings.
 func (t EmailTemplate) Resolve(placeholders map[string]any) (subject, body string) {
@@ -268,11 +415,104 @@ func (t EmailTemplate) Resolve(placeholders map[string]any) (subject, body strin
 	return subject, body
 }
 
+// LocalizedEmailTemplate defines a single localized [EmailTemplate] variant.
+type LocalizedEmailTemplate struct {
+	Subject string `form:"subject" json:"subject"`
+	Body    string `form:"body" json:"body"`
+}
+
+// Validate makes LocalizedEmailTemplate validatable by implementing [validation.Validatable] interface.
+func (t LocalizedEmailTemplate) Validate() error {
+	return validation.ValidateStruct(&t,
+		validation.Field(&t.Subject, validation.Required),
+		validation.Field(&t.Body, validation.Required),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// EmailLocaleConfig defines how the locale of the auth record is resolved
+// when sending localized email templates.
+//
+// The locales are tried in the following order:
+//   - the value of the auth record Field (if set)
+//   - the Accept-Language locales of the request that triggered the email (if any)
+//   - the Fallbacks locales
+//   - the default (non-localized) template
+type EmailLocaleConfig struct {
+	// Field is an optional text or select auth collection field name
+	// that holds the preferred locale of the auth record (ex. "pt-BR").
+	Field string `form:"field" json:"field"`
+
+	// Fallbacks is an optional list of locales to try (in order) when
+	// no variant for the auth record or request locale is found.
+	Fallbacks []string `form:"fallbacks" json:"fallbacks"`
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (c EmailLocaleConfig) MarshalJSON() ([]byte, error) {
+	type alias EmailLocaleConfig
+
+	// serialize as empty array
+	if c.Fallbacks == nil {
+		c.Fallbacks = []string{}
+	}
+
+	return json.Marshal(alias(c))
+}
+
+// Validate makes EmailLocaleConfig validatable by implementing [validation.Validatable] interface.
+func (c EmailLocaleConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Fallbacks, validation.Each(validation.By(checkLocale))),
+	)
+}
+
+// Locales returns the ordered list with the locales to try for the provided
+// auth record and request locales (aka. the template fallback chain).
+func (c EmailLocaleConfig) Locales(authRecord *Record, requestLocales ...string) []string {
+	result := make([]string, 0, 1+len(requestLocales)+len(c.Fallbacks))
+
+	if c.Field != "" {
+		if locale := authRecord.GetString(c.Field); locale != "" {
+			result = append(result, locale)
+		}
+	}
+
+	result = append(result, requestLocales...)
+	result = append(result, c.Fallbacks...)
+
+	return result
+}
+
+func checkLocale(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := language.Parse(v); err != nil {
+		return validation.NewError("validation_invalid_locale", "Invalid BCP 47 language tag.")
+	}
+
+	return nil
+}
+
 // -------------------------------------------------------------------
 
 type AuthAlertConfig struct {
-	Enabled       bool          `form:"enabled" json:"enabled"`
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// EmailTemplate is the email template that will be send to the auth record
+	// on login from a new location.
 	EmailTemplate EmailTemplate `form:"emailTemplate" json:"emailTemplate"`
+
+	// LockoutEmailTemplate is the email template that will be send to the auth record
+	// when it is temporary locked because of too many failed auth attempts.
+	//
+	// In addition to the system placeholders you can also make use of
+	// [core.EmailPlaceholderLockedUntil].
+	LockoutEmailTemplate EmailTemplate `form:"lockoutEmailTemplate" json:"lockoutEmailTemplate"`
 }
 
 // Validate makes AuthAlertConfig validatable by implementing [validation.Validatable] interface.
@@ -281,6 +521,7 @@ func (c AuthAlertConfig) Validate() error {
 		// note: for now always run the email template validations even
 		// if not enabled since it could be used separately
 		validation.Field(&c.EmailTemplate),
+		validation.Field(&c.LockoutEmailTemplate),
 	)
 }
 
@@ -376,6 +617,21 @@ type PasswordAuthConfig struct {
 	//
 	// Usually only fields that has single column UNIQUE index are accepted as values.
 	IdentityFields []string `form:"identityFields" json:"identityFields"`
+
+	// LockoutThreshold specifies the number of consecutive failed password
+	// or OTP auth attempts after which the auth record will be temporary locked.
+	//
+	// Set it to 0 to disable the lockout.
+	LockoutThreshold int `form:"lockoutThreshold" json:"lockoutThreshold"`
+
+	// LockoutDuration specifies how long the auth record to remain locked (in seconds).
+	LockoutDuration int64 `form:"lockoutDuration" json:"lockoutDuration"`
+
+	// LockoutMaxDuration is an optional max lockout duration (in seconds).
+	//
+	// If set, the duration of each consecutive lockout without a
+	// successful auth in between is doubled until LockoutMaxDuration.
+	LockoutMaxDuration int64 `form:"lockoutMaxDuration" json:"lockoutMaxDuration"`
 }
 
 // Validate makes PasswordAuthConfig validatable by implementing [validation.Validatable] interface.
@@ -383,15 +639,30 @@ func (c PasswordAuthConfig) Validate() error {
 	// strip duplicated values
 	c.IdentityFields = list.ToUniqueStringSlice(c.IdentityFields)
 
-	if !c.Enabled {
-		return nil // no need to validate
-	}
+	// note: the lockout options are validated even if the password auth
+	// is disabled because they are also applied to the OTP auth
+	lockoutEnabled := c.LockoutThreshold > 0
 
 	return validation.ValidateStruct(&c,
-		validation.Field(&c.IdentityFields, validation.Required),
+		validation.Field(&c.IdentityFields, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.LockoutThreshold, validation.Min(0), validation.Max(1000)),
+		validation.Field(&c.LockoutDuration, validation.When(lockoutEnabled, validation.Required, validation.Min(1), validation.Max(31536000))), // ~1y max
+		validation.Field(&c.LockoutMaxDuration, validation.When(lockoutEnabled, validation.Min(c.LockoutDuration), validation.Max(31536000))),
 	)
 }
 
+// LockoutDurationTime returns the lockout duration as [time.Duration]
+// based on the number of the previous consecutive lockouts.
+func (c PasswordAuthConfig) LockoutDurationTime(previousLockouts int) time.Duration {
+	duration := c.LockoutDuration
+
+	for i := 0; i < previousLockouts && duration < c.LockoutMaxDuration; i++ {
+		duration = min(duration*2, c.LockoutMaxDuration)
+	}
+
+	return time.Duration(duration) * time.Second
+}
+
 // -------------------------------------------------------------------
 
 type OAuth2KnownFields struct {
@@ -541,3 +812,83 @@ func (c OAuth2ProviderConfig) InitProvider() (auth.Provider, error) {
 
 	return provider, nil
 }
+
+// -------------------------------------------------------------------
+
+type SAMLConfig struct {
+	// MappedFields maps the assertion attribute names (or friendly names)
+	// to the auth collection field names that will be populated on record create.
+	MappedFields map[string]string `form:"mappedFields" json:"mappedFields"`
+
+	// EntityId is an optional service provider entity id
+	// (if not set, fallbacks to the collection SAML metadata url).
+	EntityId string `form:"entityId" json:"entityId"`
+
+	// IdPEntityId is the expected identity provider Issuer.
+	IdPEntityId string `form:"idpEntityId" json:"idpEntityId"`
+
+	// IdPSSOURL is the identity provider single sign-on url (HTTP-Redirect binding).
+	IdPSSOURL string `form:"idpSSOURL" json:"idpSSOURL"`
+
+	// IdPCertificate is the PEM (or raw base64 DER) encoded identity
+	// provider signing certificate(s).
+	IdPCertificate string `form:"idpCertificate" json:"idpCertificate"`
+
+	// EmailAttribute is the assertion attribute that holds the user email.
+	//
+	// If not set, the assertion NameID is used as email when its format is emailAddress.
+	EmailAttribute string `form:"emailAttribute" json:"emailAttribute"`
+
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// Validate makes SAMLConfig validatable by implementing [validation.Validatable] interface.
+func (c SAMLConfig) Validate() error {
+	if !c.Enabled {
+		return nil // no need to validate
+	}
+
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.IdPEntityId, validation.Required, validation.Length(1, 1000)),
+		validation.Field(&c.IdPSSOURL, validation.Required, is.URL),
+		validation.Field(&c.IdPCertificate, validation.Required, validation.By(checkSAMLCertificate)),
+		validation.Field(&c.EntityId, validation.Length(0, 1000)),
+	)
+}
+
+func checkSAMLCertificate(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := saml.ParseCertificates(v); err != nil {
+		return validation.NewError("validation_invalid_certificate", "Invalid or malformed certificate.")
+	}
+
+	return nil
+}
+
+// InitServiceProvider returns a new saml.ServiceProvider instance loaded
+// with the current SAMLConfig options.
+//
+// defaultEntityId is used as SP entity id in case c.EntityId is not set.
+func (c SAMLConfig) InitServiceProvider(defaultEntityId string, acsURL string) (*saml.ServiceProvider, error) {
+	certs, err := saml.ParseCertificates(c.IdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	entityId := c.EntityId
+	if entityId == "" {
+		entityId = defaultEntityId
+	}
+
+	return &saml.ServiceProvider{
+		EntityId:        entityId,
+		ACSURL:          acsURL,
+		IdPEntityId:     c.IdPEntityId,
+		IdPSSOURL:       c.IdPSSOURL,
+		IdPCertificates: certs,
+	}, nil
+}
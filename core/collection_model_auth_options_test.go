@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -154,6 +155,50 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			expectedErrors: []string{"oauth2"},
 		},
 
+		// saml
+		{
+			name: "trigger saml validations",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.SAML = core.SAMLConfig{
+					Enabled: true,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"saml"},
+		},
+		{
+			name: "saml with missing mapped field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.SAML = core.SAMLConfig{
+					Enabled:        true,
+					IdPEntityId:    "https://idp.example.com",
+					IdPSSOURL:      "https://idp.example.com/sso",
+					IdPCertificate: testSAMLCertificate,
+					MappedFields:   map[string]string{"displayName": "missing"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"saml"},
+		},
+		{
+			name: "saml with valid mapped field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.Fields.Add(&core.TextField{Name: "name"})
+				c.SAML = core.SAMLConfig{
+					Enabled:        true,
+					IdPEntityId:    "https://idp.example.com",
+					IdPSSOURL:      "https://idp.example.com/sso",
+					IdPCertificate: testSAMLCertificate,
+					MappedFields:   map[string]string{"displayName": "name"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+
 		// otp
 		{
 			name: "trigger otp validations",
@@ -205,6 +250,24 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			},
 			expectedErrors: []string{},
 		},
+		{
+			name: "mfa enabled with password and saml auth methods",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.MFA.Enabled = true
+				c.PasswordAuth.Enabled = true
+				c.OTP.Enabled = false
+				c.OAuth2.Enabled = false
+				c.SAML = core.SAMLConfig{
+					Enabled:        true,
+					IdPEntityId:    "https://idp.example.com",
+					IdPSSOURL:      "https://idp.example.com/sso",
+					IdPCertificate: testSAMLCertificate,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
 		{
 			name: "mfa disabled with invalid rule",
 			collection: func(app core.App) (*core.Collection, error) {
@@ -317,6 +380,46 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			},
 			expectedErrors: []string{"confirmEmailChangeTemplate"},
 		},
+
+		// emailLocale
+		{
+			name: "trigger emailLocale validations",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.EmailLocale.Fallbacks = []string{"invalid locale"}
+				return c, nil
+			},
+			expectedErrors: []string{"emailLocale"},
+		},
+		{
+			name: "missing emailLocale.field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.EmailLocale.Field = "missing"
+				return c, nil
+			},
+			expectedErrors: []string{"emailLocale"},
+		},
+		{
+			name: "non-text emailLocale.field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.EmailLocale.Field = "verified"
+				return c, nil
+			},
+			expectedErrors: []string{"emailLocale"},
+		},
+		{
+			name: "valid emailLocale.field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.Fields.Add(&core.SelectField{Name: "locale", Values: []string{"en", "de"}, MaxSelect: 1})
+				c.EmailLocale.Field = "locale"
+				c.EmailLocale.Fallbacks = []string{"en"}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -355,6 +458,40 @@ func TestEmailTemplateValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid locales",
+			core.EmailTemplate{
+				Subject: "a",
+				Body:    "b",
+				Locales: map[string]core.LocalizedEmailTemplate{
+					"invalid locale": {Subject: "a", Body: "b"},
+				},
+			},
+			[]string{"locales"},
+		},
+		{
+			"empty locale variant",
+			core.EmailTemplate{
+				Subject: "a",
+				Body:    "b",
+				Locales: map[string]core.LocalizedEmailTemplate{
+					"de": {},
+				},
+			},
+			[]string{"locales"},
+		},
+		{
+			"valid locales",
+			core.EmailTemplate{
+				Subject: "a",
+				Body:    "b",
+				Locales: map[string]core.LocalizedEmailTemplate{
+					"de":    {Subject: "c", Body: "d"},
+					"pt-BR": {Subject: "e", Body: "f"},
+				},
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -417,6 +554,140 @@ func TestEmailTemplateResolve(t *testing.T) {
 	}
 }
 
+func TestEmailTemplateLocalize(t *testing.T) {
+	template := core.EmailTemplate{
+		Subject: "en_subject",
+		Body:    "en_body",
+		Locales: map[string]core.LocalizedEmailTemplate{
+			"de":    {Subject: "de_subject", Body: "de_body"},
+			"pt":    {Subject: "pt_subject", Body: "pt_body"},
+			"pt-br": {Subject: "pt_br_subject", Body: "pt_br_body"},
+		},
+	}
+
+	scenarios := []struct {
+		locales         []string
+		expectedSubject string
+	}{
+		{nil, "en_subject"},
+		{[]string{"fr"}, "en_subject"},
+		{[]string{"invalid locale"}, "en_subject"},
+		{[]string{"de"}, "de_subject"},
+		{[]string{"DE-at"}, "de_subject"},
+		{[]string{"pt-BR"}, "pt_br_subject"},
+		{[]string{"pt-PT"}, "pt_subject"},
+		{[]string{"fr", "invalid locale", "pt-PT", "de"}, "pt_subject"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(strings.Join(s.locales, "_"), func(t *testing.T) {
+			result := template.Localize(s.locales...)
+
+			if result.Subject != s.expectedSubject {
+				t.Fatalf("Expected subject %q, got %q", s.expectedSubject, result.Subject)
+			}
+
+			expectedBody := strings.TrimSuffix(s.expectedSubject, "_subject") + "_body"
+			if result.Body != expectedBody {
+				t.Fatalf("Expected body %q, got %q", expectedBody, result.Body)
+			}
+
+			if len(result.Locales) != 0 {
+				t.Fatalf("Expected no locales, got %v", result.Locales)
+			}
+		})
+	}
+}
+
+func TestEmailLocaleConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.EmailLocaleConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.EmailLocaleConfig{},
+			[]string{},
+		},
+		{
+			"invalid fallbacks",
+			core.EmailLocaleConfig{Fallbacks: []string{"en", "invalid locale"}},
+			[]string{"fallbacks"},
+		},
+		{
+			"valid fallbacks",
+			core.EmailLocaleConfig{Fallbacks: []string{"en", "pt-BR"}},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestEmailLocaleConfigLocales(t *testing.T) {
+	collection := core.NewAuthCollection("test")
+	collection.Fields.Add(&core.TextField{Name: "locale"})
+
+	record := core.NewRecord(collection)
+	record.Set("locale", "bg")
+
+	emptyRecord := core.NewRecord(collection)
+
+	scenarios := []struct {
+		name           string
+		config         core.EmailLocaleConfig
+		record         *core.Record
+		requestLocales []string
+		expected       []string
+	}{
+		{
+			"zero value",
+			core.EmailLocaleConfig{},
+			record,
+			nil,
+			[]string{},
+		},
+		{
+			"request locales only",
+			core.EmailLocaleConfig{},
+			record,
+			[]string{"de", "en"},
+			[]string{"de", "en"},
+		},
+		{
+			"all sources",
+			core.EmailLocaleConfig{Field: "locale", Fallbacks: []string{"en"}},
+			record,
+			[]string{"de"},
+			[]string{"bg", "de", "en"},
+		},
+		{
+			"empty record locale",
+			core.EmailLocaleConfig{Field: "locale", Fallbacks: []string{"en"}},
+			emptyRecord,
+			[]string{"de"},
+			[]string{"de", "en"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Locales(s.record, s.requestLocales...)
+
+			if !slices.Equal(result, s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
 func TestTokenConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -484,24 +755,26 @@ func TestAuthAlertConfigValidate(t *testing.T) {
 		{
 			"zero value (disabled)",
 			core.AuthAlertConfig{},
-			[]string{"emailTemplate"},
+			[]string{"emailTemplate", "lockoutEmailTemplate"},
 		},
 		{
 			"zero value (enabled)",
 			core.AuthAlertConfig{Enabled: true},
-			[]string{"emailTemplate"},
+			[]string{"emailTemplate", "lockoutEmailTemplate"},
 		},
 		{
 			"invalid template",
 			core.AuthAlertConfig{
-				EmailTemplate: core.EmailTemplate{Body: "", Subject: "b"},
+				EmailTemplate:        core.EmailTemplate{Body: "", Subject: "b"},
+				LockoutEmailTemplate: core.EmailTemplate{Body: "a", Subject: ""},
 			},
-			[]string{"emailTemplate"},
+			[]string{"emailTemplate", "lockoutEmailTemplate"},
 		},
 		{
 			"valid data",
 			core.AuthAlertConfig{
-				EmailTemplate: core.EmailTemplate{Body: "a", Subject: "b"},
+				EmailTemplate:        core.EmailTemplate{Body: "a", Subject: "b"},
+				LockoutEmailTemplate: core.EmailTemplate{Body: "c", Subject: "d"},
 			},
 			[]string{},
 		},
@@ -701,6 +974,31 @@ func TestPasswordAuthConfigValidate(t *testing.T) {
 			core.PasswordAuthConfig{Enabled: true, IdentityFields: []string{"abc"}},
 			[]string{},
 		},
+		{
+			"negative lockout threshold",
+			core.PasswordAuthConfig{LockoutThreshold: -1},
+			[]string{"lockoutThreshold"},
+		},
+		{
+			"enabled lockout with zero duration (disabled password auth)",
+			core.PasswordAuthConfig{LockoutThreshold: 5},
+			[]string{"lockoutDuration"},
+		},
+		{
+			"enabled lockout with max duration < duration",
+			core.PasswordAuthConfig{LockoutThreshold: 5, LockoutDuration: 60, LockoutMaxDuration: 59},
+			[]string{"lockoutMaxDuration"},
+		},
+		{
+			"enabled lockout with too large durations",
+			core.PasswordAuthConfig{LockoutThreshold: 5, LockoutDuration: 31536001, LockoutMaxDuration: 31536001},
+			[]string{"lockoutDuration", "lockoutMaxDuration"},
+		},
+		{
+			"valid lockout data",
+			core.PasswordAuthConfig{Enabled: true, IdentityFields: []string{"abc"}, LockoutThreshold: 5, LockoutDuration: 60, LockoutMaxDuration: 3600},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -712,6 +1010,32 @@ func TestPasswordAuthConfigValidate(t *testing.T) {
 	}
 }
 
+func TestPasswordAuthConfigLockoutDurationTime(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		config           core.PasswordAuthConfig
+		previousLockouts int
+		expected         time.Duration
+	}{
+		{"zero value", core.PasswordAuthConfig{}, 0, 0},
+		{"no max duration", core.PasswordAuthConfig{LockoutDuration: 60}, 3, 60 * time.Second},
+		{"first lockout", core.PasswordAuthConfig{LockoutDuration: 60, LockoutMaxDuration: 600}, 0, 60 * time.Second},
+		{"second lockout", core.PasswordAuthConfig{LockoutDuration: 60, LockoutMaxDuration: 600}, 1, 120 * time.Second},
+		{"third lockout", core.PasswordAuthConfig{LockoutDuration: 60, LockoutMaxDuration: 600}, 2, 240 * time.Second},
+		{"capped lockout", core.PasswordAuthConfig{LockoutDuration: 60, LockoutMaxDuration: 600}, 10, 600 * time.Second},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.LockoutDurationTime(s.previousLockouts)
+
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
 func TestOAuth2ConfigGetProviderConfig(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -1024,3 +1348,116 @@ func TestOAuth2ProviderConfigInitProvider(t *testing.T) {
 		})
 	}
 }
+
+// testSAMLCertificate is a self-signed "test idp" certificate used only for the SAML config tests.
+const testSAMLCertificate = `-----BEGIN CERTIFICATE-----
+MIIDCTCCAfGgAwIBAgIUeIv/P0nzko6v6VNmNTXQEvI9MbUwDQYJKoZIhvcNAQEL
+BQAwEzERMA8GA1UEAwwIdGVzdCBpZHAwIBcNMjYxMDE5MDU1MTUyWhgPMjEyNjA5
+MjUwNTUxNTJaMBMxETAPBgNVBAMMCHRlc3QgaWRwMIIBIjANBgkqhkiG9w0BAQEF
+AAOCAQ8AMIIBCgKCAQEAuMTT2F7kKmulB154Z7mwf1FzH7VRjxrUSttPrSDGRp/m
+U42nDmBvhphJXL+hRKdMMs/lZBgWXVRs+cnqL4Bk0Awk4K3r6c4f2F0ouKXdU14U
+RwSfcqsoN8aAO0Me6mogl76HSN/WSyTL+aFsNOp4sfXOj0FNEM7OcRsOAo1YUfES
+O6SOwTsT0SqhWNmU/qG+aj+xjEBgR761XVFqwuzuOA4BxvjBwVJ8ZxC5VWLTe0RK
+QDHWg+HZU95fpvF7PBPwFz0px6mDAUngBm3VM3F+v8fXiPDWYpAjtANUb4XOuYE+
+hYdZHn5ODyA2Y35elfUhA3bF8R46LRNuRMSoFyMqMwIDAQABo1MwUTAdBgNVHQ4E
+FgQUOyUF7uzVyuWqyEKLZrC49NzYjxAwHwYDVR0jBBgwFoAUOyUF7uzVyuWqyEKL
+ZrC49NzYjxAwDwYDVR0TAQH/BAUwAwEB/zANBgkqhkiG9w0BAQsFAAOCAQEArlxN
+jcy/V1TY80fjzio6pnrrZw7csz7xCgI1KozAJznWWQtz+cngGyflWEb7yLi+xo7u
+LSHWEa/PxBxPXjc4Vo6xHTLOwLoeVJMQJrLsEMzo+aWCiLyOKnI5extfyFevhlRT
+SaFXEhGou3rQG/cF3vbcnexl2cfkbNIYywBx6SvQ7HJq4tvog0+WM1Qy5rdJ2zrd
+lF1fBFEic14A6DHkJrINC31dyRDTHGtFe+TGSoYPRhtPs4uz1BlHTj4leszxjZfo
+2/kVEXGrDQzngmpgxg/i47AVutd3Zi3yVd1sg4G+gWqWqFCEzgaNgwCP1h1mEViJ
+ZxNlNq99KmLrqdyGNA==
+-----END CERTIFICATE-----`
+
+func TestSAMLConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.SAMLConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value (disabled)",
+			core.SAMLConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.SAMLConfig{Enabled: true},
+			[]string{"idpEntityId", "idpSSOURL", "idpCertificate"},
+		},
+		{
+			"invalid data",
+			core.SAMLConfig{
+				Enabled:        true,
+				IdPEntityId:    "https://idp.example.com",
+				IdPSSOURL:      "!invalid!",
+				IdPCertificate: "invalid",
+			},
+			[]string{"idpSSOURL", "idpCertificate"},
+		},
+		{
+			"valid data",
+			core.SAMLConfig{
+				Enabled:        true,
+				IdPEntityId:    "https://idp.example.com",
+				IdPSSOURL:      "https://idp.example.com/sso",
+				IdPCertificate: testSAMLCertificate,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestSAMLConfigInitServiceProvider(t *testing.T) {
+	t.Run("invalid certificate", func(t *testing.T) {
+		config := core.SAMLConfig{IdPCertificate: "invalid"}
+
+		if _, err := config.InitServiceProvider("a", "b"); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("default entity id", func(t *testing.T) {
+		config := core.SAMLConfig{
+			IdPEntityId:    "test_idp",
+			IdPSSOURL:      "test_sso",
+			IdPCertificate: testSAMLCertificate,
+		}
+
+		sp, err := config.InitServiceProvider("default_entity", "test_acs")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sp.EntityId != "default_entity" || sp.ACSURL != "test_acs" ||
+			sp.IdPEntityId != "test_idp" || sp.IdPSSOURL != "test_sso" ||
+			len(sp.IdPCertificates) != 1 {
+			t.Fatalf("Unexpected service provider %#v", sp)
+		}
+	})
+
+	t.Run("custom entity id", func(t *testing.T) {
+		config := core.SAMLConfig{
+			EntityId:       "custom_entity",
+			IdPCertificate: testSAMLCertificate,
+		}
+
+		sp, err := config.InitServiceProvider("default_entity", "test_acs")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sp.EntityId != "custom_entity" {
+			t.Fatalf("Expected entity id %q, got %q", "custom_entity", sp.EntityId)
+		}
+	})
+}
@@ -2,11 +2,12 @@ package core
 
 // Common settings placeholder tokens
 const (
-	EmailPlaceholderAppName string = "{APP_NAME}"
-	EmailPlaceholderAppURL  string = "{APP_URL}"
-	EmailPlaceholderToken   string = "{TOKEN}"
-	EmailPlaceholderOTP     string = "{OTP}"
-	EmailPlaceholderOTPId   string = "{OTP_ID}"
+	EmailPlaceholderAppName     string = "{APP_NAME}"
+	EmailPlaceholderAppURL      string = "{APP_URL}"
+	EmailPlaceholderToken       string = "{TOKEN}"
+	EmailPlaceholderOTP         string = "{OTP}"
+	EmailPlaceholderOTPId       string = "{OTP_ID}"
+	EmailPlaceholderLockedUntil string = "{LOCKED_UNTIL}"
 )
 
 var defaultVerificationTemplate = EmailTemplate{
@@ -73,3 +74,14 @@ var defaultAuthAlertTemplate = EmailTemplate{
   ` + EmailPlaceholderAppName + ` team
 </p>`,
 }
+
+var defaultAuthLockoutTemplate = EmailTemplate{
+	Subject: "Your account has been temporary locked",
+	Body: `<p>Hello,</p>
+<p>Your ` + EmailPlaceholderAppName + ` account has been temporary locked until ` + EmailPlaceholderLockedUntil + ` because of too many failed login attempts.</p>
+<p>If this wasn't you, someone may be trying to guess your password and you should consider changing it once the lock expires.</p>
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+}
@@ -1,11 +1,134 @@
 package core
 
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
 var _ optionsValidator = (*collectionBaseOptions)(nil)
 
-// collectionBaseOptions defines the options for the "base" type collection.
+// collectionBaseOptions defines the options for the "base" type collection
+// (they are also available for the "auth" type collections).
 type collectionBaseOptions struct {
+	// Versioning enables the records history tracking.
+	//
+	// When enabled, a snapshot of the previous values of the changed
+	// record fields is stored in the "_recordVersions" collection
+	// after each successful record update or delete.
+	Versioning bool `form:"versioning" json:"versioning"`
+
+	// SoftDelete specifies the collection records soft delete options.
+	SoftDelete SoftDeleteConfig `form:"softDelete" json:"softDelete"`
+
+	// TenantField specifies the name of an optional text or single
+	// relation field used to scope the collection records per tenant.
+	//
+	// When set, the records API requests of the non-superuser auth records
+	// are restricted (together with the collection API rules) only to the
+	// records which TenantField value matches the value of the same named
+	// field of the requesting auth record, and the created records are
+	// automatically stamped with the requesting auth record tenant.
+	//
+	// Guest requests and auth records without tenant are not allowed
+	// to access any of the collection records (see also TenantGuestAccess).
+	TenantField string `form:"tenantField" json:"tenantField"`
+
+	// TenantGuestAccess explicitly allows guest requests to access
+	// the records of all tenants (subject only to the collection API rules).
+	TenantGuestAccess bool `form:"tenantGuestAccess" json:"tenantGuestAccess"`
+
+	// Constraints specifies the collection records unique and check constraints.
+	Constraints []CollectionConstraint `form:"constraints" json:"constraints"`
 }
 
 func (o *collectionBaseOptions) validate(cv *collectionValidator) error {
-	return nil
+	return validation.ValidateStruct(o,
+		validation.Field(
+			&o.Versioning,
+			validation.When(
+				cv.new.Name == CollectionNameRecordVersions,
+				validation.Empty.Error("The record versions collection cannot be versioned."),
+			),
+		),
+		validation.Field(&o.SoftDelete, validation.By(o.checkSoftDelete(cv))),
+		validation.Field(&o.TenantField, validation.By(o.checkTenantField(cv))),
+		validation.Field(&o.Constraints, validation.By(cv.checkConstraints)),
+	)
+}
+
+func (o *collectionBaseOptions) checkSoftDelete(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		if o.SoftDelete.Enabled && cv.new.System {
+			return validation.Errors{"enabled": validation.NewError(
+				"validation_soft_delete_system_collection",
+				"System collections cannot have soft delete.",
+			)}
+		}
+
+		return nil
+	}
+}
+
+func (o *collectionBaseOptions) checkTenantField(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil
+		}
+
+		if v != FieldNameId {
+			switch f := cv.new.Fields.GetByName(v).(type) {
+			case *TextField:
+				return nil
+			case *RelationField:
+				if !f.IsMultiple() {
+					return nil
+				}
+			}
+		}
+
+		return validation.NewError(
+			"validation_invalid_tenant_field",
+			"The tenant field must be an existing text or single relation collection field.",
+		)
+	}
+}
+
+// -------------------------------------------------------------------
+
+// SoftDeleteConfig defines the collection soft delete options.
+type SoftDeleteConfig struct {
+	// Enabled instructs App.Delete to only mark the collection records
+	// as deleted (by setting their "deleted" autodate field) instead of
+	// removing them from the database.
+	//
+	// The soft deleted records are excluded from the default record queries.
+	//
+	// Note that the soft deleted records are still part of the collection
+	// unique indexes and constraints, aka. their unique values (e.g. the auth
+	// record email) remain reserved until the record is restored or permanently
+	// deleted and reusing them fails with the regular "validation_not_unique" error.
+	// To allow reusing the values, the unique index (or constraint) could be
+	// limited to the active records with "WHERE deleted = ''", in which case
+	// the restore of a record with already taken value fails with the same error.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Retention specifies how long (in seconds) the soft deleted records
+	// are kept before permanently deleting them (including their files).
+	//
+	// Zero value means that the soft deleted records are kept until explicitly purged.
+	Retention int64 `form:"retention" json:"retention"`
+}
+
+// Validate makes SoftDeleteConfig validatable by implementing [validation.Validatable] interface.
+func (c SoftDeleteConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Retention, validation.Min(0), validation.Max(315569520)), // ~10y max
+	)
+}
+
+// RetentionTime returns the current Retention as [time.Duration].
+func (c SoftDeleteConfig) RetentionTime() time.Duration {
+	return time.Duration(c.Retention) * time.Second
 }
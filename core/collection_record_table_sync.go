@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"log/slog"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -35,6 +36,9 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 
 			// add fields definition
 			for _, field := range fields {
+				if isVirtualField(field) {
+					continue
+				}
 				cols[field.GetName()] = field.ColumnType(app)
 			}
 
@@ -43,7 +47,11 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 				return err
 			}
 
-			return createCollectionIndexes(txApp, newCollection)
+			if err := createCollectionIndexes(txApp, newCollection); err != nil {
+				return err
+			}
+
+			return createCollectionConstraints(txApp, newCollection)
 		}
 
 		// update
@@ -62,6 +70,12 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			needIndexesUpdate = true
 		}
 
+		// note: the constraints are rebuilt only when necessary because
+		// the creation of a check constraint verifies all existing records
+		needConstraintsUpdate := needTableRename ||
+			!reflect.DeepEqual(oldCollection.Constraints, newCollection.Constraints) ||
+			hasConstraintFieldsChanges(txApp, newCollection, oldCollection)
+
 		if needIndexesUpdate {
 			// drop old indexes (if any)
 			if err := dropCollectionIndexes(txApp, oldCollection); err != nil {
@@ -69,6 +83,13 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			}
 		}
 
+		if needConstraintsUpdate {
+			// drop old constraints (if any)
+			if err := dropCollectionConstraints(txApp, oldCollection); err != nil {
+				return err
+			}
+		}
+
 		// check for renamed table
 		if needTableRename {
 			_, err := txApp.DB().RenameTable("{{"+oldTableName+"}}", "{{"+newTableName+"}}").Execute()
@@ -77,10 +98,35 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			}
 		}
 
+		// drop the generated columns that are deleted or need to be recreated
+		//
+		// note: the generated columns are dropped first and readded last because
+		// their expression could reference the other deleted or changed columns
+		recreateGenerated := map[string]struct{}{}
+		needAllGeneratedRecreate := hasNonGeneratedColumnChanges(txApp, newFields, oldFields)
+		for _, oldField := range oldFields {
+			if !isGeneratedField(oldField) {
+				continue
+			}
+
+			newField := newFields.GetById(oldField.GetId())
+			if newField != nil {
+				if !needAllGeneratedRecreate && newField.ColumnType(txApp) == oldField.ColumnType(txApp) {
+					continue // no change
+				}
+				recreateGenerated[newField.GetId()] = struct{}{}
+			}
+
+			_, err := txApp.DB().DropColumn(newTableName, oldField.GetName()).Execute()
+			if err != nil {
+				return fmt.Errorf("failed to drop column %s - %w", oldField.GetName(), err)
+			}
+		}
+
 		// check for deleted columns
 		for _, oldField := range oldFields {
-			if f := newFields.GetById(oldField.GetId()); f != nil {
-				continue // exist
+			if f := newFields.GetById(oldField.GetId()); f != nil || isGeneratedField(oldField) || isVirtualField(oldField) {
+				continue // exist, already dropped or without column
 			}
 
 			_, err := txApp.DB().DropColumn(newTableName, oldField.GetName()).Execute()
@@ -91,7 +137,12 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 
 		// check for new or renamed columns
 		toRename := map[string]string{}
+		toAddGenerated := []Field{}
 		for _, field := range newFields {
+			if isVirtualField(field) {
+				continue // no column
+			}
+
 			oldField := oldFields.GetById(field.GetId())
 			// Note:
 			// We are using a temporary column name when adding or renaming columns
@@ -99,6 +150,13 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			// names switch/reuse of existing columns (eg. name, title -> title, name).
 			// This way we are always doing 1 more rename operation but it provides better less ambiguous experience.
 
+			if isGeneratedField(field) {
+				if _, ok := recreateGenerated[field.GetId()]; ok || oldField == nil {
+					toAddGenerated = append(toAddGenerated, field)
+					continue
+				}
+			}
+
 			if oldField == nil {
 				tempName := field.GetName() + security.PseudorandomString(5)
 				toRename[tempName] = field.GetName()
@@ -132,8 +190,22 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			return err
 		}
 
+		// add the new or recreated generated columns
+		for _, field := range toAddGenerated {
+			_, err := txApp.DB().AddColumn(newTableName, field.GetName(), field.ColumnType(txApp)).Execute()
+			if err != nil {
+				return fmt.Errorf("failed to add column %s - %w", field.GetName(), err)
+			}
+		}
+
 		if needIndexesUpdate {
-			return createCollectionIndexes(txApp, newCollection)
+			if err := createCollectionIndexes(txApp, newCollection); err != nil {
+				return err
+			}
+		}
+
+		if needConstraintsUpdate {
+			return createCollectionConstraints(txApp, newCollection)
 		}
 
 		return nil
@@ -152,6 +224,37 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 	return nil
 }
 
+func isGeneratedField(field Field) bool {
+	g, ok := field.(GeneratedValuer)
+
+	return ok && g.IsGenerated()
+}
+
+func isVirtualField(field Field) bool {
+	v, ok := field.(VirtualValuer)
+
+	return ok && v.IsVirtual()
+}
+
+// hasNonGeneratedColumnChanges checks whether any of the old non-generated
+// fields was deleted, renamed or has a different column definition.
+func hasNonGeneratedColumnChanges(app App, newFields FieldsList, oldFields FieldsList) bool {
+	for _, oldField := range oldFields {
+		if isGeneratedField(oldField) || isVirtualField(oldField) {
+			continue
+		}
+
+		newField := newFields.GetById(oldField.GetId())
+		if newField == nil ||
+			newField.GetName() != oldField.GetName() ||
+			newField.ColumnType(app) != oldField.ColumnType(app) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func normalizeSingleVsMultipleFieldChanges(app App, newCollection *Collection, oldCollection *Collection) error {
 	if newCollection.IsView() || oldCollection == nil {
 		return nil // view or not an update
@@ -159,6 +262,10 @@ func normalizeSingleVsMultipleFieldChanges(app App, newCollection *Collection, o
 
 	return app.RunInTransaction(func(txApp App) error {
 		for _, newField := range newCollection.Fields {
+			if isVirtualField(newField) {
+				continue // no column
+			}
+
 			// allow to continue even if there is no old field for the cases
 			// when a new field is added and there are already inserted data
 			var isOldMultiple bool
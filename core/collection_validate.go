@@ -122,6 +122,7 @@ func (validator *collectionValidator) run() error {
 			validation.When(
 				!validator.new.IsView(),
 				validation.By(validator.ensureNoSystemFieldsChange),
+				validation.By(validator.ensureNoConstraintFieldsChange),
 				validation.By(validator.ensureNoFieldsTypeChange),
 			),
 			validation.When(validator.new.IsAuth(), validation.By(validator.checkReservedAuthKeys)),
@@ -443,6 +444,35 @@ func (validator *collectionValidator) ensureNoSystemFieldsChange(value any) erro
 	return nil
 }
 
+// ensureNoConstraintFieldsChange checks that the fields referenced
+// by the collection constraints are not deleted or renamed.
+func (validator *collectionValidator) ensureNoConstraintFieldsChange(value any) error {
+	fields, ok := value.(FieldsList)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	if validator.original.IsNew() {
+		return nil // not an update
+	}
+
+	for _, c := range validator.new.Constraints {
+		for _, name := range c.fieldNames() {
+			oldField := validator.original.Fields.GetByName(name)
+			if oldField == nil || fields.GetByName(name) != nil {
+				continue // new or still existing field
+			}
+
+			return validation.NewError(
+				"validation_constraint_field_change",
+				"Field {{.fieldName}} is referenced by the {{.constraintName}} constraint and cannot be deleted or renamed without updating the constraint.",
+			).SetParams(map[string]any{"fieldName": name, "constraintName": c.Name})
+		}
+	}
+
+	return nil
+}
+
 func (cv *collectionValidator) checkFieldsForUniqueIndex(value any) error {
 	names, ok := value.([]string)
 	if !ok {
@@ -682,13 +712,158 @@ func (cv *collectionValidator) checkIndexes(value any) error {
 	return nil
 }
 
+func (cv *collectionValidator) checkConstraints(value any) error {
+	constraints, ok := value.([]CollectionConstraint)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	if len(constraints) == 0 {
+		return nil // nothing to check
+	}
+
+	names := make(map[string]struct{}, len(constraints))
+
+	errs := validation.Errors{}
+
+	for i, c := range constraints {
+		isUnique := c.Type == CollectionConstraintTypeUnique
+		isCheck := c.Type == CollectionConstraintTypeCheck
+
+		err := validation.ValidateStruct(&c,
+			validation.Field(
+				&c.Name,
+				validation.Required,
+				validation.Length(1, 100),
+				validation.Match(collectionNameRegex),
+				validation.By(func(value any) error {
+					key := strings.ToLower(c.Name)
+					if _, ok := names[key]; ok {
+						return validation.NewError("validation_duplicated_constraint_name", "The constraint name must be unique.")
+					}
+					names[key] = struct{}{}
+					return nil
+				}),
+			),
+			validation.Field(
+				&c.Type,
+				validation.Required,
+				validation.In(CollectionConstraintTypeUnique, CollectionConstraintTypeCheck),
+			),
+			validation.Field(
+				&c.Fields,
+				validation.When(isUnique, validation.Required, validation.By(cv.checkConstraintFields)),
+				validation.When(!isUnique, validation.Empty),
+			),
+			validation.Field(
+				&c.Where,
+				validation.When(isUnique, validation.By(cv.checkConstraintExpression)),
+				validation.When(!isUnique, validation.Empty),
+			),
+			validation.Field(
+				&c.Expression,
+				validation.When(isCheck, validation.Required, validation.By(cv.checkConstraintExpression)),
+				validation.When(!isCheck, validation.Empty),
+			),
+			validation.Field(&c.Message, validation.Length(0, 255)),
+		)
+		if err != nil {
+			errs[strconv.Itoa(i)] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// checkConstraintField checks whether the provided field name
+// could be used in a collection constraint.
+func (cv *collectionValidator) checkConstraintField(name string) error {
+	field := cv.new.Fields.GetByName(name)
+	if field == nil {
+		return fmt.Errorf("unknown field %q", name)
+	}
+
+	if isVirtualField(field) {
+		return fmt.Errorf("virtual fields cannot be referenced (%q)", name)
+	}
+
+	if field.Type() == FieldTypePassword {
+		return fmt.Errorf("password fields cannot be referenced (%q)", name)
+	}
+
+	if isEncryptedField(field) {
+		return fmt.Errorf("encrypted fields cannot be referenced (%q)", name)
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkConstraintFields(value any) error {
+	names, ok := value.([]string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	unique := make(map[string]struct{}, len(names))
+
+	for i, name := range names {
+		if err := cv.checkConstraintField(name); err != nil {
+			return validation.Errors{
+				strconv.Itoa(i): validation.NewError("validation_invalid_constraint_field", err.Error()),
+			}
+		}
+
+		key := strings.ToLower(name)
+		if _, ok := unique[key]; ok {
+			return validation.Errors{
+				strconv.Itoa(i): validation.NewError("validation_duplicated_constraint_field", "Duplicated constraint field."),
+			}
+		}
+		unique[key] = struct{}{}
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkConstraintExpression(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	_, err := search.FormulaData(v).BuildSQL(func(name string) (string, error) {
+		if err := cv.checkConstraintField(name); err != nil {
+			return "", err
+		}
+
+		// the generated columns value is not available in the BEFORE triggers
+		if isGeneratedField(cv.new.Fields.GetByName(name)) {
+			return "", fmt.Errorf("generated fields cannot be referenced (%q)", name)
+		}
+
+		return "[[" + name + "]]", nil
+	})
+	if err != nil {
+		return validation.NewError("validation_invalid_constraint_expression", "Invalid constraint expression - "+err.Error()+".")
+	}
+
+	return nil
+}
+
 func (validator *collectionValidator) validateOptions() error {
 	switch validator.new.Type {
 	case CollectionTypeAuth:
-		return validator.new.collectionAuthOptions.validate(validator)
+		return validators.JoinValidationErrors(
+			validator.new.collectionBaseOptions.validate(validator),
+			validator.new.collectionAuthOptions.validate(validator),
+		)
 	case CollectionTypeView:
 		return validator.new.collectionViewOptions.validate(validator)
+	default:
+		return validator.new.collectionBaseOptions.validate(validator)
 	}
-
-	return nil
 }
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*CronRun)(nil)
+
+const CronRunsTableName = "_cronRuns"
+
+// CronRun defines a single cron job run history entry.
+type CronRun struct {
+	BaseModel
+
+	JobId string `db:"jobId" json:"jobId"`
+
+	// Status is the run outcome (could be any of the cron.RunStatus* constants).
+	Status string `db:"status" json:"status"`
+
+	Error    string         `db:"error" json:"error"`
+	Started  types.DateTime `db:"started" json:"started"`
+	Finished types.DateTime `db:"finished" json:"finished"`
+
+	// Duration is the run duration in milliseconds.
+	Duration float64 `db:"duration" json:"duration"`
+
+	Created types.DateTime `db:"created" json:"created"`
+}
+
+func (m *CronRun) TableName() string {
+	return CronRunsTableName
+}
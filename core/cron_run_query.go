@@ -0,0 +1,54 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindCronRunById finds a single CronRun entry by its id.
+func (app *BaseApp) FindCronRunById(id string) (*CronRun, error) {
+	model := &CronRun{}
+
+	err := app.AuxModelQuery(model).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindLatestCronRun finds the most recently started run of the specified cron job.
+func (app *BaseApp) FindLatestCronRun(jobId string) (*CronRun, error) {
+	model := &CronRun{}
+
+	err := app.AuxModelQuery(model).
+		AndWhere(dbx.HashExp{"jobId": jobId}).
+		OrderBy("started DESC", "rowid DESC").
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// DeleteOldCronRuns deletes all cron run entries that are created before createdBefore.
+//
+// For better performance the delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteOldCronRuns(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := app.AuxNonconcurrentDB().Delete(CronRunsTableName, expr).Execute()
+
+	return err
+}
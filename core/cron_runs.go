@@ -0,0 +1,62 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// cronRunsMaxAge is the max age of the stored cron run history entries.
+const cronRunsMaxAge = 7 * 24 * time.Hour
+
+func (app *BaseApp) registerCronRunsHooks() {
+	app.Cron().SetResultHandler(app.saveCronRun)
+
+	// periodically cleanup the old cron run entries
+	app.Cron().Add("__pbCronRunsCleanup__", "20 */6 * * *", func() {
+		err := app.DeleteOldCronRuns(time.Now().Add(-cronRunsMaxAge))
+		if err != nil {
+			app.Logger().Warn("Failed to delete old cron runs", "error", err)
+		}
+	})
+}
+
+// saveCronRun persists the provided cron job run result in the run history table.
+func (app *BaseApp) saveCronRun(result *cron.RunResult) {
+	if !app.IsBootstrapped() {
+		return // e.g. a manually triggered run after the app termination
+	}
+
+	run := &CronRun{
+		JobId:    result.JobId,
+		Status:   result.Status,
+		Duration: float64(result.End.Sub(result.Start)) / float64(time.Millisecond),
+		Created:  types.NowDateTime(),
+	}
+	run.Id = GenerateDefaultRandomId()
+	run.Started, _ = types.ParseDateTime(result.Start)
+	run.Finished, _ = types.ParseDateTime(result.End)
+
+	if result.Error != nil {
+		run.Error = result.Error.Error()
+	}
+
+	if err := app.AuxSave(run); err != nil {
+		app.Logger().Warn(
+			"Failed to save cron run",
+			"error", err,
+			"jobId", result.JobId,
+			"status", result.Status,
+		)
+	}
+
+	if result.Status == cron.RunStatusError || result.Status == cron.RunStatusTimeout {
+		app.Logger().Warn(
+			"Cron job failed",
+			"error", run.Error,
+			"jobId", result.JobId,
+			"status", result.Status,
+		)
+	}
+}
@@ -0,0 +1,128 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestCronRunsHistory(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if _, err := app.FindLatestCronRun("test"); err == nil {
+		t.Fatal("Expected no cron runs")
+	}
+
+	app.Cron().MustAddWithOptions("test", "* * * * *", func(ctx context.Context) error {
+		return errors.New("test_error")
+	}, cron.JobOptions{})
+
+	for _, j := range app.Cron().Jobs() {
+		if j.Id() == "test" {
+			j.Run()
+		}
+	}
+
+	run, err := app.FindLatestCronRun("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if run.Status != cron.RunStatusError {
+		t.Fatalf("Expected status %q, got %q", cron.RunStatusError, run.Status)
+	}
+
+	if run.Error != "test_error" {
+		t.Fatalf("Expected error %q, got %q", "test_error", run.Error)
+	}
+
+	if run.Started.IsZero() || run.Finished.IsZero() || run.Finished.Time().Before(run.Started.Time()) {
+		t.Fatalf("Invalid started %v and finished %v dates", run.Started, run.Finished)
+	}
+
+	found, err := app.FindCronRunById(run.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != run.Id {
+		t.Fatalf("Expected run %q, got %q", run.Id, found.Id)
+	}
+
+	if _, err := app.FindCronRunById("missing"); err == nil {
+		t.Fatal("Expected missing run error")
+	}
+}
+
+func TestFindLatestCronRun(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dates := map[string]string{
+		"run_a": "2024-01-02 00:00:00.000Z",
+		"run_b": "2024-01-03 00:00:00.000Z",
+		"run_c": "2024-01-01 00:00:00.000Z",
+	}
+
+	for id, date := range dates {
+		run := &core.CronRun{JobId: "test", Status: cron.RunStatusSuccess}
+		run.Id = id
+		run.Started, _ = types.ParseDateTime(date)
+		if err := app.AuxSave(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run, err := app.FindLatestCronRun("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if run.Id != "run_b" {
+		t.Fatalf("Expected run_b, got %q", run.Id)
+	}
+}
+
+func TestDeleteOldCronRuns(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dates := []types.DateTime{
+		types.NowDateTime().AddDate(0, 0, -10),
+		types.NowDateTime().AddDate(0, 0, -5),
+		types.NowDateTime(),
+	}
+
+	for _, date := range dates {
+		run := &core.CronRun{JobId: "test", Status: cron.RunStatusSuccess, Created: date}
+		run.Id = core.GenerateDefaultRandomId()
+		if err := app.AuxSave(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.DeleteOldCronRuns(time.Now().AddDate(0, 0, -7)); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := []*core.CronRun{}
+	if err := app.AuxModelQuery(&core.CronRun{}).All(&runs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runs) != 2 {
+		t.Fatalf("Expected %d remaining runs, got %d", 2, len(runs))
+	}
+}
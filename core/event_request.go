@@ -13,6 +13,7 @@ import (
 // Common request store keys used by the middlewares and api handlers.
 const (
 	RequestEventKeyInfoContext = "infoContext"
+	RequestEventKeyAPIKey      = "apiKey"
 )
 
 // RequestEvent defines the PocketBase router handler event.
@@ -79,6 +80,25 @@ func (e *RequestEvent) HasSuperuserAuth() bool {
 	return e.Auth != nil && e.Auth.IsSuperuser()
 }
 
+// APIKey returns the API key used to authorize the current request (if any).
+func (e *RequestEvent) APIKey() *APIKey {
+	apiKey, _ := e.Get(RequestEventKeyAPIKey).(*APIKey)
+
+	// the auth state was changed by another handler
+	if apiKey != nil && (e.Auth == nil ||
+		e.Auth.Id != apiKey.RecordRef() ||
+		e.Auth.Collection().Id != apiKey.CollectionRef()) {
+		return nil
+	}
+
+	return apiKey
+}
+
+// HasAPIKeyAuth checks whether the current RequestEvent was authorized with an API key.
+func (e *RequestEvent) HasAPIKeyAuth() bool {
+	return e.APIKey() != nil
+}
+
 // RequestInfo parses the current request into RequestInfo instance.
 //
 // Note that the returned result is cached to avoid copying the request data multiple times
@@ -89,6 +109,7 @@ func (e *RequestEvent) RequestInfo() (*RequestInfo, error) {
 
 	if e.cachedRequestInfo != nil {
 		e.cachedRequestInfo.Auth = e.Auth
+		e.cachedRequestInfo.APIKey = e.APIKey()
 
 		infoCtx, _ := e.Get(RequestEventKeyInfoContext).(string)
 		if infoCtx != "" {
@@ -141,6 +162,7 @@ func (e *RequestEvent) initRequestInfo() error {
 	}
 
 	info.Auth = e.Auth
+	info.APIKey = e.APIKey()
 
 	e.cachedRequestInfo = info
 
@@ -158,6 +180,7 @@ const (
 	RequestInfoContextOAuth2        = "oauth2"
 	RequestInfoContextOTP           = "otp"
 	RequestInfoContextPasswordAuth  = "password"
+	RequestInfoContextSAML          = "saml"
 )
 
 // RequestInfo defines a HTTP request data struct, usually used
@@ -171,12 +194,17 @@ type RequestInfo struct {
 	Auth    *Record           `json:"auth"`
 	Method  string            `json:"method"`
 	Context string            `json:"context"`
+
+	// APIKey is the API key used to authorize the request (if any).
+	//
+	// Its scopes are available in the collection API rules as "@request.auth.scopes".
+	APIKey *APIKey `json:"-"`
 }
 
 // HasSuperuserAuth checks whether the current RequestInfo instance
 // has superuser authentication loaded.
 func (info *RequestInfo) HasSuperuserAuth() bool {
-	return info.Auth != nil && info.Auth.IsSuperuser()
+	return info != nil && info.Auth != nil && info.Auth.IsSuperuser()
 }
 
 // Clone creates a new shallow copy of the current RequestInfo and its Auth record (if any).
@@ -193,5 +221,9 @@ func (info *RequestInfo) Clone() *RequestInfo {
 		clone.Auth = info.Auth.Fresh()
 	}
 
+	if info.APIKey != nil {
+		clone.APIKey = &APIKey{Record: info.APIKey.Fresh()}
+	}
+
 	return clone
 }
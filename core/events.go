@@ -10,6 +10,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/mailer"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/saml"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 	"golang.org/x/crypto/acme/autocert"
@@ -169,6 +170,18 @@ type MailerRecordEvent struct {
 	Meta map[string]any
 }
 
+type MailReceivedEvent struct {
+	hook.Event
+	App App
+
+	// Message is the parsed inbound message.
+	Message *mailer.Message
+
+	// Record is the record created from the message by the default
+	// OnMailReceived handler (it is nil if InboundMail.Collection is not set).
+	Record *Record
+}
+
 // -------------------------------------------------------------------
 // Model events data
 // -------------------------------------------------------------------
@@ -494,6 +507,19 @@ type RecordAuthWithOTPRequestEvent struct {
 	OTP    *OTP
 }
 
+type RecordAuthLockoutEvent struct {
+	hook.Event
+	*RequestEvent
+	baseCollectionEventData
+
+	Record  *Record
+	Lockout *AuthLockout
+
+	// AuthMethod is the auth method of the failed attempt that triggered the lockout
+	// (could be any of the MFAMethod* constants, eg. "password" or "otp").
+	AuthMethod string
+}
+
 type RecordAuthRequestEvent struct {
 	hook.Event
 	*RequestEvent
@@ -529,6 +555,18 @@ type RecordAuthWithOAuth2RequestEvent struct {
 	IsNewRecord    bool
 }
 
+type RecordAuthWithSAMLRequestEvent struct {
+	hook.Event
+	*RequestEvent
+	baseCollectionEventData
+
+	ServiceProvider *saml.ServiceProvider
+	Assertion       *saml.Assertion
+	Record          *Record
+	CreateData      map[string]any
+	IsNewRecord     bool
+}
+
 type RecordAuthRefreshRequestEvent struct {
 	hook.Event
 	*RequestEvent
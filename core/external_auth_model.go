@@ -7,6 +7,7 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/tools/auth"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/saml"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -123,10 +124,11 @@ func (app *BaseApp) registerExternalAuthHooks() {
 
 	app.OnRecordValidate(CollectionNameExternalAuths).Bind(&hook.Handler[*RecordEvent]{
 		Func: func(e *RecordEvent) error {
-			providerNames := make([]any, 0, len(auth.Providers))
+			providerNames := make([]any, 0, len(auth.Providers)+1)
 			for name := range auth.Providers {
 				providerNames = append(providerNames, name)
 			}
+			providerNames = append(providerNames, saml.ProviderName)
 
 			provider := e.Record.GetString("provider")
 			if err := validation.Validate(provider, validation.Required, validation.In(providerNames...)); err != nil {
@@ -26,6 +26,7 @@ const (
 	FieldNameVerified        = "verified"
 	FieldNameTokenKey        = "tokenKey"
 	FieldNamePassword        = "password"
+	FieldNameDeleted         = "deleted"
 )
 
 // SystemFields returns special internal field names that are usually readonly.
@@ -160,12 +161,48 @@ type DriverValuer interface {
 	DriverValue(record *Record) (driver.Value, error)
 }
 
+// DriverValueScanner defines a Field interface for fields whose database
+// column value has a different representation than the one accepted
+// by [Field.PrepareValue] (ex. scaled integers).
+type DriverValueScanner interface {
+	// ScanDriverValue converts a single raw database column value into a field record value.
+	ScanDriverValue(record *Record, raw any) (any, error)
+}
+
 // MultiValuer defines a field interface that every multi-valued (eg. with MaxSelect) field has.
 type MultiValuer interface {
 	// IsMultiple checks whether the field is configured to support multiple or single values.
 	IsMultiple() bool
 }
 
+// GeneratedValuer defines a field interface for fields whose values
+// are computed by the database (aka. generated columns).
+//
+// Generated field values are excluded from the record insert/update queries.
+type GeneratedValuer interface {
+	// IsGenerated checks whether the field value is computed by the database.
+	IsGenerated() bool
+}
+
+// VirtualValuer defines a field interface for fields that don't have
+// a records table column and whose values are resolved at runtime.
+//
+// Virtual fields are excluded from the records table schema and from
+// the record insert/update queries.
+type VirtualValuer interface {
+	// IsVirtual checks whether the field doesn't have a records table column.
+	IsVirtual() bool
+}
+
+// EncryptedValuer defines a Field interface for fields which value
+// could be stored encrypted in the database.
+//
+// Encrypted field values can't be used in filter and sort expressions.
+type EncryptedValuer interface {
+	// IsEncrypted checks whether the field value is stored encrypted.
+	IsEncrypted() bool
+}
+
 // RecordInterceptor defines a field interface for reacting to various
 // Record related operations (create, delete, validate, etc.).
 type RecordInterceptor interface {
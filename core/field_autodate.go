@@ -26,11 +26,11 @@ var (
 )
 
 // AutodateField defines an "autodate" type field, aka.
-// field which datetime value could be auto set on record create/update.
+// field which datetime value could be auto set on record create/update/soft delete.
 //
 // This field is usually used for defining timestamp fields like "created" and "updated".
 //
-// Requires either both or at least one of the OnCreate or OnUpdate options to be set.
+// Requires at least one of the OnCreate, OnUpdate or OnDelete options to be set.
 type AutodateField struct {
 	// Name (required) is the unique name of the field.
 	Name string `form:"name" json:"name"`
@@ -57,6 +57,10 @@ type AutodateField struct {
 
 	// OnUpdate auto sets the current datetime as field value on record update.
 	OnUpdate bool `form:"onUpdate" json:"onUpdate"`
+
+	// OnDelete auto sets the current datetime as field value on record soft delete
+	// (applicable only for collections with enabled soft delete).
+	OnDelete bool `form:"onDelete" json:"onDelete"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -140,12 +144,12 @@ func (f *AutodateField) ValidateSettings(ctx context.Context, app App, collectio
 		validation.Field(
 			&f.OnCreate,
 			validation.When(f.System, validation.By(validators.Equal(oldOnCreate))),
-			validation.Required.Error("either onCreate or onUpdate must be enabled").When(!f.OnUpdate),
+			validation.Required.Error("either onCreate, onUpdate or onDelete must be enabled").When(!f.OnUpdate && !f.OnDelete),
 		),
 		validation.Field(
 			&f.OnUpdate,
 			validation.When(f.System, validation.By(validators.Equal(oldOnUpdate))),
-			validation.Required.Error("either onCreate or onUpdate must be enabled").When(!f.OnCreate),
+			validation.Required.Error("either onCreate, onUpdate or onDelete must be enabled").When(!f.OnCreate && !f.OnDelete),
 		),
 	)
 }
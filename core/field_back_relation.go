@@ -0,0 +1,240 @@
+package core
+
+import (
+	"context"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func init() {
+	Fields[FieldTypeBackRelation] = func() Field {
+		return &BackRelationField{}
+	}
+}
+
+const FieldTypeBackRelation = "backRelation"
+
+const (
+	// DefaultBackRelationMaxExpand is the default max number of
+	// back-related records that are expanded per record.
+	DefaultBackRelationMaxExpand = 100
+
+	// maxBackRelationMaxExpand is the max allowed BackRelationField.MaxExpand value.
+	maxBackRelationMaxExpand = 1000
+)
+
+var (
+	_ Field         = (*BackRelationField)(nil)
+	_ VirtualValuer = (*BackRelationField)(nil)
+)
+
+// BackRelationField defines "backRelation" type virtual field for
+// accessing the records of another collection that reference the
+// current one through a relation field (aka. the "yourCollection_via_yourRelField" expand).
+//
+// The field doesn't have a records table column and its value is
+// populated with the back-related record ids only when expanded.
+//
+// In filter and sort expressions the field could be used:
+//   - as a back relation, eg. "comments.message ~ 'test'" (the same as "comments_via_post.message ~ 'test'")
+//   - with the ":count" modifier, eg. "comments:count > 10" or "-comments:count"
+//
+// The field is automatically expanded in the record API responses
+// (up to MaxExpand back-related records per record).
+//
+// The respective zero record field value is empty string slice.
+type BackRelationField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// CollectionId (required) is the id of the collection
+	// that has the relation field referencing the current collection.
+	CollectionId string `form:"collectionId" json:"collectionId"`
+
+	// FieldName (required) is the name of the relation field
+	// from CollectionId that references the current collection.
+	FieldName string `form:"fieldName" json:"fieldName"`
+
+	// MaxExpand specifies the max number of back-related records
+	// that will be expanded per record.
+	//
+	// If zero, fallbacks to DefaultBackRelationMaxExpand.
+	MaxExpand int `form:"maxExpand" json:"maxExpand"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *BackRelationField) Type() string {
+	return FieldTypeBackRelation
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *BackRelationField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *BackRelationField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *BackRelationField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *BackRelationField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *BackRelationField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *BackRelationField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *BackRelationField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *BackRelationField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// IsVirtual implements the [VirtualValuer] interface.
+func (f *BackRelationField) IsVirtual() bool {
+	return true
+}
+
+// ExpandLimit returns the max number of back-related records that will be expanded per record.
+func (f *BackRelationField) ExpandLimit() int {
+	if f.MaxExpand <= 0 {
+		return DefaultBackRelationMaxExpand
+	}
+
+	return min(f.MaxExpand, maxBackRelationMaxExpand)
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+//
+// Returns an empty string because the field doesn't have a records table column.
+func (f *BackRelationField) ColumnType(app App) string {
+	return ""
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *BackRelationField) PrepareValue(record *Record, raw any) (any, error) {
+	return list.ToUniqueStringSlice(raw), nil
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *BackRelationField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	return nil // resolved at runtime
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *BackRelationField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.CollectionId, validation.Required, validation.By(f.checkCollectionId(app, collection))),
+		validation.Field(&f.FieldName, validation.Required, validation.By(f.checkFieldName(app, collection))),
+		validation.Field(&f.MaxExpand, validation.Min(0), validation.Max(maxBackRelationMaxExpand)),
+	)
+}
+
+func (f *BackRelationField) checkCollectionId(app App, collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		if collection.IsView() {
+			return validation.NewError(
+				"validation_field_back_relation_view",
+				"Back relations are not supported in view collections.",
+			)
+		}
+
+		backCollection := f.findBackCollection(app, collection)
+		if backCollection == nil {
+			return validation.NewError(
+				"validation_field_back_relation_missing_collection",
+				"The back relation collection doesn't exist.",
+			)
+		}
+
+		if backCollection.IsView() {
+			return validation.NewError(
+				"validation_field_back_relation_view_collection",
+				"The back relation collection must not be a view.",
+			)
+		}
+
+		return nil
+	}
+}
+
+func (f *BackRelationField) checkFieldName(app App, collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" || f.CollectionId == "" {
+			return nil // nothing to check
+		}
+
+		backCollection := f.findBackCollection(app, collection)
+		if backCollection == nil {
+			return nil // already checked by checkCollectionId
+		}
+
+		relField, _ := backCollection.Fields.GetByName(v).(*RelationField)
+		if relField == nil || relField.CollectionId != collection.Id {
+			return validation.NewError(
+				"validation_field_back_relation_invalid_field",
+				"The field must be a relation field referencing the current collection.",
+			)
+		}
+
+		return nil
+	}
+}
+
+// findBackCollection returns the back relation collection (or nil if missing).
+func (f *BackRelationField) findBackCollection(app App, collection *Collection) *Collection {
+	// self reference
+	if f.CollectionId == collection.Id {
+		return collection
+	}
+
+	backCollection, _ := app.FindCachedCollectionByNameOrId(f.CollectionId)
+	if backCollection == nil || backCollection.Id != f.CollectionId {
+		return nil
+	}
+
+	return backCollection
+}
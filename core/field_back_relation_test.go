@@ -0,0 +1,304 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestBackRelationFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeBackRelation)
+}
+
+func TestBackRelationFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.BackRelationField{}
+
+	if v := f.ColumnType(app); v != "" {
+		t.Fatalf("Expected empty column type, got %q", v)
+	}
+
+	if !f.IsVirtual() {
+		t.Fatal("Expected the field to be virtual")
+	}
+}
+
+func TestBackRelationFieldPrepareValue(t *testing.T) {
+	f := &core.BackRelationField{}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{nil, `[]`},
+		{"", `[]`},
+		{"a", `[a]`},
+		{[]string{"a", "b", "a"}, `[a b]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := fmt.Sprint(v); str != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestBackRelationFieldExpandLimit(t *testing.T) {
+	scenarios := []struct {
+		maxExpand int
+		expected  int
+	}{
+		{-1, core.DefaultBackRelationMaxExpand},
+		{0, core.DefaultBackRelationMaxExpand},
+		{5, 5},
+		{5000, 1000},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprint(s.maxExpand), func(t *testing.T) {
+			f := &core.BackRelationField{MaxExpand: s.maxExpand}
+
+			if v := f.ExpandLimit(); v != s.expected {
+				t.Fatalf("Expected %d, got %d", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestBackRelationFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeBackRelation)
+	testDefaultFieldNameValidation(t, core.FieldTypeBackRelation)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// note: demo1.rel_one is a self relation
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo3, err := app.FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		collection   *core.Collection
+		field        func() *core.BackRelationField
+		expectErrors []string
+	}{
+		{
+			"zero",
+			demo3,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test"}
+			},
+			[]string{"collectionId", "fieldName"},
+		},
+		{
+			"missing collection",
+			demo3,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: "missing", FieldName: "rel_one"}
+			},
+			[]string{"collectionId"},
+		},
+		{
+			"view back collection",
+			demo3,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: "v9gwnfh02gjq1q0", FieldName: "rel_one"}
+			},
+			[]string{"collectionId", "fieldName"},
+		},
+		{
+			"non-relation field",
+			demo3,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: demo1.Id, FieldName: "text"}
+			},
+			[]string{"fieldName"},
+		},
+		{
+			"relation field to another collection",
+			demo2,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: demo1.Id, FieldName: "rel_one"}
+			},
+			[]string{"fieldName"},
+		},
+		{
+			"invalid MaxExpand",
+			demo1,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: demo1.Id, FieldName: "rel_one", MaxExpand: 1001}
+			},
+			[]string{"maxExpand"},
+		},
+		{
+			"valid back relation",
+			demo1,
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: demo1.Id, FieldName: "rel_one", MaxExpand: 10}
+			},
+			[]string{},
+		},
+		{
+			"view collection",
+			core.NewViewCollection("test_view"),
+			func() *core.BackRelationField {
+				return &core.BackRelationField{Id: "test", Name: "test", CollectionId: demo1.Id, FieldName: "rel_one"}
+			},
+			[]string{"collectionId", "fieldName"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field().ValidateSettings(context.Background(), app, s.collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestBackRelationFieldResolveAndExpand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	posts := core.NewBaseCollection("test_posts")
+	posts.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	comments := core.NewBaseCollection("test_comments")
+	comments.Fields.Add(
+		&core.TextField{Name: "message"},
+		&core.RelationField{Name: "post", CollectionId: posts.Id, MaxSelect: 1},
+	)
+	if err := app.Save(comments); err != nil {
+		t.Fatal(err)
+	}
+
+	posts.Fields.Add(&core.BackRelationField{
+		Name:         "comments",
+		CollectionId: comments.Id,
+		FieldName:    "post",
+		MaxExpand:    2,
+	})
+	if err := app.Save(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	// ensure that no column was created
+	columns, err := app.TableColumns(posts.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(strings.Join(columns, ","), "comments") {
+		t.Fatalf("Expected no comments column, got %v", columns)
+	}
+
+	postIds := map[string]string{}
+	for title, total := range map[string]int{"a": 3, "b": 1, "c": 0} {
+		post := core.NewRecord(posts)
+		post.Set("title", title)
+		post.Set("comments", []string{"should_be_ignored"})
+		if err := app.Save(post); err != nil {
+			t.Fatal(err)
+		}
+		postIds[title] = post.Id
+
+		for i := 0; i < total; i++ {
+			comment := core.NewRecord(comments)
+			comment.Set("message", fmt.Sprintf("%s%d", title, i))
+			comment.Set("post", post.Id)
+			if err := app.Save(comment); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	filterScenarios := []struct {
+		filter   string
+		sort     string
+		expected string
+	}{
+		{"comments:count > 0", "-comments:count", "a,b"},
+		{"comments:count = 0", "", "c"},
+		{"comments.message = 'b0'", "", "b"},
+		{"id != ''", "comments:count", "c,b,a"},
+	}
+
+	for _, s := range filterScenarios {
+		t.Run(s.filter+"_"+s.sort, func(t *testing.T) {
+			records, err := app.FindRecordsByFilter(posts, s.filter, s.sort, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			titles := make([]string, 0, len(records))
+			for _, r := range records {
+				titles = append(titles, r.GetString("title"))
+			}
+
+			if str := strings.Join(titles, ","); str != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, str)
+			}
+		})
+	}
+
+	t.Run("invalid modifier usage", func(t *testing.T) {
+		for _, filter := range []string{"comments = ''", "comments:length > 0", "title:count > 0"} {
+			if _, err := app.FindRecordsByFilter(posts, filter, "", 0, 0); err == nil {
+				t.Fatalf("Expected error for filter %q", filter)
+			}
+		}
+	})
+
+	t.Run("expand with limit", func(t *testing.T) {
+		post, err := app.FindRecordById(posts, postIds["a"])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if errs := app.ExpandRecord(post, []string{"comments.post"}, nil); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+
+		expanded := post.ExpandedAll("comments")
+		if len(expanded) != 2 {
+			t.Fatalf("Expected 2 expanded comments, got %d", len(expanded))
+		}
+
+		if ids := post.GetStringSlice("comments"); len(ids) != 2 {
+			t.Fatalf("Expected 2 comments ids, got %v", ids)
+		}
+
+		if nested := expanded[0].ExpandedOne("post"); nested == nil || nested.Id != post.Id {
+			t.Fatalf("Expected the nested post to be expanded, got %v", nested)
+		}
+	})
+}
@@ -0,0 +1,500 @@
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypeDecimal] = func() Field {
+		return &DecimalField{}
+	}
+}
+
+const FieldTypeDecimal = "decimal"
+
+// DecimalMaxPrecision is the max number of significant digits
+// that a single DecimalField value could have.
+const DecimalMaxPrecision = 1000
+
+// decimalMaxExponent is the max absolute decimal exponent
+// that could be represented with the 4 digits db value exponent.
+const decimalMaxExponent = 4999
+
+// decimalZeroDBValue is the db representation of the zero decimal value.
+const decimalZeroDBValue = "2 0"
+
+var (
+	_ Field              = (*DecimalField)(nil)
+	_ DriverValuer       = (*DecimalField)(nil)
+	_ DriverValueScanner = (*DecimalField)(nil)
+	_ SetterFinder       = (*DecimalField)(nil)
+)
+
+var decimalRegex = regexp.MustCompile(`^([+-])?(\d*)(?:\.(\d*))?$`)
+
+var currencyCodeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// DecimalField defines "decimal" type field for storing exact
+// fixed-point numbers (ex. money amounts).
+//
+// The field value is stored in the database as text prefixed with a
+// scale independent sortable key (ex. "12.34" is stored as "3500021234 12.34")
+// which allows exact filter comparisons and correct sorting regardless
+// of the number of digits and the Scale of the compared fields.
+//
+// The record field value is a normalized decimal string with exactly
+// Scale fractional digits (ex. "12.30") and it is also serialized as
+// string to avoid the JS float precision loss.
+//
+// The respective zero record field value is "0" formatted with the field Scale (ex. "0.00").
+//
+// Note that number filter literals are parsed as float64 and therefore
+// for values with more than 15 significant digits prefer the quoted form
+// (ex. `amount > "1234567890123.4567"`).
+//
+// When referenced in a collection "check" constraint expression the
+// field is resolved to its numeric value. Formula fields cannot
+// reference decimal fields because the formulas are not exact.
+//
+// The following additional setter keys are available:
+//
+//   - "fieldName+" - exactly adds to the existing record value. For example:
+//     record.Set("total+", "5.10")
+//   - "fieldName-" - exactly subtracts from the existing record value. For example:
+//     record.Set("total-", "5.10")
+type DecimalField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Precision specifies the max number of significant digits
+	// (including the fractional ones) of the field value.
+	//
+	// If zero, fallbacks to [DecimalMaxPrecision].
+	Precision int `form:"precision" json:"precision"`
+
+	// Scale specifies the number of fractional digits of the field value.
+	//
+	// Scale cannot be changed once the field is created.
+	Scale int `form:"scale" json:"scale"`
+
+	// Currency is an optional ISO 4217 currency code (ex. "EUR")
+	// associated with the field value.
+	Currency string `form:"currency" json:"currency"`
+
+	// Required will require the field value to be non-zero.
+	Required bool `form:"required" json:"required"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *DecimalField) Type() string {
+	return FieldTypeDecimal
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *DecimalField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *DecimalField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *DecimalField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *DecimalField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *DecimalField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *DecimalField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *DecimalField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *DecimalField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *DecimalField) ColumnType(app App) string {
+	return "TEXT DEFAULT '" + decimalZeroDBValue + "' NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+//
+// Invalid decimal values are returned as plain strings so that they
+// could be reported by [DecimalField.ValidateValue].
+func (f *DecimalField) PrepareValue(record *Record, raw any) (any, error) {
+	str := decimalString(raw)
+
+	d, ok := parseDecimal(str)
+	if !ok {
+		return str, nil
+	}
+
+	return d.format(f.Scale), nil
+}
+
+// ScanDriverValue implements the [DriverValueScanner] interface.
+func (f *DecimalField) ScanDriverValue(record *Record, raw any) (any, error) {
+	str := cast.ToString(raw)
+	if str == "" {
+		return f.PrepareValue(record, nil)
+	}
+
+	d, ok := parseDecimalDBValue(str)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal field %q db value %q", f.Name, str)
+	}
+
+	return d.format(f.Scale), nil
+}
+
+// DriverValue implements the [DriverValuer] interface.
+func (f *DecimalField) DriverValue(record *Record) (driver.Value, error) {
+	d, ok := parseDecimal(record.GetString(f.Name))
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal field %q value", f.Name)
+	}
+
+	return d.dbValue()
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *DecimalField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	val, ok := record.GetRaw(f.Name).(string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	d, ok := parseDecimal(val)
+	if !ok {
+		return validation.NewError("validation_invalid_decimal", "Must be a valid decimal number.")
+	}
+
+	if d.isZero() {
+		if f.Required {
+			return validation.ErrRequired
+		}
+		return nil
+	}
+
+	if len(d.frac) > f.Scale {
+		return validation.NewError(
+			"validation_decimal_scale_constraint",
+			fmt.Sprintf("Must have no more than %d decimal places.", f.Scale),
+		).SetParams(map[string]any{"scale": f.Scale})
+	}
+
+	maxIntDigits := f.precision() - f.Scale
+	if len(d.int) > maxIntDigits {
+		return validation.NewError(
+			"validation_decimal_precision_constraint",
+			fmt.Sprintf("Must have no more than %d integer digits.", maxIntDigits),
+		).SetParams(map[string]any{"max": maxIntDigits})
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *DecimalField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.Precision, validation.Min(0), validation.Max(DecimalMaxPrecision)),
+		validation.Field(
+			&f.Scale,
+			validation.Min(0),
+			validation.Max(f.precision()),
+			validation.By(f.checkScaleChange(app, collection)),
+		),
+		validation.Field(&f.Currency, validation.Match(currencyCodeRegex)),
+	)
+}
+
+func (f *DecimalField) checkScaleChange(app App, collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		if collection.IsNew() {
+			return nil // nothing to check
+		}
+
+		oldCollection, err := app.FindCachedCollectionByNameOrId(collection.Id)
+		if err != nil {
+			return err
+		}
+
+		// the stored scaled integers are not converted
+		oldField, _ := oldCollection.Fields.GetById(f.Id).(*DecimalField)
+		if oldField != nil && oldField.Scale != f.Scale {
+			return validation.NewError("validation_field_decimal_scale_change", "The decimal scale cannot be changed.")
+		}
+
+		return nil
+	}
+}
+
+func (f *DecimalField) precision() int {
+	if f.Precision <= 0 {
+		return DecimalMaxPrecision
+	}
+
+	return f.Precision
+}
+
+// FindSetter implements the [SetterFinder] interface.
+func (f *DecimalField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		return f.setValue
+	case f.Name + "+":
+		return f.addValue
+	case f.Name + "-":
+		return f.subtractValue
+	default:
+		return nil
+	}
+}
+
+func (f *DecimalField) setValue(record *Record, raw any) {
+	v, _ := f.PrepareValue(record, raw)
+
+	record.SetRaw(f.Name, v)
+}
+
+func (f *DecimalField) addValue(record *Record, raw any) {
+	f.sumValue(record, raw, false)
+}
+
+func (f *DecimalField) subtractValue(record *Record, raw any) {
+	f.sumValue(record, raw, true)
+}
+
+func (f *DecimalField) sumValue(record *Record, raw any, negate bool) {
+	a, aOk := parseDecimal(record.GetString(f.Name))
+	b, bOk := parseDecimal(decimalString(raw))
+	if !aOk || !bOk {
+		// store the invalid value so that it can be reported on validation
+		f.setValue(record, raw)
+		return
+	}
+
+	if negate {
+		b.neg = !b.neg
+	}
+
+	sum := new(big.Rat).Add(a.rat(), b.rat())
+
+	digits := max(len(a.frac), len(b.frac))
+
+	f.setValue(record, sum.FloatString(digits))
+}
+
+// normalizeDecimalParam converts a filter literal decimal value
+// into its sortable database representation (see [decimal.dbValue]).
+func normalizeDecimalParam(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	str := decimalString(value)
+	if str == "" {
+		return "", nil
+	}
+
+	d, ok := parseDecimal(str)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal value %q", str)
+	}
+
+	return d.dbValue()
+}
+
+// decimalSQLValue returns the SQL expression that extracts the
+// numeric value of the specified decimal column identifier.
+func decimalSQLValue(identifier string) string {
+	return "CAST(substr(" + identifier + ", instr(" + identifier + ", ' ') + 1) AS NUMERIC)"
+}
+
+// -------------------------------------------------------------------
+
+// decimal is a parsed and normalized decimal number.
+type decimal struct {
+	int  string // integer digits without leading zeros
+	frac string // fractional digits without trailing zeros
+	neg  bool
+}
+
+// decimalString converts a raw decimal value into its string representation.
+func decimalString(raw any) string {
+	switch v := raw.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return cast.ToString(v)
+	}
+}
+
+// parseDecimal parses the provided decimal string (ex. "-12.340").
+//
+// An empty string is treated as zero.
+func parseDecimal(str string) (decimal, bool) {
+	var d decimal
+
+	if str == "" {
+		return d, true
+	}
+
+	match := decimalRegex.FindStringSubmatch(str)
+	if match == nil || match[2]+match[3] == "" {
+		return d, false
+	}
+
+	d.int = strings.TrimLeft(match[2], "0")
+	d.frac = strings.TrimRight(match[3], "0")
+	d.neg = match[1] == "-" && !d.isZero()
+
+	return d, true
+}
+
+func (d decimal) isZero() bool {
+	return d.int == "" && d.frac == ""
+}
+
+// format returns the decimal string representation with at least scale fractional digits.
+func (d decimal) format(scale int) string {
+	var sb strings.Builder
+
+	if d.neg {
+		sb.WriteString("-")
+	}
+
+	if d.int == "" {
+		sb.WriteString("0")
+	} else {
+		sb.WriteString(d.int)
+	}
+
+	frac := d.frac
+	if len(frac) < scale {
+		frac += strings.Repeat("0", scale-len(frac))
+	}
+
+	if frac != "" {
+		sb.WriteString(".")
+		sb.WriteString(frac)
+	}
+
+	return sb.String()
+}
+
+// dbValue returns the sortable database representation of the decimal
+// in the format "key plain" (ex. "3500021234 12.34").
+//
+// For x = 0.D*10^E (D - the significant digits) the key is:
+//   - "2" for zero
+//   - "3" + (E+5000) as 4 digits + D for positive values
+//   - "1" + (4999-E) as 4 digits + nines complement of D + "~" for negative values
+//
+// This way equal values always have the same representation and
+// the keys are ordered the same way as their numeric values.
+func (d decimal) dbValue() (string, error) {
+	if d.isZero() {
+		return decimalZeroDBValue, nil
+	}
+
+	var exp int
+	var digits string
+	if d.int != "" {
+		exp = len(d.int)
+		digits = strings.TrimRight(d.int+d.frac, "0")
+	} else {
+		digits = strings.TrimLeft(d.frac, "0")
+		exp = len(digits) - len(d.frac)
+	}
+
+	if exp > decimalMaxExponent || exp < -decimalMaxExponent {
+		return "", errors.New("the value is out of range")
+	}
+
+	var sb strings.Builder
+	if d.neg {
+		sb.WriteString(fmt.Sprintf("1%04d", decimalMaxExponent-exp))
+		for _, c := range digits {
+			sb.WriteByte(byte('9' - c + '0'))
+		}
+		sb.WriteString("~")
+	} else {
+		sb.WriteString(fmt.Sprintf("3%04d", exp+decimalMaxExponent+1))
+		sb.WriteString(digits)
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(d.format(0))
+
+	return sb.String(), nil
+}
+
+func (d decimal) rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.format(0))
+	return r
+}
+
+// parseDecimalDBValue parses a decimal database value (see [decimal.dbValue]).
+func parseDecimalDBValue(str string) (decimal, bool) {
+	_, plain, found := strings.Cut(str, " ")
+	if !found {
+		plain = str
+	}
+
+	return parseDecimal(plain)
+}
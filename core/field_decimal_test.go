@@ -0,0 +1,428 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDecimalFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeDecimal)
+}
+
+func TestDecimalFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.DecimalField{}
+
+	expected := "TEXT DEFAULT '2 0' NOT NULL"
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestDecimalFieldPrepareValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.DecimalField{Scale: 2}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{nil, "0.00"},
+		{"", "0.00"},
+		{"test", "test"},
+		{"-0", "0.00"},
+		{"  1.5 ", "1.50"},
+		{"+001.230", "1.23"},
+		{"-.5", "-0.50"},
+		{"1.2345", "1.2345"},
+		{-2, "-2.00"},
+		{0.1, "0.10"},
+		{json.Number("12.3"), "12.30"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v != s.expected {
+				t.Fatalf("Expected %q, got %#v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name        string
+		field       *core.DecimalField
+		value       any
+		expectError bool
+	}{
+		{"invalid raw value", &core.DecimalField{Name: "test"}, 123, true},
+		{"invalid decimal", &core.DecimalField{Name: "test"}, "1.2.3", true},
+		{"zero (non-required)", &core.DecimalField{Name: "test"}, "0", false},
+		{"zero (required)", &core.DecimalField{Name: "test", Required: true}, "0.00", true},
+		{"non-zero (required)", &core.DecimalField{Name: "test", Required: true, Scale: 2}, "0.01", false},
+		{"more decimal places than the scale", &core.DecimalField{Name: "test", Scale: 2}, "1.001", true},
+		{"trailing zeros within the scale", &core.DecimalField{Name: "test", Scale: 2}, "1.100", false},
+		{"more integer digits than the precision", &core.DecimalField{Name: "test", Precision: 5, Scale: 2}, "1234.5", true},
+		{"max integer digits", &core.DecimalField{Name: "test", Precision: 5, Scale: 2}, "-123.45", false},
+		{"max default precision", &core.DecimalField{Name: "test"}, strings.Repeat("9", core.DecimalMaxPrecision), false},
+		{"more than the max default precision", &core.DecimalField{Name: "test"}, strings.Repeat("9", core.DecimalMaxPrecision+1), true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			record := core.NewRecord(collection)
+			record.SetRaw("test", s.value)
+
+			err := s.field.ValidateValue(context.Background(), app, record)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeDecimal)
+	testDefaultFieldNameValidation(t, core.FieldTypeDecimal)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name         string
+		field        func() *core.DecimalField
+		expectErrors []string
+	}{
+		{
+			"zero minimal",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test"}
+			},
+			[]string{},
+		},
+		{
+			"invalid precision, scale and currency",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: core.DecimalMaxPrecision + 1, Scale: -1, Currency: "eur"}
+			},
+			[]string{"precision", "scale", "currency"},
+		},
+		{
+			"scale larger than the precision",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: 5, Scale: 6}
+			},
+			[]string{"scale"},
+		},
+		{
+			"valid settings",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: 12, Scale: 2, Currency: "EUR"}
+			},
+			[]string{},
+		},
+		{
+			"precision larger than 18 digits",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: 40, Scale: 20}
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field().ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestDecimalFieldScaleChange(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_decimal")
+	collection.Fields.Add(&core.DecimalField{Name: "amount", Scale: 2})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Fields.GetByName("amount").(*core.DecimalField).Scale = 3
+
+	err := app.Save(collection)
+	if err == nil || !strings.Contains(fmt.Sprintf("%#v", err), "validation_field_decimal_scale_change") {
+		t.Fatalf("Expected scale change error, got %v", err)
+	}
+}
+
+func TestDecimalFieldFindSetter(t *testing.T) {
+	field := &core.DecimalField{Name: "test", Scale: 2}
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(field)
+
+	scenarios := []struct {
+		name     string
+		key      string
+		value    any
+		expected string
+	}{
+		{"no match", "example", "1", "10.10"},
+		{"exact match", "test", "0.3", "0.30"},
+		{"add", "test+", "0.20", "10.30"},
+		{"add float", "test+", 0.2, "10.30"},
+		{"subtract", "test-", "10.2", "-0.10"},
+		{"add invalid", "test+", "abc", "abc"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			record := core.NewRecord(collection)
+			record.SetRaw("test", "10.10")
+
+			setter := field.FindSetter(s.key)
+			if setter != nil {
+				setter(record, s.value)
+			}
+
+			if v := record.GetRaw("test"); v != s.expected {
+				t.Fatalf("Expected %q, got %#v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldPersistenceAndFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_decimal")
+	collection.Fields.Add(
+		&core.DecimalField{Name: "amount", Scale: 2, Currency: "EUR"},
+		&core.DecimalField{Name: "limit", Scale: 3},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// values that can't be represented exactly as float64 sums
+	values := [][2]string{
+		{"0.10", "0.1"},
+		{"0.20", "0.205"},
+		{"0.30", "0.3"},
+		{"-5", "-5.001"},
+		{"1000000000000.01", "0"},
+	}
+	for _, v := range values {
+		record := core.NewRecord(collection)
+		record.Set("amount", v[0])
+		record.Set("limit", v[1])
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the sortable representation is stored in the db
+	var stored string
+	err := app.DB().Select("amount").From(collection.Name).OrderBy("amount DESC").Limit(1).Row(&stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "35013100000000000001 1000000000000.01"; stored != expected {
+		t.Fatalf("Expected db value %q, got %q", expected, stored)
+	}
+
+	// the loaded record value and its JSON serialization are decimal strings
+	record, err := app.FindFirstRecordByFilter(collection, "amount = 0.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record.GetString("amount"); v != "0.30" {
+		t.Fatalf("Expected amount %q, got %q", "0.30", v)
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"amount":"0.30"`) {
+		t.Fatalf("Expected the amount to be serialized as string, got %s", raw)
+	}
+
+	filterScenarios := []struct {
+		filter   string
+		expected []string
+	}{
+		{"amount = 0.1", []string{"0.10"}},
+		{"amount = '0.10'", []string{"0.10"}},
+		{"amount != 0.1 && amount < 1", []string{"-5.00", "0.20", "0.30"}},
+		{"amount > 0.25", []string{"0.30", "1000000000000.01"}},
+		{"amount >= '1000000000000.01'", []string{"1000000000000.01"}},
+		{"amount < 0.205", []string{"-5.00", "0.10", "0.20"}},
+		{"0.2 <= amount && amount <= {:max}", []string{"0.20", "0.30"}},
+		{"amount = limit", []string{"0.10", "0.30"}},
+		{"amount < limit", []string{"0.20"}},
+		{"amount > limit", []string{"-5.00", "1000000000000.01"}},
+		{"limit = 0", []string{"1000000000000.01"}},
+		{"limit < -5", []string{"-5.00"}},
+	}
+
+	for _, s := range filterScenarios {
+		t.Run(s.filter, func(t *testing.T) {
+			records, err := app.FindRecordsByFilter(collection, s.filter, "amount", 0, 0, dbx.Params{"max": "0.3"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := make([]string, len(records))
+			for i, r := range records {
+				result[i] = r.GetString("amount")
+			}
+
+			if strings.Join(result, ",") != strings.Join(s.expected, ",") {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+
+	if _, err := app.FindRecordsByFilter(collection, "amount > 'abc'", "", 0, 0); err == nil {
+		t.Fatal("Expected invalid decimal literal error")
+	}
+}
+
+func TestDecimalFieldLargePrecision(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_decimal")
+	collection.Fields.Add(&core.DecimalField{Name: "amount", Precision: 60, Scale: 20})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	values := []string{
+		"-123456789012345678901234567890.12345678901234567891",
+		"-123456789012345678901234567890.1234567890123456789",
+		"-0.00000000000000000001",
+		"0.00000000000000000001",
+		"99999999999999999999.99999999999999999999",
+		"100000000000000000000",
+		"123456789012345678901234567890.12345678901234567891",
+	}
+	for _, v := range values {
+		record := core.NewRecord(collection)
+		record.Set("amount", v)
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %q: %v", v, err)
+		}
+	}
+
+	scenarios := []struct {
+		filter   string
+		expected []int // values indexes
+	}{
+		{"", []int{0, 1, 2, 3, 4, 5, 6}},
+		{"amount = '0.00000000000000000001'", []int{3}},
+		{"amount > '99999999999999999999.9999999999999999999'", []int{4, 5, 6}},
+		{"amount < '-123456789012345678901234567890.1234567890123456789'", []int{0}},
+		{"amount >= '123456789012345678901234567890.1234567890123456789'", []int{6}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter, func(t *testing.T) {
+			records, err := app.FindRecordsByFilter(collection, s.filter, "amount", 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := make([]string, len(records))
+			for i, r := range records {
+				result[i] = r.GetString("amount")
+			}
+
+			expected := make([]string, len(s.expected))
+			for i, index := range s.expected {
+				v, _ := collection.Fields.GetByName("amount").PrepareValue(nil, values[index])
+				expected[i] = v.(string)
+			}
+
+			if strings.Join(result, ",") != strings.Join(expected, ",") {
+				t.Fatalf("Expected\n%v\ngot\n%v", expected, result)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldCheckConstraint(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_decimal")
+	collection.Fields.Add(
+		&core.DecimalField{Name: "amount", Scale: 2},
+		&core.DecimalField{Name: "limit", Scale: 3},
+	)
+	collection.Constraints = []core.CollectionConstraint{{
+		Name:       "amount_limit",
+		Type:       core.CollectionConstraintTypeCheck,
+		Expression: "amount > 0.5 && amount <= limit",
+	}}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		amount      string
+		limit       string
+		expectError bool
+	}{
+		{"0.50", "1", true},
+		{"0.51", "0.509", true},
+		{"0.51", "0.51", false},
+		{"12.34", "100.001", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.amount+"_"+s.limit, func(t *testing.T) {
+			record := core.NewRecord(collection)
+			record.Set("amount", s.amount)
+			record.Set("limit", s.limit)
+
+			err := app.Save(record)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// encryptedValuePrefix is the prefix (aka. header) of the encrypted field values.
+//
+// The full stored value format is:
+//
+//	pbenc1:[keyId]:[base64 AES-256-GCM cipher text]
+//
+// where keyId is a short fingerprint of the key used for the encryption
+// allowing the decryption of values encrypted with a previous (rotated) key.
+const encryptedValuePrefix = "pbenc1:"
+
+// previousEncryptionKeysEnvSuffix is the suffix of the env variable
+// with the comma separated list of the previous (rotated) encryption keys,
+// eg. "PB_ENCRYPTION_KEY_PREVIOUS" for "PB_ENCRYPTION_KEY".
+const previousEncryptionKeysEnvSuffix = "_PREVIOUS"
+
+// undecryptableFieldPrefix is the record original data key prefix used to
+// keep the stored value of an encrypted field that failed to be decrypted.
+const undecryptableFieldPrefix = internalCustomFieldKeyPrefix + "_undecryptable_"
+
+var (
+	_ EncryptedValuer = (*TextField)(nil)
+	_ EncryptedValuer = (*JSONField)(nil)
+)
+
+// encryptedKeyIdRegex matches the encrypted value key id (aka. the first 4 bytes of the key fingerprint).
+var encryptedKeyIdRegex = regexp.MustCompile(`^[0-9a-f]{8}$`)
+
+type fieldEncryptionKey struct {
+	id  string
+	key string
+}
+
+// deriveFieldEncryptionKey derives a dedicated 32 bytes field encryption key
+// from the provided master key (so that the same key is not reused as it is
+// for the settings encryption).
+func deriveFieldEncryptionKey(masterKey string) fieldEncryptionKey {
+	mac := hmac.New(sha256.New, []byte(masterKey))
+	mac.Write([]byte("pb_fields_encryption"))
+	key := mac.Sum(nil)
+
+	fingerprint := sha256.Sum256(key)
+
+	return fieldEncryptionKey{
+		id:  hex.EncodeToString(fingerprint[:4]),
+		key: string(key),
+	}
+}
+
+// fieldsEncryptionKeys returns the app fields encryption key
+// followed by the previous (rotated) ones.
+func fieldsEncryptionKeys(app App) ([]fieldEncryptionKey, error) {
+	env := app.EncryptionEnv()
+	if env == "" {
+		return nil, errors.New("missing app encryption env")
+	}
+
+	current := os.Getenv(env)
+	if current == "" {
+		return nil, fmt.Errorf("missing or empty encryption key env %q", env)
+	}
+
+	keys := []fieldEncryptionKey{deriveFieldEncryptionKey(current)}
+
+	for _, prev := range strings.Split(os.Getenv(env+previousEncryptionKeysEnvSuffix), ",") {
+		prev = strings.TrimSpace(prev)
+		if prev != "" {
+			keys = append(keys, deriveFieldEncryptionKey(prev))
+		}
+	}
+
+	return keys, nil
+}
+
+// fieldEncryptionAAD returns the additional authenticated data that binds
+// an encrypted value to its collection, record and field, so that
+// a cipher text copied from another record or field fails to be decrypted.
+//
+// The field id is used instead of its name so that renaming the field
+// doesn't invalidate the already stored values.
+func fieldEncryptionAAD(collectionId string, recordId string, field Field) []byte {
+	return []byte(collectionId + "/" + recordId + "/" + field.GetId())
+}
+
+// encryptFieldValue encrypts the provided plain value with the app fields encryption key.
+func encryptFieldValue(app App, plain []byte, aad []byte) (string, error) {
+	keys, err := fieldsEncryptionKeys(app)
+	if err != nil {
+		return "", err
+	}
+
+	cipherText, err := security.EncryptWithAAD(plain, keys[0].key, aad)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedValuePrefix + keys[0].id + ":" + cipherText, nil
+}
+
+// parseEncryptedFieldValue checks whether the provided value is
+// in the encrypted value format and returns its key id and cipher text.
+func parseEncryptedFieldValue(value string) (keyId string, cipherText string, ok bool) {
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return "", "", false
+	}
+
+	keyId, cipherText, ok = strings.Cut(strings.TrimPrefix(value, encryptedValuePrefix), ":")
+	if !ok || !encryptedKeyIdRegex.MatchString(keyId) {
+		return "", "", false
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(cipherText); err != nil {
+		return "", "", false
+	}
+
+	return keyId, cipherText, true
+}
+
+// decryptFieldValue decrypts the provided encrypted field value
+// using the app key matching its header key id.
+func decryptFieldValue(app App, value string, aad []byte) ([]byte, error) {
+	keyId, cipherText, ok := parseEncryptedFieldValue(value)
+	if !ok {
+		return nil, errors.New("invalid encrypted field value format")
+	}
+
+	keys, err := fieldsEncryptionKeys(app)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		if k.id == keyId {
+			return security.DecryptWithAAD(cipherText, k.key, aad)
+		}
+	}
+
+	return nil, fmt.Errorf("missing encryption key with id %q", keyId)
+}
+
+// exportEncryptedFieldValue converts the plain record field value
+// into its encrypted database representation.
+//
+// Empty values are stored as they are to allow the zero checks.
+//
+// The submitted values are always encrypted (even if they look like
+// an encrypted value). The only exception is an unchanged value of a
+// field that couldn't be decrypted on load, in which case its original
+// stored value is kept (see [Record.HasUndecryptableValue]).
+func exportEncryptedFieldValue(app App, record *Record, field Field, value any) (any, error) {
+	name := field.GetName()
+
+	if raw, ok := record.originalData[undecryptableFieldPrefix+name]; ok && areValuesEqual(value, record.originalData[name]) {
+		return raw, nil
+	}
+
+	aad := fieldEncryptionAAD(record.Collection().Id, record.Id, field)
+
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return v, nil
+		}
+
+		return encryptFieldValue(app, []byte(v), aad)
+	case types.JSONRaw:
+		if len(v) == 0 {
+			return nil, nil
+		}
+
+		encrypted, err := encryptFieldValue(app, v, aad)
+		if err != nil {
+			return nil, err
+		}
+
+		// the encrypted json value is stored as json string
+		return strconv.Quote(encrypted), nil
+	default:
+		return value, nil
+	}
+}
+
+// importEncryptedFieldValue decrypts the raw database value of an encrypted field.
+//
+// Values that are not in the encrypted format (eg. stored before enabling
+// the field "encrypted" option) are returned as they are.
+//
+// Values that fail to be decrypted (eg. because of a missing rotated key
+// or a cipher text copied from another record) return an error and
+// the caller is expected to use the field zero value instead.
+func importEncryptedFieldValue(app App, collectionId string, recordId string, field Field, raw string) (string, error) {
+	value := raw
+
+	_, isJSON := field.(*JSONField)
+	if isJSON {
+		unquoted, err := strconv.Unquote(raw)
+		if err != nil {
+			return raw, nil
+		}
+		value = unquoted
+	}
+
+	if _, _, ok := parseEncryptedFieldValue(value); !ok {
+		return raw, nil
+	}
+
+	plain, err := decryptFieldValue(app, value, fieldEncryptionAAD(collectionId, recordId, field))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+// checkFieldsEncryptionKey returns a validation rule that checks
+// whether the app has a valid fields encryption key configured.
+func checkFieldsEncryptionKey(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(bool)
+		if !v {
+			return nil // not enabled
+		}
+
+		env := app.EncryptionEnv()
+		if env == "" || os.Getenv(env) == "" {
+			return validation.NewError(
+				"validation_field_encryption_key_missing",
+				"The app encryption key must be set in order to enable the field encryption.",
+			)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,284 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypeFormula] = func() Field {
+		return &FormulaField{}
+	}
+}
+
+const FieldTypeFormula = "formula"
+
+const (
+	FormulaReturnTypeNumber = "number"
+	FormulaReturnTypeText   = "text"
+	FormulaReturnTypeBool   = "bool"
+)
+
+var (
+	_ Field             = (*FormulaField)(nil)
+	_ SetterFinder      = (*FormulaField)(nil)
+	_ GeneratedValuer   = (*FormulaField)(nil)
+	_ RecordInterceptor = (*FormulaField)(nil)
+)
+
+// FormulaField defines "formula" type field whose value is computed
+// by the database from the other record fields (aka. SQLite generated column).
+//
+// The Expression uses the filter grammar extended with arithmetic operators
+// and scalar functions (see [search.FormulaData]). For example:
+//
+//	qty * price
+//	concat(firstName, ' ', lastName)
+//	if(stock > 0, 'available', 'sold out')
+//
+// The field value is read-only and cannot be changed with record.Set() or the record upsert form.
+//
+// The respective zero record field value is 0, empty string or false
+// depending on the ReturnType.
+type FormulaField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Expression (required) is the formula used to compute the field value.
+	//
+	// Only the same record non-formula fields could be referenced.
+	Expression string `form:"expression" json:"expression"`
+
+	// ReturnType (required) specifies the computed value type.
+	//
+	// It must be one of "number", "text" or "bool".
+	ReturnType string `form:"returnType" json:"returnType"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *FormulaField) Type() string {
+	return FieldTypeFormula
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *FormulaField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *FormulaField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *FormulaField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *FormulaField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *FormulaField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *FormulaField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *FormulaField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *FormulaField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// IsGenerated implements the [GeneratedValuer] interface.
+func (f *FormulaField) IsGenerated() bool {
+	return true
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *FormulaField) ColumnType(app App) string {
+	var columnType string
+	switch f.ReturnType {
+	case FormulaReturnTypeText:
+		columnType = "TEXT"
+	case FormulaReturnTypeBool:
+		columnType = "BOOLEAN"
+	default:
+		columnType = "NUMERIC"
+	}
+
+	expr, err := f.buildSQL()
+	if err != nil {
+		// normally shouldn't happen because the expression is validated with ValidateSettings
+		expr = "NULL"
+	}
+
+	return fmt.Sprintf("%s GENERATED ALWAYS AS (%s) VIRTUAL", columnType, expr)
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *FormulaField) PrepareValue(record *Record, raw any) (any, error) {
+	switch f.ReturnType {
+	case FormulaReturnTypeText:
+		return cast.ToString(raw), nil
+	case FormulaReturnTypeBool:
+		return cast.ToBool(raw), nil
+	default:
+		return cast.ToFloat64(raw), nil
+	}
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *FormulaField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	return nil // computed by the database
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *FormulaField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(
+			&f.ReturnType,
+			validation.Required,
+			validation.In(FormulaReturnTypeNumber, FormulaReturnTypeText, FormulaReturnTypeBool),
+		),
+		validation.Field(
+			&f.Expression,
+			validation.Required,
+			// views don't have generated columns and their field
+			// value is resolved from the view query
+			validation.When(!collection.IsView(), validation.By(f.checkExpression(collection))),
+		),
+	)
+}
+
+func (f *FormulaField) checkExpression(collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		_, err := search.FormulaData(v).BuildSQL(func(name string) (string, error) {
+			field := collection.Fields.GetByName(name)
+			if field == nil {
+				return "", fmt.Errorf("unknown field %q", name)
+			}
+
+			switch field.Type() {
+			case FieldTypeFormula:
+				return "", fmt.Errorf("formula fields cannot reference other formula fields (%q)", name)
+			case FieldTypePassword:
+				return "", fmt.Errorf("password fields cannot be referenced (%q)", name)
+			case FieldTypeDecimal:
+				return "", fmt.Errorf("decimal fields cannot be referenced (%q)", name)
+			}
+
+			if isEncryptedField(field) {
+				return "", fmt.Errorf("encrypted fields cannot be referenced (%q)", name)
+			}
+
+			if field.GetHidden() && !f.Hidden {
+				return "", fmt.Errorf("the hidden field %q can be referenced only by hidden formula fields", name)
+			}
+
+			return "[[" + name + "]]", nil
+		})
+		if err != nil {
+			return validation.NewError("validation_invalid_formula", "Invalid formula expression - "+err.Error()+".")
+		}
+
+		return nil
+	}
+}
+
+func (f *FormulaField) buildSQL() (string, error) {
+	return search.FormulaData(f.Expression).BuildSQL(func(name string) (string, error) {
+		return "[[" + name + "]]", nil
+	})
+}
+
+// FindSetter implements the [SetterFinder] interface.
+func (f *FormulaField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		// return noopSetter to disallow updating the value with record.Set()
+		return noopSetter
+	default:
+		return nil
+	}
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+//
+// It reloads the computed field value after the record is persisted.
+func (f *FormulaField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	switch actionName {
+	case InterceptorActionCreateExecute, InterceptorActionUpdateExecute:
+		if err := actionFunc(); err != nil {
+			return err
+		}
+
+		var raw any
+
+		err := app.DB().Select(f.Name).
+			From(record.Collection().Name).
+			Where(dbx.HashExp{FieldNameId: record.Id}).
+			Limit(1).
+			WithContext(ctx).
+			Row(&raw)
+		if err != nil {
+			return fmt.Errorf("failed to load the %q formula value: %w", f.Name, err)
+		}
+
+		v, err := f.PrepareValue(record, raw)
+		if err != nil {
+			return err
+		}
+
+		record.SetRaw(f.Name, v)
+
+		return nil
+	default:
+		return actionFunc()
+	}
+}
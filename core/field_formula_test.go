@@ -0,0 +1,367 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestFormulaFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeFormula)
+}
+
+func TestFormulaFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name     string
+		field    *core.FormulaField
+		expected string
+	}{
+		{
+			"number",
+			&core.FormulaField{Expression: "a * b", ReturnType: core.FormulaReturnTypeNumber},
+			"NUMERIC GENERATED ALWAYS AS (([[a]] * [[b]])) VIRTUAL",
+		},
+		{
+			"text",
+			&core.FormulaField{Expression: "concat(a, b)", ReturnType: core.FormulaReturnTypeText},
+			"TEXT GENERATED ALWAYS AS (([[a]] || [[b]])) VIRTUAL",
+		},
+		{
+			"bool",
+			&core.FormulaField{Expression: "a > 1", ReturnType: core.FormulaReturnTypeBool},
+			"BOOLEAN GENERATED ALWAYS AS (([[a]] > 1)) VIRTUAL",
+		},
+		{
+			"invalid expression",
+			&core.FormulaField{Expression: "a +", ReturnType: core.FormulaReturnTypeNumber},
+			"NUMERIC GENERATED ALWAYS AS (NULL) VIRTUAL",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if v := s.field.ColumnType(app); v != s.expected {
+				t.Fatalf("Expected\n%q\ngot\n%q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestFormulaFieldPrepareValue(t *testing.T) {
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		returnType string
+		raw        any
+		expected   any
+	}{
+		{core.FormulaReturnTypeNumber, nil, 0.0},
+		{core.FormulaReturnTypeNumber, int64(2), 2.0},
+		{core.FormulaReturnTypeNumber, "1.5", 1.5},
+		{core.FormulaReturnTypeText, nil, ""},
+		{core.FormulaReturnTypeText, 123, "123"},
+		{core.FormulaReturnTypeBool, nil, false},
+		{core.FormulaReturnTypeBool, int64(1), true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s_%#v", i, s.returnType, s.raw), func(t *testing.T) {
+			f := &core.FormulaField{ReturnType: s.returnType}
+
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v != s.expected {
+				t.Fatalf("Expected %#v, got %#v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestFormulaFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeFormula)
+	testDefaultFieldNameValidation(t, core.FieldTypeFormula)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(
+		&core.NumberField{Name: "qty"},
+		&core.NumberField{Name: "price"},
+		&core.TextField{Name: "secret", Hidden: true},
+		&core.PasswordField{Name: "pass"},
+		&core.TextField{Name: "enc", Encrypted: true},
+		&core.DecimalField{Name: "amount", Scale: 2},
+		&core.FormulaField{Name: "other", Expression: "qty", ReturnType: core.FormulaReturnTypeNumber},
+	)
+
+	view := core.NewViewCollection("test_view")
+
+	scenarios := []struct {
+		name         string
+		collection   *core.Collection
+		field        func() *core.FormulaField
+		expectErrors []string
+	}{
+		{
+			"zero",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test"}
+			},
+			[]string{"expression", "returnType"},
+		},
+		{
+			"invalid return type",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "qty", ReturnType: "date"}
+			},
+			[]string{"returnType"},
+		},
+		{
+			"invalid expression syntax",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "qty *", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"unknown field",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "qty * missing", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"formula field reference",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "other + 1", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"password field reference",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "length(pass)", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"encrypted field reference",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "length(enc)", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"decimal field reference",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "amount * 2", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"hidden field reference from non-hidden formula",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "length(secret)", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{"expression"},
+		},
+		{
+			"hidden field reference from hidden formula",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Hidden: true, Expression: "length(secret)", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{},
+		},
+		{
+			"valid expression",
+			collection,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "round(qty * price, 2)", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{},
+		},
+		{
+			"view collection (skip expression check)",
+			view,
+			func() *core.FormulaField {
+				return &core.FormulaField{Id: "test", Name: "test", Expression: "missing", ReturnType: core.FormulaReturnTypeNumber}
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field().ValidateSettings(context.Background(), app, s.collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestFormulaFieldFindSetter(t *testing.T) {
+	field := &core.FormulaField{Name: "test", Expression: "1", ReturnType: core.FormulaReturnTypeNumber}
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(field)
+
+	if f := field.FindSetter("abc"); f != nil {
+		t.Fatal("Expected nil setter")
+	}
+
+	record := core.NewRecord(collection)
+	record.SetRaw("test", 1.0)
+	record.Set("test", 2)
+
+	if v := record.GetFloat("test"); v != 1 {
+		t.Fatalf("Expected the record.Set() call to be ignored, got %v", v)
+	}
+}
+
+func TestFormulaFieldRecordSave(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_formula")
+	collection.Fields.Add(
+		&core.NumberField{Name: "qty"},
+		&core.NumberField{Name: "price"},
+		&core.TextField{Name: "title"},
+		&core.FormulaField{Name: "total", Expression: "qty * price", ReturnType: core.FormulaReturnTypeNumber},
+		&core.FormulaField{Name: "label", Expression: "upper(concat(title, ' x', qty))", ReturnType: core.FormulaReturnTypeText},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, data := range []map[string]any{
+		{"qty": 2, "price": 10, "title": "a"},
+		{"qty": 3, "price": 1.5, "title": "b"},
+		{"qty": 1, "price": 100, "title": "c"},
+	} {
+		record := core.NewRecord(collection)
+		record.Load(data)
+		if err := app.Save(record); err != nil {
+			t.Fatalf("[%d] Failed to save record: %v", i, err)
+		}
+	}
+
+	t.Run("reload after save", func(t *testing.T) {
+		record := core.NewRecord(collection)
+		record.Set("qty", 4)
+		record.Set("price", 2.5)
+		record.Set("title", "test")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if v := record.GetFloat("total"); v != 10 {
+			t.Fatalf("Expected total 10, got %v", v)
+		}
+
+		if v := record.GetString("label"); v != "TEST X4" {
+			t.Fatalf("Expected label %q, got %q", "TEST X4", v)
+		}
+
+		record.Set("qty", 1)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if v := record.GetFloat("total"); v != 2.5 {
+			t.Fatalf("Expected updated total 2.5, got %v", v)
+		}
+
+		if err := app.Delete(record); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("filter and sort", func(t *testing.T) {
+		records, err := app.FindRecordsByFilter(collection, "total >= 5", "-total", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var totals []float64
+		for _, r := range records {
+			totals = append(totals, r.GetFloat("total"))
+		}
+
+		if str := fmt.Sprint(totals); str != "[100 20]" {
+			t.Fatalf("Expected totals [100 20], got %s", str)
+		}
+	})
+
+	t.Run("expression change", func(t *testing.T) {
+		collection.Fields.GetByName("total").(*core.FormulaField).Expression = "qty * price + 1"
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		record, err := app.FindFirstRecordByData(collection, "title", "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := record.GetFloat("total"); v != 21 {
+			t.Fatalf("Expected total 21, got %v", v)
+		}
+	})
+
+	t.Run("referenced field rename and delete", func(t *testing.T) {
+		collection.Fields.GetByName("qty").SetName("quantity")
+		collection.Fields.GetByName("total").(*core.FormulaField).Expression = "quantity * price"
+		collection.Fields.GetByName("label").(*core.FormulaField).Expression = "upper(title)"
+		collection.Fields.RemoveByName("price")
+		collection.Fields.GetByName("total").(*core.FormulaField).Expression = "quantity * 2"
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		record, err := app.FindFirstRecordByData(collection, "title", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := record.GetFloat("total"); v != 6 {
+			t.Fatalf("Expected total 6, got %v", v)
+		}
+
+		if v := record.GetString("label"); v != "B" {
+			t.Fatalf("Expected label %q, got %q", "B", v)
+		}
+	})
+
+	t.Run("removed field still referenced", func(t *testing.T) {
+		collection, err := app.FindCollectionByNameOrId("test_formula")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		collection.Fields.RemoveByName("quantity")
+
+		err = app.Save(collection)
+		tests.TestValidationErrors(t, err, []string{"fields"})
+	})
+}
@@ -59,6 +59,13 @@ type JSONField struct {
 	// Required will require the field value to be non-empty JSON value
 	// (aka. not "null", `""`, "[]", "{}").
 	Required bool `form:"required" json:"required"`
+
+	// Encrypted stores the field value encrypted in the database using
+	// a key derived from the app EncryptionEnv secret.
+	//
+	// The encrypted value is stored as JSON string and the field
+	// can't be used in filter and sort expressions.
+	Encrypted bool `form:"encrypted" json:"encrypted"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -111,6 +118,11 @@ func (f *JSONField) ColumnType(app App) string {
 	return "JSON DEFAULT NULL"
 }
 
+// IsEncrypted implements the [EncryptedValuer] interface.
+func (f *JSONField) IsEncrypted() bool {
+	return f.Encrypted
+}
+
 // PrepareValue implements [Field.PrepareValue] interface method.
 func (f *JSONField) PrepareValue(record *Record, raw any) (any, error) {
 	if str, ok := raw.(string); ok {
@@ -182,6 +194,7 @@ func (f *JSONField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
 		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
 		validation.Field(&f.MaxSize, validation.Min(0), validation.Max(maxSafeJSONInt)),
+		validation.Field(&f.Encrypted, validation.By(checkFieldsEncryptionKey(app))),
 	)
 }
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -192,6 +193,9 @@ func TestJSONFieldValidateSettings(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
 
+	// ensure that the encryption key is not set
+	t.Setenv(app.EncryptionEnv(), "")
+
 	collection := core.NewBaseCollection("test_collection")
 
 	scenarios := []struct {
@@ -242,6 +246,17 @@ func TestJSONFieldValidateSettings(t *testing.T) {
 			},
 			[]string{"maxSize"},
 		},
+		{
+			"encrypted without app encryption key",
+			func() *core.JSONField {
+				return &core.JSONField{
+					Id:        "test",
+					Name:      "test",
+					Encrypted: true,
+				}
+			},
+			[]string{"encrypted"},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -275,3 +290,72 @@ func TestJSONFieldCalculateMaxBodySize(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONFieldEncrypted(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	t.Setenv(app.EncryptionEnv(), "test_key")
+
+	collection := core.NewBaseCollection("test_encrypted")
+	collection.Fields.Add(&core.JSONField{Name: "data", Encrypted: true})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	rawData := func(id string) *string {
+		var raw *string
+		err := app.DB().Select("data").From(collection.Name).Where(dbx.HashExp{"id": id}).Row(&raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	scenarios := []struct {
+		value     any
+		expected  string
+		encrypted bool
+	}{
+		{nil, "null", false},
+		{`{"a":123}`, `{"a":123}`, true},
+		{[]int{1, 2}, `[1,2]`, true},
+		{"test", `"test"`, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			record := core.NewRecord(collection)
+			record.Set("data", s.value)
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+
+			raw := rawData(record.Id)
+			if s.encrypted {
+				if raw == nil || !strings.HasPrefix(*raw, `"pbenc1:`) || strings.Contains(*raw, s.expected) {
+					t.Fatalf("Expected encrypted json string, got %v", raw)
+				}
+			} else if raw != nil {
+				t.Fatalf("Expected NULL, got %q", *raw)
+			}
+
+			found, err := app.FindRecordById(collection, record.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := found.GetString("data"); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		for _, filter := range []string{"data.a = 123", "data != null", "data:length > 0"} {
+			if _, err := app.FindRecordsByFilter(collection, filter, "", 0, 0); err == nil {
+				t.Fatalf("Expected error for filter %q", filter)
+			}
+		}
+	})
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/passwords"
 	"github.com/spf13/cast"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -21,6 +23,9 @@ func init() {
 
 const FieldTypePassword = "password"
 
+// maxPasswordHistorySize is the max allowed PasswordField.HistorySize option value.
+const maxPasswordHistorySize = 24
+
 var (
 	_ Field             = (*PasswordField)(nil)
 	_ GetterFinder      = (*PasswordField)(nil)
@@ -87,6 +92,47 @@ type PasswordField struct {
 	// If explicitly set, must be between [bcrypt.MinCost] and [bcrypt.MaxCost].
 	Cost int `form:"cost" json:"cost"`
 
+	// RequireLowercase requires the field value to contain at least one lowercase letter.
+	RequireLowercase bool `form:"requireLowercase" json:"requireLowercase"`
+
+	// RequireUppercase requires the field value to contain at least one uppercase letter.
+	RequireUppercase bool `form:"requireUppercase" json:"requireUppercase"`
+
+	// RequireDigit requires the field value to contain at least one digit.
+	RequireDigit bool `form:"requireDigit" json:"requireDigit"`
+
+	// RequireSymbol requires the field value to contain at least one
+	// non-alphanumeric character (eg. punctuation, whitespace, etc.).
+	RequireSymbol bool `form:"requireSymbol" json:"requireSymbol"`
+
+	// MinStrength specifies an optional min password strength score
+	// in the range 1-4 (see [passwords.Strength]).
+	//
+	// The auth record email, username and name field values are
+	// also considered as guessable when estimating the score.
+	//
+	// If zero, the strength check is skipped.
+	MinStrength int `form:"minStrength" json:"minStrength"`
+
+	// DisallowBreached rejects passwords that are found in the locally
+	// configured breached passwords source (see [BreachedPasswordsConfig]).
+	//
+	// The check is skipped if there is no configured source.
+	DisallowBreached bool `form:"disallowBreached" json:"disallowBreached"`
+
+	// HistorySize specifies the number of the last password hashes to keep
+	// per record and disallow their reuse (applicable only for auth collections).
+	//
+	// If zero, the password history check is skipped.
+	HistorySize int `form:"historySize" json:"historySize"`
+
+	// MaxAge specifies the max allowed password age in seconds after which
+	// the password has to be reset before authenticating with it again
+	// (applicable only for auth collections).
+	//
+	// If zero, the passwords never expire.
+	MaxAge int64 `form:"maxAge" json:"maxAge"`
+
 	// Required will require the field value to be non-empty string.
 	Required bool `form:"required" json:"required"`
 }
@@ -201,9 +247,117 @@ func (f *PasswordField) ValidateValue(ctx context.Context, app App, record *Reco
 		}
 	}
 
+	if err := f.checkCharClasses(fp.Plain); err != nil {
+		return err
+	}
+
+	if f.MinStrength > 0 {
+		userInputs := make([]string, 0, 3)
+		for _, name := range []string{FieldNameEmail, "username", "name"} {
+			if v := record.GetString(name); v != "" {
+				userInputs = append(userInputs, v)
+			}
+		}
+
+		if passwords.Strength(fp.Plain, userInputs...) < f.MinStrength {
+			return validation.NewError("validation_password_too_weak", "The password is too weak or easy to guess")
+		}
+	}
+
+	if f.DisallowBreached {
+		if source := app.Settings().BreachedPasswords.Source; source != "" {
+			count, err := passwords.BreachedCount(source, fp.Plain)
+			if err != nil {
+				app.Logger().Warn("Failed to check the breached passwords source", "error", err, "source", source)
+			} else if count > 0 {
+				return validation.NewError("validation_password_breached", "The password was found in a known data breach")
+			}
+		}
+	}
+
+	if f.HistorySize > 0 && !record.IsNew() && record.Collection().IsAuth() {
+		reused, err := f.isReused(app, record, fp.Plain)
+		if err != nil {
+			return err
+		}
+		if reused {
+			return validation.NewError(
+				"validation_password_reused",
+				fmt.Sprintf("Must be different from the last %d password(s)", f.HistorySize),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (f *PasswordField) checkCharClasses(plain string) error {
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+
+	for _, c := range plain {
+		switch {
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case !unicode.IsLetter(c):
+			hasSymbol = true
+		}
+	}
+
+	if f.RequireLowercase && !hasLower {
+		return validation.NewError("validation_password_missing_lowercase", "Must contain at least one lowercase letter")
+	}
+
+	if f.RequireUppercase && !hasUpper {
+		return validation.NewError("validation_password_missing_uppercase", "Must contain at least one uppercase letter")
+	}
+
+	if f.RequireDigit && !hasDigit {
+		return validation.NewError("validation_password_missing_digit", "Must contain at least one digit")
+	}
+
+	if f.RequireSymbol && !hasSymbol {
+		return validation.NewError("validation_password_missing_symbol", "Must contain at least one symbol")
+	}
+
 	return nil
 }
 
+// isReused checks whether the plain password matches the current
+// or any of the last HistorySize stored record password hashes.
+func (f *PasswordField) isReused(app App, record *Record, plain string) (bool, error) {
+	hashes := make([]string, 0, f.HistorySize+1)
+
+	if original := record.Original(); original != nil {
+		if hash := original.GetString(f.Name + ":hash"); hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+
+	history, err := app.FindAllPasswordHistoryByRecord(record, f.Name)
+	if err != nil {
+		return false, err
+	}
+
+	for i, entry := range history {
+		if i >= f.HistorySize {
+			break
+		}
+		hashes = append(hashes, entry.Hash())
+	}
+
+	for _, hash := range hashes {
+		if (PasswordFieldValue{Hash: hash}).Validate(plain) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // ValidateSettings implements [Field.ValidateSettings] interface method.
 func (f *PasswordField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
 	return validation.ValidateStruct(f,
@@ -213,6 +367,18 @@ func (f *PasswordField) ValidateSettings(ctx context.Context, app App, collectio
 		validation.Field(&f.Max, validation.Min(f.Min), validation.Max(71)),
 		validation.Field(&f.Cost, validation.Min(bcrypt.MinCost), validation.Max(bcrypt.MaxCost)),
 		validation.Field(&f.Pattern, validation.By(validators.IsRegex)),
+		validation.Field(&f.MinStrength, validation.Min(0), validation.Max(passwords.StrengthVeryStrong)),
+		validation.Field(
+			&f.HistorySize,
+			validation.Min(0),
+			validation.Max(maxPasswordHistorySize),
+			validation.When(!collection.IsAuth(), validation.Empty),
+		),
+		validation.Field(
+			&f.MaxAge,
+			validation.Min(int64(0)),
+			validation.When(!collection.IsAuth(), validation.Empty),
+		),
 	)
 }
 
@@ -241,6 +407,19 @@ func (f *PasswordField) Intercept(
 	actionFunc func() error,
 ) error {
 	switch actionName {
+	case InterceptorActionCreateExecute, InterceptorActionUpdateExecute:
+		fp := f.getPasswordValue(record)
+
+		if err := actionFunc(); err != nil {
+			return err
+		}
+
+		// track the new password change
+		if fp.Plain != "" && record.Collection().IsAuth() && (f.HistorySize > 0 || f.MaxAge > 0) {
+			return f.savePasswordHistory(app, record, fp.Hash)
+		}
+
+		return nil
 	case InterceptorActionAfterCreate, InterceptorActionAfterUpdate:
 		// unset the plain field value after successful create/update
 		fp := f.getPasswordValue(record)
@@ -250,6 +429,35 @@ func (f *PasswordField) Intercept(
 	return actionFunc()
 }
 
+// savePasswordHistory stores the new record password hash and
+// deletes the history entries that are no longer needed.
+func (f *PasswordField) savePasswordHistory(app App, record *Record, hash string) error {
+	entry := NewPasswordHistory(app)
+	entry.SetCollectionRef(record.Collection().Id)
+	entry.SetRecordRef(record.Id)
+	entry.SetField(f.Name)
+	entry.SetHash(hash)
+	if err := app.Save(entry); err != nil {
+		return err
+	}
+
+	history, err := app.FindAllPasswordHistoryByRecord(record, f.Name)
+	if err != nil {
+		return err
+	}
+
+	// always keep the latest entry as password change date reference
+	keep := max(f.HistorySize, 1)
+
+	for i := keep; i < len(history); i++ {
+		if err := app.Delete(history[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // FindGetter implements the [GetterFinder] interface.
 func (f *PasswordField) FindGetter(key string) GetterFunc {
 	switch key {
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/passwords"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -270,8 +273,127 @@ func TestPasswordFieldValidateValue(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"missing lowercase",
+			&core.PasswordField{Name: "test", RequireLowercase: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "ABC123!"})
+				return record
+			},
+			true,
+		},
+		{
+			"missing uppercase",
+			&core.PasswordField{Name: "test", RequireUppercase: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "abc123!"})
+				return record
+			},
+			true,
+		},
+		{
+			"missing digit",
+			&core.PasswordField{Name: "test", RequireDigit: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "abcABC!"})
+				return record
+			},
+			true,
+		},
+		{
+			"missing symbol",
+			&core.PasswordField{Name: "test", RequireSymbol: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "abcABC123"})
+				return record
+			},
+			true,
+		},
+		{
+			"all required character classes",
+			&core.PasswordField{Name: "test", RequireLowercase: true, RequireUppercase: true, RequireDigit: true, RequireSymbol: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "бA1 "}) // multi-byte chars test
+				return record
+			},
+			false,
+		},
+		{
+			"< MinStrength",
+			&core.PasswordField{Name: "test", MinStrength: 3},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "password123"})
+				return record
+			},
+			true,
+		},
+		{
+			"< MinStrength (guessable user inputs)",
+			&core.PasswordField{Name: "test", MinStrength: 3},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("name", "johnsmith")
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "johnsmith2000"})
+				return record
+			},
+			true,
+		},
+		{
+			">= MinStrength",
+			&core.PasswordField{Name: "test", MinStrength: 3},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "v9#Lq2!zR"})
+				return record
+			},
+			false,
+		},
+		{
+			"breached password (DisallowBreached disabled)",
+			&core.PasswordField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "breached123"})
+				return record
+			},
+			false,
+		},
+		{
+			"breached password (DisallowBreached enabled)",
+			&core.PasswordField{Name: "test", DisallowBreached: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "breached123"})
+				return record
+			},
+			true,
+		},
+		{
+			"non-breached password (DisallowBreached enabled)",
+			&core.PasswordField{Name: "test", DisallowBreached: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", &core.PasswordFieldValue{Plain: "not_breached123"})
+				return record
+			},
+			false,
+		},
 	}
 
+	// breached passwords range source
+	breachedDir := t.TempDir()
+	prefix, suffix := passwords.BreachedHashParts("breached123")
+	if err := os.WriteFile(filepath.Join(breachedDir, prefix+".txt"), []byte(suffix+":10"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app.Settings().BreachedPasswords.Source = breachedDir
+
 	for _, s := range scenarios {
 		t.Run(s.name, func(t *testing.T) {
 			err := s.field.ValidateValue(context.Background(), app, s.record())
@@ -429,6 +551,77 @@ func TestPasswordFieldValidateSettings(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"MinStrength < 0",
+			func(col *core.Collection) *core.PasswordField {
+				return &core.PasswordField{
+					Id:          "test",
+					Name:        "test",
+					MinStrength: -1,
+				}
+			},
+			[]string{"minStrength"},
+		},
+		{
+			"MinStrength > 4",
+			func(col *core.Collection) *core.PasswordField {
+				return &core.PasswordField{
+					Id:          "test",
+					Name:        "test",
+					MinStrength: 5,
+				}
+			},
+			[]string{"minStrength"},
+		},
+		{
+			"valid MinStrength",
+			func(col *core.Collection) *core.PasswordField {
+				return &core.PasswordField{
+					Id:          "test",
+					Name:        "test",
+					MinStrength: 4,
+				}
+			},
+			[]string{},
+		},
+		{
+			"HistorySize and MaxAge in non-auth collection",
+			func(col *core.Collection) *core.PasswordField {
+				return &core.PasswordField{
+					Id:          "test",
+					Name:        "test",
+					HistorySize: 1,
+					MaxAge:      1,
+				}
+			},
+			[]string{"historySize", "maxAge"},
+		},
+		{
+			"invalid HistorySize and MaxAge in auth collection",
+			func(col *core.Collection) *core.PasswordField {
+				col.Type = core.CollectionTypeAuth
+				return &core.PasswordField{
+					Id:          "test",
+					Name:        "test",
+					HistorySize: 25,
+					MaxAge:      -1,
+				}
+			},
+			[]string{"historySize", "maxAge"},
+		},
+		{
+			"valid HistorySize and MaxAge in auth collection",
+			func(col *core.Collection) *core.PasswordField {
+				col.Type = core.CollectionTypeAuth
+				return &core.PasswordField{
+					Id:          "test",
+					Name:        "test",
+					HistorySize: 24,
+					MaxAge:      86400,
+				}
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/spf13/cast"
+)
+
+const positionalModifiersPrefix = internalCustomFieldKeyPrefix + "_positionalModifiers_"
+
+// positionalModifier represents the value of the "fieldName@insert"
+// and "fieldName@move" multi-value field setters.
+//
+// It could be specified either as plain value(s), in which case the
+// values are appended, or as object (or its JSON string representation) with:
+//
+//   - "value" - the single value or array of values to insert/move
+//   - "index" - the final position of the first value (negative values are counted from the end, eg. -1 is the last position)
+//   - "before" - an existing value before which to place the values
+//   - "after" - an existing value after which to place the values
+//   - "keys" - fractional ordering keys in the format {"value": key} (see below)
+//
+// If the position is not specified or the "before"/"after" value is
+// missing, the values are placed at the end of the list.
+//
+// With "keys" the remaining list items are assigned their index as ordering key
+// (0, 1, 2...) and each of the specified values is placed according to its
+// fractional key, e.g. {"keys": {"a": 0.5, "b": -1}} places "b" first and "a"
+// between the current first and second item (on equal keys the specified value goes first).
+// This allows multiple values to be positioned in a single operation without
+// having to compute their final indexes.
+type positionalModifier struct {
+	values []string
+	keys   map[string]float64
+	index  *int
+	before string
+	after  string
+}
+
+// pendingPositionalModifier is a positional modifier applied to a
+// record value that is not persisted yet.
+type pendingPositionalModifier struct {
+	modifier     positionalModifier
+	onlyExisting bool
+}
+
+func parsePositionalModifier(raw any) positionalModifier {
+	if str, ok := raw.(string); ok && strings.HasPrefix(strings.TrimSpace(str), "{") {
+		var data map[string]any
+		if err := json.Unmarshal([]byte(str), &data); err == nil {
+			raw = data
+		}
+	}
+
+	data, ok := raw.(map[string]any)
+	if !ok {
+		return positionalModifier{values: list.ToUniqueStringSlice(raw)}
+	}
+
+	result := positionalModifier{
+		values: list.ToUniqueStringSlice(data["value"]),
+		before: cast.ToString(data["before"]),
+		after:  cast.ToString(data["after"]),
+	}
+
+	if v, ok := data["index"]; ok && v != nil {
+		index := cast.ToInt(v)
+		result.index = &index
+	}
+
+	if keys := cast.ToStringMap(data["keys"]); len(keys) > 0 {
+		result.keys = make(map[string]float64, len(keys))
+		for v, key := range keys {
+			if v != "" {
+				result.keys[v] = cast.ToFloat64(key)
+			}
+		}
+	}
+
+	return result
+}
+
+// apply places the modifier values at the specified position of the provided list.
+//
+// Values that already exist in the list are moved to the new position.
+// If onlyExisting is set, the values that are not already in the list are ignored.
+func (p positionalModifier) apply(current []string, onlyExisting bool) []string {
+	if len(p.keys) > 0 {
+		return p.applyKeys(current, onlyExisting)
+	}
+
+	values := p.values
+	if onlyExisting {
+		values = slices.DeleteFunc(slices.Clone(values), func(v string) bool {
+			return !slices.Contains(current, v)
+		})
+	}
+
+	rest := list.SubtractSlice(current, values)
+
+	pos := len(rest)
+	switch {
+	case p.before != "":
+		if i := slices.Index(rest, p.before); i >= 0 {
+			pos = i
+		}
+	case p.after != "":
+		if i := slices.Index(rest, p.after); i >= 0 {
+			pos = i + 1
+		}
+	case p.index != nil:
+		pos = *p.index
+		if pos < 0 {
+			pos = len(rest) + 1 + pos
+		}
+		pos = min(max(pos, 0), len(rest))
+	}
+
+	result := make([]string, 0, len(rest)+len(values))
+	result = append(result, rest[:pos]...)
+	result = append(result, values...)
+	result = append(result, rest[pos:]...)
+
+	return result
+}
+
+func (p positionalModifier) applyKeys(current []string, onlyExisting bool) []string {
+	type item struct {
+		value    string
+		key      float64
+		explicit bool
+	}
+
+	items := make([]item, 0, len(current)+len(p.keys))
+
+	keyed := make([]string, 0, len(p.keys))
+	for v, key := range p.keys {
+		if onlyExisting && !slices.Contains(current, v) {
+			continue
+		}
+		keyed = append(keyed, v)
+		items = append(items, item{value: v, key: key, explicit: true})
+	}
+
+	for i, v := range list.SubtractSlice(current, keyed) {
+		items = append(items, item{value: v, key: float64(i)})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].key != items[j].key {
+			return items[i].key < items[j].key
+		}
+		if items[i].explicit != items[j].explicit {
+			return items[i].explicit
+		}
+		// deterministic order for the explicit values with equal keys
+		return items[i].explicit && items[i].value < items[j].value
+	})
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.value
+	}
+
+	return result
+}
+
+// setPositionalValue applies the raw positional modifier to the field record value.
+//
+// For multi-value fields the modifier is also remembered so that it could be
+// reapplied on top of the latest stored value in case of a concurrent
+// update (see interceptPositionalModifiers).
+//
+// For single-value fields "@insert" replaces the value with the last
+// specified "value" one (the position and "keys" are ignored) and "@move" is a no-op.
+func setPositionalValue(record *Record, fieldName string, isMultiple bool, raw any, onlyExisting bool, setValue func(*Record, any)) {
+	modifier := parsePositionalModifier(raw)
+
+	if !isMultiple {
+		if !onlyExisting && len(modifier.values) > 0 {
+			setValue(record, modifier.values[len(modifier.values)-1])
+		} else {
+			setValue(record, record.GetRaw(fieldName)) // normalize
+		}
+		return
+	}
+
+	setValue(record, modifier.apply(list.ToUniqueStringSlice(record.GetRaw(fieldName)), onlyExisting))
+
+	pending, _ := record.GetRaw(positionalModifiersPrefix + fieldName).([]pendingPositionalModifier)
+	record.SetRaw(positionalModifiersPrefix+fieldName, append(pending, pendingPositionalModifier{
+		modifier:     modifier,
+		onlyExisting: onlyExisting,
+	}))
+}
+
+// interceptPositionalModifiers is a shared [RecordInterceptor] helper
+// for the fields that support the positional modifiers.
+//
+// Before persisting an update of a record with pending positional modifiers,
+// the field value is rebased on the latest stored one, aka. concurrent
+// changes to the same field are preserved and the modifiers are reapplied:
+//
+//   - values removed by the current record change are removed from the latest value
+//   - values added by the current record change are appended to the latest value
+//   - the pending positional modifiers are applied in their original order
+func interceptPositionalModifiers(
+	ctx context.Context,
+	app App,
+	record *Record,
+	field Field,
+	setValue func(*Record, any),
+	actionName string,
+	actionFunc func() error,
+) error {
+	key := positionalModifiersPrefix + field.GetName()
+
+	switch actionName {
+	case InterceptorActionCreateExecute, InterceptorActionUpdateExecute:
+		pending, _ := record.GetRaw(key).([]pendingPositionalModifier)
+		if len(pending) > 0 && !record.IsNew() {
+			if err := rebasePositionalValue(ctx, app, record, field, setValue, pending); err != nil {
+				return err
+			}
+		}
+
+		return actionFunc()
+	case InterceptorActionAfterCreate, InterceptorActionAfterUpdate,
+		InterceptorActionAfterCreateError, InterceptorActionAfterUpdateError:
+		record.SetRaw(key, nil)
+
+		return actionFunc()
+	default:
+		return actionFunc()
+	}
+}
+
+func rebasePositionalValue(
+	ctx context.Context,
+	app App,
+	record *Record,
+	field Field,
+	setValue func(*Record, any),
+	pending []pendingPositionalModifier,
+) error {
+	name := field.GetName()
+
+	latestRecord, err := app.FindRecordById(record.Collection(), cast.ToString(record.LastSavedPK()))
+	if err != nil {
+		return nil // missing or inaccessible (it will be handled by the update execution)
+	}
+
+	original := list.ToUniqueStringSlice(record.Original().GetRaw(name))
+	latest := list.ToUniqueStringSlice(latestRecord.GetRaw(name))
+	if slices.Equal(original, latest) {
+		return nil // no concurrent changes
+	}
+
+	current := list.ToUniqueStringSlice(record.GetRaw(name))
+
+	result := list.SubtractSlice(latest, list.SubtractSlice(original, current))
+	result = append(result, list.SubtractSlice(list.SubtractSlice(current, original), result)...)
+
+	for _, p := range pending {
+		result = p.modifier.apply(result, p.onlyExisting)
+	}
+
+	setValue(record, result)
+
+	// recheck the rebased value (e.g. in case of exceeded max select)
+	if err := field.ValidateValue(ctx, app, record); err != nil {
+		return validation.Errors{name: err}
+	}
+
+	return nil
+}
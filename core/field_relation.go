@@ -18,11 +18,28 @@ func init() {
 
 const FieldTypeRelation = "relation"
 
+// RelationField.OnDelete modes.
+const (
+	// RelationOnDeleteRestrict prevents the deletion of a record that is still referenced.
+	RelationOnDeleteRestrict = "restrict"
+
+	// RelationOnDeleteSetNull removes the deleted record id from the referencing field value.
+	RelationOnDeleteSetNull = "setNull"
+
+	// RelationOnDeleteCascade deletes the referencing record
+	// (or only unsets the deleted id if there are other linked relations).
+	RelationOnDeleteCascade = "cascade"
+
+	// RelationOnDeleteNoAction leaves the referencing field value unchanged.
+	RelationOnDeleteNoAction = "noAction"
+)
+
 var (
-	_ Field        = (*RelationField)(nil)
-	_ MultiValuer  = (*RelationField)(nil)
-	_ DriverValuer = (*RelationField)(nil)
-	_ SetterFinder = (*RelationField)(nil)
+	_ Field             = (*RelationField)(nil)
+	_ MultiValuer       = (*RelationField)(nil)
+	_ DriverValuer      = (*RelationField)(nil)
+	_ SetterFinder      = (*RelationField)(nil)
+	_ RecordInterceptor = (*RelationField)(nil)
 )
 
 // RelationField defines "relation" type field for storing single or
@@ -51,6 +68,26 @@ var (
 //   - "fieldName-" - subtract one or more values from the existing record one. For example:
 //
 //     record.Set("categories-", "old1") // []string{"old2"}
+//
+//   - "fieldName@insert" - insert one or more values at a specific position
+//     (existing values are moved). The position could be specified with "index",
+//     "before" or "after" (multipart/form-data requests could submit it as JSON string). For example:
+//
+//     record.Set("categories@insert", map[string]any{"value": "new1", "index": 1}) // []string{"old1", "new1", "old2"}
+//     record.Set("categories@insert", map[string]any{"value": "new1", "before": "old2"}) // []string{"old1", "new1", "old2"}
+//
+//     record.Set("categories@insert", map[string]any{"keys": map[string]any{"new1": 0.5}}) // []string{"old1", "new1", "old2"}
+//
+//     The "keys" form assigns fractional ordering keys to the specified values
+//     (the remaining values are keyed with their index, aka. 0, 1, 2...).
+//     For single value fields it replaces the current value with the last specified "value" one.
+//
+//   - "fieldName@move" - similar to "fieldName@insert" but moves only the already existing values. For example:
+//
+//     record.Set("categories@move", map[string]any{"value": "old2", "index": 0}) // []string{"old2", "old1"}
+//
+// On update the "@insert" and "@move" modifiers are reapplied on top of the
+// latest stored field value to preserve concurrent changes of the same field.
 type RelationField struct {
 	// Name (required) is the unique name of the field.
 	Name string `form:"name" json:"name"`
@@ -77,8 +114,22 @@ type RelationField struct {
 
 	// CascadeDelete indicates whether the root model should be deleted
 	// in case of delete of all linked relations.
+	//
+	// It is preserved for backward compatibility and it is used only when OnDelete is not set
+	// (if both are set, OnDelete must be "cascade").
 	CascadeDelete bool `form:"cascadeDelete" json:"cascadeDelete"`
 
+	// OnDelete specifies what should happen with the field value
+	// when a referenced record is deleted.
+	//
+	// It could be one of "restrict", "setNull", "cascade" or "noAction".
+	//
+	// If not set, it fallbacks to "cascade" or "setNull" depending on the CascadeDelete option.
+	//
+	// Note that with "setNull" the deletion will fail if the field is
+	// required and the deleted record is its only linked relation.
+	OnDelete string `form:"onDelete" json:"onDelete"`
+
 	// MinSelect indicates the min number of allowed relation records
 	// that could be linked to the main model.
 	//
@@ -148,6 +199,20 @@ func (f *RelationField) IsMultiple() bool {
 	return f.MaxSelect > 1
 }
 
+// OnDeleteMode returns the effective OnDelete mode of the field
+// (fallbacks to the CascadeDelete option if OnDelete is not set).
+func (f *RelationField) OnDeleteMode() string {
+	if f.OnDelete != "" {
+		return f.OnDelete
+	}
+
+	if f.CascadeDelete {
+		return RelationOnDeleteCascade
+	}
+
+	return RelationOnDeleteSetNull
+}
+
 // ColumnType implements [Field.ColumnType] interface method.
 func (f *RelationField) ColumnType(app App) string {
 	if f.IsMultiple() {
@@ -240,9 +305,32 @@ func (f *RelationField) ValidateSettings(ctx context.Context, app App, collectio
 		validation.Field(&f.CollectionId, validation.Required, validation.By(f.checkCollectionId(app, collection))),
 		validation.Field(&f.MinSelect, validation.Min(0)),
 		validation.Field(&f.MaxSelect, validation.When(f.MinSelect > 0, validation.Required), validation.Min(f.MinSelect)),
+		validation.Field(
+			&f.OnDelete,
+			validation.In(
+				RelationOnDeleteRestrict,
+				RelationOnDeleteSetNull,
+				RelationOnDeleteCascade,
+				RelationOnDeleteNoAction,
+			),
+			validation.By(f.checkOnDeleteConflict),
+		),
 	)
 }
 
+func (f *RelationField) checkOnDeleteConflict(value any) error {
+	v, _ := value.(string)
+
+	if f.CascadeDelete && v != "" && v != RelationOnDeleteCascade {
+		return validation.NewError(
+			"validation_field_relation_on_delete_conflict",
+			"The OnDelete mode conflicts with the enabled CascadeDelete option (it must be empty or cascade).",
+		)
+	}
+
+	return nil
+}
+
 func (f *RelationField) checkCollectionId(app App, collection *Collection) validation.RuleFunc {
 	return func(value any) error {
 		v, _ := value.(string)
@@ -307,6 +395,10 @@ func (f *RelationField) FindSetter(key string) SetterFunc {
 		return f.appendValue
 	case f.Name + "-":
 		return f.subtractValue
+	case f.Name + "@insert":
+		return f.insertValue
+	case f.Name + "@move":
+		return f.moveValue
 	default:
 		return nil
 	}
@@ -348,3 +440,25 @@ func (f *RelationField) subtractValue(record *Record, modifierValue any) {
 
 	f.setValue(record, val)
 }
+
+func (f *RelationField) insertValue(record *Record, modifierValue any) {
+	setPositionalValue(record, f.Name, f.IsMultiple(), modifierValue, false, f.setValue)
+}
+
+func (f *RelationField) moveValue(record *Record, modifierValue any) {
+	setPositionalValue(record, f.Name, f.IsMultiple(), modifierValue, true, f.setValue)
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+//
+// It rebases the pending "@insert" and "@move" modifiers on the latest
+// stored field value to preserve concurrent updates of the same record.
+func (f *RelationField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	return interceptPositionalModifiers(ctx, app, record, f, f.setValue, actionName, actionFunc)
+}
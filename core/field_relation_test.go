@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/core"
@@ -82,6 +83,43 @@ func TestRelationFieldIsMultiple(t *testing.T) {
 	}
 }
 
+func TestRelationFieldOnDeleteMode(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		field    *core.RelationField
+		expected string
+	}{
+		{
+			"zero",
+			&core.RelationField{},
+			core.RelationOnDeleteSetNull,
+		},
+		{
+			"CascadeDelete fallback",
+			&core.RelationField{CascadeDelete: true},
+			core.RelationOnDeleteCascade,
+		},
+		{
+			"OnDelete with CascadeDelete",
+			&core.RelationField{CascadeDelete: true, OnDelete: core.RelationOnDeleteRestrict},
+			core.RelationOnDeleteRestrict,
+		},
+		{
+			"OnDelete without CascadeDelete",
+			&core.RelationField{OnDelete: core.RelationOnDeleteNoAction},
+			core.RelationOnDeleteNoAction,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if v := s.field.OnDeleteMode(); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
 func TestRelationFieldPrepareValue(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -467,6 +505,68 @@ func TestRelationFieldValidateSettings(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid OnDelete",
+			func(col *core.Collection) *core.RelationField {
+				return &core.RelationField{
+					Id:           "test",
+					Name:         "test",
+					CollectionId: demo1.Id,
+					OnDelete:     "invalid",
+				}
+			},
+			[]string{"onDelete"},
+		},
+		{
+			"valid OnDelete",
+			func(col *core.Collection) *core.RelationField {
+				return &core.RelationField{
+					Id:           "test",
+					Name:         "test",
+					CollectionId: demo1.Id,
+					OnDelete:     core.RelationOnDeleteRestrict,
+				}
+			},
+			[]string{},
+		},
+		{
+			"CascadeDelete with conflicting OnDelete",
+			func(col *core.Collection) *core.RelationField {
+				return &core.RelationField{
+					Id:            "test",
+					Name:          "test",
+					CollectionId:  demo1.Id,
+					CascadeDelete: true,
+					OnDelete:      core.RelationOnDeleteRestrict,
+				}
+			},
+			[]string{"onDelete"},
+		},
+		{
+			"CascadeDelete with cascade OnDelete",
+			func(col *core.Collection) *core.RelationField {
+				return &core.RelationField{
+					Id:            "test",
+					Name:          "test",
+					CollectionId:  demo1.Id,
+					CascadeDelete: true,
+					OnDelete:      core.RelationOnDeleteCascade,
+				}
+			},
+			[]string{},
+		},
+		{
+			"CascadeDelete without OnDelete",
+			func(col *core.Collection) *core.RelationField {
+				return &core.RelationField{
+					Id:            "test",
+					Name:          "test",
+					CollectionId:  demo1.Id,
+					CascadeDelete: true,
+				}
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -566,6 +666,118 @@ func TestRelationFieldFindSetter(t *testing.T) {
 			true,
 			`["d"]`,
 		},
+		{
+			"insert (single)",
+			"test@insert",
+			map[string]any{"value": "a", "index": 0},
+			&core.RelationField{Name: "test", MaxSelect: 1},
+			true,
+			`"a"`, // replaced with the inserted value
+		},
+		{
+			"move (single)",
+			"test@move",
+			map[string]any{"value": "c", "index": 0},
+			&core.RelationField{Name: "test", MaxSelect: 1},
+			true,
+			`"d"`,
+		},
+		{
+			"insert plain value (multiple)",
+			"test@insert",
+			"a",
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["c","d","a"]`,
+		},
+		{
+			"insert at index (multiple)",
+			"test@insert",
+			map[string]any{"value": []string{"a", "b"}, "index": 1},
+			&core.RelationField{Name: "test", MaxSelect: 4},
+			true,
+			`["c","a","b","d"]`,
+		},
+		{
+			"insert at negative index (multiple)",
+			"test@insert",
+			map[string]any{"value": "a", "index": -2},
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["c","a","d"]`,
+		},
+		{
+			"insert out of range index (multiple)",
+			"test@insert",
+			map[string]any{"value": "a", "index": 10},
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["c","d","a"]`,
+		},
+		{
+			"insert before (multiple)",
+			"test@insert",
+			`{"value":"a","before":"c"}`,
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["a","c","d"]`,
+		},
+		{
+			"insert after missing value (multiple)",
+			"test@insert",
+			map[string]any{"value": "a", "after": "missing"},
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["c","d","a"]`,
+		},
+		{
+			"insert existing value (multiple)",
+			"test@insert",
+			map[string]any{"value": []string{"a", "d"}, "after": "c"},
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["c","a","d"]`,
+		},
+		{
+			"move (multiple)",
+			"test@move",
+			map[string]any{"value": []string{"a", "d"}, "index": 0},
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["d","c"]`,
+		},
+		{
+			"move after (multiple)",
+			"test@move",
+			map[string]any{"value": "c", "after": "d"},
+			&core.RelationField{Name: "test", MaxSelect: 3},
+			true,
+			`["d","c"]`,
+		},
+		{
+			"insert with fractional keys (multiple)",
+			"test@insert",
+			map[string]any{"keys": map[string]any{"a": 0.5, "b": -1}},
+			&core.RelationField{Name: "test", MaxSelect: 4},
+			true,
+			`["b","c","a","d"]`,
+		},
+		{
+			"insert with equal fractional keys (multiple)",
+			"test@insert",
+			`{"keys":{"b":1,"a":1}}`,
+			&core.RelationField{Name: "test", MaxSelect: 4},
+			true,
+			`["c","a","b","d"]`,
+		},
+		{
+			"move with fractional keys (multiple)",
+			"test@move",
+			map[string]any{"keys": map[string]any{"d": 0, "a": 0.5, "c": "1.5"}},
+			&core.RelationField{Name: "test", MaxSelect: 4},
+			true,
+			`["d","c"]`,
+		},
 	}
 
 	for _, s := range scenarios {
@@ -601,3 +813,71 @@ func TestRelationFieldFindSetter(t *testing.T) {
 		})
 	}
 }
+
+func TestRelationFieldInterceptPositionalModifiers(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		modify   func(record *core.Record)
+		expected []string
+	}{
+		{
+			"direct setter",
+			func(record *core.Record) {
+				record.Set("rel_many@move", map[string]any{"value": "oap640cot4yru2s", "index": 0})
+			},
+			[]string{"oap640cot4yru2s", "4q1xlclmfloku33"},
+		},
+		{
+			"ReplaceModifiers and Load",
+			func(record *core.Record) {
+				record.Load(record.ReplaceModifiers(map[string]any{
+					"rel_many@insert": map[string]any{"value": "bgs820n361vj1qd", "before": "4q1xlclmfloku33"},
+				}))
+			},
+			[]string{"oap640cot4yru2s", "bgs820n361vj1qd", "4q1xlclmfloku33"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			record, err := app.FindRecordById("demo1", "al1h9ijdeojtsjy")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// concurrent update of the same field
+			concurrent, err := app.FindRecordById("demo1", "al1h9ijdeojtsjy")
+			if err != nil {
+				t.Fatal(err)
+			}
+			concurrent.Set("rel_many-", "bgs820n361vj1qd")
+			concurrent.Set("rel_many@move", map[string]any{"value": "oap640cot4yru2s", "index": 0})
+			if err := app.Save(concurrent); err != nil {
+				t.Fatal(err)
+			}
+
+			s.modify(record)
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+
+			if v := record.GetStringSlice("rel_many"); !slices.Equal(v, s.expected) {
+				t.Fatalf("Expected record value %v, got %v", s.expected, v)
+			}
+
+			stored, err := app.FindRecordById("demo1", "al1h9ijdeojtsjy")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v := stored.GetStringSlice("rel_many"); !slices.Equal(v, s.expected) {
+				t.Fatalf("Expected stored value %v, got %v", s.expected, v)
+			}
+
+		})
+	}
+}
@@ -19,10 +19,11 @@ func init() {
 const FieldTypeSelect = "select"
 
 var (
-	_ Field        = (*SelectField)(nil)
-	_ MultiValuer  = (*SelectField)(nil)
-	_ DriverValuer = (*SelectField)(nil)
-	_ SetterFinder = (*SelectField)(nil)
+	_ Field             = (*SelectField)(nil)
+	_ MultiValuer       = (*SelectField)(nil)
+	_ DriverValuer      = (*SelectField)(nil)
+	_ SetterFinder      = (*SelectField)(nil)
+	_ RecordInterceptor = (*SelectField)(nil)
 )
 
 // SelectField defines "select" type field for storing single or
@@ -51,6 +52,26 @@ var (
 //   - "fieldName-" - subtract one or more values from the existing record one. For example:
 //
 //     record.Set("roles-", "old1") // []string{"old2"}
+//
+//   - "fieldName@insert" - insert one or more values at a specific position
+//     (existing values are moved). The position could be specified with "index",
+//     "before" or "after" (multipart/form-data requests could submit it as JSON string). For example:
+//
+//     record.Set("roles@insert", map[string]any{"value": "new1", "index": 1}) // []string{"old1", "new1", "old2"}
+//     record.Set("roles@insert", map[string]any{"value": "new1", "before": "old2"}) // []string{"old1", "new1", "old2"}
+//
+//     record.Set("roles@insert", map[string]any{"keys": map[string]any{"new1": 0.5}}) // []string{"old1", "new1", "old2"}
+//
+//     The "keys" form assigns fractional ordering keys to the specified values
+//     (the remaining values are keyed with their index, aka. 0, 1, 2...).
+//     For single value fields it replaces the current value with the last specified "value" one.
+//
+//   - "fieldName@move" - similar to "fieldName@insert" but moves only the already existing values. For example:
+//
+//     record.Set("roles@move", map[string]any{"value": "old2", "index": 0}) // []string{"old2", "old1"}
+//
+// On update the "@insert" and "@move" modifiers are reapplied on top of the
+// latest stored field value to preserve concurrent changes of the same field.
 type SelectField struct {
 	// Name (required) is the unique name of the field.
 	Name string `form:"name" json:"name"`
@@ -232,6 +253,10 @@ func (f *SelectField) FindSetter(key string) SetterFunc {
 		return f.appendValue
 	case f.Name + "-":
 		return f.subtractValue
+	case f.Name + "@insert":
+		return f.insertValue
+	case f.Name + "@move":
+		return f.moveValue
 	default:
 		return nil
 	}
@@ -273,3 +298,25 @@ func (f *SelectField) subtractValue(record *Record, modifierValue any) {
 
 	f.setValue(record, val)
 }
+
+func (f *SelectField) insertValue(record *Record, modifierValue any) {
+	setPositionalValue(record, f.Name, f.IsMultiple(), modifierValue, false, f.setValue)
+}
+
+func (f *SelectField) moveValue(record *Record, modifierValue any) {
+	setPositionalValue(record, f.Name, f.IsMultiple(), modifierValue, true, f.setValue)
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+//
+// It rebases the pending "@insert" and "@move" modifiers on the latest
+// stored field value to preserve concurrent updates of the same record.
+func (f *SelectField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	return interceptPositionalModifiers(ctx, app, record, f, f.setValue, actionName, actionFunc)
+}
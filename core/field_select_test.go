@@ -479,6 +479,118 @@ func TestSelectFieldFindSetter(t *testing.T) {
 			true,
 			`["d"]`,
 		},
+		{
+			"insert (single)",
+			"test@insert",
+			map[string]any{"value": "a", "index": 0},
+			&core.SelectField{Name: "test", MaxSelect: 1, Values: values},
+			true,
+			`"a"`, // replaced with the inserted value
+		},
+		{
+			"move (single)",
+			"test@move",
+			map[string]any{"value": "c", "index": 0},
+			&core.SelectField{Name: "test", MaxSelect: 1, Values: values},
+			true,
+			`"d"`,
+		},
+		{
+			"insert plain value (multiple)",
+			"test@insert",
+			"a",
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["c","d","a"]`,
+		},
+		{
+			"insert at index (multiple)",
+			"test@insert",
+			map[string]any{"value": []string{"a", "b"}, "index": 1},
+			&core.SelectField{Name: "test", MaxSelect: 4, Values: values},
+			true,
+			`["c","a","b","d"]`,
+		},
+		{
+			"insert at negative index (multiple)",
+			"test@insert",
+			map[string]any{"value": "a", "index": -2},
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["c","a","d"]`,
+		},
+		{
+			"insert out of range index (multiple)",
+			"test@insert",
+			map[string]any{"value": "a", "index": 10},
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["c","d","a"]`,
+		},
+		{
+			"insert before (multiple)",
+			"test@insert",
+			`{"value":"a","before":"c"}`,
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["a","c","d"]`,
+		},
+		{
+			"insert after missing value (multiple)",
+			"test@insert",
+			map[string]any{"value": "a", "after": "missing"},
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["c","d","a"]`,
+		},
+		{
+			"insert existing value (multiple)",
+			"test@insert",
+			map[string]any{"value": []string{"a", "d"}, "after": "c"},
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["c","a","d"]`,
+		},
+		{
+			"move (multiple)",
+			"test@move",
+			map[string]any{"value": []string{"a", "d"}, "index": 0},
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["d","c"]`,
+		},
+		{
+			"move after (multiple)",
+			"test@move",
+			map[string]any{"value": "c", "after": "d"},
+			&core.SelectField{Name: "test", MaxSelect: 3, Values: values},
+			true,
+			`["d","c"]`,
+		},
+		{
+			"insert with fractional keys (multiple)",
+			"test@insert",
+			map[string]any{"keys": map[string]any{"a": 0.5, "b": -1}},
+			&core.SelectField{Name: "test", MaxSelect: 4, Values: values},
+			true,
+			`["b","c","a","d"]`,
+		},
+		{
+			"insert with equal fractional keys (multiple)",
+			"test@insert",
+			`{"keys":{"b":1,"a":1}}`,
+			&core.SelectField{Name: "test", MaxSelect: 4, Values: values},
+			true,
+			`["c","a","b","d"]`,
+		},
+		{
+			"move with fractional keys (multiple)",
+			"test@move",
+			map[string]any{"keys": map[string]any{"d": 0, "a": 0.5, "c": "1.5"}},
+			&core.SelectField{Name: "test", MaxSelect: 4, Values: values},
+			true,
+			`["d","c"]`,
+		},
 	}
 
 	for _, s := range scenarios {
@@ -91,6 +91,12 @@ type TextField struct {
 	//
 	// A single collection can have only 1 field marked as primary key.
 	PrimaryKey bool `form:"primaryKey" json:"primaryKey"`
+
+	// Encrypted stores the field value encrypted in the database using
+	// a key derived from the app EncryptionEnv secret.
+	//
+	// Encrypted fields can't be used in filter and sort expressions.
+	Encrypted bool `form:"encrypted" json:"encrypted"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -150,6 +156,11 @@ func (f *TextField) ColumnType(app App) string {
 	return "TEXT DEFAULT '' NOT NULL"
 }
 
+// IsEncrypted implements the [EncryptedValuer] interface.
+func (f *TextField) IsEncrypted() bool {
+	return f.Encrypted
+}
+
 // PrepareValue implements [Field.PrepareValue] interface method.
 func (f *TextField) PrepareValue(record *Record, raw any) (any, error) {
 	return cast.ToString(raw), nil
@@ -265,6 +276,11 @@ func (f *TextField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Hidden, validation.When(f.PrimaryKey, validation.Empty)),
 		validation.Field(&f.Required, validation.When(f.PrimaryKey, validation.Required)),
 		validation.Field(&f.AutogeneratePattern, validation.By(validators.IsRegex), validation.By(f.checkAutogeneratePattern)),
+		validation.Field(
+			&f.Encrypted,
+			validation.When(f.PrimaryKey, validation.Empty.Error("Primary keys cannot be encrypted.")),
+			validation.By(checkFieldsEncryptionKey(app)),
+		),
 	)
 }
 
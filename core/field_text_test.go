@@ -6,8 +6,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestTextFieldBaseMethods(t *testing.T) {
@@ -295,6 +297,9 @@ func TestTextFieldValidateSettings(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
 
+	// ensure that the encryption key is not set
+	t.Setenv(app.EncryptionEnv(), "")
+
 	scenarios := []struct {
 		name         string
 		field        func() *core.TextField
@@ -474,6 +479,17 @@ func TestTextFieldValidateSettings(t *testing.T) {
 			},
 			[]string{"min"},
 		},
+		{
+			"encrypted without app encryption key",
+			func() *core.TextField {
+				return &core.TextField{
+					Id:        "test",
+					Name:      "test",
+					Encrypted: true,
+				}
+			},
+			[]string{"encrypted"},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -652,3 +668,180 @@ func TestTextFieldFindSetter(t *testing.T) {
 		})
 	}
 }
+
+func TestTextFieldEncrypted(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	t.Setenv(app.EncryptionEnv(), "old_key")
+
+	collection := core.NewBaseCollection("test_encrypted")
+	collection.Fields.Add(&core.TextField{Name: "secret", Encrypted: true})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("secret", "abc")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	rawSecret := func() string {
+		var raw string
+		err := app.DB().Select("secret").From(collection.Name).Where(dbx.HashExp{"id": record.Id}).Row(&raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	oldRaw := rawSecret()
+	if !strings.HasPrefix(oldRaw, "pbenc1:") || strings.Contains(oldRaw, "abc") {
+		t.Fatalf("Expected the stored value to be encrypted, got %q", oldRaw)
+	}
+
+	// rotate the key
+	t.Setenv(app.EncryptionEnv(), "new_key")
+	t.Setenv(app.EncryptionEnv()+"_PREVIOUS", "other_key, old_key")
+
+	found, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := found.GetString("secret"); v != "abc" {
+		t.Fatalf("Expected the decrypted value %q, got %q", "abc", v)
+	}
+
+	// resave with the new key
+	if err := app.Save(found); err != nil {
+		t.Fatal(err)
+	}
+
+	newRaw := rawSecret()
+	if newRaw == oldRaw || !strings.HasPrefix(newRaw, "pbenc1:") || strings.Split(newRaw, ":")[1] == strings.Split(oldRaw, ":")[1] {
+		t.Fatalf("Expected the stored value to be reencrypted with the new key, got %q (old %q)", newRaw, oldRaw)
+	}
+
+	// missing key (the zero value is loaded and the stored value is kept as it is if unchanged)
+	t.Setenv(app.EncryptionEnv()+"_PREVIOUS", "")
+	t.Setenv(app.EncryptionEnv(), "another_key")
+	undecrypted, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatalf("Expected the record to be loaded with the undecrypted value, got %v", err)
+	}
+	if v := undecrypted.GetString("secret"); v != "" {
+		t.Fatalf("Expected empty value for the undecryptable field, got %q", v)
+	}
+	if !undecrypted.HasUndecryptableValue("secret") {
+		t.Fatal("Expected the secret field to be marked as undecryptable")
+	}
+	if err := app.Save(undecrypted); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawSecret(); raw != newRaw {
+		t.Fatalf("Expected the stored value to remain %q, got %q", newRaw, raw)
+	}
+	// saved again after the PostScan refresh
+	if err := app.Save(undecrypted); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawSecret(); raw != newRaw {
+		t.Fatalf("Expected the stored value to remain %q after a second save, got %q", newRaw, raw)
+	}
+	t.Setenv(app.EncryptionEnv(), "new_key")
+
+	// submitted values that look encrypted are encrypted as any other value
+	submitted, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	submitted.Set("secret", newRaw)
+	if err := app.Save(submitted); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawSecret(); raw == newRaw || !strings.HasPrefix(raw, "pbenc1:") {
+		t.Fatalf("Expected the submitted value to be encrypted, got %q", raw)
+	}
+	submitted, err = app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := submitted.GetString("secret"); v != newRaw {
+		t.Fatalf("Expected the submitted value %q to be decrypted as it is, got %q", newRaw, v)
+	}
+
+	// cipher text copied from another record
+	other := core.NewRecord(collection)
+	other.Set("secret", "other_secret")
+	if err := app.Save(other); err != nil {
+		t.Fatal(err)
+	}
+	var otherRaw string
+	if err := app.DB().Select("secret").From(collection.Name).Where(dbx.HashExp{"id": other.Id}).Row(&otherRaw); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.DB().Update(collection.Name, dbx.Params{"secret": otherRaw}, dbx.HashExp{"id": record.Id}).Execute(); err != nil {
+		t.Fatal(err)
+	}
+	copied, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := copied.GetString("secret"); v != "" || !copied.HasUndecryptableValue("secret") {
+		t.Fatalf("Expected the copied cipher text to fail to be decrypted, got %q", v)
+	}
+
+	// changing an undecryptable value encrypts the new one
+	copied.Set("secret", "replaced")
+	if err := app.Save(copied); err != nil {
+		t.Fatal(err)
+	}
+	replaced, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := replaced.GetString("secret"); v != "replaced" || replaced.HasUndecryptableValue("secret") {
+		t.Fatalf("Expected the replaced value %q, got %q", "replaced", v)
+	}
+
+	// legacy plain values with the encrypted value prefix
+	legacyPlain := "pbenc1:not encrypted"
+	if _, err := app.DB().Update(collection.Name, dbx.Params{"secret": legacyPlain}, dbx.HashExp{"id": record.Id}).Execute(); err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := legacy.GetString("secret"); v != legacyPlain {
+		t.Fatalf("Expected the legacy plain value %q, got %q", legacyPlain, v)
+	}
+	if err := app.Save(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawSecret(); !strings.HasPrefix(raw, "pbenc1:") || raw == legacyPlain {
+		t.Fatalf("Expected the legacy plain value to be encrypted on save, got %q", raw)
+	}
+
+	// empty values are not encrypted
+	record.Set("secret", "")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawSecret(); raw != "" {
+		t.Fatalf("Expected empty stored value, got %q", raw)
+	}
+
+	t.Run("filter and sort", func(t *testing.T) {
+		for _, s := range [][2]string{{"secret = 'abc'", ""}, {"secret:lower = 'abc'", ""}, {"id != ''", "secret"}} {
+			if _, err := app.FindRecordsByFilter(collection, s[0], s[1], 0, 0); err == nil {
+				t.Fatalf("Expected error for filter %q and sort %q", s[0], s[1])
+			}
+		}
+
+		collection.ListRule = types.Pointer("secret != ''")
+		tests.TestValidationErrors(t, app.Save(collection), []string{"listRule"})
+	})
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*Job)(nil)
+
+const JobsTableName = "_jobs"
+
+const (
+	// JobStatusPending is the status of a job waiting to be run (or retried).
+	JobStatusPending = "pending"
+
+	// JobStatusRunning is the status of a job currently processed by a worker.
+	JobStatusRunning = "running"
+
+	// JobStatusDead is the status of a job that has exhausted all its attempts
+	// (aka. dead-letter) and will not be run again unless explicitly retried.
+	JobStatusDead = "dead"
+)
+
+// Job defines a single background job from the persistent job queue.
+type Job struct {
+	BaseModel
+
+	// Name is the name of the registered job handler (see [App.RegisterJob]).
+	Name string `db:"name" json:"name"`
+
+	// Payload is the serialized job payload data.
+	Payload types.JSONRaw `db:"payload" json:"payload"`
+
+	// Status is the current job state (could be any of the JobStatus* constants).
+	Status string `db:"status" json:"status"`
+
+	// Priority is the job priority (jobs with higher priority are run first).
+	Priority int `db:"priority" json:"priority"`
+
+	// UniqueKey is an optional key that prevents enqueuing more than one
+	// pending or running job with the same key.
+	UniqueKey string `db:"uniqueKey" json:"uniqueKey"`
+
+	// Attempts is the number of the already started run attempts.
+	Attempts int `db:"attempts" json:"attempts"`
+
+	// MaxAttempts is the max number of run attempts before moving the job
+	// to the dead-letter state.
+	MaxAttempts int `db:"maxAttempts" json:"maxAttempts"`
+
+	// RunAt is the earliest time when the job could be run.
+	RunAt types.DateTime `db:"runAt" json:"runAt"`
+
+	// LastError is the error message of the last failed run attempt.
+	LastError string `db:"lastError" json:"lastError"`
+
+	Created types.DateTime `db:"created" json:"created"`
+	Updated types.DateTime `db:"updated" json:"updated"`
+}
+
+func (m *Job) TableName() string {
+	return JobsTableName
+}
+
+// UnmarshalPayload unmarshals the job payload into the provided result.
+func (m *Job) UnmarshalPayload(result any) error {
+	return json.Unmarshal(m.Payload, result)
+}
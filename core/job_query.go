@@ -0,0 +1,21 @@
+package core
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// FindJobById finds a single background job by its id.
+func (app *BaseApp) FindJobById(id string) (*Job, error) {
+	model := &Job{}
+
+	err := app.AuxModelQuery(model).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
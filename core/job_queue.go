@@ -0,0 +1,381 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// maxJobRetryDelay is the max delay between two run attempts of a failed job.
+const maxJobRetryDelay = 24 * time.Hour
+
+// jobQueueWorkerInterval is the max interval between two job queue checks
+// (the worker is also notified on each newly enqueued job).
+const jobQueueWorkerInterval = 5 * time.Second
+
+// JobHandler defines a background job handler function (see [App.RegisterJob]).
+//
+// The provided ctx is canceled when the job is canceled while running.
+// Returning an error marks the run attempt as failed.
+type JobHandler func(ctx context.Context, job *Job) error
+
+// JobOptions defines the optional [App.EnqueueJob] settings.
+type JobOptions struct {
+	// Delay is the min duration to wait before running the job.
+	Delay time.Duration
+
+	// Priority is the job priority (jobs with higher priority are run first).
+	Priority int
+
+	// UniqueKey is an optional job key that prevents enqueuing
+	// more than one pending or running job with the same key.
+	UniqueKey string
+
+	// MaxAttempts is the max number of run attempts before moving the job
+	// to the dead-letter state (if zero, fallbacks to the Jobs.MaxAttempts setting).
+	MaxAttempts int
+}
+
+// jobQueue holds the registered job handlers and the job queue worker state.
+type jobQueue struct {
+	queueWorker
+
+	handlersMu sync.RWMutex
+	handlers   map[string]JobHandler
+
+	runningMu sync.Mutex
+	running   map[string]*runningJob
+}
+
+type runningJob struct {
+	cancel   context.CancelFunc
+	canceled bool
+}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		handlers: map[string]JobHandler{},
+		running:  map[string]*runningJob{},
+	}
+}
+
+// RegisterJob registers (or replaces) the handler of the named background job.
+func (app *BaseApp) RegisterJob(name string, handler JobHandler) {
+	app.jobQueue.handlersMu.Lock()
+	defer app.jobQueue.handlersMu.Unlock()
+
+	app.jobQueue.handlers[name] = handler
+}
+
+// EnqueueJob stores a new job with the provided payload in the persistent job queue
+// for later processing by the job queue workers.
+//
+// The payload is serialized as JSON (see [Job.UnmarshalPayload]).
+//
+// If opts.UniqueKey is set and there is already a pending or running job
+// with the same key, no new job is created and the existing one is returned.
+func (app *BaseApp) EnqueueJob(name string, payload any, opts *JobOptions) (*Job, error) {
+	if name == "" {
+		return nil, errors.New("missing job name")
+	}
+
+	if opts == nil {
+		opts = &JobOptions{}
+	}
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the job payload: %w", err)
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = app.Settings().Jobs.MaxAttempts
+	}
+
+	now := types.NowDateTime()
+
+	job := &Job{
+		Name:        name,
+		Payload:     rawPayload,
+		Status:      JobStatusPending,
+		Priority:    opts.Priority,
+		UniqueKey:   opts.UniqueKey,
+		MaxAttempts: max(maxAttempts, 1),
+		RunAt:       now.Add(opts.Delay),
+		Created:     now,
+		Updated:     now,
+	}
+	job.Id = GenerateDefaultRandomId()
+
+	var existing *Job
+
+	err = app.AuxRunInTransaction(func(txApp App) error {
+		if job.UniqueKey != "" {
+			existing, _ = findActiveJobByUniqueKey(txApp, job.UniqueKey)
+			if existing != nil {
+				return nil
+			}
+		}
+
+		return txApp.AuxSave(job)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return existing, nil
+	}
+
+	app.jobQueue.notify()
+
+	return job, nil
+}
+
+func findActiveJobByUniqueKey(app App, uniqueKey string) (*Job, error) {
+	job := &Job{}
+
+	err := app.AuxModelQuery(job).
+		AndWhere(dbx.HashExp{
+			"uniqueKey": uniqueKey,
+			"status":    []any{JobStatusPending, JobStatusRunning},
+		}).
+		Limit(1).
+		One(job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// RetryJob reschedules the provided pending or dead job to run
+// as soon as possible with reset attempts counter.
+func (app *BaseApp) RetryJob(job *Job) error {
+	if job.Status == JobStatusRunning {
+		return errors.New("the job is already running")
+	}
+
+	job.Status = JobStatusPending
+	job.Attempts = 0
+	job.LastError = ""
+	job.Updated = types.NowDateTime()
+	job.RunAt = job.Updated
+
+	if err := app.AuxSave(job); err != nil {
+		return err
+	}
+
+	app.jobQueue.notify()
+
+	return nil
+}
+
+// CancelJob removes the provided job from the job queue.
+//
+// If the job is currently running, its handler context is also canceled
+// and the handler result is discarded.
+func (app *BaseApp) CancelJob(job *Job) error {
+	if err := app.AuxDelete(job); err != nil {
+		return err
+	}
+
+	app.jobQueue.runningMu.Lock()
+	if r, ok := app.jobQueue.running[job.Id]; ok {
+		r.canceled = true
+		r.cancel()
+	}
+	app.jobQueue.runningMu.Unlock()
+
+	return nil
+}
+
+// ProcessJobs runs all queued jobs that are due using a pool of
+// Jobs.Concurrency workers and returns once there are no more due jobs.
+//
+// Successfully completed jobs are removed from the queue.
+// Failed jobs are rescheduled with exponential backoff until their
+// MaxAttempts is reached, after which they are moved to the dead-letter state.
+func (app *BaseApp) ProcessJobs() error {
+	app.jobQueue.processMu.Lock()
+	defer app.jobQueue.processMu.Unlock()
+
+	concurrency := max(app.Settings().Jobs.Concurrency, 1)
+
+	errs := make([]error, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range concurrency {
+		wg.Go(func() {
+			for {
+				job, err := app.claimNextJob()
+				if err != nil {
+					errs[i] = err
+					return
+				}
+
+				if job == nil {
+					return // no more due jobs
+				}
+
+				if err := app.runJob(job); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// claimNextJob atomically marks the next due job as running and returns it.
+//
+// Returns nil job if there are no due jobs.
+func (app *BaseApp) claimNextJob() (*Job, error) {
+	now := types.NowDateTime().String()
+
+	job := &Job{}
+
+	err := app.AuxNonconcurrentDB().NewQuery(`
+		UPDATE {{` + JobsTableName + `}}
+		SET [[status]] = {:running}, [[attempts]] = [[attempts]] + 1, [[updated]] = {:now}
+		WHERE [[id]] = (
+			SELECT [[id]] FROM {{` + JobsTableName + `}}
+			WHERE [[status]] = {:pending} AND [[runAt]] <= {:now}
+			ORDER BY [[priority]] DESC, [[runAt]] ASC, [[rowid]] ASC
+			LIMIT 1
+		)
+		RETURNING *
+	`).Bind(dbx.Params{
+		"running": JobStatusRunning,
+		"pending": JobStatusPending,
+		"now":     now,
+	}).One(job)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// runJob executes the handler of the provided claimed job and updates its queue state.
+//
+// Returns an error only if the queue state couldn't be updated.
+func (app *BaseApp) runJob(job *Job) error {
+	app.jobQueue.handlersMu.RLock()
+	handler := app.jobQueue.handlers[job.Name]
+	app.jobQueue.handlersMu.RUnlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	state := &runningJob{cancel: cancel}
+
+	app.jobQueue.runningMu.Lock()
+	app.jobQueue.running[job.Id] = state
+	app.jobQueue.runningMu.Unlock()
+
+	var runErr error
+	if handler == nil {
+		runErr = fmt.Errorf("missing %q job handler", job.Name)
+	} else {
+		runErr = callJobHandler(ctx, handler, job)
+	}
+
+	app.jobQueue.runningMu.Lock()
+	delete(app.jobQueue.running, job.Id)
+	canceled := state.canceled
+	app.jobQueue.runningMu.Unlock()
+
+	if canceled {
+		return nil // already removed from the queue
+	}
+
+	job.Updated = types.NowDateTime()
+
+	if runErr == nil {
+		return app.AuxDelete(job)
+	}
+
+	job.LastError = runErr.Error()
+
+	if job.Attempts >= max(job.MaxAttempts, 1) {
+		job.Status = JobStatusDead
+
+		app.Logger().Warn(
+			"Job failed and was moved to the dead-letter queue",
+			"error", runErr,
+			"jobId", job.Id,
+			"jobName", job.Name,
+			"attempts", job.Attempts,
+		)
+	} else {
+		job.Status = JobStatusPending
+		job.RunAt = job.Updated.Add(app.Settings().Jobs.RetryDelayTime(job.Attempts))
+	}
+
+	return app.AuxSave(job)
+}
+
+// callJobHandler calls the job handler and normalizes its panics to errors.
+func callJobHandler(ctx context.Context, handler JobHandler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+func (app *BaseApp) registerJobQueueHooks() {
+	app.OnServe().Bind(&hook.Handler[*ServeEvent]{
+		Id: "__pbJobQueueWorkerStart__",
+		Func: func(e *ServeEvent) error {
+			// reset the jobs interrupted by a previous process termination
+			_, err := app.AuxNonconcurrentDB().Update(
+				JobsTableName,
+				dbx.Params{"status": JobStatusPending},
+				dbx.HashExp{"status": JobStatusRunning},
+			).Execute()
+			if err != nil {
+				app.Logger().Warn("Failed to reset the interrupted jobs", "error", err)
+			}
+
+			app.jobQueue.start(jobQueueWorkerInterval, func() {
+				if err := app.ProcessJobs(); err != nil {
+					app.Logger().Warn("Failed to process the job queue", "error", err)
+				}
+			})
+
+			return e.Next()
+		},
+		Priority: 999,
+	})
+
+	app.OnTerminate().Bind(&hook.Handler[*TerminateEvent]{
+		Id: "__pbJobQueueWorkerStop__",
+		Func: func(e *TerminateEvent) error {
+			app.jobQueue.halt()
+
+			return e.Next()
+		},
+		Priority: -999,
+	})
+}
@@ -0,0 +1,390 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestEnqueueJob(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Jobs.MaxAttempts = 4
+
+	t.Run("missing name", func(t *testing.T) {
+		if _, err := app.EnqueueJob("", nil, nil); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("non-serializable payload", func(t *testing.T) {
+		if _, err := app.EnqueueJob("test", func() {}, nil); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		job, err := app.EnqueueJob("test", map[string]any{"a": 1}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := app.FindJobById(job.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stored.Status != core.JobStatusPending {
+			t.Fatalf("Expected status %q, got %q", core.JobStatusPending, stored.Status)
+		}
+
+		if stored.MaxAttempts != 4 {
+			t.Fatalf("Expected the settings MaxAttempts 4, got %d", stored.MaxAttempts)
+		}
+
+		if str := stored.Payload.String(); str != `{"a":1}` {
+			t.Fatalf("Expected payload %q, got %q", `{"a":1}`, str)
+		}
+
+		if stored.RunAt.Time().After(time.Now()) {
+			t.Fatalf("Expected RunAt to be due, got %v", stored.RunAt)
+		}
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		job, err := app.EnqueueJob("test", nil, &core.JobOptions{
+			Delay:       time.Hour,
+			Priority:    5,
+			MaxAttempts: 2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := app.FindJobById(job.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stored.Priority != 5 {
+			t.Fatalf("Expected priority 5, got %d", stored.Priority)
+		}
+
+		if stored.MaxAttempts != 2 {
+			t.Fatalf("Expected MaxAttempts 2, got %d", stored.MaxAttempts)
+		}
+
+		if !stored.RunAt.Time().After(time.Now().Add(59 * time.Minute)) {
+			t.Fatalf("Expected delayed RunAt, got %v", stored.RunAt)
+		}
+	})
+
+	t.Run("unique key", func(t *testing.T) {
+		job1, err := app.EnqueueJob("test", 1, &core.JobOptions{UniqueKey: "abc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		job2, err := app.EnqueueJob("test", 2, &core.JobOptions{UniqueKey: "abc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job1.Id != job2.Id {
+			t.Fatalf("Expected the existing job %q to be returned, got %q", job1.Id, job2.Id)
+		}
+
+		// dead jobs shouldn't block new ones with the same key
+		job1.Status = core.JobStatusDead
+		if err := app.AuxSave(job1); err != nil {
+			t.Fatal(err)
+		}
+
+		job3, err := app.EnqueueJob("test", 3, &core.JobOptions{UniqueKey: "abc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job3.Id == job1.Id {
+			t.Fatal("Expected a new job to be created")
+		}
+	})
+}
+
+func TestProcessJobs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Jobs.Concurrency = 1
+	app.Settings().Jobs.RetryDelay = 60
+
+	var mu sync.Mutex
+	var order []int
+
+	app.RegisterJob("ok", func(ctx context.Context, job *core.Job) error {
+		var n int
+		if err := job.UnmarshalPayload(&n); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		order = append(order, n)
+		mu.Unlock()
+
+		return nil
+	})
+
+	app.RegisterJob("fail", func(ctx context.Context, job *core.Job) error {
+		return errors.New("test_error")
+	})
+
+	app.RegisterJob("panic", func(ctx context.Context, job *core.Job) error {
+		panic("test_panic")
+	})
+
+	low, _ := app.EnqueueJob("ok", 1, nil)
+	high, _ := app.EnqueueJob("ok", 2, &core.JobOptions{Priority: 10})
+	delayed, _ := app.EnqueueJob("ok", 3, &core.JobOptions{Delay: time.Hour})
+	retried, _ := app.EnqueueJob("fail", nil, &core.JobOptions{MaxAttempts: 2})
+	dead, _ := app.EnqueueJob("panic", nil, &core.JobOptions{MaxAttempts: 1})
+	missing, _ := app.EnqueueJob("missing", nil, &core.JobOptions{MaxAttempts: 1})
+
+	if err := app.ProcessJobs(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(order) != 2 || order[0] != 2 || order[1] != 1 {
+		t.Fatalf("Expected jobs [2 1] to be run in priority order, got %v", order)
+	}
+
+	for _, job := range []*core.Job{low, high} {
+		if _, err := app.FindJobById(job.Id); err == nil {
+			t.Fatalf("Expected completed job %q to be deleted", job.Id)
+		}
+	}
+
+	if job, err := app.FindJobById(delayed.Id); err != nil || job.Status != core.JobStatusPending || job.Attempts != 0 {
+		t.Fatalf("Expected the delayed job to be untouched, got %v (%v)", job, err)
+	}
+
+	job, err := app.FindJobById(retried.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != core.JobStatusPending || job.Attempts != 1 || job.LastError != "test_error" {
+		t.Fatalf("Expected pending job with 1 attempt and test_error, got %q, %d, %q", job.Status, job.Attempts, job.LastError)
+	}
+	if !job.RunAt.Time().After(time.Now().Add(50 * time.Second)) {
+		t.Fatalf("Expected the failed job to be rescheduled with backoff, got %v", job.RunAt)
+	}
+
+	for _, j := range []*core.Job{dead, missing} {
+		job, err := app.FindJobById(j.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != core.JobStatusDead || job.Attempts != 1 || job.LastError == "" {
+			t.Fatalf("Expected dead job %q with 1 attempt and error, got %q, %d, %q", job.Name, job.Status, job.Attempts, job.LastError)
+		}
+	}
+
+	// retry the failed job immediately
+	if err := app.RetryJob(job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.ProcessJobs(); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err = app.FindJobById(retried.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != core.JobStatusPending || job.Attempts != 1 {
+		t.Fatalf("Expected the retried job to have reset attempts, got %q, %d", job.Status, job.Attempts)
+	}
+}
+
+func TestProcessJobsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Jobs.Concurrency = 3
+
+	var mu sync.Mutex
+	var running, maxRunning, total int
+
+	app.RegisterJob("test", func(ctx context.Context, job *core.Job) error {
+		mu.Lock()
+		running++
+		total++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil
+	})
+
+	for range 6 {
+		if _, err := app.EnqueueJob("test", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.ProcessJobs(); err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 6 {
+		t.Fatalf("Expected 6 processed jobs, got %d", total)
+	}
+
+	if maxRunning < 2 || maxRunning > 3 {
+		t.Fatalf("Expected between 2 and 3 parallel jobs, got %d", maxRunning)
+	}
+}
+
+func TestRetryJob(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	job, err := app.EnqueueJob("test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job.Status = core.JobStatusRunning
+	if err := app.RetryJob(job); err == nil {
+		t.Fatal("Expected running job retry error")
+	}
+
+	job.Status = core.JobStatusDead
+	job.Attempts = 3
+	job.LastError = "test"
+	job.RunAt = job.RunAt.Add(time.Hour)
+	if err := app.AuxSave(job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.RetryJob(job); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := app.FindJobById(job.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Status != core.JobStatusPending || stored.Attempts != 0 || stored.LastError != "" {
+		t.Fatalf("Expected reset pending job, got %q, %d, %q", stored.Status, stored.Attempts, stored.LastError)
+	}
+
+	if stored.RunAt.Time().After(time.Now()) {
+		t.Fatalf("Expected RunAt to be due, got %v", stored.RunAt)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	t.Run("pending job", func(t *testing.T) {
+		job, err := app.EnqueueJob("test", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		job.RunAt = job.RunAt.Add(time.Hour)
+
+		if err := app.CancelJob(job); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := app.FindJobById(job.Id); err == nil {
+			t.Fatal("Expected the job to be deleted")
+		}
+	})
+
+	t.Run("running job", func(t *testing.T) {
+		started := make(chan *core.Job)
+
+		app.RegisterJob("long", func(ctx context.Context, job *core.Job) error {
+			started <- job
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		if _, err := app.EnqueueJob("long", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		done := make(chan error)
+		go func() {
+			done <- app.ProcessJobs()
+		}()
+
+		job := <-started
+
+		if err := app.CancelJob(job); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the running job to be canceled")
+		}
+
+		if _, err := app.FindJobById(job.Id); err == nil {
+			t.Fatal("Expected the canceled job to remain deleted")
+		}
+	})
+}
+
+func TestJobsConfigRetryDelayTime(t *testing.T) {
+	t.Parallel()
+
+	c := core.JobsConfig{RetryDelay: 10}
+
+	scenarios := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{100, 24 * time.Hour},
+	}
+
+	for _, s := range scenarios {
+		if v := c.RetryDelayTime(s.attempts); v != s.expected {
+			t.Fatalf("[%d] Expected %v, got %v", s.attempts, s.expected, v)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"io"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// ReceiveMail triggers the OnMailReceived hook for the provided
+// inbound message (usually parsed with [mailer.ParseMessage]).
+//
+// If the InboundMail.Collection setting is set, the default hook handler
+// creates a new record from the message (see [InboundMailConfig.Collection]).
+//
+// Returns the created record (if any).
+func (app *BaseApp) ReceiveMail(message *mailer.Message) (*Record, error) {
+	event := new(MailReceivedEvent)
+	event.App = app
+	event.Message = message
+
+	err := app.OnMailReceived().Trigger(event, func(e *MailReceivedEvent) error {
+		collectionNameOrId := e.App.Settings().InboundMail.Collection
+		if collectionNameOrId == "" {
+			return nil // no default route
+		}
+
+		collection, err := e.App.FindCachedCollectionByNameOrId(collectionNameOrId)
+		if err != nil {
+			return err
+		}
+
+		record, err := newRecordFromMailMessage(collection, e.Message)
+		if err != nil {
+			return err
+		}
+
+		if err := e.App.Save(record); err != nil {
+			return err
+		}
+
+		e.Record = record
+
+		return nil
+	})
+
+	return event.Record, err
+}
+
+// MailAttachmentsToFiles converts the message attachments
+// (including the inline ones) to a list of [filesystem.File]
+// sorted by their name (ex. to assign them to a [FileField]).
+func MailAttachmentsToFiles(message *mailer.Message) ([]*filesystem.File, error) {
+	files := make([]*filesystem.File, 0, len(message.Attachments)+len(message.InlineAttachments))
+
+	for _, attachments := range []map[string]io.Reader{message.Attachments, message.InlineAttachments} {
+		names := make([]string, 0, len(attachments))
+		for name := range attachments {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			r := attachments[name]
+
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+
+			// rewind to allow reading the attachment again
+			if seeker, ok := r.(io.Seeker); ok {
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+					return nil, err
+				}
+			}
+
+			file, err := filesystem.NewFileFromBytes(data, name)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func newRecordFromMailMessage(collection *Collection, message *mailer.Message) (*Record, error) {
+	record := NewRecord(collection)
+
+	values := map[string]any{
+		"from":       message.From.Address,
+		"to":         joinMailAddresses(message.To),
+		"cc":         joinMailAddresses(message.Cc),
+		"subject":    message.Subject,
+		"text":       message.Text,
+		"html":       message.HTML,
+		"messageId":  message.Headers["Message-Id"],
+		"inReplyTo":  message.Headers["In-Reply-To"],
+		"references": message.Headers["References"],
+		"headers":    message.Headers,
+	}
+
+	for name, value := range values {
+		if collection.Fields.GetByName(name) != nil {
+			record.Set(name, value)
+		}
+	}
+
+	if _, ok := collection.Fields.GetByName("attachments").(*FileField); ok {
+		files, err := MailAttachmentsToFiles(message)
+		if err != nil {
+			return nil, err
+		}
+
+		record.Set("attachments", files)
+	}
+
+	return record, nil
+}
+
+func joinMailAddresses(addresses []mail.Address) string {
+	list := make([]string, len(addresses))
+
+	for i, addr := range addresses {
+		list[i] = addr.Address
+	}
+
+	return strings.Join(list, ", ")
+}
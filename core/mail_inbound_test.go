@@ -0,0 +1,143 @@
+package core_test
+
+import (
+	"errors"
+	"io"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+func TestReceiveMail(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	message := &mailer.Message{
+		From:    mail.Address{Name: "John", Address: "john@example.com"},
+		To:      []mail.Address{{Address: "a@example.com"}, {Address: "b@example.com"}},
+		Subject: "test_subject",
+		Text:    "test_text",
+		Headers: map[string]string{"Message-Id": "<abc@example.com>"},
+	}
+
+	// no default collection
+	record, err := app.ReceiveMail(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record != nil {
+		t.Fatalf("Expected nil record, got %v", record)
+	}
+
+	// missing default collection
+	app.Settings().InboundMail.Collection = "missing"
+	if _, err := app.ReceiveMail(message); err == nil {
+		t.Fatal("Expected missing collection error")
+	}
+
+	// default collection (only the matching fields are filled)
+	inbox := core.NewBaseCollection("inbox")
+	inbox.Fields.Add(
+		&core.TextField{Name: "from"},
+		&core.TextField{Name: "to"},
+		&core.TextField{Name: "subject"},
+		&core.TextField{Name: "messageId"},
+		&core.TextField{Name: "attachments"}, // non-file field
+	)
+	if err := app.Save(inbox); err != nil {
+		t.Fatal(err)
+	}
+	app.Settings().InboundMail.Collection = inbox.Name
+
+	record, err = app.ReceiveMail(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"from":        "john@example.com",
+		"to":          "a@example.com, b@example.com",
+		"subject":     "test_subject",
+		"messageId":   "<abc@example.com>",
+		"attachments": "",
+	}
+	for k, v := range expected {
+		if record.GetString(k) != v {
+			t.Fatalf("Expected %q to be %q, got %q", k, v, record.GetString(k))
+		}
+	}
+
+	// custom route
+	app.OnMailReceived().BindFunc(func(e *core.MailReceivedEvent) error {
+		if e.Message.Subject == "skip" {
+			return nil // don't call e.Next() to skip the default handler
+		}
+		if e.Message.Subject == "error" {
+			return errors.New("test_error")
+		}
+		return e.Next()
+	})
+
+	message.Subject = "skip"
+	record, err = app.ReceiveMail(message)
+	if err != nil || record != nil {
+		t.Fatalf("Expected nil record and error, got %v, %v", record, err)
+	}
+
+	message.Subject = "error"
+	if _, err := app.ReceiveMail(message); err == nil || err.Error() != "test_error" {
+		t.Fatalf("Expected test_error, got %v", err)
+	}
+
+	total, err := app.CountRecords(inbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("Expected 1 inbox record, got %d", total)
+	}
+}
+
+func TestMailAttachmentsToFiles(t *testing.T) {
+	t.Parallel()
+
+	message := &mailer.Message{
+		Attachments: map[string]io.Reader{
+			"b.txt": strings.NewReader("b"),
+			"a.txt": strings.NewReader("a"),
+		},
+		InlineAttachments: map[string]io.Reader{
+			"c.png": strings.NewReader("c"),
+		},
+	}
+
+	files, err := core.MailAttachmentsToFiles(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedNames := []string{"a.txt", "b.txt", "c.png"}
+	if len(files) != len(expectedNames) {
+		t.Fatalf("Expected %d files, got %d", len(expectedNames), len(files))
+	}
+	for i, name := range expectedNames {
+		if files[i].OriginalName != name {
+			t.Fatalf("Expected file %d to be %q, got %q", i, name, files[i].OriginalName)
+		}
+	}
+
+	// the attachments should be still readable
+	data, err := io.ReadAll(message.Attachments["a.txt"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a" {
+		t.Fatalf("Expected the attachment reader to be rewinded, got %q", data)
+	}
+}
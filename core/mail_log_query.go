@@ -0,0 +1,37 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindMailLogById finds a single MailLog entry by its id.
+func (app *BaseApp) FindMailLogById(id string) (*MailLog, error) {
+	model := &MailLog{}
+
+	err := app.AuxModelQuery(model).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// DeleteOldMailLogs deletes all mail log entries that are created before createdBefore.
+//
+// For better performance the delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteOldMailLogs(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := app.AuxNonconcurrentDB().Delete(MailLogsTableName, expr).Execute()
+
+	return err
+}
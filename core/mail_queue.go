@@ -0,0 +1,211 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// maxMailRetryDelay is the max delay between two delivery attempts of a queued mail.
+const maxMailRetryDelay = 24 * time.Hour
+
+// mailQueueBatchSize is the max number of queued mails loaded at once by the queue worker.
+const mailQueueBatchSize = 50
+
+var _ mailer.Queue = (*appMailQueue)(nil)
+
+// appMailQueue implements [mailer.Queue] interface for the app mail queue.
+type appMailQueue struct {
+	app App
+}
+
+// Enqueue implements [mailer.Queue] interface.
+func (q *appMailQueue) Enqueue(message *mailer.Message) error {
+	return q.app.EnqueueMail(message)
+}
+
+// EnqueueMail stores the provided message in the persistent mail queue
+// for later delivery by the mail queue worker.
+func (app *BaseApp) EnqueueMail(message *mailer.Message) error {
+	data, err := mailer.EncodeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	now := types.NowDateTime()
+
+	item := &QueuedMail{
+		Message:     data,
+		NextAttempt: now,
+		Created:     now,
+		Updated:     now,
+	}
+	item.Id = GenerateDefaultRandomId()
+
+	err = app.AuxRunInTransaction(func(txApp App) error {
+		if err := txApp.AuxSave(item); err != nil {
+			return err
+		}
+
+		return saveMailLog(txApp, item, message, MailLogStatusQueued, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	app.mailQueue.notify()
+
+	return nil
+}
+
+// ProcessMailQueue delivers all queued mails that are due for
+// a delivery attempt using the app SMTP or Sendmail client.
+//
+// Successfully delivered mails are removed from the queue.
+// Failed mails are rescheduled with exponential backoff until
+// the MailQueue.MaxAttempts setting is reached.
+// Each delivery attempt is recorded in the mail log.
+func (app *BaseApp) ProcessMailQueue() error {
+	app.mailQueue.processMu.Lock()
+	defer app.mailQueue.processMu.Unlock()
+
+	for {
+		items := []*QueuedMail{}
+
+		err := app.AuxModelQuery(&QueuedMail{}).
+			AndWhere(dbx.NewExp("[[nextAttempt]] <= {:now}", dbx.Params{"now": types.NowDateTime().String()})).
+			OrderBy("nextAttempt ASC", "rowid ASC").
+			Limit(mailQueueBatchSize).
+			All(&items)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := app.deliverQueuedMail(item); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < mailQueueBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliverQueuedMail makes a single delivery attempt of the provided queued mail.
+//
+// Returns an error only if the queue state couldn't be updated.
+func (app *BaseApp) deliverQueuedMail(item *QueuedMail) error {
+	config := app.Settings().MailQueue
+
+	message, sendErr := mailer.DecodeMessage(item.Message)
+	if sendErr == nil {
+		sendErr = app.newDirectMailClient().Send(message)
+	} else {
+		message = &mailer.Message{}
+	}
+
+	item.Attempts++
+	item.Updated = types.NowDateTime()
+
+	return app.AuxRunInTransaction(func(txApp App) error {
+		if sendErr == nil {
+			if err := txApp.AuxDelete(item); err != nil {
+				return err
+			}
+			return saveMailLog(txApp, item, message, MailLogStatusSent, nil)
+		}
+
+		if item.Attempts >= max(config.MaxAttempts, 1) {
+			if err := txApp.AuxDelete(item); err != nil {
+				return err
+			}
+			return saveMailLog(txApp, item, message, MailLogStatusFailed, sendErr)
+		}
+
+		item.NextAttempt = item.Updated.Add(config.RetryDelayTime(item.Attempts))
+		if err := txApp.AuxSave(item); err != nil {
+			return err
+		}
+		return saveMailLog(txApp, item, message, MailLogStatusRetry, sendErr)
+	})
+}
+
+func saveMailLog(app App, item *QueuedMail, message *mailer.Message, status string, sendErr error) error {
+	to := make([]string, len(message.To))
+	for i, addr := range message.To {
+		to[i] = addr.Address
+	}
+
+	entry := &MailLog{
+		MailRef: item.Id,
+		To:      strings.Join(to, ", "),
+		Subject: message.Subject,
+		Status:  status,
+		Attempt: item.Attempts,
+		Created: types.NowDateTime(),
+	}
+	entry.Id = GenerateDefaultRandomId()
+
+	if sendErr != nil {
+		entry.Error = sendErr.Error()
+	}
+
+	return app.AuxSave(entry)
+}
+
+func (app *BaseApp) registerMailQueueHooks() {
+	app.OnServe().Bind(&hook.Handler[*ServeEvent]{
+		Id: "__pbMailQueueWorkerStart__",
+		Func: func(e *ServeEvent) error {
+			app.startMailQueueWorker()
+
+			return e.Next()
+		},
+		Priority: 999,
+	})
+
+	app.OnTerminate().Bind(&hook.Handler[*TerminateEvent]{
+		Id: "__pbMailQueueWorkerStop__",
+		Func: func(e *TerminateEvent) error {
+			app.stopMailQueueWorker()
+
+			return e.Next()
+		},
+		Priority: -999,
+	})
+
+	// periodically cleanup the old mail log entries
+	app.Cron().Add("__pbMailLogsCleanup__", "10 */6 * * *", func() {
+		maxDays := app.Settings().MailQueue.LogsMaxDays
+		if maxDays <= 0 {
+			return // keep indefinitely
+		}
+
+		err := app.DeleteOldMailLogs(time.Now().AddDate(0, 0, -1*maxDays))
+		if err != nil {
+			app.Logger().Warn("Failed to delete old mail logs", "error", err)
+		}
+	})
+}
+
+// mailQueueWorkerInterval is the max interval between two mail queue checks
+// (the worker is also notified on each newly enqueued mail).
+const mailQueueWorkerInterval = 10 * time.Second
+
+func (app *BaseApp) startMailQueueWorker() {
+	app.mailQueue.start(mailQueueWorkerInterval, func() {
+		if err := app.ProcessMailQueue(); err != nil {
+			app.Logger().Warn("Failed to process the mail queue", "error", err)
+		}
+	})
+}
+
+func (app *BaseApp) stopMailQueueWorker() {
+	app.mailQueue.halt()
+}
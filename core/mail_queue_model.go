@@ -0,0 +1,62 @@
+package core
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	_ Model = (*QueuedMail)(nil)
+	_ Model = (*MailLog)(nil)
+)
+
+const (
+	QueuedMailsTableName = "_mailQueue"
+	MailLogsTableName    = "_mailLog"
+)
+
+const (
+	MailLogStatusQueued = "queued"
+	MailLogStatusSent   = "sent"
+	MailLogStatusRetry  = "retry"
+	MailLogStatusFailed = "failed"
+)
+
+// QueuedMail defines a single pending email message in the persistent mail queue.
+type QueuedMail struct {
+	BaseModel
+
+	// Message is the serialized mailer.Message (see [mailer.EncodeMessage]).
+	Message types.JSONRaw `db:"message" json:"message"`
+
+	// Attempts is the number of the already failed delivery attempts.
+	Attempts int `db:"attempts" json:"attempts"`
+
+	NextAttempt types.DateTime `db:"nextAttempt" json:"nextAttempt"`
+	Created     types.DateTime `db:"created" json:"created"`
+	Updated     types.DateTime `db:"updated" json:"updated"`
+}
+
+func (m *QueuedMail) TableName() string {
+	return QueuedMailsTableName
+}
+
+// MailLog defines a single mail queue delivery log entry.
+type MailLog struct {
+	BaseModel
+
+	MailRef string `db:"mailRef" json:"mailRef"`
+	To      string `db:"to" json:"to"`
+	Subject string `db:"subject" json:"subject"`
+
+	// Status is the state of the queued mail at the time of the log entry
+	// (could be any of the MailLogStatus* constants).
+	Status string `db:"status" json:"status"`
+
+	Attempt int            `db:"attempt" json:"attempt"`
+	Error   string         `db:"error" json:"error"`
+	Created types.DateTime `db:"created" json:"created"`
+}
+
+func (m *MailLog) TableName() string {
+	return MailLogsTableName
+}
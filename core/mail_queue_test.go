@@ -0,0 +1,237 @@
+package core_test
+
+import (
+	"errors"
+	"io"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewMailClientWithMailQueue(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if _, ok := app.NewMailClient().(*mailer.QueueMailer); ok {
+		t.Fatal("Expected direct mail client with disabled mail queue")
+	}
+
+	app.Settings().MailQueue.Enabled = true
+
+	if _, ok := app.NewMailClient().(*mailer.QueueMailer); !ok {
+		t.Fatal("Expected queue mail client with enabled mail queue")
+	}
+}
+
+func TestMailQueueDelivery(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().MailQueue.Enabled = true
+
+	err := app.NewMailClient().Send(&mailer.Message{
+		From:        mail.Address{Address: "from@example.com"},
+		To:          []mail.Address{{Address: "to1@example.com"}, {Address: "to2@example.com"}},
+		Subject:     "Test subject",
+		HTML:        "<p>Test</p>",
+		Attachments: map[string]io.Reader{"a.txt": strings.NewReader("abc")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if app.TestMailer.TotalSend() != 0 {
+		t.Fatalf("Expected no sent messages before processing the queue, got %d", app.TestMailer.TotalSend())
+	}
+
+	assertMailQueueTotal(t, app, 1)
+	assertMailLogs(t, app, []string{core.MailLogStatusQueued})
+
+	if err := app.ProcessMailQueue(); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected 1 sent message, got %d", app.TestMailer.TotalSend())
+	}
+
+	sent := app.TestMailer.LastMessage()
+	if sent.Subject != "Test subject" || len(sent.To) != 2 || len(sent.Attachments) != 1 {
+		t.Fatalf("Sent message doesn't match the enqueued one: %#v", sent)
+	}
+
+	assertMailQueueTotal(t, app, 0)
+	logs := assertMailLogs(t, app, []string{core.MailLogStatusQueued, core.MailLogStatusSent})
+
+	if logs[1].To != "to1@example.com, to2@example.com" || logs[1].Subject != "Test subject" || logs[1].Attempt != 1 {
+		t.Fatalf("Unexpected sent log entry %#v", logs[1])
+	}
+}
+
+func TestMailQueueRetries(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().MailQueue.Enabled = true
+	app.Settings().MailQueue.MaxAttempts = 2
+	app.Settings().MailQueue.RetryDelay = 60
+
+	app.OnMailerSend().Bind(&hook.Handler[*core.MailerEvent]{
+		Func: func(e *core.MailerEvent) error {
+			return errors.New("test_send_error")
+		},
+		Priority: -999999, // before the test mailer handler
+	})
+
+	err := app.EnqueueMail(&mailer.Message{
+		To:      []mail.Address{{Address: "to@example.com"}},
+		Subject: "Test subject",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first failed attempt
+	if err := app.ProcessMailQueue(); err != nil {
+		t.Fatal(err)
+	}
+
+	items := assertMailQueueTotal(t, app, 1)
+	if items[0].Attempts != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", items[0].Attempts)
+	}
+	delay := items[0].NextAttempt.Sub(items[0].Updated)
+	if delay != 60*time.Second {
+		t.Fatalf("Expected next attempt after %v, got %v", 60*time.Second, delay)
+	}
+
+	logs := assertMailLogs(t, app, []string{core.MailLogStatusQueued, core.MailLogStatusRetry})
+	if logs[1].Error != "test_send_error" {
+		t.Fatalf("Expected the send error to be logged, got %q", logs[1].Error)
+	}
+
+	// not due yet
+	if err := app.ProcessMailQueue(); err != nil {
+		t.Fatal(err)
+	}
+	assertMailQueueTotal(t, app, 1)
+	assertMailLogs(t, app, []string{core.MailLogStatusQueued, core.MailLogStatusRetry})
+
+	// move the next attempt in the past
+	_, err = app.AuxNonconcurrentDB().Update(
+		core.QueuedMailsTableName,
+		dbx.Params{"nextAttempt": types.NowDateTime().Add(-time.Second).String()},
+		nil,
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// last failed attempt
+	if err := app.ProcessMailQueue(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertMailQueueTotal(t, app, 0)
+	assertMailLogs(t, app, []string{core.MailLogStatusQueued, core.MailLogStatusRetry, core.MailLogStatusFailed})
+
+	if app.TestMailer.TotalSend() != 0 {
+		t.Fatalf("Expected no sent messages, got %d", app.TestMailer.TotalSend())
+	}
+}
+
+func TestDeleteOldMailLogs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dates := []types.DateTime{
+		types.NowDateTime().AddDate(0, 0, -10),
+		types.NowDateTime().AddDate(0, 0, -5),
+		types.NowDateTime(),
+	}
+
+	for _, date := range dates {
+		entry := &core.MailLog{Status: core.MailLogStatusSent, Created: date}
+		entry.Id = core.GenerateDefaultRandomId()
+		if err := app.AuxSave(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.DeleteOldMailLogs(time.Now().AddDate(0, 0, -7)); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := []*core.MailLog{}
+	if err := app.AuxModelQuery(&core.MailLog{}).All(&logs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("Expected %d remaining logs, got %d", 2, len(logs))
+	}
+
+	found, err := app.FindMailLogById(logs[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != logs[0].Id {
+		t.Fatalf("Expected log %q, got %q", logs[0].Id, found.Id)
+	}
+
+	if _, err := app.FindMailLogById("missing"); err == nil {
+		t.Fatal("Expected missing log error")
+	}
+}
+
+func assertMailQueueTotal(t *testing.T, app core.App, expected int) []*core.QueuedMail {
+	t.Helper()
+
+	items := []*core.QueuedMail{}
+	if err := app.AuxModelQuery(&core.QueuedMail{}).All(&items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != expected {
+		t.Fatalf("Expected %d queued mails, got %d", expected, len(items))
+	}
+
+	return items
+}
+
+func assertMailLogs(t *testing.T, app core.App, expectedStatuses []string) []*core.MailLog {
+	t.Helper()
+
+	logs := []*core.MailLog{}
+	if err := app.AuxModelQuery(&core.MailLog{}).OrderBy("rowid ASC").All(&logs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != len(expectedStatuses) {
+		t.Fatalf("Expected %d mail logs, got %d", len(expectedStatuses), len(logs))
+	}
+
+	for i, status := range expectedStatuses {
+		if logs[i].Status != status {
+			t.Fatalf("Expected log %d status %q, got %q", i, status, logs[i].Status)
+		}
+	}
+
+	return logs
+}
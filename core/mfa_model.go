@@ -13,6 +13,7 @@ const (
 	MFAMethodPassword = "password"
 	MFAMethodOAuth2   = "oauth2"
 	MFAMethodOTP      = "otp"
+	MFAMethodSAML     = "saml"
 )
 
 const CollectionNameMFAs = "_mfas"
@@ -0,0 +1,207 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewPasswordHistory(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	entry := core.NewPasswordHistory(app)
+
+	if entry.Collection().Name != core.CollectionNamePasswordHistory {
+		t.Fatalf("Expected record with %q collection, got %q", core.CollectionNamePasswordHistory, entry.Collection().Name)
+	}
+}
+
+func TestPasswordHistoryProxyRecord(t *testing.T) {
+	t.Parallel()
+
+	record := core.NewRecord(core.NewBaseCollection("test"))
+	record.Id = "test_id"
+
+	entry := core.PasswordHistory{}
+	entry.SetProxyRecord(record)
+
+	if entry.ProxyRecord() == nil || entry.ProxyRecord().Id != record.Id {
+		t.Fatalf("Expected proxy record with id %q, got %v", record.Id, entry.ProxyRecord())
+	}
+}
+
+func TestPasswordHistoryStringFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	entry := core.NewPasswordHistory(app)
+
+	scenarios := []struct {
+		field  string
+		setter func(string)
+		getter func() string
+	}{
+		{"recordRef", entry.SetRecordRef, entry.RecordRef},
+		{"collectionRef", entry.SetCollectionRef, entry.CollectionRef},
+		{"field", entry.SetField, entry.Field},
+		{"hash", entry.SetHash, entry.Hash},
+	}
+
+	for _, s := range scenarios {
+		for i, testValue := range []string{"test_1", "test2", ""} {
+			t.Run(fmt.Sprintf("%s_%d_%q", s.field, i, testValue), func(t *testing.T) {
+				s.setter(testValue)
+
+				if v := s.getter(); v != testValue {
+					t.Fatalf("Expected getter %q, got %q", testValue, v)
+				}
+
+				if v := entry.GetString(s.field); v != testValue {
+					t.Fatalf("Expected field value %q, got %q", testValue, v)
+				}
+			})
+		}
+	}
+}
+
+func TestPasswordHistoryCreatedAndUpdated(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	entry := core.NewPasswordHistory(app)
+
+	if !entry.Created().IsZero() || !entry.Updated().IsZero() {
+		t.Fatal("Expected zero created and updated dates")
+	}
+
+	now := types.NowDateTime()
+	entry.SetRaw("created", now)
+	entry.SetRaw("updated", now)
+
+	if entry.Created().String() != now.String() || entry.Updated().String() != now.String() {
+		t.Fatalf("Expected created and updated %q, got %q and %q", now.String(), entry.Created().String(), entry.Updated().String())
+	}
+}
+
+func TestPasswordHistoryPreValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	col, err := app.FindCollectionByNameOrId(core.CollectionNamePasswordHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no proxy record", func(t *testing.T) {
+		entry := &core.PasswordHistory{}
+
+		if err := app.Validate(entry); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("non-PasswordHistory collection", func(t *testing.T) {
+		entry := &core.PasswordHistory{}
+		entry.SetProxyRecord(core.NewRecord(core.NewBaseCollection("invalid")))
+		entry.SetRecordRef(user.Id)
+		entry.SetCollectionRef(user.Collection().Id)
+		entry.SetField("password")
+		entry.SetHash("test")
+
+		if err := app.Validate(entry); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("PasswordHistory collection", func(t *testing.T) {
+		entry := &core.PasswordHistory{}
+		entry.SetProxyRecord(core.NewRecord(col))
+		entry.SetRecordRef(user.Id)
+		entry.SetCollectionRef(user.Collection().Id)
+		entry.SetField("password")
+		entry.SetHash("test")
+
+		if err := app.Validate(entry); err != nil {
+			t.Fatalf("Expected nil validation error, got %v", err)
+		}
+	})
+}
+
+func TestPasswordHistoryValidateHook(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		entry        func() *core.PasswordHistory
+		expectErrors []string
+	}{
+		{
+			"empty",
+			func() *core.PasswordHistory {
+				return core.NewPasswordHistory(app)
+			},
+			[]string{"collectionRef", "recordRef", "field", "hash"},
+		},
+		{
+			"non-auth collection",
+			func() *core.PasswordHistory {
+				entry := core.NewPasswordHistory(app)
+				entry.SetCollectionRef(demo1.Collection().Id)
+				entry.SetRecordRef(demo1.Id)
+				entry.SetField("password")
+				entry.SetHash("test")
+				return entry
+			},
+			[]string{"collectionRef"},
+		},
+		{
+			"valid ref",
+			func() *core.PasswordHistory {
+				entry := core.NewPasswordHistory(app)
+				entry.SetCollectionRef(user.Collection().Id)
+				entry.SetRecordRef(user.Id)
+				entry.SetField("password")
+				entry.SetHash("test")
+				return entry
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := app.Validate(s.entry())
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+// FindAllPasswordHistoryByRecord returns all PasswordHistory models linked
+// to the provided auth record and password field name (in DESC order).
+func (app *BaseApp) FindAllPasswordHistoryByRecord(authRecord *Record, fieldName string) ([]*PasswordHistory, error) {
+	result := []*PasswordHistory{}
+
+	err := app.RecordQuery(CollectionNamePasswordHistory).
+		AndWhere(dbx.HashExp{
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+			"field":         fieldName,
+		}).
+		OrderBy("created DESC", "rowid DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// HasPasswordExpired checks whether the password of the provided
+// auth record is older than the password field MaxAge option.
+//
+// Records without a tracked password change (eg. created before
+// enabling the MaxAge option) are not considered expired.
+func (app *BaseApp) HasPasswordExpired(authRecord *Record) (bool, error) {
+	field, ok := authRecord.Collection().Fields.GetByName(FieldNamePassword).(*PasswordField)
+	if !ok || field.MaxAge <= 0 {
+		return false, nil
+	}
+
+	entry := &PasswordHistory{}
+
+	err := app.RecordQuery(CollectionNamePasswordHistory).
+		AndWhere(dbx.HashExp{
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+			"field":         field.Name,
+		}).
+		OrderBy("created DESC", "rowid DESC").
+		Limit(1).
+		One(entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	maxAge := time.Duration(field.MaxAge) * time.Second
+
+	return time.Since(entry.Created().Time()) > maxAge, nil
+}
+
+// DeleteAllPasswordHistoryByRecord deletes all PasswordHistory models associated with the provided record.
+//
+// Returns a combined error with the failed deletes.
+func (app *BaseApp) DeleteAllPasswordHistoryByRecord(authRecord *Record) error {
+	models := []*PasswordHistory{}
+
+	err := app.RecordQuery(CollectionNamePasswordHistory).
+		AndWhere(dbx.HashExp{
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+		}).
+		All(&models)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, m := range models {
+		if err := app.Delete(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestPasswordHistoryTracking(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Fields.GetByName(core.FieldNamePassword).(*core.PasswordField).HistorySize = 2
+	if err := app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changePassword := func(password string) error {
+		user.SetPassword(password)
+		return app.Save(user)
+	}
+
+	// reuse of the current password
+	err = changePassword("1234567890")
+	assertPasswordReusedError(t, err)
+
+	for _, password := range []string{"new_password_1", "new_password_2", "new_password_3"} {
+		if err := changePassword(password); err != nil {
+			t.Fatalf("Failed to change password to %q: %v", password, err)
+		}
+	}
+
+	history, err := app.FindAllPasswordHistoryByRecord(user, core.FieldNamePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected %d history entries, got %d", 2, len(history))
+	}
+
+	// within the last 2 passwords
+	assertPasswordReusedError(t, changePassword("new_password_2"))
+
+	// older than the last 2 passwords
+	if err := changePassword("new_password_1"); err != nil {
+		t.Fatalf("Expected the old password to be allowed, got %v", err)
+	}
+
+	// deleting the user should also delete its history
+	if err := app.Delete(user); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err = app.FindAllPasswordHistoryByRecord(user, core.FieldNamePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("Expected no history entries, got %d", len(history))
+	}
+}
+
+func assertPasswordReusedError(t *testing.T, err error) {
+	t.Helper()
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected validation errors, got %v", err)
+	}
+
+	var fieldErr validation.Error
+	if !errors.As(errs[core.FieldNamePassword], &fieldErr) || fieldErr.Code() != "validation_password_reused" {
+		t.Fatalf("Expected validation_password_reused error, got %v", errs[core.FieldNamePassword])
+	}
+}
+
+func TestHasPasswordExpired(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertExpired := func(expected bool) {
+		t.Helper()
+
+		expired, err := app.HasPasswordExpired(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expired != expected {
+			t.Fatalf("Expected expired %v, got %v", expected, expired)
+		}
+	}
+
+	// no MaxAge
+	assertExpired(false)
+
+	passwordField := users.Fields.GetByName(core.FieldNamePassword).(*core.PasswordField)
+	passwordField.MaxAge = 3600
+	if err := app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	// reload to refresh the user collection options
+	user, err = app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no tracked password change
+	assertExpired(false)
+
+	user.SetPassword("new_password_1")
+	if err := app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	// fresh password
+	assertExpired(false)
+
+	history, err := app.FindAllPasswordHistoryByRecord(user, core.FieldNamePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Expected %d history entry, got %d", 1, len(history))
+	}
+
+	old, _ := types.ParseDateTime(time.Now().Add(-2 * time.Hour))
+	if _, err := app.DB().Update(
+		core.CollectionNamePasswordHistory,
+		map[string]any{"created": old},
+		nil,
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// older than MaxAge
+	assertExpired(true)
+}
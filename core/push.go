@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/webpush"
+)
+
+// pushSendTimeout is the max duration of a single push service request.
+const pushSendTimeout = 30 * time.Second
+
+// pushClientStoreKey is the app store key of the shared Web Push client.
+const pushClientStoreKey = "@pushClient"
+
+// SendPush encrypts and delivers the payload as Web Push notification
+// to all push subscriptions of the provided auth record.
+//
+// Subscriptions for which the push service responded with 404 or 410
+// are considered expired and are automatically deleted.
+//
+// Returns a combined error with the failed deliveries (if any).
+func (app *BaseApp) SendPush(authRecord *Record, payload []byte) error {
+	config := app.Settings().WebPush
+	if !config.Enabled {
+		return errors.New("web push notifications are not enabled")
+	}
+
+	if len(payload) > webpush.MaxPayloadSize {
+		return webpush.ErrPayloadTooLarge
+	}
+
+	subs, err := app.FindAllPushSubscriptionsByRecord(authRecord)
+	if err != nil {
+		return err
+	}
+
+	client := app.pushClient(config)
+
+	opts := &webpush.Options{TTL: config.TTL}
+
+	var errs []error
+	for _, sub := range subs {
+		err := app.sendPushToSubscription(client, sub, payload, opts)
+		if err == nil {
+			continue
+		}
+
+		var respErr *webpush.ResponseError
+		if errors.As(err, &respErr) && respErr.IsExpired() {
+			if err := app.Delete(sub); err != nil {
+				app.Logger().Warn(
+					"Failed to delete expired push subscription",
+					"error", err,
+					"subscriptionId", sub.Id,
+				)
+			}
+			continue
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// pushClient returns the app Web Push client.
+//
+// The client (and its HTTP connections pool) is shared between the
+// SendPush calls and it is rebuilt only when the WebPush settings change.
+func (app *BaseApp) pushClient(config WebPushConfig) *webpush.Client {
+	var client *webpush.Client
+
+	app.Store().SetFunc(pushClientStoreKey, func(old any) any {
+		client, _ = old.(*webpush.Client)
+		if client == nil ||
+			client.Subject != config.Subject ||
+			client.PublicKey != config.PublicKey ||
+			client.PrivateKey != config.PrivateKey ||
+			!slices.Equal(client.AllowedHosts, config.AllowedHosts) {
+			client = &webpush.Client{
+				Subject:      config.Subject,
+				PublicKey:    config.PublicKey,
+				PrivateKey:   config.PrivateKey,
+				AllowedHosts: slices.Clone(config.AllowedHosts),
+			}
+		}
+
+		return client
+	})
+
+	return client
+}
+
+func (app *BaseApp) sendPushToSubscription(client *webpush.Client, sub *PushSubscription, payload []byte, opts *webpush.Options) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	return client.Send(ctx, sub.Subscription(), payload, opts)
+}
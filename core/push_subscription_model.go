@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/webpush"
+)
+
+const CollectionNamePushSubscriptions = "_pushSubscriptions"
+
+var (
+	_ Model        = (*PushSubscription)(nil)
+	_ PreValidator = (*PushSubscription)(nil)
+	_ RecordProxy  = (*PushSubscription)(nil)
+)
+
+// PushSubscription defines a Record proxy for working with the pushSubscriptions collection.
+type PushSubscription struct {
+	*Record
+}
+
+// NewPushSubscription instantiates and returns a new blank *PushSubscription model.
+//
+// Example usage:
+//
+//	sub := core.NewPushSubscription(app)
+//	sub.SetRecordRef(user.Id)
+//	sub.SetCollectionRef(user.Collection().Id)
+//	sub.SetEndpoint("https://fcm.googleapis.com/fcm/send/...")
+//	sub.SetP256dh("...")
+//	sub.SetAuth("...")
+//	app.Save(sub)
+func NewPushSubscription(app App) *PushSubscription {
+	m := &PushSubscription{}
+
+	c, err := app.FindCachedCollectionByNameOrId(CollectionNamePushSubscriptions)
+	if err != nil {
+		// this is just to make tests easier since pushSubscriptions is a system collection and it is expected to be always accessible
+		// (note: the loaded record is further checked on PushSubscription.PreValidate())
+		c = NewBaseCollection("@__invalid__")
+	}
+
+	m.Record = NewRecord(c)
+
+	return m
+}
+
+// PreValidate implements the [PreValidator] interface and checks
+// whether the proxy is properly loaded.
+func (m *PushSubscription) PreValidate(ctx context.Context, app App) error {
+	if m.Record == nil || m.Record.Collection().Name != CollectionNamePushSubscriptions {
+		return errors.New("missing or invalid PushSubscription ProxyRecord")
+	}
+
+	return nil
+}
+
+// ProxyRecord returns the proxied Record model.
+func (m *PushSubscription) ProxyRecord() *Record {
+	return m.Record
+}
+
+// SetProxyRecord loads the specified record model into the current proxy.
+func (m *PushSubscription) SetProxyRecord(record *Record) {
+	m.Record = record
+}
+
+// CollectionRef returns the "collectionRef" field value.
+func (m *PushSubscription) CollectionRef() string {
+	return m.GetString("collectionRef")
+}
+
+// SetCollectionRef updates the "collectionRef" record field value.
+func (m *PushSubscription) SetCollectionRef(collectionId string) {
+	m.Set("collectionRef", collectionId)
+}
+
+// RecordRef returns the "recordRef" record field value.
+func (m *PushSubscription) RecordRef() string {
+	return m.GetString("recordRef")
+}
+
+// SetRecordRef updates the "recordRef" record field value.
+func (m *PushSubscription) SetRecordRef(recordId string) {
+	m.Set("recordRef", recordId)
+}
+
+// Endpoint returns the "endpoint" record field value.
+func (m *PushSubscription) Endpoint() string {
+	return m.GetString("endpoint")
+}
+
+// SetEndpoint updates the "endpoint" record field value.
+func (m *PushSubscription) SetEndpoint(endpoint string) {
+	m.Set("endpoint", endpoint)
+}
+
+// P256dh returns the "p256dh" record field value
+// (the base64 encoded user agent public key).
+func (m *PushSubscription) P256dh() string {
+	return m.GetString("p256dh")
+}
+
+// SetP256dh updates the "p256dh" record field value.
+func (m *PushSubscription) SetP256dh(p256dh string) {
+	m.Set("p256dh", p256dh)
+}
+
+// Auth returns the "auth" record field value
+// (the base64 encoded user agent authentication secret).
+func (m *PushSubscription) Auth() string {
+	return m.GetString("auth")
+}
+
+// SetAuth updates the "auth" record field value.
+func (m *PushSubscription) SetAuth(auth string) {
+	m.Set("auth", auth)
+}
+
+// UserAgent returns the "userAgent" record field value.
+func (m *PushSubscription) UserAgent() string {
+	return m.GetString("userAgent")
+}
+
+// SetUserAgent updates the "userAgent" record field value.
+func (m *PushSubscription) SetUserAgent(userAgent string) {
+	m.Set("userAgent", userAgent)
+}
+
+// Created returns the "created" record field value.
+func (m *PushSubscription) Created() types.DateTime {
+	return m.GetDateTime("created")
+}
+
+// Updated returns the "updated" record field value.
+func (m *PushSubscription) Updated() types.DateTime {
+	return m.GetDateTime("updated")
+}
+
+// Subscription returns the [webpush.Subscription] representation of the current model.
+func (m *PushSubscription) Subscription() webpush.Subscription {
+	return webpush.Subscription{
+		Endpoint: m.Endpoint(),
+		Keys: webpush.SubscriptionKeys{
+			P256dh: m.P256dh(),
+			Auth:   m.Auth(),
+		},
+	}
+}
+
+func (app *BaseApp) registerPushSubscriptionHooks() {
+	recordRefHooks[*PushSubscription](app, CollectionNamePushSubscriptions, CollectionTypeAuth)
+
+	app.OnRecordValidate(CollectionNamePushSubscriptions).Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			// the url format and required checks are handled by the field validators
+			endpoint := e.Record.GetString("endpoint")
+			if endpoint != "" && is.URL.Validate(endpoint) == nil {
+				if err := webpush.ValidateEndpoint(endpoint, e.App.Settings().WebPush.AllowedHosts); err != nil {
+					return validation.Errors{"endpoint": validation.NewError(
+						"validation_forbidden_push_endpoint",
+						"The push subscription endpoint must be a public https url.",
+					)}
+				}
+			}
+
+			keys := webpush.SubscriptionKeys{
+				P256dh: e.Record.GetString("p256dh"),
+				Auth:   e.Record.GetString("auth"),
+			}
+
+			// the required checks are handled by the fields validators
+			if keys.P256dh != "" && keys.Auth != "" {
+				if err := webpush.CheckSubscriptionKeys(keys); err != nil {
+					return validation.Errors{"p256dh": validation.NewError(
+						"validation_invalid_push_subscription_keys",
+						"Invalid push subscription keys.",
+					)}
+				}
+			}
+
+			return e.Next()
+		},
+		Priority: 99,
+	})
+}
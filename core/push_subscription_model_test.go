@@ -0,0 +1,344 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/webpush"
+)
+
+// testPushAuth is a base64 encoded 16 bytes push subscription auth secret.
+const testPushAuth = "AAAAAAAAAAAAAAAAAAAAAA"
+
+// testPushP256dh returns a new base64 encoded P-256 public key.
+func testPushP256dh(t testing.TB) string {
+	pub, _, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pub
+}
+
+func TestNewPushSubscription(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sub := core.NewPushSubscription(app)
+
+	if sub.Collection().Name != core.CollectionNamePushSubscriptions {
+		t.Fatalf("Expected record with %q collection, got %q", core.CollectionNamePushSubscriptions, sub.Collection().Name)
+	}
+}
+
+func TestPushSubscriptionProxyRecord(t *testing.T) {
+	t.Parallel()
+
+	record := core.NewRecord(core.NewBaseCollection("test"))
+	record.Id = "test_id"
+
+	sub := core.PushSubscription{}
+	sub.SetProxyRecord(record)
+
+	if sub.ProxyRecord() == nil || sub.ProxyRecord().Id != record.Id {
+		t.Fatalf("Expected proxy record with id %q, got %v", record.Id, sub.ProxyRecord())
+	}
+}
+
+func TestPushSubscriptionStringFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sub := core.NewPushSubscription(app)
+
+	fields := []struct {
+		name   string
+		setter func(string)
+		getter func() string
+	}{
+		{"collectionRef", sub.SetCollectionRef, sub.CollectionRef},
+		{"recordRef", sub.SetRecordRef, sub.RecordRef},
+		{"endpoint", sub.SetEndpoint, sub.Endpoint},
+		{"p256dh", sub.SetP256dh, sub.P256dh},
+		{"auth", sub.SetAuth, sub.Auth},
+		{"userAgent", sub.SetUserAgent, sub.UserAgent},
+	}
+
+	testValues := []string{"test_1", "test2", ""}
+
+	for _, f := range fields {
+		for i, testValue := range testValues {
+			t.Run(fmt.Sprintf("%s_%d_%q", f.name, i, testValue), func(t *testing.T) {
+				f.setter(testValue)
+
+				if v := f.getter(); v != testValue {
+					t.Fatalf("Expected getter %q, got %q", testValue, v)
+				}
+
+				if v := sub.GetString(f.name); v != testValue {
+					t.Fatalf("Expected field value %q, got %q", testValue, v)
+				}
+			})
+		}
+	}
+}
+
+func TestPushSubscriptionCreatedAndUpdated(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sub := core.NewPushSubscription(app)
+
+	if v := sub.Created().String(); v != "" {
+		t.Fatalf("Expected empty created, got %q", v)
+	}
+
+	if v := sub.Updated().String(); v != "" {
+		t.Fatalf("Expected empty updated, got %q", v)
+	}
+
+	now := "2024-01-01 00:00:00.000Z"
+	sub.SetRaw("created", now)
+	sub.SetRaw("updated", now)
+
+	if v := sub.Created().String(); v != now {
+		t.Fatalf("Expected created %q, got %q", now, v)
+	}
+
+	if v := sub.Updated().String(); v != now {
+		t.Fatalf("Expected updated %q, got %q", now, v)
+	}
+}
+
+func TestPushSubscriptionSubscription(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sub := core.NewPushSubscription(app)
+	sub.SetEndpoint("https://example.com/push")
+	sub.SetP256dh("test_p256dh")
+	sub.SetAuth("test_auth")
+
+	result := sub.Subscription()
+
+	if result.Endpoint != "https://example.com/push" {
+		t.Fatalf("Expected endpoint %q, got %q", "https://example.com/push", result.Endpoint)
+	}
+
+	if result.Keys.P256dh != "test_p256dh" {
+		t.Fatalf("Expected p256dh %q, got %q", "test_p256dh", result.Keys.P256dh)
+	}
+
+	if result.Keys.Auth != "test_auth" {
+		t.Fatalf("Expected auth %q, got %q", "test_auth", result.Keys.Auth)
+	}
+}
+
+func TestPushSubscriptionPreValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	subsCol, err := app.FindCollectionByNameOrId(core.CollectionNamePushSubscriptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p256dh := testPushP256dh(t)
+
+	t.Run("no proxy record", func(t *testing.T) {
+		sub := &core.PushSubscription{}
+
+		if err := app.Validate(sub); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("non-PushSubscription collection", func(t *testing.T) {
+		sub := &core.PushSubscription{}
+		sub.SetProxyRecord(core.NewRecord(core.NewBaseCollection("invalid")))
+		sub.SetRecordRef(user.Id)
+		sub.SetCollectionRef(user.Collection().Id)
+		sub.SetEndpoint("https://example.com/push")
+		sub.SetP256dh(p256dh)
+		sub.SetAuth(testPushAuth)
+
+		if err := app.Validate(sub); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("PushSubscription collection", func(t *testing.T) {
+		sub := &core.PushSubscription{}
+		sub.SetProxyRecord(core.NewRecord(subsCol))
+		sub.SetRecordRef(user.Id)
+		sub.SetCollectionRef(user.Collection().Id)
+		sub.SetEndpoint("https://example.com/push")
+		sub.SetP256dh(p256dh)
+		sub.SetAuth(testPushAuth)
+
+		if err := app.Validate(sub); err != nil {
+			t.Fatalf("Expected nil validation error, got %v", err)
+		}
+	})
+}
+
+func TestPushSubscriptionValidateHook(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p256dh := testPushP256dh(t)
+
+	scenarios := []struct {
+		name         string
+		sub          func() *core.PushSubscription
+		expectErrors []string
+	}{
+		{
+			"empty",
+			func() *core.PushSubscription {
+				return core.NewPushSubscription(app)
+			},
+			[]string{"collectionRef", "recordRef", "endpoint", "p256dh", "auth"},
+		},
+		{
+			"non-auth collection",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(demo1.Collection().Id)
+				sub.SetRecordRef(demo1.Id)
+				sub.SetEndpoint("https://example.com/push")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{"collectionRef"},
+		},
+		{
+			"missing record id",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef("missing")
+				sub.SetEndpoint("https://example.com/push")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{"recordRef"},
+		},
+		{
+			"http endpoint",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef(user.Id)
+				sub.SetEndpoint("http://example.com/push")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{"endpoint"},
+		},
+		{
+			"private network endpoint",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef(user.Id)
+				sub.SetEndpoint("https://169.254.169.254/latest/meta-data")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{"endpoint"},
+		},
+		{
+			"invalid endpoint",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef(user.Id)
+				sub.SetEndpoint("invalid")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{"endpoint"},
+		},
+		{
+			"invalid p256dh",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef(user.Id)
+				sub.SetEndpoint("https://example.com/push")
+				sub.SetP256dh("invalid")
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{"p256dh"},
+		},
+		{
+			"invalid auth",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef(user.Id)
+				sub.SetEndpoint("https://example.com/push")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth("abc")
+				return sub
+			},
+			[]string{"p256dh"},
+		},
+		{
+			"valid",
+			func() *core.PushSubscription {
+				sub := core.NewPushSubscription(app)
+				sub.SetCollectionRef(user.Collection().Id)
+				sub.SetRecordRef(user.Id)
+				sub.SetEndpoint("https://example.com/push")
+				sub.SetP256dh(p256dh)
+				sub.SetAuth(testPushAuth)
+				return sub
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := app.Validate(s.sub())
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
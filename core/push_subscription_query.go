@@ -0,0 +1,64 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+)
+
+// FindAllPushSubscriptionsByRecord returns all PushSubscription models linked to the provided auth record (in DESC order).
+func (app *BaseApp) FindAllPushSubscriptionsByRecord(authRecord *Record) ([]*PushSubscription, error) {
+	result := []*PushSubscription{}
+
+	err := app.RecordQuery(CollectionNamePushSubscriptions).
+		AndWhere(dbx.HashExp{
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+		}).
+		OrderBy("created DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindPushSubscriptionById returns a single PushSubscription model by its id.
+func (app *BaseApp) FindPushSubscriptionById(id string) (*PushSubscription, error) {
+	result := &PushSubscription{}
+
+	err := app.RecordQuery(CollectionNamePushSubscriptions).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteAllPushSubscriptionsByRecord deletes all PushSubscription models associated with the provided record.
+//
+// Returns a combined error with the failed deletes.
+func (app *BaseApp) DeleteAllPushSubscriptionsByRecord(authRecord *Record) error {
+	models, err := app.FindAllPushSubscriptionsByRecord(authRecord)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, m := range models {
+		if err := app.Delete(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
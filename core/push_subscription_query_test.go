@@ -0,0 +1,180 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+// createTestPushSubscription creates and saves a new push subscription for the provided auth record.
+func createTestPushSubscription(t testing.TB, app core.App, authRecord *core.Record, endpoint string) *core.PushSubscription {
+	sub := core.NewPushSubscription(app)
+	sub.SetCollectionRef(authRecord.Collection().Id)
+	sub.SetRecordRef(authRecord.Id)
+	sub.SetEndpoint(endpoint)
+	sub.SetP256dh(testPushP256dh(t))
+	sub.SetAuth(testPushAuth)
+
+	if err := app.Save(sub); err != nil {
+		t.Fatal(err)
+	}
+
+	return sub
+}
+
+func TestFindAllPushSubscriptionsByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub1 := createTestPushSubscription(t, app, user1, "https://example.com/push1")
+	sub2 := createTestPushSubscription(t, app, user1, "https://example.com/push2")
+	createTestPushSubscription(t, app, user2, "https://example.com/push3")
+
+	result, err := app.FindAllPushSubscriptionsByRecord(user1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 subscriptions, got %d", len(result))
+	}
+
+	for _, id := range []string{sub1.Id, sub2.Id} {
+		var found bool
+		for _, sub := range result {
+			if sub.Id == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("Missing subscription %q", id)
+		}
+	}
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = app.FindAllPushSubscriptionsByRecord(superuser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 0 {
+		t.Fatalf("Expected no subscriptions, got %d", len(result))
+	}
+}
+
+func TestFindPushSubscriptionById(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := createTestPushSubscription(t, app, user, "https://example.com/push")
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{sub.Id, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.id, func(t *testing.T) {
+			result, err := app.FindPushSubscriptionById(s.id)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if result.Id != s.id {
+				t.Fatalf("Expected record with id %q, got %q", s.id, result.Id)
+			}
+		})
+	}
+}
+
+func TestDeleteAllPushSubscriptionsByRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createTestPushSubscription(t, app, user1, "https://example.com/push1")
+	createTestPushSubscription(t, app, user1, "https://example.com/push2")
+	createTestPushSubscription(t, app, user2, "https://example.com/push3")
+
+	if err := app.DeleteAllPushSubscriptionsByRecord(user1); err != nil {
+		t.Fatal(err)
+	}
+
+	subs1, _ := app.FindAllPushSubscriptionsByRecord(user1)
+	if len(subs1) != 0 {
+		t.Fatalf("Expected all user1 subscriptions to be deleted, found %d", len(subs1))
+	}
+
+	subs2, _ := app.FindAllPushSubscriptionsByRecord(user2)
+	if len(subs2) != 1 {
+		t.Fatalf("Expected the user2 subscriptions to remain, found %d", len(subs2))
+	}
+}
+
+func TestPushSubscriptionsDeleteOnRecordDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := createTestPushSubscription(t, app, user, "https://example.com/push")
+
+	if err := app.Delete(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindPushSubscriptionById(sub.Id); err == nil {
+		t.Fatal("Expected the push subscription to be deleted together with its auth record")
+	}
+}
@@ -0,0 +1,167 @@
+package core_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/webpush"
+)
+
+func TestSendPush(t *testing.T) {
+	t.Parallel()
+
+	// local push service stub
+	var mu sync.Mutex
+	requests := map[string]*http.Request{}
+	var totalConns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		mu.Lock()
+		requests[r.URL.Path] = r
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			totalConns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	pub, priv, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		if err := app.SendPush(user, []byte("test")); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	app.Settings().WebPush.Enabled = true
+	app.Settings().WebPush.Subject = "mailto:test@example.com"
+	app.Settings().WebPush.PublicKey = pub
+	app.Settings().WebPush.PrivateKey = priv
+	app.Settings().WebPush.TTL = 60
+	app.Settings().WebPush.AllowedHosts = []string{server.Listener.Addr().String()}
+
+	t.Run("too large payload", func(t *testing.T) {
+		err := app.SendPush(user, bytes.Repeat([]byte("a"), webpush.MaxPayloadSize+1))
+		if !errors.Is(err, webpush.ErrPayloadTooLarge) {
+			t.Fatalf("Expected ErrPayloadTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("no subscriptions", func(t *testing.T) {
+		if err := app.SendPush(user, []byte("test")); err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+	})
+
+	ok := createTestPushSubscription(t, app, user, server.URL+"/ok")
+	gone := createTestPushSubscription(t, app, user, server.URL+"/gone")
+	missing := createTestPushSubscription(t, app, user, server.URL+"/missing")
+	failed := createTestPushSubscription(t, app, user, server.URL+"/error")
+
+	t.Run("with subscriptions", func(t *testing.T) {
+		err := app.SendPush(user, []byte("test"))
+		if err == nil || !strings.Contains(err.Error(), "500") {
+			t.Fatalf("Expected the /error push to fail, got %v", err)
+		}
+
+		for _, path := range []string{"/ok", "/gone", "/missing", "/error"} {
+			r := requests[path]
+			if r == nil {
+				t.Fatalf("Expected %s push request", path)
+			}
+
+			if v := r.Header.Get("TTL"); v != "60" {
+				t.Fatalf("[%s] Expected TTL header 60, got %q", path, v)
+			}
+
+			if v := r.Header.Get("Content-Encoding"); v != "aes128gcm" {
+				t.Fatalf("[%s] Expected aes128gcm Content-Encoding, got %q", path, v)
+			}
+
+			if v := r.Header.Get("Authorization"); !strings.HasPrefix(v, "vapid t=") || !strings.HasSuffix(v, ", k="+pub) {
+				t.Fatalf("[%s] Invalid VAPID Authorization header %q", path, v)
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			if len(body) <= 86 {
+				t.Fatalf("[%s] Expected encrypted body, got %d bytes", path, len(body))
+			}
+		}
+
+		// the expired subscriptions should have been pruned
+		for _, sub := range []*core.PushSubscription{gone, missing} {
+			if _, err := app.FindPushSubscriptionById(sub.Id); err == nil {
+				t.Fatalf("Expected expired subscription %q to be deleted", sub.Endpoint())
+			}
+		}
+
+		for _, sub := range []*core.PushSubscription{ok, failed} {
+			if _, err := app.FindPushSubscriptionById(sub.Id); err != nil {
+				t.Fatalf("Expected subscription %q to remain, got %v", sub.Endpoint(), err)
+			}
+		}
+	})
+
+	t.Run("reuse the client", func(t *testing.T) {
+		conns := totalConns.Load()
+
+		if err := app.SendPush(user, []byte("test")); err == nil {
+			t.Fatal("Expected the /error push to fail")
+		}
+
+		if v := totalConns.Load(); v != conns {
+			t.Fatalf("Expected the push client connections to be reused, got %d new connections", v-conns)
+		}
+	})
+
+	t.Run("rebuild the client on settings change", func(t *testing.T) {
+		conns := totalConns.Load()
+
+		app.Settings().WebPush.Subject = "mailto:test2@example.com"
+
+		if err := app.SendPush(user, []byte("test")); err == nil {
+			t.Fatal("Expected the /error push to fail")
+		}
+
+		if v := totalConns.Load(); v == conns {
+			t.Fatal("Expected a new push client to be created")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// queueWorker holds the shared state of a persistent queue background worker
+// that processes the queue periodically or when explicitly notified.
+type queueWorker struct {
+	processMu sync.Mutex
+
+	mu   sync.Mutex
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// notify wakes up the running worker (if any) without blocking.
+func (w *queueWorker) notify() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.wake == nil {
+		return
+	}
+
+	select {
+	case w.wake <- struct{}{}:
+	default: // already notified
+	}
+}
+
+// start starts the background worker loop that calls process
+// every interval or on notify (it is no-op if the worker is already running).
+func (w *queueWorker) start(interval time.Duration, process func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop != nil {
+		return // already running
+	}
+
+	wake := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	w.wake = wake
+	w.stop = stop
+	w.done = done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+
+			// prioritize the stop signal in case multiple channels are ready
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			process()
+		}
+	}()
+}
+
+// halt stops the background worker loop (if running)
+// and waits for the in-progress processing (if any) to complete.
+func (w *queueWorker) halt() {
+	w.mu.Lock()
+
+	if w.stop == nil {
+		w.mu.Unlock()
+		return // not running
+	}
+
+	close(w.stop)
+	done := w.done
+
+	w.wake = nil
+	w.stop = nil
+	w.done = nil
+
+	// unlock before waiting because the processing could still call notify
+	w.mu.Unlock()
+
+	<-done
+}
+
+// retryDelay returns an exponential backoff delay based on the number of
+// the already failed attempts (the initial delay is doubled after each attempt).
+func retryDelay(initial time.Duration, failedAttempts int, maxDelay time.Duration) time.Duration {
+	delay := initial
+
+	for i := 1; i < failedAttempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
@@ -17,6 +17,7 @@ import (
 
 // filter modifiers
 const (
+	countModifier  string = "count"
 	eachModifier   string = "each"
 	issetModifier  string = "isset"
 	lengthModifier string = "length"
@@ -105,10 +106,21 @@ func NewRecordFieldResolver(
 		r.staticRequestInfo["auth"] = nil
 		if r.requestInfo.Auth != nil {
 			authClone := r.requestInfo.Auth.Clone()
-			r.staticRequestInfo["auth"] = authClone.
+			authData := authClone.
 				Unhide(authClone.Collection().Fields.FieldNames()...).
 				IgnoreEmailVisibility(true).
 				PublicExport()
+
+			// virtual API key scopes field (if not shadowed by a regular collection field)
+			if authClone.Collection().Fields.GetByName(requestAuthScopesField) == nil {
+				scopes := []string{}
+				if r.requestInfo.APIKey != nil {
+					scopes = r.requestInfo.APIKey.Scopes()
+				}
+				authData[requestAuthScopesField] = scopes
+			}
+
+			r.staticRequestInfo["auth"] = authData
 		}
 	}
 
@@ -120,6 +132,11 @@ func NewRecordFieldResolver(
 // Conditionally updates the provided search query based on the
 // resolved fields (eg. dynamically joining relations).
 func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
+	// restrict the base collection records to the request auth tenant (if any)
+	if exp := tenantScopeExp(r.baseCollection, r.requestInfo, r.baseCollection.Name); exp != nil {
+		query.AndWhere(exp)
+	}
+
 	if len(r.joins) > 0 {
 		query.Distinct(true)
 
@@ -243,7 +260,7 @@ func (r *RecordFieldResolver) registerJoin(tableName string, tableAlias string,
 	join := &join{
 		tableName:  tableName,
 		tableAlias: tableAlias,
-		on:         on,
+		on:         r.scopedJoinExp(tableName, tableAlias, on),
 	}
 
 	// replace existing join
@@ -258,6 +275,38 @@ func (r *RecordFieldResolver) registerJoin(tableName string, tableAlias string,
 	r.joins = append(r.joins, join)
 }
 
+// scopedJoinExp extends the on join expression to exclude the soft
+// deleted and the other tenants tableName rows (if tableName is
+// a collection with soft delete and/or tenant field).
+func (r *RecordFieldResolver) scopedJoinExp(tableName string, tableAlias string, on dbx.Expression) dbx.Expression {
+	collection, _ := r.loadCollection(tableName)
+	if collection == nil {
+		return on
+	}
+
+	exps := []dbx.Expression{}
+	if on != nil {
+		exps = append(exps, on)
+	}
+
+	if hasSoftDelete(collection) {
+		exps = append(exps, &notDeletedExp{tableAlias: tableAlias})
+	}
+
+	if exp := tenantScopeExp(collection, r.requestInfo, tableAlias); exp != nil {
+		exps = append(exps, exp)
+	}
+
+	switch len(exps) {
+	case 0:
+		return nil
+	case 1:
+		return exps[0]
+	default:
+		return dbx.And(exps...)
+	}
+}
+
 type mapExtractor interface {
 	AsMap() map[string]any
 }
@@ -393,6 +442,7 @@ func splitModifier(combined string) (string, string, error) {
 	// validate modifier
 	switch parts[1] {
 	case issetModifier,
+		countModifier,
 		eachModifier,
 		lengthModifier,
 		lowerModifier:
@@ -24,6 +24,9 @@ type multiMatchSubquery struct {
 	valueIdentifier string
 	joins           []*join
 	params          dbx.Params
+
+	// joinExp is an optional function to extend the joins on expression.
+	joinExp func(tableName string, tableAlias string, on dbx.Expression) dbx.Expression
 }
 
 // Build converts the expression into a SQL fragment.
@@ -52,9 +55,14 @@ func (m *multiMatchSubquery) Build(db *dbx.DB, params dbx.Params) string {
 		mergedJoins.WriteString(db.QuoteTableName(j.tableName))
 		mergedJoins.WriteString(" ")
 		mergedJoins.WriteString(db.QuoteTableName(j.tableAlias))
-		if j.on != nil {
+
+		on := j.on
+		if m.joinExp != nil {
+			on = m.joinExp(j.tableName, j.tableAlias, on)
+		}
+		if on != nil {
 			mergedJoins.WriteString(" ON ")
-			mergedJoins.WriteString(j.on.Build(db, params))
+			mergedJoins.WriteString(on.Build(db, params))
 		}
 	}
 
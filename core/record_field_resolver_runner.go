@@ -21,6 +21,10 @@ import (
 // maxNestedRels defines the max allowed nested relations depth.
 const maxNestedRels = 6
 
+// requestAuthScopesField is the name of the virtual "@request.auth.*"
+// field that holds the scopes of the API key used for the request auth.
+const requestAuthScopesField = "scopes"
+
 // list of auth filter fields that don't require join with the auth
 // collection or any other extra checks to be resolved.
 var plainRequestAuthFields = map[string]struct{}{
@@ -149,6 +153,7 @@ func (r *runner) prepare() {
 	r.multiMatch = &multiMatchSubquery{
 		baseTableAlias: r.activeTableAlias,
 		params:         dbx.Params{},
+		joinExp:        r.resolver.scopedJoinExp,
 	}
 	r.multiMatch.fromTableName = inflector.Columnify(r.activeCollectionName)
 	r.multiMatch.fromTableAlias = "__mm_" + r.activeTableAlias
@@ -211,6 +216,19 @@ func (r *runner) processRequestAuthField() (*search.ResolverResult, error) {
 	// ---
 	collection := r.resolver.requestInfo.Auth.Collection()
 
+	// virtual API key scopes field
+	// ---
+	if len(r.activeProps) == 3 && collection.Fields.GetByName(requestAuthScopesField) == nil {
+		name, modifier, err := splitModifier(r.activeProps[2])
+		if err != nil {
+			return nil, err
+		}
+
+		if name == requestAuthScopesField {
+			return r.processRequestAuthScopesField(modifier)
+		}
+	}
+
 	r.activeCollectionName = collection.Name
 	r.activeTableAlias = "__auth_" + inflector.Columnify(r.activeCollectionName)
 
@@ -244,6 +262,57 @@ func (r *runner) processRequestAuthField() (*search.ResolverResult, error) {
 	return r.processActiveProps()
 }
 
+// processRequestAuthScopesField resolves the virtual "@request.auth.scopes" field.
+//
+// The scopes are always treated as a multi-valued field, aka.
+// "@request.auth.scopes ?= 'posts:write'" checks whether the request
+// API key has the "posts:write" scope.
+func (r *runner) processRequestAuthScopesField(modifier string) (*search.ResolverResult, error) {
+	var scopes []string
+	if r.resolver.requestInfo.APIKey != nil {
+		scopes = r.resolver.requestInfo.APIKey.Scopes()
+	}
+
+	if modifier == lengthModifier {
+		return &search.ResolverResult{Identifier: strconv.Itoa(len(scopes))}, nil
+	}
+
+	if modifier != "" && modifier != eachModifier {
+		return nil, fmt.Errorf("unsupported @request.auth.%s modifier %q", requestAuthScopesField, modifier)
+	}
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	scopesRaw, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize the @request.auth.%s data", requestAuthScopesField)
+	}
+
+	// note: use a unique alias so that multiple scopes expressions
+	// could be combined (eg. "scopes ?= 'a' && scopes ?= 'b'")
+	suffix := security.PseudorandomString(6)
+	placeholder := "authScopes" + suffix
+	jeAlias := "__authScopes_" + suffix + "_je"
+	r.resolver.registerJoin(fmt.Sprintf("json_each({:%s})", placeholder), jeAlias, nil)
+
+	placeholder2 := "mm" + placeholder
+	jeAlias2 := "__mm" + jeAlias
+	r.multiMatch.joins = append(r.multiMatch.joins, &join{
+		tableName:  fmt.Sprintf("json_each({:%s})", placeholder2),
+		tableAlias: jeAlias2,
+	})
+	r.multiMatch.params[placeholder2] = scopesRaw
+	r.multiMatch.valueIdentifier = fmt.Sprintf("[[%s.value]]", jeAlias2)
+
+	return &search.ResolverResult{
+		Identifier:         fmt.Sprintf("[[%s.value]]", jeAlias),
+		Params:             dbx.Params{placeholder: scopesRaw},
+		MultiMatchSubQuery: r.multiMatch,
+	}, nil
+}
+
 // note: nil value is returned as empty slice
 func toSlice(value any) []any {
 	if value == nil {
@@ -419,6 +488,21 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 			return nil, fmt.Errorf("non-filterable field %q", prop)
 		}
 
+		if isEncryptedField(field) {
+			return nil, fmt.Errorf("encrypted field %q cannot be used in filter and sort expressions", prop)
+		}
+
+		// back relation field -> resolve as its equivalent "yourCollection_via_yourRelField" prop
+		if backRelField, ok := field.(*BackRelationField); ok {
+			backCollection, err := r.resolver.loadCollection(backRelField.CollectionId)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load back relation field %q collection", prop)
+			}
+
+			prop = backCollection.Name + "_via_" + backRelField.FieldName
+			field = nil
+		}
+
 		// json or geoPoint field -> treat the rest of the props as json path
 		// @todo consider converting to "JSONExtractable" interface with optional extra validation for the remaining props?
 		if field != nil && (field.Type() == FieldTypeJSON || field.Type() == FieldTypeGeoPoint) {
@@ -694,6 +778,24 @@ func (r *runner) processLastProp(collection *Collection, prop string) (*search.R
 		return nil, fmt.Errorf("non-filterable field %q", name)
 	}
 
+	if isEncryptedField(field) {
+		return nil, fmt.Errorf("encrypted field %q cannot be used in filter and sort expressions", name)
+	}
+
+	// back relation fields ":count" modifier
+	// -------------------------------------------------------
+	if backRelField, ok := field.(*BackRelationField); ok {
+		if modifier != countModifier {
+			return nil, fmt.Errorf("back relation field %q could be used only with the :count modifier or a nested field", name)
+		}
+
+		return r.processBackRelationCount(backRelField)
+	}
+
+	if modifier == countModifier {
+		return nil, fmt.Errorf("the :count modifier is supported only by back relation fields, got %q", name)
+	}
+
 	multvaluer, isMultivaluer := field.(MultiValuer)
 
 	cleanFieldName := inflector.Columnify(field.GetName())
@@ -758,6 +860,11 @@ func (r *runner) processLastProp(collection *Collection, prop string) (*search.R
 		result.MultiMatchSubQuery = r.multiMatch
 	}
 
+	// compare the decimal literals with the stored sortable values
+	if _, ok := field.(*DecimalField); ok {
+		result.ParamNormalizer = normalizeDecimalParam
+	}
+
 	// allow querying only auth records with emails marked as public
 	if field.GetName() == FieldNameEmail && !r.allowHiddenFields && collection.IsAuth() {
 		result.AfterBuild = func(expr dbx.Expression) dbx.Expression {
@@ -790,3 +897,97 @@ func (r *runner) processLastProp(collection *Collection, prop string) (*search.R
 
 	return result, nil
 }
+
+func (r *runner) processBackRelationCount(backRelField *BackRelationField) (*search.ResolverResult, error) {
+	backCollection, err := r.resolver.loadCollection(backRelField.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load back relation field %q collection", backRelField.Name)
+	}
+
+	relField, _ := backCollection.Fields.GetByName(backRelField.FieldName).(*RelationField)
+	if relField == nil {
+		return nil, fmt.Errorf("missing back relation field %q", backRelField.FieldName)
+	}
+
+	if relField.GetHidden() && !r.allowHiddenFields {
+		return nil, fmt.Errorf("non-filterable back relation field %q", relField.Name)
+	}
+
+	countAlias := r.activeTableAlias + "_" + inflector.Columnify(backRelField.Name) + "_count"
+
+	// count only the request auth tenant back relation records (if any)
+	var tenantParams dbx.Params
+	tenantWhere := func(alias string) string { return "" }
+	if tenant, ok := RequestTenant(backCollection, r.resolver.requestInfo); ok && tenant == "" {
+		tenantWhere = func(alias string) string { return " AND 1=0" } // no tenant -> no access
+	} else if ok {
+		placeholder := "tenant" + security.PseudorandomString(6)
+		tenantParams = dbx.Params{placeholder: tenant}
+		tenantWhere = func(alias string) string {
+			return fmt.Sprintf(" AND [[%s.%s]] = {:%s}", alias, inflector.Columnify(backCollection.TenantField), placeholder)
+		}
+	}
+
+	result := &search.ResolverResult{
+		Identifier: backRelationCountExpr(backCollection, relField, r.activeTableAlias, countAlias, tenantWhere(countAlias)),
+		Params:     tenantParams,
+	}
+
+	if r.withMultiMatch {
+		countAlias2 := r.multiMatchActiveTableAlias + "_" + inflector.Columnify(backRelField.Name) + "_count"
+		r.multiMatch.valueIdentifier = backRelationCountExpr(backCollection, relField, r.multiMatchActiveTableAlias, countAlias2, tenantWhere(countAlias2))
+		for k, v := range tenantParams {
+			r.multiMatch.params[k] = v
+		}
+		result.MultiMatchSubQuery = r.multiMatch
+	}
+
+	return result, nil
+}
+
+// backRelationCountExpr returns a subquery expression that counts the
+// backCollection records referencing the tableAlias record through relField.
+//
+// extraWhere is an optional condition appended to the subquery WHERE clause.
+func backRelationCountExpr(backCollection *Collection, relField *RelationField, tableAlias string, countAlias string, extraWhere string) string {
+	cleanFieldName := inflector.Columnify(relField.Name)
+
+	// exclude the soft deleted back relation records
+	where := extraWhere
+	if hasSoftDelete(backCollection) {
+		where += fmt.Sprintf(" AND [[%s.%s]] = ''", countAlias, FieldNameDeleted)
+	}
+
+	if !relField.IsMultiple() {
+		return fmt.Sprintf(
+			"(SELECT COUNT(*) FROM {{%s}} {{%s}} WHERE [[%s.%s]] = [[%s.id]]%s)",
+			inflector.Columnify(backCollection.Name),
+			countAlias,
+			countAlias,
+			cleanFieldName,
+			tableAlias,
+			where,
+		)
+	}
+
+	jeAlias := countAlias + "_je"
+
+	return fmt.Sprintf(
+		"(SELECT COUNT(*) FROM {{%s}} {{%s}} WHERE EXISTS (SELECT 1 FROM %s {{%s}} WHERE [[%s.value]] = [[%s.id]])%s)",
+		inflector.Columnify(backCollection.Name),
+		countAlias,
+		dbutils.JSONEach(countAlias+"."+cleanFieldName),
+		jeAlias,
+		jeAlias,
+		tableAlias,
+		where,
+	)
+}
+
+// isEncryptedField checks whether the provided field value is stored encrypted
+// (aka. it cannot be compared or sorted on the db level).
+func isEncryptedField(field Field) bool {
+	e, ok := field.(EncryptedValuer)
+
+	return ok && e.IsEncrypted()
+}
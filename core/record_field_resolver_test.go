@@ -806,3 +806,76 @@ func TestRecordFieldResolverResolveStaticRequestInfoFields(t *testing.T) {
 		t.Fatalf("Expected the original authRecord email to not be exported, got %q", v)
 	}
 }
+
+func TestRecordFieldResolverRequestAuthScopes(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := app.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey := core.NewAPIKey(app)
+	apiKey.SetScopes([]string{"posts:read", "posts:write"})
+
+	scenarios := []struct {
+		name        string
+		apiKey      *core.APIKey
+		rule        string
+		expectError bool
+		expectTotal int
+	}{
+		{"no API key", nil, "@request.auth.scopes ?= 'posts:write'", false, 0},
+		{"no API key (length)", nil, "@request.auth.scopes:length = 0", false, 3},
+		{"missing scope", apiKey, "@request.auth.scopes ?= 'posts:delete'", false, 0},
+		{"existing scope", apiKey, "@request.auth.scopes ?= 'posts:write'", false, 3},
+		{"multiple existing scopes", apiKey, "@request.auth.scopes ?= 'posts:read' && @request.auth.scopes ?= 'posts:write'", false, 3},
+		{"existing and missing scopes", apiKey, "@request.auth.scopes ?= 'posts:read' && @request.auth.scopes ?= 'posts:delete'", false, 0},
+		{"all scopes match", apiKey, "@request.auth.scopes = 'posts:write'", false, 0},
+		{"scopes length", apiKey, "@request.auth.scopes:length = 2", false, 3},
+		{"scopes each", apiKey, "@request.auth.scopes:each ~ 'posts:'", false, 3},
+		{"unsupported modifier", apiKey, "@request.auth.scopes:lower = 'posts:write'", true, 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			requestInfo := &core.RequestInfo{Auth: authRecord, APIKey: s.apiKey}
+
+			r := core.NewRecordFieldResolver(app, collection, requestInfo, false)
+
+			expr, err := search.FilterData(s.rule).BuildExpr(r)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v", s.expectError, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			query := app.RecordQuery(collection)
+
+			if err := r.UpdateQuery(query); err != nil {
+				t.Fatal(err)
+			}
+
+			var records []*core.Record
+			if err := query.AndWhere(expr).All(&records); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != s.expectTotal {
+				t.Fatalf("Expected %d records, got %d", s.expectTotal, len(records))
+			}
+		})
+	}
+}
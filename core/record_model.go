@@ -240,6 +240,12 @@ func (app *BaseApp) registerRecordHooks() {
 				err := me.App.OnRecordDeleteExecute().Trigger(re, func(re *RecordEvent) error {
 					syncModelEventWithRecordEvent(me, re)
 					defer syncRecordEventWithModelEvent(re, me)
+
+					// mark as deleted instead of removing the db row
+					if re.Record.isSoftDelete() {
+						return softDeleteRecord(re)
+					}
+
 					return me.Next()
 				})
 				syncModelEventWithRecordEvent(me, re)
@@ -415,6 +421,8 @@ func (app *BaseApp) registerRecordHooks() {
 	app.OnRecordDelete().Bind(&hook.Handler[*RecordEvent]{
 		Id: systemHookIdRecord,
 		Func: func(e *RecordEvent) error {
+			e.Record.initSoftDelete()
+
 			return e.Record.callFieldInterceptors(
 				e.Context,
 				e.App,
@@ -478,8 +486,9 @@ func (app *BaseApp) registerRecordHooks() {
 // newRecordFromNullStringMap initializes a single new Record model
 // with data loaded from the provided NullStringMap.
 //
-// Note that this method is intended to load and Scan data from a database row result.
-func newRecordFromNullStringMap(collection *Collection, data dbx.NullStringMap) (*Record, error) {
+// Note that this method is intended to load and Scan data from a database row result
+// (the app is used to decrypt the encrypted fields values).
+func newRecordFromNullStringMap(app App, collection *Collection, data dbx.NullStringMap) (*Record, error) {
 	record := NewRecord(collection)
 
 	var fieldName string
@@ -491,7 +500,32 @@ func newRecordFromNullStringMap(collection *Collection, data dbx.NullStringMap)
 		var value any
 		var err error
 
-		if ok && nullString.Valid {
+		if ok && nullString.Valid && isEncryptedField(field) {
+			plain, decryptErr := importEncryptedFieldValue(app, collection.Id, data[FieldNameId].String, field, nullString.String)
+			if decryptErr != nil {
+				// load the field zero value and keep the stored one only
+				// to persist it back as it is if the field is not changed
+				app.Logger().Warn(
+					"Failed to decrypt field value",
+					"error", decryptErr,
+					"fieldName", fieldName,
+					"recordId", data[FieldNameId].String,
+					"collectionId", collection.Id,
+				)
+				record.originalData[undecryptableFieldPrefix+fieldName] = nullString.String
+				nullString.Valid = false
+			} else {
+				nullString.String = plain
+			}
+		}
+
+		if scanner, isScanner := field.(DriverValueScanner); isScanner {
+			if ok && nullString.Valid {
+				value, err = scanner.ScanDriverValue(record, nullString.String)
+			} else {
+				value, err = scanner.ScanDriverValue(record, nil)
+			}
+		} else if ok && nullString.Valid {
 			value, err = field.PrepareValue(record, nullString.String)
 		} else {
 			value, err = field.PrepareValue(record, nil)
@@ -519,12 +553,12 @@ func newRecordFromNullStringMap(collection *Collection, data dbx.NullStringMap)
 // each row in the provided NullStringMap slice.
 //
 // Note that this method is intended to load and Scan data from a database rows result.
-func newRecordsFromNullStringMaps(collection *Collection, rows []dbx.NullStringMap) ([]*Record, error) {
+func newRecordsFromNullStringMaps(app App, collection *Collection, rows []dbx.NullStringMap) ([]*Record, error) {
 	result := make([]*Record, len(rows))
 
 	var err error
 	for i, row := range rows {
-		result[i], err = newRecordFromNullStringMap(collection, row)
+		result[i], err = newRecordFromNullStringMap(app, collection, row)
 		if err != nil {
 			return nil, err
 		}
@@ -589,11 +623,32 @@ func (m *Record) PostScan() error {
 		return err
 	}
 
-	m.originalData = m.FieldsData()
+	newOriginalData := m.FieldsData()
+
+	// keep the undecryptable fields stored values (if still unchanged)
+	for k, v := range m.originalData {
+		name, ok := strings.CutPrefix(k, undecryptableFieldPrefix)
+		if ok && areValuesEqual(m.originalData[name], newOriginalData[name]) {
+			newOriginalData[k] = v
+		}
+	}
+
+	m.originalData = newOriginalData
 
 	return nil
 }
 
+// HasUndecryptableValue reports whether the stored value of the
+// specified encrypted field failed to be decrypted when the record was loaded
+// (eg. because of a missing encryption key).
+//
+// In this case the field has its zero value and the stored encrypted
+// value is preserved as it is on save as long as the field is not changed.
+func (m *Record) HasUndecryptableValue(fieldName string) bool {
+	_, ok := m.originalData[undecryptableFieldPrefix+fieldName]
+	return ok
+}
+
 // HookTags returns the hook tags associated with the current record.
 func (m *Record) HookTags() []string {
 	return []string{m.collection.Name, m.collection.Id}
@@ -1109,10 +1164,16 @@ func (m *Record) DBExport(app App) (map[string]any, error) {
 			return nil, err
 		}
 
+		// the encrypted values are bound to the record id and must be re-encrypted on id change
+		idChanged := m.Id != cast.ToString(m.LastSavedPK())
+
 		for oldK, oldV := range oldResult {
 			if oldK == idColumn {
 				continue
 			}
+			if idChanged && isEncryptedField(m.Collection().Fields.GetByName(oldK)) {
+				continue
+			}
 			newV, ok := result[oldK]
 			if ok && areValuesEqual(newV, oldV) {
 				delete(result, oldK)
@@ -1120,6 +1181,22 @@ func (m *Record) DBExport(app App) (map[string]any, error) {
 		}
 	}
 
+	// encrypt the remaining encrypted fields values
+	// (after the above check because the encryption is not deterministic)
+	for _, field := range m.Collection().Fields {
+		name := field.GetName()
+
+		v, ok := result[name]
+		if !ok || !isEncryptedField(field) {
+			continue
+		}
+
+		result[name], err = exportEncryptedFieldValue(app, m, field, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt field %q value: %w", name, err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -1130,6 +1207,14 @@ func (m *Record) dbExport() (map[string]any, error) {
 
 	var fieldName string
 	for _, field := range fields {
+		if g, ok := field.(GeneratedValuer); ok && g.IsGenerated() {
+			continue // computed by the database
+		}
+
+		if v, ok := field.(VirtualValuer); ok && v.IsVirtual() {
+			continue // no table column
+		}
+
 		fieldName = field.GetName()
 
 		if f, ok := field.(DriverValuer); ok {
@@ -1383,6 +1468,12 @@ func (m *Record) ReplaceModifiers(data map[string]any) map[string]any {
 
 			// store the transformed value under the field name
 			dataCopy[field.GetName()] = recordCopy.Get(field.GetName())
+
+			// transfer the pending positional modifiers (if any)
+			// so that they could be rebased on save (see interceptPositionalModifiers)
+			if pending := recordCopy.GetRaw(positionalModifiersPrefix + field.GetName()); pending != nil {
+				m.SetRaw(positionalModifiersPrefix+field.GetName(), pending)
+			}
 		}
 	}
 
@@ -1430,7 +1521,7 @@ func onRecordSaveExecute(e *RecordEvent) error {
 	if e.Record.Collection().IsAuth() {
 		// ensure that the token key is regenerated on password change or email change
 		if !e.Record.IsNew() {
-			lastSavedRecord, err := e.App.FindRecordById(e.Record.Collection(), e.Record.Id)
+			lastSavedRecord, err := e.App.FindRecordById(e.Record.Collection(), e.Record.Id, WithDeletedRecords)
 			if err != nil {
 				return err
 			}
@@ -1451,7 +1542,7 @@ func onRecordSaveExecute(e *RecordEvent) error {
 			if e.Record.Collection().Id == collection.Id {
 				continue // skip current collection (sqlite will do the check for us)
 			}
-			record, _ := e.App.FindRecordById(collection, e.Record.Id)
+			record, _ := e.App.FindRecordById(collection, e.Record.Id, WithDeletedRecords)
 			if record != nil {
 				return validation.Errors{
 					FieldNameId: validation.NewError("validation_invalid_auth_id", "Invalid or duplicated auth record id."),
@@ -1465,6 +1556,10 @@ func onRecordSaveExecute(e *RecordEvent) error {
 		return nil
 	}
 
+	if constraintErr := normalizeConstraintError(err, e.Record.Collection()); constraintErr != nil {
+		return constraintErr
+	}
+
 	return validators.NormalizeUniqueIndexError(
 		err,
 		e.Record.Collection().Name,
@@ -1473,6 +1568,12 @@ func onRecordSaveExecute(e *RecordEvent) error {
 }
 
 func onRecordDeleteExecute(e *RecordEvent) error {
+	// the references are checked and updated only on permanent delete
+	// so that the soft deleted record could be restored as it was
+	if e.Record.isSoftDelete() {
+		return e.Next()
+	}
+
 	// fetch rel references (if any)
 	//
 	// note: the select is outside of the transaction to minimize
@@ -1486,6 +1587,11 @@ func onRecordDeleteExecute(e *RecordEvent) error {
 	txErr := e.App.RunInTransaction(func(txApp App) error {
 		e.App = txApp
 
+		// check for references that don't allow the record to be deleted
+		if err := checkBlockingRecordRefs(txApp, e.Record, refs); err != nil {
+			return err
+		}
+
 		// delete the record before the relation references to ensure that there
 		// will be no "A<->B" relations to prevent deadlock when calling DeleteRecord recursively
 		if err := e.Next(); err != nil {
@@ -1499,49 +1605,103 @@ func onRecordDeleteExecute(e *RecordEvent) error {
 	return txErr
 }
 
-// cascadeRecordDelete triggers cascade deletion for the provided references.
+// maxBlockingRefIds is the max number of reference record ids listed in a single blocking reference error.
+const maxBlockingRefIds = 10
+
+// checkBlockingRecordRefs returns a validation error listing the references
+// that prevent the main record deletion, aka. references from "restrict"
+// relation fields or from required "setNull" relation fields without other linked relations.
 //
 // NB! This method is expected to be called from inside of a transaction.
-func cascadeRecordDelete(app App, mainRecord *Record, refs map[*Collection][]Field) error {
-	// Sort the refs keys to ensure that the cascade events firing order is always the same.
-	// This is not necessary for the operation to function correctly but it helps having deterministic output during testing.
-	sortedRefKeys := make([]*Collection, 0, len(refs))
-	for k := range refs {
-		sortedRefKeys = append(sortedRefKeys, k)
-	}
-	sort.Slice(sortedRefKeys, func(i, j int) bool {
-		return sortedRefKeys[i].Name < sortedRefKeys[j].Name
-	})
-
-	for _, refCollection := range sortedRefKeys {
-		fields, ok := refs[refCollection]
+func checkBlockingRecordRefs(app App, mainRecord *Record, refs map[*Collection][]Field) error {
+	errs := validation.Errors{}
 
-		if !ok || refCollection.IsView() {
-			continue // skip missing or view collections
+	for _, refCollection := range sortedRefCollections(refs) {
+		if refCollection.IsView() {
+			continue // views don't have records to update
 		}
 
-		recordTableName := inflector.Columnify(refCollection.Name)
+		for _, field := range refs[refCollection] {
+			relField, _ := field.(*RelationField)
+			if relField == nil {
+				continue
+			}
+
+			query := recordRefsQuery(app, mainRecord, refCollection, relField)
 
-		for _, field := range fields {
-			prefixedFieldName := recordTableName + "." + inflector.Columnify(field.GetName())
+			switch relField.OnDeleteMode() {
+			case RelationOnDeleteRestrict:
+				// any reference is blocking
+			case RelationOnDeleteSetNull:
+				if !relField.Required {
+					continue
+				}
+				if relField.IsMultiple() {
+					// only the references without other linked relations
+					query.AndWhere(dbx.NewExp(dbutils.JSONArrayLength(
+						inflector.Columnify(refCollection.Name)+"."+inflector.Columnify(relField.Name),
+					) + " <= 1"))
+				}
+			default:
+				continue
+			}
 
-			query := app.RecordQuery(refCollection)
+			ids := []string{}
+			err := query.Select(inflector.Columnify(refCollection.Name) + ".id").
+				Limit(maxBlockingRefIds + 1).
+				Column(&ids)
+			if err != nil {
+				return err
+			}
 
-			if opt, ok := field.(MultiValuer); !ok || !opt.IsMultiple() {
-				query.AndWhere(dbx.HashExp{prefixedFieldName: mainRecord.Id})
-			} else {
-				query.AndWhere(dbx.Exists(dbx.NewExp(fmt.Sprintf(
-					`SELECT 1 FROM %s {{__je__}} WHERE [[__je__.value]]={:jevalue}`,
-					dbutils.JSONEach(prefixedFieldName),
-				), dbx.Params{
-					"jevalue": mainRecord.Id,
-				})))
+			if len(ids) == 0 {
+				continue
+			}
+
+			idsList := strings.Join(ids[:min(len(ids), maxBlockingRefIds)], ", ")
+			if len(ids) > maxBlockingRefIds {
+				idsList += ", ..."
 			}
 
-			if refCollection.Id == mainRecord.Collection().Id {
-				query.AndWhere(dbx.Not(dbx.HashExp{recordTableName + ".id": mainRecord.Id}))
+			errs[refCollection.Name+"."+relField.Name] = validation.NewError(
+				"validation_relation_blocking_reference",
+				"The record cannot be deleted because it is referenced by {{.collection}}.{{.field}} in record(s) {{.ids}}.",
+			).SetParams(map[string]any{
+				"collection": refCollection.Name,
+				"field":      relField.Name,
+				"ids":        idsList,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// cascadeRecordDelete triggers cascade deletion for the provided references.
+//
+// NB! This method is expected to be called from inside of a transaction.
+func cascadeRecordDelete(app App, mainRecord *Record, refs map[*Collection][]Field) error {
+	for _, refCollection := range sortedRefCollections(refs) {
+		if refCollection.IsView() {
+			continue // skip view collections
+		}
+
+		for _, field := range refs[refCollection] {
+			if relField, ok := field.(*RelationField); ok {
+				switch relField.OnDeleteMode() {
+				case RelationOnDeleteRestrict, RelationOnDeleteNoAction:
+					// nothing to update
+					// (the restrict references are checked before the delete)
+					continue
+				}
 			}
 
+			query := recordRefsQuery(app, mainRecord, refCollection, field)
+
 			// trigger cascade for each batchSize rel items until there is none
 			batchSize := 4000
 			rows := make([]*Record, 0, batchSize)
@@ -1572,7 +1732,50 @@ func cascadeRecordDelete(app App, mainRecord *Record, refs map[*Collection][]Fie
 	return nil
 }
 
-// deleteRefRecords checks if related records has to be deleted (if `CascadeDelete` is set)
+// sortedRefCollections returns the refs collection keys sorted by their name.
+//
+// This is not necessary for the delete operation to function correctly but it
+// helps having deterministic cascade events firing order and output during testing.
+func sortedRefCollections(refs map[*Collection][]Field) []*Collection {
+	result := make([]*Collection, 0, len(refs))
+	for k := range refs {
+		result = append(result, k)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// recordRefsQuery returns a query for the refCollection records
+// that reference the main record through the specified field.
+func recordRefsQuery(app App, mainRecord *Record, refCollection *Collection, field Field) *dbx.SelectQuery {
+	recordTableName := inflector.Columnify(refCollection.Name)
+	prefixedFieldName := recordTableName + "." + inflector.Columnify(field.GetName())
+
+	query := app.RecordQuery(refCollection)
+
+	if opt, ok := field.(MultiValuer); !ok || !opt.IsMultiple() {
+		query.AndWhere(dbx.HashExp{prefixedFieldName: mainRecord.Id})
+	} else {
+		query.AndWhere(dbx.Exists(dbx.NewExp(fmt.Sprintf(
+			`SELECT 1 FROM %s {{__je__}} WHERE [[__je__.value]]={:jevalue}`,
+			dbutils.JSONEach(prefixedFieldName),
+		), dbx.Params{
+			"jevalue": mainRecord.Id,
+		})))
+	}
+
+	if refCollection.Id == mainRecord.Collection().Id {
+		query.AndWhere(dbx.Not(dbx.HashExp{recordTableName + ".id": mainRecord.Id}))
+	}
+
+	return query
+}
+
+// deleteRefRecords checks if related records has to be deleted (if OnDelete is "cascade")
 // OR
 // just unset the record id from any relation field values (if they are not required).
 //
@@ -1596,7 +1799,7 @@ func deleteRefRecords(app App, mainRecord *Record, refRecords []*Record, field F
 
 		// cascade delete the reference
 		// (only if there are no other active references in case of multiple select)
-		if relField.CascadeDelete && len(ids) == 0 {
+		if relField.OnDeleteMode() == RelationOnDeleteCascade && len(ids) == 0 {
 			if err := app.Delete(refRecord); err != nil {
 				return err
 			}
@@ -2290,6 +2290,148 @@ func TestRecordDelete(t *testing.T) {
 	}
 }
 
+func TestRecordDeleteOnDelete(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name         string
+		field        *core.RelationField
+		refValue     any
+		expectErrors []string
+		expectRef    bool
+		expectValue  string
+	}{
+		{
+			"restrict",
+			&core.RelationField{OnDelete: core.RelationOnDeleteRestrict, MaxSelect: 2},
+			[]string{"p1aaaaaaaaaaaaa", "p2aaaaaaaaaaaaa"},
+			[]string{"children.rel"},
+			true,
+			`["p1aaaaaaaaaaaaa","p2aaaaaaaaaaaaa"]`,
+		},
+		{
+			"setNull (single)",
+			&core.RelationField{OnDelete: core.RelationOnDeleteSetNull},
+			"p1aaaaaaaaaaaaa",
+			nil,
+			true,
+			`""`,
+		},
+		{
+			"setNull (multiple)",
+			&core.RelationField{OnDelete: core.RelationOnDeleteSetNull, MaxSelect: 2},
+			[]string{"p1aaaaaaaaaaaaa", "p2aaaaaaaaaaaaa"},
+			nil,
+			true,
+			`["p2aaaaaaaaaaaaa"]`,
+		},
+		{
+			"setNull (required single)",
+			&core.RelationField{OnDelete: core.RelationOnDeleteSetNull, Required: true},
+			"p1aaaaaaaaaaaaa",
+			[]string{"children.rel"},
+			true,
+			`"p1aaaaaaaaaaaaa"`,
+		},
+		{
+			"setNull (required multiple with other relations)",
+			&core.RelationField{OnDelete: core.RelationOnDeleteSetNull, Required: true, MaxSelect: 2},
+			[]string{"p1aaaaaaaaaaaaa", "p2aaaaaaaaaaaaa"},
+			nil,
+			true,
+			`["p2aaaaaaaaaaaaa"]`,
+		},
+		{
+			"cascade (single)",
+			&core.RelationField{OnDelete: core.RelationOnDeleteCascade},
+			"p1aaaaaaaaaaaaa",
+			nil,
+			false,
+			"",
+		},
+		{
+			"cascade (multiple with other relations)",
+			&core.RelationField{OnDelete: core.RelationOnDeleteCascade, MaxSelect: 2},
+			[]string{"p1aaaaaaaaaaaaa", "p2aaaaaaaaaaaaa"},
+			nil,
+			true,
+			`["p2aaaaaaaaaaaaa"]`,
+		},
+		{
+			"noAction",
+			&core.RelationField{OnDelete: core.RelationOnDeleteNoAction, Required: true},
+			"p1aaaaaaaaaaaaa",
+			nil,
+			true,
+			`"p1aaaaaaaaaaaaa"`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			parents := core.NewBaseCollection("parents")
+			if err := app.Save(parents); err != nil {
+				t.Fatal(err)
+			}
+
+			s.field.Name = "rel"
+			s.field.CollectionId = parents.Id
+
+			children := core.NewBaseCollection("children")
+			children.Fields.Add(s.field)
+			if err := app.Save(children); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range []string{"p1aaaaaaaaaaaaa", "p2aaaaaaaaaaaaa"} {
+				parent := core.NewRecord(parents)
+				parent.Id = id
+				if err := app.Save(parent); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			child := core.NewRecord(children)
+			child.Set("rel", s.refValue)
+			if err := app.Save(child); err != nil {
+				t.Fatal(err)
+			}
+
+			parent, err := app.FindRecordById(parents, "p1aaaaaaaaaaaaa")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = app.Delete(parent)
+			tests.TestValidationErrors(t, err, s.expectErrors)
+
+			_, findErr := app.FindRecordById(parents, "p1aaaaaaaaaaaaa")
+			if hasParent := findErr == nil; hasParent != (len(s.expectErrors) > 0) {
+				t.Fatalf("Expected the parent record to exist %v, got %v", len(s.expectErrors) > 0, hasParent)
+			}
+
+			refreshedChild, _ := app.FindRecordById(children, child.Id)
+			if hasRef := refreshedChild != nil; hasRef != s.expectRef {
+				t.Fatalf("Expected the child record to exist %v, got %v", s.expectRef, hasRef)
+			}
+
+			if refreshedChild != nil {
+				raw, err := json.Marshal(refreshedChild.Get("rel"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if str := string(raw); str != s.expectValue {
+					t.Fatalf("Expected rel value %s, got %s", s.expectValue, str)
+				}
+			}
+		})
+	}
+}
+
 func TestRecordDeleteBatchProcessing(t *testing.T) {
 	t.Parallel()
 
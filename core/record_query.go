@@ -37,6 +37,11 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 
 	query := app.ConcurrentDB().Select(app.ConcurrentDB().QuoteSimpleColumnName(tableName) + ".*").From(tableName)
 
+	// exclude the soft deleted records (unless WithDeletedRecords is applied)
+	if hasSoftDelete(collection) {
+		query.AndWhere(&notDeletedExp{tableAlias: tableName})
+	}
+
 	// in case of an error attach a new context and cancel it immediately with the error
 	if collectionErr != nil {
 		ctx, cancelFunc := context.WithCancelCause(context.Background())
@@ -53,7 +58,7 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 
 				switch v := a.(type) {
 				case *Record:
-					record, err := resolveRecordOneHook(collection, op)
+					record, err := resolveRecordOneHook(app, collection, op)
 					if err != nil {
 						return err
 					}
@@ -62,7 +67,7 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 
 					return nil
 				case RecordProxy:
-					record, err := resolveRecordOneHook(collection, op)
+					record, err := resolveRecordOneHook(app, collection, op)
 					if err != nil {
 						return err
 					}
@@ -80,7 +85,7 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 
 				switch v := sliceA.(type) {
 				case *[]*Record:
-					records, err := resolveRecordAllHook(collection, op)
+					records, err := resolveRecordAllHook(app, collection, op)
 					if err != nil {
 						return err
 					}
@@ -89,7 +94,7 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 
 					return nil
 				case *[]Record:
-					records, err := resolveRecordAllHook(collection, op)
+					records, err := resolveRecordAllHook(app, collection, op)
 					if err != nil {
 						return err
 					}
@@ -126,7 +131,7 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 						return op(sliceA)
 					}
 
-					records, err := resolveRecordAllHook(collection, op)
+					records, err := resolveRecordAllHook(app, collection, op)
 					if err != nil {
 						return err
 					}
@@ -164,20 +169,20 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 	})
 }
 
-func resolveRecordOneHook(collection *Collection, op func(dst any) error) (*Record, error) {
+func resolveRecordOneHook(app App, collection *Collection, op func(dst any) error) (*Record, error) {
 	data := dbx.NullStringMap{}
 	if err := op(&data); err != nil {
 		return nil, err
 	}
-	return newRecordFromNullStringMap(collection, data)
+	return newRecordFromNullStringMap(app, collection, data)
 }
 
-func resolveRecordAllHook(collection *Collection, op func(dst any) error) ([]*Record, error) {
+func resolveRecordAllHook(app App, collection *Collection, op func(dst any) error) ([]*Record, error) {
 	data := []dbx.NullStringMap{}
 	if err := op(&data); err != nil {
 		return nil, err
 	}
-	return newRecordsFromNullStringMaps(collection, data)
+	return newRecordsFromNullStringMaps(app, collection, data)
 }
 
 // dereference returns the underlying value v points to.
@@ -461,6 +466,26 @@ func (app *BaseApp) CountRecords(collectionModelOrIdentifier any, exprs ...dbx.E
 	return total, err
 }
 
+// CountRequestRecords is similar to [BaseApp.CountRecords] but counts only the
+// collection records of the requestInfo tenant (if the collection is tenant scoped).
+//
+// Example:
+//
+//	requestInfo, _ := e.RequestInfo()
+//	total, err := app.CountRequestRecords(requestInfo, "posts", dbx.HashExp{"status": "active"})
+func (app *BaseApp) CountRequestRecords(requestInfo *RequestInfo, collectionModelOrIdentifier any, exprs ...dbx.Expression) (int64, error) {
+	collection, err := getCollectionByModelOrIdentifier(app, collectionModelOrIdentifier)
+	if err != nil {
+		return 0, err
+	}
+
+	scopedExprs := make([]dbx.Expression, 0, len(exprs)+1)
+	scopedExprs = append(scopedExprs, exprs...)
+	scopedExprs = append(scopedExprs, TenantScope(collection, requestInfo))
+
+	return app.CountRecords(collection, scopedExprs...)
+}
+
 // FindAuthRecordByToken finds the auth record associated with the provided JWT
 // (auth, file, verifyEmail, changeEmail, passwordReset types).
 //
@@ -594,8 +619,10 @@ func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, ac
 		return false, nil
 	}
 
-	// empty public rule, aka. everyone can access
-	if *accessRule == "" {
+	_, isTenantScoped := RequestTenant(record.Collection(), requestInfo)
+
+	// empty public rule, aka. everyone (from the same tenant) can access
+	if *accessRule == "" && !isTenantScoped {
 		return true, nil
 	}
 
@@ -607,13 +634,16 @@ func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, ac
 
 	// parse and apply the access rule filter
 	resolver := NewRecordFieldResolver(app, record.Collection(), requestInfo, true)
-	expr, err := search.FilterData(*accessRule).BuildExpr(resolver)
-	if err != nil {
-		return false, err
+	if *accessRule != "" {
+		expr, err := search.FilterData(*accessRule).BuildExpr(resolver)
+		if err != nil {
+			return false, err
+		}
+		query.AndWhere(expr)
 	}
 	resolver.UpdateQuery(query)
 
-	err = query.AndWhere(expr).Limit(1).Row(&exists)
+	err := query.Limit(1).Row(&exists)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return false, err
 	}
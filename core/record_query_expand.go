@@ -74,8 +74,19 @@ func (app *BaseApp) expandRecords(records []*Record, expandPath string, fetchFun
 	parts := strings.SplitN(expandPath, ".", 2)
 	var matches []string
 
-	// @todo remove the old syntax support
-	if strings.Contains(parts[0], "(") {
+	// the limit is arbitrary chosen and may change in the future
+	indirectLimit := 1000
+
+	if backRelField, ok := mainCollection.Fields.GetByName(parts[0]).(*BackRelationField); ok {
+		// back relation field (resolved as its equivalent "yourCollection_via_yourRelField" expand)
+		backCollection, _ := getCollectionByModelOrIdentifier(app, backRelField.CollectionId)
+		if backCollection == nil {
+			return fmt.Errorf("couldn't find back-related collection %q", backRelField.CollectionId)
+		}
+		matches = []string{parts[0], backCollection.Name, backRelField.FieldName}
+		indirectLimit = backRelField.ExpandLimit()
+	} else if strings.Contains(parts[0], "(") {
+		// @todo remove the old syntax support
 		matches = indirectExpandRegexOld.FindStringSubmatch(parts[0])
 		if len(matches) == 3 {
 			log.Printf(
@@ -105,7 +116,11 @@ func (app *BaseApp) expandRecords(records []*Record, expandPath string, fetchFun
 		prepErr := func() error {
 			q := app.ConcurrentDB().Select("id").
 				From(indirectRel.Name).
-				Limit(1000) // the limit is arbitrary chosen and may change in the future
+				Limit(int64(indirectLimit))
+
+			if hasSoftDelete(indirectRel) {
+				q.AndWhere(dbx.HashExp{FieldNameDeleted: ""})
+			}
 
 			if indirectRelField.IsMultiple() {
 				q.AndWhere(dbx.Exists(dbx.NewExp(fmt.Sprintf(
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// used to keep track whether the current record delete operation is a soft delete
+const softDeleteKey = internalCustomFieldKeyPrefix + "_softDelete"
+
+// withDeletedRecordsParam is the name of the query param used to mark
+// the record queries that shouldn't exclude the soft deleted records.
+const withDeletedRecordsParam = "__pbWithDeletedRecords"
+
+// WithDeletedRecords is a record query filter func that disables
+// the default exclusion of the soft deleted records.
+//
+// Example:
+//
+//	app.FindRecordById("posts", "RECORD_ID", core.WithDeletedRecords)
+//
+//	query := app.RecordQuery("posts")
+//	core.WithDeletedRecords(query)
+func WithDeletedRecords(q *dbx.SelectQuery) error {
+	q.AndBind(dbx.Params{withDeletedRecordsParam: true})
+
+	return nil
+}
+
+var _ dbx.Expression = (*notDeletedExp)(nil)
+
+// notDeletedExp defines a lazy expression that excludes the soft deleted
+// tableAlias rows unless the query is marked with [WithDeletedRecords].
+//
+// Note that a noop "1=1" fragment is returned instead of an empty string
+// because dbx.And/Or wraps the empty composite expressions as "()".
+type notDeletedExp struct {
+	tableAlias string
+}
+
+// Build converts the expression into a SQL fragment.
+//
+// Implements [dbx.Expression] interface.
+func (e *notDeletedExp) Build(db *dbx.DB, params dbx.Params) string {
+	if _, ok := params[withDeletedRecordsParam]; ok {
+		return "1=1"
+	}
+
+	return db.QuoteColumnName(e.tableAlias+"."+FieldNameDeleted) + " = ''"
+}
+
+// hasSoftDelete checks whether the records of the provided collection could be soft deleted.
+func hasSoftDelete(collection *Collection) bool {
+	return collection != nil && collection.SoftDelete.Enabled && !collection.IsView()
+}
+
+// IsDeleted checks whether the current record is soft deleted,
+// aka. whether it has a nonzero "deleted" field value.
+//
+// Always returns false if the record collection doesn't have soft delete.
+func (m *Record) IsDeleted() bool {
+	return hasSoftDelete(m.collection) && !m.GetDateTime(FieldNameDeleted).IsZero()
+}
+
+// initSoftDelete marks the current record delete operation
+// as soft delete if the record is not already soft deleted.
+//
+// It is expected to be called at the start of the record delete operation.
+func (m *Record) initSoftDelete() {
+	m.SetRaw(softDeleteKey, hasSoftDelete(m.collection) && !m.IsDeleted())
+}
+
+// isSoftDelete checks whether the current record delete
+// operation only marks the record as deleted.
+func (m *Record) isSoftDelete() bool {
+	return hasSoftDelete(m.collection) && m.GetBool(softDeleteKey)
+}
+
+// isSoftDeleteModel checks whether m is a record (or record proxy)
+// model which delete operation is only a soft delete.
+func isSoftDeleteModel(m Model) bool {
+	switch v := m.(type) {
+	case *Record:
+		return v.isSoftDelete()
+	case RecordProxy:
+		return v.ProxyRecord() != nil && v.ProxyRecord().isSoftDelete()
+	}
+
+	return false
+}
+
+// softDeleteRecord marks the event record as deleted by setting its
+// OnDelete autodate field(s) instead of removing the record db row.
+func softDeleteRecord(e *RecordEvent) error {
+	now := types.NowDateTime()
+
+	data := dbx.Params{FieldNameDeleted: now}
+	for _, field := range e.Record.Collection().Fields {
+		if f, ok := field.(*AutodateField); ok && f.OnDelete {
+			data[f.Name] = now
+		}
+	}
+
+	pk := cast.ToString(e.Record.LastSavedPK())
+
+	err := baseLockRetry(func(attempt int) error {
+		_, err := e.App.NonconcurrentDB().Update(
+			e.Record.TableName(),
+			data,
+			dbx.HashExp{FieldNameId: pk},
+		).WithContext(e.Context).Execute()
+
+		return err
+	}, defaultMaxLockRetries)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range data {
+		e.Record.SetRaw(name, value)
+	}
+
+	return nil
+}
+
+func (app *BaseApp) registerSoftDeleteHooks() {
+	// run on every hour to permanently delete the expired soft deleted records
+	app.Cron().Add("__pbSoftDeleteRetention__", "40 * * * *", func() {
+		if err := app.PurgeExpiredDeletedRecords(); err != nil {
+			app.Logger().Warn("Failed to purge the expired soft deleted records", "error", err)
+		}
+	})
+}
+
+// PurgeExpiredDeletedRecords permanently deletes the soft deleted records
+// (including their files) that are older than their collection soft delete retention.
+//
+// Records that fail to be deleted (e.g. because of a hook error) are
+// logged and skipped so that they don't block the purge of the others.
+func (app *BaseApp) PurgeExpiredDeletedRecords() error {
+	collections, err := app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+	if err != nil {
+		return err
+	}
+
+	// the limit is arbitrary chosen and may change in the future
+	batchSize := 500
+
+	for _, collection := range collections {
+		if !hasSoftDelete(collection) || collection.SoftDelete.Retention <= 0 {
+			continue
+		}
+
+		minDate, err := types.ParseDateTime(time.Now().Add(-1 * collection.SoftDelete.RetentionTime()))
+		if err != nil {
+			return err
+		}
+
+		records := make([]*Record, 0, batchSize)
+
+		var lastId string
+
+		for {
+			query := app.RecordQuery(collection).
+				AndWhere(dbx.NewExp("[[" + FieldNameDeleted + "]] != ''")).
+				AndWhere(dbx.NewExp("[["+FieldNameDeleted+"]] < {:minDate}", dbx.Params{"minDate": minDate})).
+				AndWhere(dbx.NewExp("[["+FieldNameId+"]] > {:lastId}", dbx.Params{"lastId": lastId})).
+				OrderBy(FieldNameId + " ASC").
+				Limit(int64(batchSize))
+
+			if err := WithDeletedRecords(query); err != nil {
+				return err
+			}
+
+			if err := query.All(&records); err != nil {
+				return err
+			}
+
+			// the records are already soft deleted so they will be
+			// permanently deleted together with their files
+			for _, record := range records {
+				lastId = record.Id
+
+				if err := app.Delete(record); err != nil {
+					app.Logger().Warn(
+						"Failed to purge expired soft deleted record",
+						"error", err,
+						"recordId", record.Id,
+						"collectionId", collection.Id,
+					)
+				}
+			}
+
+			if len(records) < batchSize {
+				break // no more items
+			}
+
+			records = records[:0] // keep allocated memory
+		}
+	}
+
+	return nil
+}
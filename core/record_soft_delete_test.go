@@ -0,0 +1,426 @@
+package core_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func createSoftDeleteCollection(t *testing.T, app core.App, retention int64) *core.Collection {
+	collection := core.NewBaseCollection("test_soft_delete")
+	collection.SoftDelete.Enabled = true
+	collection.SoftDelete.Retention = retention
+	collection.Fields.Add(&core.TextField{Name: "title"})
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func TestSoftDeleteValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name          string
+		collection    func() (*core.Collection, error)
+		expectedError string
+	}{
+		{
+			"system collection",
+			func() (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+				if err != nil {
+					return nil, err
+				}
+				c.SoftDelete.Enabled = true
+				return c, nil
+			},
+			"validation_soft_delete_system_collection",
+		},
+		{
+			"negative retention",
+			func() (*core.Collection, error) {
+				c := core.NewBaseCollection("new_a")
+				c.SoftDelete.Retention = -1
+				return c, nil
+			},
+			"validation_min_greater_equal_than_required",
+		},
+		{
+			"valid",
+			func() (*core.Collection, error) {
+				c := core.NewBaseCollection("new_b")
+				c.SoftDelete.Enabled = true
+				c.SoftDelete.Retention = 3600
+				return c, nil
+			},
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection, err := s.collection()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = app.Validate(collection)
+
+			if s.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(fmt.Sprintf("%#v", err), s.expectedError) {
+				t.Fatalf("Expected error %q, got %v", s.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteDeletedField(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSoftDeleteCollection(t, app, 0)
+
+	field, ok := collection.Fields.GetByName(core.FieldNameDeleted).(*core.AutodateField)
+	if !ok {
+		t.Fatalf("Expected %q autodate field", core.FieldNameDeleted)
+	}
+
+	if !field.System || !field.OnDelete || field.OnCreate || field.OnUpdate {
+		t.Fatalf("Unexpected %q field options: %#v", core.FieldNameDeleted, field)
+	}
+}
+
+func TestSoftDeleteRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSoftDeleteCollection(t, app, 0)
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record.IsDeleted() {
+		t.Fatal("Expected the new record to not be deleted")
+	}
+
+	// soft delete
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if !record.IsDeleted() {
+		t.Fatal("Expected the record to be marked as deleted")
+	}
+
+	if _, err := app.FindRecordById(collection, record.Id); err == nil {
+		t.Fatal("Expected the soft deleted record to be excluded by default")
+	}
+
+	total, err := app.CountRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected 0 counted records, got %d", total)
+	}
+
+	deleted, err := app.FindRecordById(collection, record.Id, core.WithDeletedRecords)
+	if err != nil {
+		t.Fatalf("Expected the soft deleted record to be found with WithDeletedRecords, got %v", err)
+	}
+	if !deleted.IsDeleted() {
+		t.Fatal("Expected the found record to be marked as deleted")
+	}
+
+	// restore
+	deleted.SetRaw(core.FieldNameDeleted, types.DateTime{})
+	if err := app.Save(deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatalf("Expected the restored record to be found, got %v", err)
+	}
+	if restored.IsDeleted() || restored.GetString("title") != "a" {
+		t.Fatalf("Unexpected restored record %v", restored)
+	}
+
+	// soft delete again and then permanently delete
+	if err := app.Delete(restored); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(restored); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordById(collection, record.Id, core.WithDeletedRecords); err == nil {
+		t.Fatal("Expected the record to be permanently deleted")
+	}
+}
+
+func TestSoftDeleteKeepsFiles(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.SoftDelete.Enabled = true
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.FindRecordById(collection, "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	filesDir := record.BaseFilesPath()
+
+	files, err := fsys.List(filesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("Expected the record to have files")
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err = fsys.List(filesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("Expected the record files to be kept after soft delete")
+	}
+}
+
+func TestPurgeExpiredDeletedRecords(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSoftDeleteCollection(t, app, 3600)
+
+	ids := map[string]time.Duration{
+		"old":  -2 * time.Hour,
+		"old2": -3 * time.Hour,
+		"fail": -2 * time.Hour,
+		"new":  -10 * time.Minute,
+	}
+
+	for title, diff := range ids {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Delete(record); err != nil {
+			t.Fatal(err)
+		}
+
+		deleted, _ := types.ParseDateTime(time.Now().Add(diff))
+
+		_, err := app.DB().Update(
+			collection.Name,
+			dbx.Params{core.FieldNameDeleted: deleted},
+			dbx.HashExp{"id": record.Id},
+		).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// non-deleted record
+	active := core.NewRecord(collection)
+	active.Set("title", "active")
+	if err := app.Save(active); err != nil {
+		t.Fatal(err)
+	}
+
+	// the failed record deletes shouldn't stop the purge of the others
+	app.OnRecordDelete(collection.Name).BindFunc(func(e *core.RecordEvent) error {
+		if e.Record.GetString("title") == "fail" {
+			return errors.New("test delete error")
+		}
+		return e.Next()
+	})
+
+	if err := app.PurgeExpiredDeletedRecords(); err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	err := app.RecordQuery(collection).
+		Select("title").
+		OrderBy("title").
+		Column(&titles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(titles, ",") != "active" {
+		t.Fatalf("Expected only the active record to be listed, got %v", titles)
+	}
+
+	query := app.RecordQuery(collection).Select("title").OrderBy("title")
+	if err := core.WithDeletedRecords(query); err != nil {
+		t.Fatal(err)
+	}
+	titles = nil
+	if err := query.Column(&titles); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(titles, ",") != "active,fail,new" {
+		t.Fatalf("Expected the old deleted record to be purged, got %v", titles)
+	}
+}
+
+func TestSoftDeleteUniqueIndex(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSoftDeleteCollection(t, app, 0)
+	collection.AddIndex("idx_test_soft_delete_title", true, "title", "")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	createRecord := func(title string) (*core.Record, error) {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		return record, app.Save(record)
+	}
+
+	assertNotUnique := func(err error) {
+		t.Helper()
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Fatalf("Expected validation.Errors, got %v", err)
+		}
+
+		titleErr, ok := errs["title"].(validation.Error)
+		if !ok || titleErr.Code() != "validation_not_unique" {
+			t.Fatalf("Expected title validation_not_unique error, got %v", errs)
+		}
+	}
+
+	deleted, err := createRecord("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	// the soft deleted record values are still reserved
+	_, err = createRecord("a")
+	assertNotUnique(err)
+
+	// limit the unique index only to the active records
+	collection.RemoveIndex("idx_test_soft_delete_title")
+	collection.AddIndex("idx_test_soft_delete_title", true, "title", "`deleted` = ''")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := createRecord("a"); err != nil {
+		t.Fatalf("Expected the soft deleted record value to be reusable, got %v", err)
+	}
+
+	// restore with already taken value
+	deleted.SetRaw(core.FieldNameDeleted, types.DateTime{})
+	assertNotUnique(app.Save(deleted))
+}
+
+func TestSoftDeleteRelationFilter(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	target := createSoftDeleteCollection(t, app, 0)
+
+	owner := core.NewBaseCollection("test_soft_delete_owner")
+	owner.Fields.Add(&core.RelationField{Name: "rel", CollectionId: target.Id, MaxSelect: 1})
+	if err := app.Save(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	rel := core.NewRecord(target)
+	rel.Set("title", "a")
+	if err := app.Save(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(owner)
+	record.Set("rel", rel.Id)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	assertTotal := func(expected int) {
+		t.Helper()
+
+		records, err := app.FindRecordsByFilter(owner, "rel.title = 'a'", "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != expected {
+			t.Fatalf("Expected %d records, got %d", expected, len(records))
+		}
+	}
+
+	assertTotal(1)
+
+	if err := app.Delete(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	assertTotal(0)
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// RequestTenant returns the tenant value of the requestInfo auth record
+// for the provided collection.
+//
+// The second returned value reports whether the collection records
+// should be scoped to the returned tenant, aka. whether the collection
+// has [Collection.TenantField] and the request is not from a superuser
+// (or from a guest with enabled [Collection.TenantGuestAccess]).
+//
+// An empty tenant with true scoped result (eg. guest request or auth record
+// whose collection doesn't have the tenant field) means that the
+// request is not allowed to access any of the collection records.
+//
+// Note that nil requestInfo is treated as internal system call and it is never scoped.
+func RequestTenant(collection *Collection, requestInfo *RequestInfo) (string, bool) {
+	if collection == nil ||
+		collection.TenantField == "" ||
+		collection.IsView() ||
+		requestInfo == nil ||
+		requestInfo.HasSuperuserAuth() {
+		return "", false
+	}
+
+	if requestInfo.Auth == nil {
+		return "", !collection.TenantGuestAccess
+	}
+
+	return requestInfo.Auth.GetString(collection.TenantField), true
+}
+
+// TenantScope returns a query expression that restricts the collection
+// records to the tenant of the requestInfo auth record (see [RequestTenant]).
+//
+// Returns nil if the collection records shouldn't be tenant scoped.
+//
+// Example:
+//
+//	requestInfo, _ := e.RequestInfo()
+//	records, err := app.FindAllRecords(collection, core.TenantScope(collection, requestInfo))
+func TenantScope(collection *Collection, requestInfo *RequestInfo) dbx.Expression {
+	if collection == nil {
+		return nil
+	}
+
+	return tenantScopeExp(collection, requestInfo, collection.Name)
+}
+
+// tenantScopeExp is similar to [TenantScope] but allows specifying a custom table alias.
+func tenantScopeExp(collection *Collection, requestInfo *RequestInfo, tableAlias string) dbx.Expression {
+	tenant, ok := RequestTenant(collection, requestInfo)
+	if !ok {
+		return nil
+	}
+
+	// no tenant -> no access
+	if tenant == "" {
+		return dbx.NewExp("1=0")
+	}
+
+	return dbx.HashExp{tableAlias + "." + collection.TenantField: tenant}
+}
@@ -0,0 +1,309 @@
+package core_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// createTenantCollections adds a "tenant" text field to the users
+// collection and creates a new tenant scoped "test_tenants" collection
+// with 3 records - one for tenant "t1", one for tenant "t2" and one without tenant.
+func createTenantCollections(t *testing.T, app core.App) (*core.Collection, *core.Record) {
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Fields.Add(&core.TextField{Name: "tenant"})
+	if err := app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail(users, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Set("tenant", "t1")
+	if err := app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	collection := core.NewBaseCollection("test_tenants")
+	collection.TenantField = "tenant"
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "tenant"},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tenant := range []string{"t1", "t2", ""} {
+		record := core.NewRecord(collection)
+		record.Set("title", "title_"+tenant)
+		record.Set("tenant", tenant)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return collection, user
+}
+
+func TestTenantFieldValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		tenantField string
+		expectError bool
+	}{
+		{"empty", "", false},
+		{"missing field", "missing", true},
+		{"id field", "id", true},
+		{"non-text field", "number", true},
+		{"multiple relation field", "rel_multi", true},
+		{"single relation field", "rel_single", false},
+		{"text field", "text", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection := core.NewBaseCollection("new_test")
+			collection.TenantField = s.tenantField
+			collection.Fields.Add(
+				&core.TextField{Name: "text"},
+				&core.NumberField{Name: "number"},
+				&core.RelationField{Name: "rel_single", CollectionId: "_pb_users_auth_", MaxSelect: 1},
+				&core.RelationField{Name: "rel_multi", CollectionId: "_pb_users_auth_", MaxSelect: 2},
+			)
+
+			err := app.Validate(collection)
+
+			hasErr := err != nil && strings.Contains(fmt.Sprintf("%#v", err), "validation_invalid_tenant_field")
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestRequestTenant(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, user := createTenantCollections(t, app)
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherUser, err := app.FindAuthRecordByEmail("clients", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guestAccessCollection := core.NewBaseCollection("guest_access_test")
+	guestAccessCollection.TenantField = "tenant"
+	guestAccessCollection.TenantGuestAccess = true
+
+	scenarios := []struct {
+		name           string
+		collection     *core.Collection
+		requestInfo    *core.RequestInfo
+		expectedTenant string
+		expectedScoped bool
+	}{
+		{"nil request info", collection, nil, "", false},
+		{"guest", collection, &core.RequestInfo{}, "", true},
+		{"guest (with guest access)", guestAccessCollection, &core.RequestInfo{}, "", false},
+		{"superuser", collection, &core.RequestInfo{Auth: superuser}, "", false},
+		{"non-tenant collection", demo2, &core.RequestInfo{Auth: user}, "", false},
+		{"auth record without tenant field", collection, &core.RequestInfo{Auth: otherUser}, "", true},
+		{"auth record with tenant field", collection, &core.RequestInfo{Auth: user}, "t1", true},
+		{"auth record with tenant field (with guest access)", guestAccessCollection, &core.RequestInfo{Auth: user}, "t1", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tenant, scoped := core.RequestTenant(s.collection, s.requestInfo)
+
+			if tenant != s.expectedTenant {
+				t.Fatalf("Expected tenant %q, got %q", s.expectedTenant, tenant)
+			}
+
+			if scoped != s.expectedScoped {
+				t.Fatalf("Expected scoped %v, got %v", s.expectedScoped, scoped)
+			}
+
+			exp := core.TenantScope(s.collection, s.requestInfo)
+			if (exp != nil) != s.expectedScoped {
+				t.Fatalf("Expected TenantScope expression to be nil %v, got %v", !s.expectedScoped, exp)
+			}
+		})
+	}
+}
+
+func TestTenantCountRequestRecords(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, user := createTenantCollections(t, app)
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherUser, err := app.FindAuthRecordByEmail("clients", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		guestAccess bool
+		requestInfo *core.RequestInfo
+		expected    int64
+	}{
+		{"nil request info", false, nil, 3},
+		{"superuser", false, &core.RequestInfo{Auth: superuser}, 3},
+		{"guest", false, &core.RequestInfo{}, 0},
+		{"guest (with guest access)", true, &core.RequestInfo{}, 3},
+		{"auth record without tenant field", false, &core.RequestInfo{Auth: otherUser}, 0},
+		{"auth record with tenant", false, &core.RequestInfo{Auth: user}, 1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection.TenantGuestAccess = s.guestAccess
+
+			total, err := app.CountRequestRecords(s.requestInfo, collection)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if total != s.expected {
+				t.Fatalf("Expected %d records, got %d", s.expected, total)
+			}
+		})
+	}
+}
+
+func TestTenantCanAccessRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, user := createTenantCollections(t, app)
+
+	records, err := app.FindAllRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		requestInfo *core.RequestInfo
+		rule        *string
+		expected    map[string]bool // tenant => access
+	}{
+		{
+			&core.RequestInfo{},
+			types.Pointer(""),
+			map[string]bool{"t1": false, "t2": false, "": false},
+		},
+		{
+			&core.RequestInfo{Auth: user},
+			types.Pointer(""),
+			map[string]bool{"t1": true, "t2": false, "": false},
+		},
+		{
+			&core.RequestInfo{Auth: user},
+			types.Pointer("title != ''"),
+			map[string]bool{"t1": true, "t2": false, "": false},
+		},
+		{
+			&core.RequestInfo{Auth: user},
+			types.Pointer("title = 'missing'"),
+			map[string]bool{"t1": false, "t2": false, "": false},
+		},
+	}
+
+	for i, s := range scenarios {
+		for _, record := range records {
+			tenant := record.GetString("tenant")
+
+			t.Run(fmt.Sprintf("%d_%s", i, tenant), func(t *testing.T) {
+				result, err := app.CanAccessRecord(record, s.requestInfo, s.rule)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if result != s.expected[tenant] {
+					t.Fatalf("Expected %v, got %v", s.expected[tenant], result)
+				}
+			})
+		}
+	}
+}
+
+func TestTenantRecordFieldResolverJoins(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, user := createTenantCollections(t, app)
+
+	scenarios := []struct {
+		filter   string
+		expected int
+	}{
+		{"@collection.test_tenants:other.title ?= 'title_t1'", 1},
+		// the base and the @collection joined tables should include only the requester tenant records
+		{"@collection.test_tenants:other.title ?= 'title_t2'", 0},
+		{"@collection.test_tenants:other.title ?= 'title_'", 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter, func(t *testing.T) {
+			resolver := core.NewRecordFieldResolver(app, collection, &core.RequestInfo{Auth: user}, true)
+
+			expr, err := search.FilterData(s.filter).BuildExpr(resolver)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			query := app.RecordQuery(collection).Select("count(*)").AndWhere(expr)
+			resolver.UpdateQuery(query)
+
+			var total int
+			if err := query.Row(&total); err != nil {
+				t.Fatal(err)
+			}
+
+			if total != s.expected {
+				t.Fatalf("Expected %d records, got %d", s.expected, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const CollectionNameRecordVersions = "_recordVersions"
+
+// Record version actions.
+const (
+	RecordVersionActionUpdate = "update"
+	RecordVersionActionDelete = "delete"
+)
+
+var (
+	_ Model        = (*RecordVersion)(nil)
+	_ PreValidator = (*RecordVersion)(nil)
+	_ RecordProxy  = (*RecordVersion)(nil)
+)
+
+// RecordVersion defines a Record proxy for working with the recordVersions collection.
+//
+// Each RecordVersion model stores the previous values of the changed
+// fields of a single record update (or all field values in case of delete)
+// of a collection with enabled versioning.
+type RecordVersion struct {
+	*Record
+}
+
+// NewRecordVersion instantiates and returns a new blank *RecordVersion model.
+//
+// Example usage:
+//
+//	version := core.NewRecordVersion(app)
+//	version.SetCollectionRef(record.Collection().Id)
+//	version.SetRecordRef(record.Id)
+//	version.SetVersion(1)
+//	version.SetAction(core.RecordVersionActionUpdate)
+//	version.SetData(map[string]any{"title": "old title"})
+//	app.Save(version)
+func NewRecordVersion(app App) *RecordVersion {
+	m := &RecordVersion{}
+
+	c, err := app.FindCachedCollectionByNameOrId(CollectionNameRecordVersions)
+	if err != nil {
+		// this is just to make tests easier since recordVersions is a system collection and it is expected to be always accessible
+		// (note: the loaded record is further checked on RecordVersion.PreValidate())
+		c = NewBaseCollection("@__invalid__")
+	}
+
+	m.Record = NewRecord(c)
+
+	return m
+}
+
+// PreValidate implements the [PreValidator] interface and checks
+// whether the proxy is properly loaded.
+func (m *RecordVersion) PreValidate(ctx context.Context, app App) error {
+	if m.Record == nil || m.Record.Collection().Name != CollectionNameRecordVersions {
+		return errors.New("missing or invalid RecordVersion ProxyRecord")
+	}
+
+	return nil
+}
+
+// ProxyRecord returns the proxied Record model.
+func (m *RecordVersion) ProxyRecord() *Record {
+	return m.Record
+}
+
+// SetProxyRecord loads the specified record model into the current proxy.
+func (m *RecordVersion) SetProxyRecord(record *Record) {
+	m.Record = record
+}
+
+// CollectionRef returns the "collectionRef" field value.
+func (m *RecordVersion) CollectionRef() string {
+	return m.GetString("collectionRef")
+}
+
+// SetCollectionRef updates the "collectionRef" record field value.
+func (m *RecordVersion) SetCollectionRef(collectionId string) {
+	m.Set("collectionRef", collectionId)
+}
+
+// RecordRef returns the "recordRef" record field value.
+func (m *RecordVersion) RecordRef() string {
+	return m.GetString("recordRef")
+}
+
+// SetRecordRef updates the "recordRef" record field value.
+func (m *RecordVersion) SetRecordRef(recordId string) {
+	m.Set("recordRef", recordId)
+}
+
+// Version returns the "version" record field value.
+func (m *RecordVersion) Version() int {
+	return m.GetInt("version")
+}
+
+// SetVersion updates the "version" record field value.
+func (m *RecordVersion) SetVersion(version int) {
+	m.Set("version", version)
+}
+
+// Action returns the "action" record field value.
+func (m *RecordVersion) Action() string {
+	return m.GetString("action")
+}
+
+// SetAction updates the "action" record field value.
+func (m *RecordVersion) SetAction(action string) {
+	m.Set("action", action)
+}
+
+// Data returns the "data" record field value unmarshalled as map
+// (aka. the previous values of the changed record fields).
+//
+// Note that the encrypted record fields values are returned as they are stored
+// (see [RecordVersionState] for a decrypted version of the record state).
+func (m *RecordVersion) Data() map[string]any {
+	result := map[string]any{}
+
+	_ = m.UnmarshalJSONField("data", &result)
+
+	return result
+}
+
+// SetData updates the "data" record field value.
+func (m *RecordVersion) SetData(data map[string]any) {
+	m.Set("data", data)
+}
+
+// ActorCollectionRef returns the "actorCollectionRef" record field value
+// (aka. the collection id of the auth record that performed the change).
+func (m *RecordVersion) ActorCollectionRef() string {
+	return m.GetString("actorCollectionRef")
+}
+
+// SetActorCollectionRef updates the "actorCollectionRef" record field value.
+func (m *RecordVersion) SetActorCollectionRef(collectionId string) {
+	m.Set("actorCollectionRef", collectionId)
+}
+
+// ActorRef returns the "actorRef" record field value
+// (aka. the id of the auth record that performed the change).
+func (m *RecordVersion) ActorRef() string {
+	return m.GetString("actorRef")
+}
+
+// SetActorRef updates the "actorRef" record field value.
+func (m *RecordVersion) SetActorRef(recordId string) {
+	m.Set("actorRef", recordId)
+}
+
+// Created returns the "created" record field value.
+func (m *RecordVersion) Created() types.DateTime {
+	return m.GetDateTime("created")
+}
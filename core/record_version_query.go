@@ -0,0 +1,59 @@
+package core
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// FindAllRecordVersions returns all RecordVersion models
+// linked to the provided record (in DESC version order).
+func (app *BaseApp) FindAllRecordVersions(record *Record) ([]*RecordVersion, error) {
+	result := []*RecordVersion{}
+
+	err := app.RecordQuery(CollectionNameRecordVersions).
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		OrderBy("version DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordVersion returns a single RecordVersion model
+// linked to the provided record by its version number.
+func (app *BaseApp) FindRecordVersion(record *Record, version int) (*RecordVersion, error) {
+	result := &RecordVersion{}
+
+	err := app.RecordQuery(CollectionNameRecordVersions).
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+			"version":       version,
+		}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteAllRecordVersionsByCollection deletes all RecordVersion models
+// associated with the provided collection.
+//
+// For better performance the delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteAllRecordVersionsByCollection(collection *Collection) error {
+	_, err := app.NonconcurrentDB().Delete(CollectionNameRecordVersions, dbx.HashExp{
+		"collectionRef": collection.Id,
+	}).Execute()
+
+	return err
+}
@@ -0,0 +1,327 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/spf13/cast"
+)
+
+type actorContextKey struct{}
+
+// WithActor returns a copy of ctx with the provided auth record attached
+// as actor of the model operations performed with the context
+// (eg. it is stored as author of the record versions).
+//
+// Returns ctx as it is if auth is nil.
+func WithActor(ctx context.Context, auth *Record) context.Context {
+	if auth == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, actorContextKey{}, auth)
+}
+
+// ActorFromContext returns the auth record attached to ctx with [WithActor] (if any).
+func ActorFromContext(ctx context.Context) *Record {
+	if ctx == nil {
+		return nil
+	}
+
+	auth, _ := ctx.Value(actorContextKey{}).(*Record)
+
+	return auth
+}
+
+func (app *BaseApp) registerRecordVersionHooks() {
+	app.OnRecordValidate(CollectionNameRecordVersions).Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			// note: the record existence is not checked because the
+			// versions are expected to remain after the record deletion
+			err := validation.Validate(
+				e.Record.GetString("collectionRef"),
+				validation.Required,
+				validation.By(validateCollectionId(e.App, CollectionTypeBase, CollectionTypeAuth)),
+			)
+			if err != nil {
+				return validation.Errors{"collectionRef": err}
+			}
+
+			return e.Next()
+		},
+		Priority: 99,
+	})
+
+	// delete the collection versions on collection delete
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if e.Collection.Name == CollectionNameRecordVersions || e.Collection.IsView() {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return txApp.DeleteAllRecordVersionsByCollection(e.Collection)
+			})
+			e.App = originalApp
+
+			return txErr
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			return recordVersionExecute(e, RecordVersionActionUpdate)
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			return recordVersionExecute(e, RecordVersionActionDelete)
+		},
+		Priority: 99,
+	})
+}
+
+// recordVersionExecute persists the e.Record change and stores the previous
+// values of the changed fields (or all values on delete) as a new record version.
+//
+// The previous state is loaded from the db (and not from e.Record.Original())
+// because the in-memory original state may not be up-to-date (eg. after create).
+func recordVersionExecute(e *RecordEvent, action string) error {
+	collection := e.Record.Collection()
+	if !collection.Versioning || collection.IsView() || collection.Name == CollectionNameRecordVersions {
+		return e.Next()
+	}
+
+	originalApp := e.App
+	txErr := e.App.RunInTransaction(func(txApp App) error {
+		e.App = txApp
+
+		previous, err := txApp.FindRecordById(collection, cast.ToString(e.Record.LastSavedPK()), WithDeletedRecords)
+		if err != nil {
+			return fmt.Errorf("failed to load the previous record state: %w", err)
+		}
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		return saveRecordVersion(txApp, e.Context, previous, e.Record, action)
+	})
+	e.App = originalApp
+
+	return txErr
+}
+
+// saveRecordVersion creates a new version of record from its previous state.
+//
+// Nothing is stored if none of the tracked fields was changed.
+func saveRecordVersion(app App, ctx context.Context, previous *Record, record *Record, action string) error {
+	collection := record.Collection()
+
+	data := map[string]any{}
+	for _, field := range versionableFields(collection) {
+		name := field.GetName()
+
+		oldValue := previous.GetRaw(name)
+		if action == RecordVersionActionUpdate && areValuesEqual(oldValue, record.GetRaw(name)) {
+			continue
+		}
+
+		// store the encrypted fields values as they are in the db
+		if isEncryptedField(field) {
+			v, err := exportEncryptedFieldValue(app, previous, field, oldValue)
+			if err != nil {
+				return fmt.Errorf("failed to export the %q field value: %w", name, err)
+			}
+			oldValue = v
+		}
+
+		data[name] = oldValue
+	}
+
+	if len(data) == 0 {
+		return nil // no tracked changes
+	}
+
+	next, err := nextRecordVersion(app, record)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the next record version: %w", err)
+	}
+
+	version := NewRecordVersion(app)
+	version.SetCollectionRef(collection.Id)
+	version.SetRecordRef(record.Id)
+	version.SetVersion(next)
+	version.SetAction(action)
+	version.SetData(data)
+
+	if actor := ActorFromContext(ctx); actor != nil {
+		version.SetActorCollectionRef(actor.Collection().Id)
+		version.SetActorRef(actor.Id)
+	}
+
+	return app.SaveWithContext(ctx, version)
+}
+
+// nextRecordVersion returns the next version number of the provided record.
+func nextRecordVersion(app App, record *Record) (int, error) {
+	var last int
+
+	err := app.DB().
+		Select("COALESCE(MAX([[version]]), 0)").
+		From(CollectionNameRecordVersions).
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		Row(&last)
+
+	return last + 1, err
+}
+
+// versionableFields returns the collection fields which values are tracked in the record versions.
+//
+// Password, file, autodate, generated and virtual fields (as well as the auth collection "tokenKey")
+// are not tracked.
+func versionableFields(collection *Collection) []Field {
+	result := make([]Field, 0, len(collection.Fields))
+
+	for _, field := range collection.Fields {
+		if isVersionableField(collection, field) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+func isVersionableField(collection *Collection, field Field) bool {
+	if g, ok := field.(GeneratedValuer); ok && g.IsGenerated() {
+		return false
+	}
+
+	if v, ok := field.(VirtualValuer); ok && v.IsVirtual() {
+		return false
+	}
+
+	switch field.Type() {
+	case FieldTypePassword, FieldTypeFile, FieldTypeAutodate:
+		return false
+	}
+
+	if collection.IsAuth() && field.GetName() == FieldNameTokenKey {
+		return false
+	}
+
+	return field.GetName() != FieldNameId
+}
+
+// FieldsData returns the version data values normalized with
+// the provided collection fields (encrypted values are decrypted).
+//
+// Removed or no longer tracked collection fields are skipped.
+func (m *RecordVersion) FieldsData(app App, collection *Collection) (map[string]any, error) {
+	data := m.Data()
+
+	// dummy record to satisfy the PrepareValue signature
+	record := NewRecord(collection)
+
+	result := make(map[string]any, len(data))
+
+	for name, value := range data {
+		field := collection.Fields.GetByName(name)
+		if field == nil || !isVersionableField(collection, field) {
+			continue
+		}
+
+		if str, ok := value.(string); ok && isEncryptedField(field) {
+			plain, err := importEncryptedFieldValue(app, collection.Id, m.RecordRef(), field, str)
+			if err != nil {
+				app.Logger().Warn(
+					"Failed to decrypt record version field value",
+					"error", err,
+					"fieldName", name,
+					"recordId", m.RecordRef(),
+					"collectionId", collection.Id,
+				)
+				value = nil // never expose the encrypted value as plain one
+			} else {
+				value = plain
+			}
+		}
+
+		v, err := field.PrepareValue(record, value)
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = v
+	}
+
+	return result, nil
+}
+
+// RecordVersionState returns the tracked field values of the provided
+// record as they were right before the specified version change.
+//
+// If version is <= 0, the current record state is returned.
+func RecordVersionState(app App, record *Record, version int) (map[string]any, error) {
+	collection := record.Collection()
+	fields := versionableFields(collection)
+
+	state := make(map[string]any, len(fields))
+	for _, field := range fields {
+		state[field.GetName()] = record.GetRaw(field.GetName())
+	}
+
+	if version <= 0 {
+		return state, nil
+	}
+
+	versions, err := app.FindAllRecordVersions(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var found bool
+
+	// apply the previous values from the latest to the requested version
+	for _, v := range versions {
+		if v.Version() < version {
+			break
+		}
+
+		if v.Version() == version {
+			found = true
+		}
+
+		data, err := v.FieldsData(app, collection)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load record version %d data: %w", v.Version(), err)
+		}
+
+		for name, value := range data {
+			state[name] = value
+		}
+	}
+
+	if !found {
+		return nil, errors.New("missing record version")
+	}
+
+	return state, nil
+}
@@ -0,0 +1,238 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func createVersionedCollection(t *testing.T, app core.App, versioning bool) *core.Collection {
+	collection := core.NewBaseCollection("test_versions")
+	collection.Versioning = versioning
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.NumberField{Name: "count"},
+		&core.TextField{Name: "secret", Hidden: true},
+		&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+	)
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func TestRecordVersioningValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	versions, err := app.FindCollectionByNameOrId(core.CollectionNameRecordVersions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions.Versioning = true
+
+	err = app.Validate(versions)
+	if err == nil || !strings.Contains(err.Error(), "versioning") {
+		t.Fatalf("Expected versioning validation error, got %v", err)
+	}
+}
+
+func TestRecordVersionsOnUpdateAndDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createVersionedCollection(t, app, true)
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	record.Set("count", 1)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// create doesn't have a previous state
+	assertRecordVersionsCount(t, app, record, 0)
+
+	// save without changes
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	assertRecordVersionsCount(t, app, record, 0)
+
+	record.Set("title", "b")
+	if err := app.SaveWithContext(core.WithActor(context.Background(), user), record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "c")
+	record.Set("count", 2)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	versions := assertRecordVersionsCount(t, app, record, 2)
+
+	// sorted by version DESC
+	if v := versions[1]; v.Version() != 1 ||
+		v.Action() != core.RecordVersionActionUpdate ||
+		v.ActorCollectionRef() != user.Collection().Id ||
+		v.ActorRef() != user.Id ||
+		len(v.Data()) != 1 ||
+		v.Data()["title"] != "a" {
+		t.Fatalf("Unexpected version 1: %v %v %v %v %v", v.Version(), v.Action(), v.ActorCollectionRef(), v.ActorRef(), v.Data())
+	}
+
+	if v := versions[0]; v.Version() != 2 ||
+		v.ActorRef() != "" ||
+		len(v.Data()) != 2 ||
+		v.Data()["title"] != "b" {
+		t.Fatalf("Unexpected version 2: %v %v %v", v.Version(), v.ActorRef(), v.Data())
+	}
+
+	// state
+	state, err := core.RecordVersionState(app, record, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["title"] != "a" || state["count"] != 1.0 {
+		t.Fatalf("Expected version 1 state title=a and count=1, got %v", state)
+	}
+	if _, ok := state["updated"]; ok {
+		t.Fatalf("Expected the autodate field to not be tracked, got %v", state)
+	}
+
+	state, err = core.RecordVersionState(app, record, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["title"] != "b" || state["count"] != 1.0 {
+		t.Fatalf("Expected version 2 state title=b and count=1, got %v", state)
+	}
+
+	state, err = core.RecordVersionState(app, record, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["title"] != "c" || state["count"] != 2.0 {
+		t.Fatalf("Expected current state title=c and count=2, got %v", state)
+	}
+
+	if _, err := core.RecordVersionState(app, record, 3); err == nil {
+		t.Fatal("Expected missing version error")
+	}
+
+	// delete
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	versions = assertRecordVersionsCount(t, app, record, 3)
+	if v := versions[0]; v.Version() != 3 ||
+		v.Action() != core.RecordVersionActionDelete ||
+		v.Data()["title"] != "c" ||
+		v.Data()["secret"] != "" {
+		t.Fatalf("Unexpected delete version: %v %v %v", v.Version(), v.Action(), v.Data())
+	}
+
+	// collection delete
+	if err := app.Delete(collection); err != nil {
+		t.Fatal(err)
+	}
+	assertRecordVersionsCount(t, app, record, 0)
+}
+
+func TestRecordVersionsDisabled(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createVersionedCollection(t, app, false)
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "b")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRecordVersionsCount(t, app, record, 0)
+}
+
+func TestRecordVersionsEncryptedField(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	t.Setenv(app.EncryptionEnv(), "abcdabcdabcdabcdabcdabcdabcdabcd")
+
+	collection := core.NewBaseCollection("test_versions")
+	collection.Versioning = true
+	collection.Fields.Add(&core.TextField{Name: "secret", Encrypted: true})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("secret", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("secret", "b")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	versions := assertRecordVersionsCount(t, app, record, 1)
+
+	raw, _ := versions[0].Data()["secret"].(string)
+	if !strings.HasPrefix(raw, "pbenc1:") {
+		t.Fatalf("Expected the version value to be stored encrypted, got %q", raw)
+	}
+
+	data, err := versions[0].FieldsData(app, collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["secret"] != "a" {
+		t.Fatalf("Expected decrypted value %q, got %v", "a", data["secret"])
+	}
+}
+
+func assertRecordVersionsCount(t *testing.T, app core.App, record *core.Record, expected int) []*core.RecordVersion {
+	t.Helper()
+
+	versions, err := app.FindAllRecordVersions(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(versions) != expected {
+		t.Fatalf("Expected %d record versions, got %d", expected, len(versions))
+	}
+
+	return versions
+}
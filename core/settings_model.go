@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"regexp"
 	"slices"
@@ -21,6 +22,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/mailer"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/webpush"
 )
 
 const (
@@ -128,6 +130,14 @@ type settings struct {
 	TrustedProxy TrustedProxyConfig `form:"trustedProxy" json:"trustedProxy"`
 	Batch        BatchConfig        `form:"batch" json:"batch"`
 	Logs         LogsConfig         `form:"logs" json:"logs"`
+	SCIM         SCIMConfig         `form:"scim" json:"scim"`
+
+	BreachedPasswords BreachedPasswordsConfig `form:"breachedPasswords" json:"breachedPasswords"`
+	MailQueue         MailQueueConfig         `form:"mailQueue" json:"mailQueue"`
+	MailDriver        MailDriverConfig        `form:"mailDriver" json:"mailDriver"`
+	InboundMail       InboundMailConfig       `form:"inboundMail" json:"inboundMail"`
+	WebPush           WebPushConfig           `form:"webPush" json:"webPush"`
+	Jobs              JobsConfig              `form:"jobs" json:"jobs"`
 }
 
 // Settings defines the PocketBase app settings.
@@ -161,6 +171,17 @@ func newDefaultSettings() *Settings {
 				Password: "",
 				TLS:      false,
 			},
+			MailQueue: MailQueueConfig{
+				Enabled:     false,
+				MaxAttempts: 5,
+				RetryDelay:  30,
+				LogsMaxDays: 7,
+			},
+			Jobs: JobsConfig{
+				Concurrency: 2,
+				MaxAttempts: 3,
+				RetryDelay:  10,
+			},
 			Backups: BackupsConfig{
 				CronMaxKeep: 3,
 			},
@@ -287,6 +308,13 @@ func (s *Settings) PostValidate(ctx context.Context, app App) error {
 		validation.Field(&s.Batch),
 		validation.Field(&s.RateLimits),
 		validation.Field(&s.TrustedProxy),
+		validation.Field(&s.SCIM, validation.By(checkSCIMCollections(app))),
+		validation.Field(&s.BreachedPasswords),
+		validation.Field(&s.MailQueue),
+		validation.Field(&s.MailDriver),
+		validation.Field(&s.InboundMail, validation.By(checkInboundMailCollection(app))),
+		validation.Field(&s.WebPush),
+		validation.Field(&s.Jobs),
 	)
 }
 
@@ -331,6 +359,10 @@ func (s *Settings) MarshalJSON() ([]byte, error) {
 		&copy.SMTP.Password,
 		&copy.S3.Secret,
 		&copy.Backups.S3.Secret,
+		&copy.SCIM.TokenHash,
+		&copy.MailDriver.Secret,
+		&copy.InboundMail.Secret,
+		&copy.WebPush.PrivateKey,
 	}
 
 	// mask all sensitive fields
@@ -394,6 +426,203 @@ func (c SMTPConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+const (
+	MailDriverSES      = "ses"
+	MailDriverMailgun  = "mailgun"
+	MailDriverPostmark = "postmark"
+)
+
+// MailDriverConfig defines the settings of the HTTP-API mail drivers
+// that could be used as alternative to the SMTP mail client.
+type MailDriverConfig struct {
+	// Driver is the HTTP-API mail driver to use
+	// (could be any of the MailDriver* constants).
+	//
+	// Leave it empty to use the SMTP (or sendmail) mail client.
+	Driver string `form:"driver" json:"driver"`
+
+	// Endpoint is an optional custom API base url
+	// (ex. for EU hosted accounts or a local stub server).
+	Endpoint string `form:"endpoint" json:"endpoint"`
+
+	// Region is the AWS region of the SES driver.
+	Region string `form:"region" json:"region"`
+
+	// Domain is the sending domain of the Mailgun driver.
+	Domain string `form:"domain" json:"domain"`
+
+	// AccessKey is the AWS access key id of the SES driver.
+	AccessKey string `form:"accessKey" json:"accessKey"`
+
+	// Secret is the AWS secret key (SES), the private API key (Mailgun)
+	// or the server token (Postmark) of the selected driver.
+	Secret string `form:"secret" json:"secret,omitempty"`
+}
+
+// Validate makes MailDriverConfig validatable by implementing [validation.Validatable] interface.
+func (c MailDriverConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Driver, validation.In(MailDriverSES, MailDriverMailgun, MailDriverPostmark)),
+		validation.Field(&c.Endpoint, is.URL),
+		validation.Field(&c.Region, validation.When(c.Driver == MailDriverSES, validation.Required)),
+		validation.Field(&c.Domain, validation.When(c.Driver == MailDriverMailgun, validation.Required), is.Domain),
+		validation.Field(&c.AccessKey, validation.When(c.Driver == MailDriverSES, validation.Required)),
+		validation.Field(&c.Secret, validation.When(c.Driver != "", validation.Required)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// InboundMailConfig defines the settings of the inbound mail webhook
+// that accepts raw RFC 5322 messages (ex. replies to notification emails).
+type InboundMailConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Secret is the shared secret that the inbound webhook requests
+	// must send as "Authorization: Bearer {secret}" header.
+	Secret string `form:"secret" json:"secret,omitempty"`
+
+	// Collection is an optional collection name or id where to store
+	// the received messages (leave empty to handle them only via OnMailReceived).
+	//
+	// The message data is stored in the collection fields with matching name:
+	// "from", "to", "cc", "subject", "text", "html", "messageId",
+	// "inReplyTo", "references", "headers" and "attachments" (file field).
+	Collection string `form:"collection" json:"collection"`
+
+	// MaxSize is the max allowed raw message size in bytes
+	// (if zero, fallbacks to 25MB).
+	MaxSize int64 `form:"maxSize" json:"maxSize"`
+}
+
+// Validate makes InboundMailConfig validatable by implementing [validation.Validatable] interface.
+func (c InboundMailConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Secret, validation.When(c.Enabled, validation.Required), validation.Length(10, 255)),
+		validation.Field(&c.MaxSize, validation.Min(0)),
+	)
+}
+
+func checkInboundMailCollection(app App) validation.RuleFunc {
+	return func(value any) error {
+		c, _ := value.(InboundMailConfig)
+		if c.Collection == "" {
+			return nil // nothing to check
+		}
+
+		collection, err := app.FindCollectionByNameOrId(c.Collection)
+		if err != nil || collection.IsView() {
+			return validation.Errors{"collection": validation.NewError(
+				"validation_invalid_collection",
+				"Missing or invalid collection.",
+			)}
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
+// WebPushConfig defines the Web Push notifications settings
+// (see [App.SendPush]).
+type WebPushConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Subject is the VAPID contact of the application
+	// (must be a "mailto:" or "https:" url).
+	Subject string `form:"subject" json:"subject"`
+
+	// PublicKey is the base64 encoded VAPID P-256 public key
+	// (the "applicationServerKey" passed to the browser PushManager.subscribe()).
+	PublicKey string `form:"publicKey" json:"publicKey"`
+
+	// PrivateKey is the base64 encoded VAPID P-256 private key.
+	PrivateKey string `form:"privateKey" json:"privateKey,omitempty"`
+
+	// TTL is the default number of seconds the push service should retain
+	// undelivered messages (if zero, fallbacks to 24 hours).
+	TTL int `form:"ttl" json:"ttl"`
+
+	// AllowedHosts is an optional list of trusted push service hosts
+	// (in the format "host" or "host:port", e.g. a self-hosted push service
+	// in the local network) that skip the subscription endpoint https and
+	// public network address checks.
+	AllowedHosts []string `form:"allowedHosts" json:"allowedHosts"`
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (c WebPushConfig) MarshalJSON() ([]byte, error) {
+	type alias WebPushConfig
+
+	// serialize as empty array
+	if c.AllowedHosts == nil {
+		c.AllowedHosts = []string{}
+	}
+
+	return json.Marshal(alias(c))
+}
+
+// Validate makes WebPushConfig validatable by implementing [validation.Validatable] interface.
+func (c WebPushConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Subject,
+			validation.When(c.Enabled, validation.Required),
+			validation.By(checkVAPIDSubject),
+		),
+		validation.Field(&c.PublicKey, validation.When(c.Enabled, validation.Required)),
+		validation.Field(
+			&c.PrivateKey,
+			validation.When(c.Enabled, validation.Required),
+			validation.When(c.PrivateKey != "", validation.By(checkVAPIDKeys(c.PublicKey))),
+		),
+		validation.Field(&c.TTL, validation.Min(0), validation.Max(28*24*60*60)),
+		validation.Field(&c.AllowedHosts, validation.Each(validation.Required, validation.By(checkHostWithOptionalPort))),
+	)
+}
+
+func checkHostWithOptionalPort(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	host := v
+	if h, _, err := net.SplitHostPort(v); err == nil {
+		host = h
+	}
+
+	return is.Host.Validate(strings.Trim(host, "[]"))
+}
+
+func checkVAPIDSubject(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if !strings.HasPrefix(v, "mailto:") && !strings.HasPrefix(v, "https://") {
+		return validation.NewError("validation_invalid_vapid_subject", "Must be a mailto: or https:// url.")
+	}
+
+	return nil
+}
+
+func checkVAPIDKeys(publicKey string) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+
+		if err := webpush.CheckVAPIDKeys(publicKey, v); err != nil {
+			return validation.NewError("validation_invalid_vapid_keys", "Invalid or mismatched VAPID key pair.")
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
 type S3Config struct {
 	Enabled        bool   `form:"enabled" json:"enabled"`
 	Bucket         string `form:"bucket" json:"bucket"`
@@ -488,6 +717,151 @@ func checkCronExpression(value any) error {
 
 // -------------------------------------------------------------------
 
+type BreachedPasswordsConfig struct {
+	// Source is the local path to a HIBP formatted SHA-1 hashes source
+	// used by the password fields with enabled DisallowBreached option.
+	//
+	// It could be either a single "HASH:COUNT" file ordered by hash or
+	// a directory with "PREFIX.txt" range files (see [passwords.BreachedRange]).
+	Source string `form:"source" json:"source"`
+}
+
+// Validate makes BreachedPasswordsConfig validatable by implementing [validation.Validatable] interface.
+func (c BreachedPasswordsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Source, validation.By(checkBreachedPasswordsSource)),
+	)
+}
+
+func checkBreachedPasswordsSource(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := os.Stat(v); err != nil {
+		return validation.NewError("validation_invalid_breached_passwords_source", "Missing or inaccessible breached passwords source.")
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type SCIMConfig struct {
+	// UsersCollection is the name or id of the auth collection
+	// mapped to the SCIM "Users" resource.
+	UsersCollection string `form:"usersCollection" json:"usersCollection"`
+
+	// UserNameField is the UsersCollection field mapped to the
+	// SCIM "userName" attribute (fallbacks to "email" if empty).
+	UserNameField string `form:"userNameField" json:"userNameField"`
+
+	// DisplayNameField is an optional UsersCollection field mapped
+	// to the SCIM "displayName" and "name.formatted" attributes.
+	DisplayNameField string `form:"displayNameField" json:"displayNameField"`
+
+	// ActiveField is an optional UsersCollection bool field mapped
+	// to the SCIM "active" attribute.
+	//
+	// If not set, all users are reported as active and deactivation
+	// requests are rejected.
+	ActiveField string `form:"activeField" json:"activeField"`
+
+	// ExternalIdField is an optional UsersCollection field mapped
+	// to the SCIM "externalId" attribute.
+	ExternalIdField string `form:"externalIdField" json:"externalIdField"`
+
+	// GroupsCollection is an optional name or id of a base collection
+	// mapped to the SCIM "Groups" resource.
+	GroupsCollection string `form:"groupsCollection" json:"groupsCollection"`
+
+	// GroupsNameField is the GroupsCollection field mapped to the SCIM "displayName" attribute.
+	GroupsNameField string `form:"groupsNameField" json:"groupsNameField"`
+
+	// GroupsMembersField is the GroupsCollection relation field
+	// (to UsersCollection) mapped to the SCIM "members" attribute.
+	GroupsMembersField string `form:"groupsMembersField" json:"groupsMembersField"`
+
+	// TokenHash is the SHA256 hash of the superuser issued static
+	// bearer token that is used to authorize the SCIM requests.
+	TokenHash string `form:"tokenHash" json:"tokenHash,omitempty"`
+
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// Validate makes SCIMConfig validatable by implementing [validation.Validatable] interface.
+func (c SCIMConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.UsersCollection, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.GroupsNameField, validation.When(c.Enabled && c.GroupsCollection != "", validation.Required)),
+		validation.Field(&c.GroupsMembersField, validation.When(c.Enabled && c.GroupsCollection != "", validation.Required)),
+	)
+}
+
+func checkSCIMCollections(app App) validation.RuleFunc {
+	return func(value any) error {
+		c, _ := value.(SCIMConfig)
+		if !c.Enabled || c.UsersCollection == "" {
+			return nil // nothing to check
+		}
+
+		users, err := app.FindCollectionByNameOrId(c.UsersCollection)
+		if err != nil || !users.IsAuth() {
+			return validation.Errors{"usersCollection": validation.NewError(
+				"validation_invalid_auth_collection",
+				"Missing or invalid auth collection.",
+			)}
+		}
+
+		checkFields := [][2]string{
+			{"userNameField", c.UserNameField},
+			{"displayNameField", c.DisplayNameField},
+			{"activeField", c.ActiveField},
+			{"externalIdField", c.ExternalIdField},
+		}
+		for _, pair := range checkFields {
+			if pair[1] != "" && users.Fields.GetByName(pair[1]) == nil {
+				return validation.Errors{pair[0]: validation.NewError("validation_missing_field", "Missing collection field.")}
+			}
+		}
+
+		if c.ActiveField != "" {
+			if _, ok := users.Fields.GetByName(c.ActiveField).(*BoolField); !ok {
+				return validation.Errors{"activeField": validation.NewError("validation_invalid_bool_field", "The field must be a bool field.")}
+			}
+		}
+
+		if c.GroupsCollection == "" {
+			return nil
+		}
+
+		groups, err := app.FindCollectionByNameOrId(c.GroupsCollection)
+		if err != nil || !groups.IsBase() {
+			return validation.Errors{"groupsCollection": validation.NewError(
+				"validation_invalid_base_collection",
+				"Missing or invalid base collection.",
+			)}
+		}
+
+		if groups.Fields.GetByName(c.GroupsNameField) == nil {
+			return validation.Errors{"groupsNameField": validation.NewError("validation_missing_field", "Missing collection field.")}
+		}
+
+		members, _ := groups.Fields.GetByName(c.GroupsMembersField).(*RelationField)
+		if members == nil || members.CollectionId != users.Id {
+			return validation.Errors{"groupsMembersField": validation.NewError(
+				"validation_invalid_relation_field",
+				"The field must be a relation field to the users collection.",
+			)}
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
 type MetaConfig struct {
 	AppName       string `form:"appName" json:"appName"`
 	AppURL        string `form:"appURL" json:"appURL"`
@@ -524,6 +898,77 @@ func (c LogsConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+type MailQueueConfig struct {
+	// Enabled specifies whether to enqueue the app emails in a persistent
+	// queue and deliver them in the background instead of sending them inline.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxAttempts specifies the max number of delivery attempts
+	// before marking the queued email as failed.
+	MaxAttempts int `form:"maxAttempts" json:"maxAttempts"`
+
+	// RetryDelay specifies the initial delay (in seconds) before retrying
+	// a failed delivery attempt.
+	//
+	// The delay is doubled after each subsequent failed attempt.
+	RetryDelay int64 `form:"retryDelay" json:"retryDelay"`
+
+	// LogsMaxDays specifies how long to keep the mail delivery log entries.
+	//
+	// Set it to 0 to keep them indefinitely.
+	LogsMaxDays int `form:"logsMaxDays" json:"logsMaxDays"`
+}
+
+// Validate makes MailQueueConfig validatable by implementing [validation.Validatable] interface.
+func (c MailQueueConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxAttempts, validation.When(c.Enabled, validation.Required), validation.Min(0), validation.Max(100)),
+		validation.Field(&c.RetryDelay, validation.When(c.Enabled, validation.Required), validation.Min(0), validation.Max(86400)),
+		validation.Field(&c.LogsMaxDays, validation.Min(0)),
+	)
+}
+
+// RetryDelayTime returns the delay before the next delivery attempt
+// based on the number of the already failed attempts.
+func (c MailQueueConfig) RetryDelayTime(failedAttempts int) time.Duration {
+	return retryDelay(time.Duration(c.RetryDelay)*time.Second, failedAttempts, maxMailRetryDelay)
+}
+
+// -------------------------------------------------------------------
+
+// JobsConfig defines the background job queue settings (see [App.EnqueueJob]).
+type JobsConfig struct {
+	// Concurrency specifies the max number of jobs that could run in parallel.
+	Concurrency int `form:"concurrency" json:"concurrency"`
+
+	// MaxAttempts specifies the default max number of run attempts
+	// before moving the job to the dead-letter state.
+	MaxAttempts int `form:"maxAttempts" json:"maxAttempts"`
+
+	// RetryDelay specifies the initial delay (in seconds) before retrying
+	// a failed job.
+	//
+	// The delay is doubled after each subsequent failed attempt.
+	RetryDelay int64 `form:"retryDelay" json:"retryDelay"`
+}
+
+// Validate makes JobsConfig validatable by implementing [validation.Validatable] interface.
+func (c JobsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Concurrency, validation.Min(0), validation.Max(100)),
+		validation.Field(&c.MaxAttempts, validation.Min(0), validation.Max(100)),
+		validation.Field(&c.RetryDelay, validation.Min(0), validation.Max(86400)),
+	)
+}
+
+// RetryDelayTime returns the delay before the next job run attempt
+// based on the number of the already failed attempts.
+func (c JobsConfig) RetryDelayTime(failedAttempts int) time.Duration {
+	return retryDelay(time.Duration(c.RetryDelay)*time.Second, failedAttempts, maxJobRetryDelay)
+}
+
+// -------------------------------------------------------------------
+
 type TrustedProxyConfig struct {
 	// Headers is a list of explicit trusted header(s) to check.
 	Headers []string `form:"headers" json:"headers"`
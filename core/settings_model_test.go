@@ -3,13 +3,17 @@ package core_test
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/webpush"
 )
 
 func TestSettingsDelete(t *testing.T) {
@@ -99,6 +103,10 @@ func TestSettingsMarshalJSON(t *testing.T) {
 	settings.SMTP.Password = testSecret
 	settings.S3.Secret = testSecret
 	settings.Backups.S3.Secret = testSecret
+	settings.SCIM.TokenHash = testSecret
+	settings.MailDriver.Secret = testSecret
+	settings.InboundMail.Secret = testSecret
+	settings.WebPush.PrivateKey = testSecret
 
 	raw, err := json.Marshal(settings)
 	if err != nil {
@@ -106,7 +114,7 @@ func TestSettingsMarshalJSON(t *testing.T) {
 	}
 	rawStr := string(raw)
 
-	expected := `{"smtp":{"enabled":false,"port":0,"host":"","username":"abc","authMethod":"","tls":false,"localName":""},"backups":{"cron":"","cronMaxKeep":0,"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false}},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false},"meta":{"appName":"test123","appURL":"","senderName":"","senderAddress":"","hideControls":false},"rateLimits":{"rules":[],"enabled":false},"trustedProxy":{"headers":[],"useLeftmostIP":false},"batch":{"enabled":false,"maxRequests":0,"timeout":0,"maxBodySize":0},"logs":{"maxDays":0,"minLevel":0,"logIP":false,"logAuthId":false}}`
+	expected := `{"smtp":{"enabled":false,"port":0,"host":"","username":"abc","authMethod":"","tls":false,"localName":""},"backups":{"cron":"","cronMaxKeep":0,"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false}},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false},"meta":{"appName":"test123","appURL":"","senderName":"","senderAddress":"","hideControls":false},"rateLimits":{"rules":[],"enabled":false},"trustedProxy":{"headers":[],"useLeftmostIP":false},"batch":{"enabled":false,"maxRequests":0,"timeout":0,"maxBodySize":0},"logs":{"maxDays":0,"minLevel":0,"logIP":false,"logAuthId":false},"scim":{"usersCollection":"","userNameField":"","displayNameField":"","activeField":"","externalIdField":"","groupsCollection":"","groupsNameField":"","groupsMembersField":"","enabled":false},"breachedPasswords":{"source":""},"mailQueue":{"enabled":false,"maxAttempts":0,"retryDelay":0,"logsMaxDays":0},"mailDriver":{"driver":"","endpoint":"","region":"","domain":"","accessKey":""},"inboundMail":{"enabled":false,"collection":"","maxSize":0},"webPush":{"enabled":false,"subject":"","publicKey":"","ttl":0,"allowedHosts":[]},"jobs":{"concurrency":0,"maxAttempts":0,"retryDelay":0}}`
 
 	if rawStr != expected {
 		t.Fatalf("Expected\n%v\ngot\n%v", expected, rawStr)
@@ -135,6 +143,17 @@ func TestSettingsValidate(t *testing.T) {
 	s.Batch.Timeout = -1
 	s.RateLimits.Enabled = true
 	s.RateLimits.Rules = nil
+	s.SCIM.Enabled = true
+	s.SCIM.UsersCollection = ""
+	s.BreachedPasswords.Source = "/missing/breached/passwords.txt"
+	s.MailQueue.Enabled = true
+	s.MailQueue.MaxAttempts = 0
+	s.MailDriver.Driver = "invalid"
+	s.InboundMail.Enabled = true
+	s.InboundMail.Secret = ""
+	s.WebPush.Enabled = true
+	s.WebPush.Subject = ""
+	s.Jobs.Concurrency = -1
 
 	// check if Validate() is triggering the members validate methods.
 	err := app.Validate(s)
@@ -150,6 +169,13 @@ func TestSettingsValidate(t *testing.T) {
 		`"backups":{`,
 		`"batch":{`,
 		`"rateLimits":{`,
+		`"scim":{`,
+		`"breachedPasswords":{`,
+		`"mailQueue":{`,
+		`"mailDriver":{`,
+		`"inboundMail":{`,
+		`"webPush":{`,
+		`"jobs":{`,
 	}
 
 	errBytes, _ := json.Marshal(err)
@@ -430,6 +456,258 @@ func TestBackupsConfigValidate(t *testing.T) {
 	}
 }
 
+func TestMailDriverConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.MailDriverConfig
+		expectedErrors []string
+	}{
+		{
+			"zero values",
+			core.MailDriverConfig{},
+			[]string{},
+		},
+		{
+			"unknown driver",
+			core.MailDriverConfig{Driver: "missing"},
+			[]string{"driver", "secret"},
+		},
+		{
+			"ses zero values",
+			core.MailDriverConfig{Driver: core.MailDriverSES},
+			[]string{"region", "accessKey", "secret"},
+		},
+		{
+			"mailgun zero values",
+			core.MailDriverConfig{Driver: core.MailDriverMailgun},
+			[]string{"domain", "secret"},
+		},
+		{
+			"postmark zero values",
+			core.MailDriverConfig{Driver: core.MailDriverPostmark},
+			[]string{"secret"},
+		},
+		{
+			"invalid endpoint and domain",
+			core.MailDriverConfig{Driver: core.MailDriverMailgun, Endpoint: "test:test:test", Domain: "invalid domain", Secret: "test"},
+			[]string{"endpoint", "domain"},
+		},
+		{
+			"valid ses data",
+			core.MailDriverConfig{Driver: core.MailDriverSES, Region: "eu-west-1", AccessKey: "test", Secret: "test"},
+			[]string{},
+		},
+		{
+			"valid mailgun data",
+			core.MailDriverConfig{Driver: core.MailDriverMailgun, Endpoint: "https://api.eu.mailgun.net", Domain: "mg.example.com", Secret: "test"},
+			[]string{},
+		},
+		{
+			"valid postmark data",
+			core.MailDriverConfig{Driver: core.MailDriverPostmark, Endpoint: "http://127.0.0.1:8080", Secret: "test"},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestInboundMailConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.InboundMailConfig
+		expectedErrors []string
+	}{
+		{
+			"zero values (disabled)",
+			core.InboundMailConfig{},
+			[]string{},
+		},
+		{
+			"zero values (enabled)",
+			core.InboundMailConfig{Enabled: true},
+			[]string{"secret"},
+		},
+		{
+			"invalid data",
+			core.InboundMailConfig{Enabled: true, Secret: "short", MaxSize: -1},
+			[]string{"secret", "maxSize"},
+		},
+		{
+			"valid data",
+			core.InboundMailConfig{Enabled: true, Secret: "1234567890", MaxSize: 100},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestWebPushConfigValidate(t *testing.T) {
+	pub, priv, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherPub, _, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		config         core.WebPushConfig
+		expectedErrors []string
+	}{
+		{
+			"zero values (disabled)",
+			core.WebPushConfig{},
+			[]string{},
+		},
+		{
+			"zero values (enabled)",
+			core.WebPushConfig{Enabled: true},
+			[]string{"subject", "publicKey", "privateKey"},
+		},
+		{
+			"invalid data",
+			core.WebPushConfig{
+				Enabled:      true,
+				Subject:      "test@example.com",
+				PublicKey:    otherPub,
+				PrivateKey:   priv,
+				TTL:          -1,
+				AllowedHosts: []string{"", "in valid:80"},
+			},
+			[]string{"subject", "privateKey", "ttl", "allowedHosts"},
+		},
+		{
+			"invalid data (disabled)",
+			core.WebPushConfig{
+				Subject:    "http://example.com",
+				PublicKey:  pub,
+				PrivateKey: "invalid",
+				TTL:        30 * 24 * 60 * 60,
+			},
+			[]string{"subject", "privateKey", "ttl"},
+		},
+		{
+			"valid data (mailto)",
+			core.WebPushConfig{
+				Enabled:      true,
+				Subject:      "mailto:test@example.com",
+				PublicKey:    pub,
+				PrivateKey:   priv,
+				TTL:          60,
+				AllowedHosts: []string{"push.local", "192.168.1.2:8080", "[::1]:8080"},
+			},
+			[]string{},
+		},
+		{
+			"valid data (https)",
+			core.WebPushConfig{
+				Enabled:    true,
+				Subject:    "https://example.com",
+				PublicKey:  pub,
+				PrivateKey: priv,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestJobsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.JobsConfig
+		expectedErrors []string
+	}{
+		{
+			"zero values",
+			core.JobsConfig{},
+			[]string{},
+		},
+		{
+			"invalid data (negative)",
+			core.JobsConfig{Concurrency: -1, MaxAttempts: -1, RetryDelay: -1},
+			[]string{"concurrency", "maxAttempts", "retryDelay"},
+		},
+		{
+			"invalid data (too large)",
+			core.JobsConfig{Concurrency: 101, MaxAttempts: 101, RetryDelay: 86401},
+			[]string{"concurrency", "maxAttempts", "retryDelay"},
+		},
+		{
+			"valid data",
+			core.JobsConfig{Concurrency: 2, MaxAttempts: 3, RetryDelay: 10},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestSettingsValidateInboundMailCollection(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		collection     string
+		expectedErrors []string
+	}{
+		{"empty collection", "", []string{}},
+		{"missing collection", "missing", []string{"collection"}},
+		{"view collection", "view1", []string{"collection"}},
+		{"base collection", "demo1", []string{}},
+		{"auth collection", "users", []string{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			settings := app.Settings()
+			settings.InboundMail.Collection = s.collection
+
+			err := app.Validate(settings)
+
+			var inboundErr error
+			if errs, ok := err.(validation.Errors); ok {
+				inboundErr = errs["inboundMail"]
+			}
+
+			tests.TestValidationErrors(t, inboundErr, s.expectedErrors)
+		})
+	}
+}
+
 func TestBatchConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -485,6 +763,246 @@ func TestBatchConfigValidate(t *testing.T) {
 	}
 }
 
+func TestBreachedPasswordsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.BreachedPasswordsConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.BreachedPasswordsConfig{},
+			[]string{},
+		},
+		{
+			"missing source",
+			core.BreachedPasswordsConfig{Source: filepath.Join(os.TempDir(), "missing_breached_passwords.txt")},
+			[]string{"source"},
+		},
+		{
+			"existing source",
+			core.BreachedPasswordsConfig{Source: os.TempDir()},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestMailQueueConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.MailQueueConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value (disabled)",
+			core.MailQueueConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.MailQueueConfig{Enabled: true},
+			[]string{"maxAttempts", "retryDelay"},
+		},
+		{
+			"invalid data",
+			core.MailQueueConfig{MaxAttempts: 101, RetryDelay: 86401, LogsMaxDays: -1},
+			[]string{"maxAttempts", "retryDelay", "logsMaxDays"},
+		},
+		{
+			"valid data (enabled)",
+			core.MailQueueConfig{Enabled: true, MaxAttempts: 5, RetryDelay: 30, LogsMaxDays: 7},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestMailQueueConfigRetryDelayTime(t *testing.T) {
+	scenarios := []struct {
+		config         core.MailQueueConfig
+		failedAttempts int
+		expected       time.Duration
+	}{
+		{core.MailQueueConfig{}, 1, 0},
+		{core.MailQueueConfig{RetryDelay: 30}, 0, 30 * time.Second},
+		{core.MailQueueConfig{RetryDelay: 30}, 1, 30 * time.Second},
+		{core.MailQueueConfig{RetryDelay: 30}, 2, 60 * time.Second},
+		{core.MailQueueConfig{RetryDelay: 30}, 4, 240 * time.Second},
+		{core.MailQueueConfig{RetryDelay: 3600}, 100, 24 * time.Hour},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%d", s.config.RetryDelay, s.failedAttempts), func(t *testing.T) {
+			result := s.config.RetryDelayTime(s.failedAttempts)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestSCIMConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.SCIMConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.SCIMConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.SCIMConfig{Enabled: true},
+			[]string{"usersCollection"},
+		},
+		{
+			"enabled with groups collection but missing groups fields",
+			core.SCIMConfig{
+				Enabled:          true,
+				UsersCollection:  "users",
+				GroupsCollection: "demo1",
+			},
+			[]string{"groupsNameField", "groupsMembersField"},
+		},
+		{
+			"valid data",
+			core.SCIMConfig{
+				Enabled:            true,
+				UsersCollection:    "users",
+				GroupsCollection:   "demo1",
+				GroupsNameField:    "text",
+				GroupsMembersField: "rel_many",
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestSettingsValidateSCIMCollections(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		config         core.SCIMConfig
+		expectedErrors []string
+	}{
+		{
+			"disabled with invalid collections",
+			core.SCIMConfig{
+				UsersCollection:  "missing",
+				GroupsCollection: "missing",
+			},
+			[]string{},
+		},
+		{
+			"non-auth users collection",
+			core.SCIMConfig{
+				Enabled:         true,
+				UsersCollection: "demo1",
+			},
+			[]string{"usersCollection"},
+		},
+		{
+			"missing users field",
+			core.SCIMConfig{
+				Enabled:          true,
+				UsersCollection:  "users",
+				DisplayNameField: "missing",
+			},
+			[]string{"displayNameField"},
+		},
+		{
+			"non-bool active field",
+			core.SCIMConfig{
+				Enabled:         true,
+				UsersCollection: "users",
+				ActiveField:     "name",
+			},
+			[]string{"activeField"},
+		},
+		{
+			"non-base groups collection",
+			core.SCIMConfig{
+				Enabled:            true,
+				UsersCollection:    "users",
+				GroupsCollection:   "clients",
+				GroupsNameField:    "name",
+				GroupsMembersField: "rel",
+			},
+			[]string{"groupsCollection"},
+		},
+		{
+			"groups members field not related to the users collection",
+			core.SCIMConfig{
+				Enabled:            true,
+				UsersCollection:    "users",
+				GroupsCollection:   "demo1",
+				GroupsNameField:    "text",
+				GroupsMembersField: "rel_one",
+			},
+			[]string{"groupsMembersField"},
+		},
+		{
+			"valid config",
+			core.SCIMConfig{
+				Enabled:            true,
+				UsersCollection:    "users",
+				UserNameField:      "username",
+				DisplayNameField:   "name",
+				ActiveField:        "verified",
+				GroupsCollection:   "demo1",
+				GroupsNameField:    "text",
+				GroupsMembersField: "rel_many",
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			settings := app.Settings()
+			settings.SCIM = s.config
+
+			err := app.Validate(settings)
+
+			var scimErr error
+			if errs, ok := err.(validation.Errors); ok {
+				scimErr = errs["scim"]
+			}
+
+			tests.TestValidationErrors(t, scimErr, s.expectedErrors)
+		})
+	}
+}
+
 func TestRateLimitsConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindWebhookLogById finds a single WebhookLog entry by its id.
+func (app *BaseApp) FindWebhookLogById(id string) (*WebhookLog, error) {
+	model := &WebhookLog{}
+
+	err := app.AuxModelQuery(model).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// DeleteOldWebhookLogs deletes all webhook log entries that are created before createdBefore.
+//
+// For better performance the delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteOldWebhookLogs(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := app.AuxNonconcurrentDB().Delete(WebhookLogsTableName, expr).Execute()
+
+	return err
+}
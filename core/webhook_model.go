@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	CollectionNameWebhooks = "_webhooks"
+	WebhookLogsTableName   = "_webhookLogs"
+)
+
+const (
+	WebhookEventCreate = "create"
+	WebhookEventUpdate = "update"
+	WebhookEventDelete = "delete"
+)
+
+var (
+	_ Model        = (*Webhook)(nil)
+	_ PreValidator = (*Webhook)(nil)
+	_ RecordProxy  = (*Webhook)(nil)
+	_ Model        = (*WebhookLog)(nil)
+)
+
+// Webhook defines a Record proxy for working with the webhooks collection.
+type Webhook struct {
+	*Record
+}
+
+// NewWebhook instantiates and returns a new blank *Webhook model.
+//
+// Example usage:
+//
+//	webhook := core.NewWebhook(app)
+//	webhook.SetURL("https://example.com/hook")
+//	webhook.SetCollectionRef(posts.Id)
+//	webhook.SetEvents([]string{core.WebhookEventCreate})
+//	webhook.SetSecret("...")
+//	webhook.SetEnabled(true)
+//	app.Save(webhook)
+func NewWebhook(app App) *Webhook {
+	m := &Webhook{}
+
+	c, err := app.FindCachedCollectionByNameOrId(CollectionNameWebhooks)
+	if err != nil {
+		// this is just to make tests easier since webhooks is a system collection and it is expected to be always accessible
+		// (note: the loaded record is further checked on Webhook.PreValidate())
+		c = NewBaseCollection("@__invalid__")
+	}
+
+	m.Record = NewRecord(c)
+
+	return m
+}
+
+// PreValidate implements the [PreValidator] interface and checks
+// whether the proxy is properly loaded.
+func (m *Webhook) PreValidate(ctx context.Context, app App) error {
+	if m.Record == nil || m.Record.Collection().Name != CollectionNameWebhooks {
+		return errors.New("missing or invalid Webhook ProxyRecord")
+	}
+
+	return nil
+}
+
+// ProxyRecord returns the proxied Record model.
+func (m *Webhook) ProxyRecord() *Record {
+	return m.Record
+}
+
+// SetProxyRecord loads the specified record model into the current proxy.
+func (m *Webhook) SetProxyRecord(record *Record) {
+	m.Record = record
+}
+
+// Name returns the "name" record field value.
+func (m *Webhook) Name() string {
+	return m.GetString("name")
+}
+
+// SetName updates the "name" record field value.
+func (m *Webhook) SetName(name string) {
+	m.Set("name", name)
+}
+
+// URL returns the "url" record field value.
+func (m *Webhook) URL() string {
+	return m.GetString("url")
+}
+
+// SetURL updates the "url" record field value.
+func (m *Webhook) SetURL(url string) {
+	m.Set("url", url)
+}
+
+// CollectionRef returns the "collectionRef" record field value.
+func (m *Webhook) CollectionRef() string {
+	return m.GetString("collectionRef")
+}
+
+// SetCollectionRef updates the "collectionRef" record field value.
+func (m *Webhook) SetCollectionRef(collectionId string) {
+	m.Set("collectionRef", collectionId)
+}
+
+// Events returns the "events" record field value.
+func (m *Webhook) Events() []string {
+	return m.GetStringSlice("events")
+}
+
+// SetEvents updates the "events" record field value.
+func (m *Webhook) SetEvents(events []string) {
+	m.Set("events", events)
+}
+
+// HasEvent checks whether the webhook is subscribed for the specified record event.
+func (m *Webhook) HasEvent(event string) bool {
+	return slices.Contains(m.Events(), event)
+}
+
+// Filter returns the "filter" record field value.
+func (m *Webhook) Filter() string {
+	return m.GetString("filter")
+}
+
+// SetFilter updates the "filter" record field value.
+func (m *Webhook) SetFilter(filter string) {
+	m.Set("filter", filter)
+}
+
+// Secret returns the "secret" record field value.
+func (m *Webhook) Secret() string {
+	return m.GetString("secret")
+}
+
+// SetSecret updates the "secret" record field value.
+func (m *Webhook) SetSecret(secret string) {
+	m.Set("secret", secret)
+}
+
+// Enabled returns the "enabled" record field value.
+func (m *Webhook) Enabled() bool {
+	return m.GetBool("enabled")
+}
+
+// SetEnabled updates the "enabled" record field value.
+func (m *Webhook) SetEnabled(enabled bool) {
+	m.Set("enabled", enabled)
+}
+
+// Created returns the "created" record field value.
+func (m *Webhook) Created() types.DateTime {
+	return m.GetDateTime("created")
+}
+
+// Updated returns the "updated" record field value.
+func (m *Webhook) Updated() types.DateTime {
+	return m.GetDateTime("updated")
+}
+
+// -------------------------------------------------------------------
+
+// WebhookLog defines a single webhook delivery attempt log entry.
+type WebhookLog struct {
+	BaseModel
+
+	WebhookRef string `db:"webhookRef" json:"webhookRef"`
+
+	// DeliveryId is the id of the delivery job
+	// (it is the same for all attempts of a single delivery).
+	DeliveryId string `db:"deliveryId" json:"deliveryId"`
+
+	Event      string `db:"event" json:"event"`
+	Collection string `db:"collection" json:"collection"`
+	RecordRef  string `db:"recordRef" json:"recordRef"`
+	URL        string `db:"url" json:"url"`
+
+	// Status is the HTTP response status code (0 if no response was received).
+	Status int `db:"status" json:"status"`
+
+	Error   string `db:"error" json:"error"`
+	Attempt int    `db:"attempt" json:"attempt"`
+
+	// Duration is the request duration in milliseconds.
+	Duration float64 `db:"duration" json:"duration"`
+
+	Created types.DateTime `db:"created" json:"created"`
+}
+
+func (m *WebhookLog) TableName() string {
+	return WebhookLogsTableName
+}
@@ -0,0 +1,409 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// webhookDeliveryJobName is the name of the background job that delivers
+// a single webhook request (failed deliveries are retried by the job queue).
+const webhookDeliveryJobName = "__pbWebhookDelivery__"
+
+// webhookRequestTimeout is the max duration of a single webhook delivery request.
+const webhookRequestTimeout = 30 * time.Second
+
+// webhookLogsMaxAge is the max age of the stored webhook delivery log entries.
+const webhookLogsMaxAge = 7 * 24 * time.Hour
+
+// webhookMaxErrorBodySize is the max number of response body bytes
+// stored in the delivery log error of a failed request.
+const webhookMaxErrorBodySize = 512
+
+// pendingDeleteWebhooksKey is the internal record key that holds
+// the matching delete webhooks until the record deletion completes.
+const pendingDeleteWebhooksKey = internalCustomFieldKeyPrefix + "_pendingDeleteWebhooks"
+
+// Webhook delivery request headers.
+const (
+	WebhookHeaderId        = "X-Webhook-Id"
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+// WebhookPayload defines the JSON body of a webhook delivery request.
+type WebhookPayload struct {
+	Event          string          `json:"event"`
+	CollectionId   string          `json:"collectionId"`
+	CollectionName string          `json:"collectionName"`
+	Record         json.RawMessage `json:"record"`
+}
+
+// webhookDelivery defines the webhook delivery job payload.
+type webhookDelivery struct {
+	WebhookId string          `json:"webhookId"`
+	RecordId  string          `json:"recordId"`
+	Event     string          `json:"event"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// WebhookSignature returns the signature of a webhook delivery request body
+// in the format "sha256=HMAC_SHA256(timestamp.body, secret)".
+//
+// Receivers are expected to compute the same value from the
+// X-Webhook-Timestamp header and the raw request body and
+// compare it with the X-Webhook-Signature header.
+func WebhookSignature(secret string, timestamp string, body []byte) string {
+	return "sha256=" + security.HS256(timestamp+"."+string(body), secret)
+}
+
+func (app *BaseApp) registerWebhookHooks() {
+	app.RegisterJob(webhookDeliveryJobName, app.deliverWebhook)
+
+	app.OnRecordValidate(CollectionNameWebhooks).Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			collectionId := e.Record.GetString("collectionRef")
+			err := validation.Validate(collectionId, validation.Required, validation.By(validateCollectionId(e.App, CollectionTypeBase, CollectionTypeAuth)))
+			if err != nil {
+				return validation.Errors{"collectionRef": err}
+			}
+
+			collection, err := e.App.FindCachedCollectionByNameOrId(collectionId)
+			if err != nil {
+				return validation.Errors{"collectionRef": validation.NewError(
+					"validation_invalid_collection_id",
+					"Missing or invalid collection.",
+				)}
+			}
+
+			if collection.Name == CollectionNameWebhooks {
+				return validation.Errors{"collectionRef": validation.NewError(
+					"validation_webhooks_collection_not_allowed",
+					"Webhooks can't be registered for the webhooks collection.",
+				)}
+			}
+
+			if filter := e.Record.GetString("filter"); filter != "" {
+				r := NewRecordFieldResolver(e.App, collection, nil, true)
+				if _, err := search.FilterData(filter).BuildExpr(r); err != nil {
+					return validation.Errors{"filter": validation.NewError(
+						"validation_invalid_webhook_filter",
+						"Invalid filter expression - {{.error}}.",
+					).SetParams(map[string]any{"error": err.Error()})}
+				}
+			}
+
+			return e.Next()
+		},
+		Priority: 99,
+	})
+
+	// delete the collection webhooks on collection delete
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if e.Collection.Name == CollectionNameWebhooks {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				webhooks, err := txApp.FindAllRecords(CollectionNameWebhooks, dbx.HashExp{"collectionRef": e.Collection.Id})
+				if err != nil {
+					return err
+				}
+
+				for _, webhook := range webhooks {
+					if err := txApp.Delete(webhook); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			})
+			e.App = originalApp
+
+			return txErr
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordAfterCreateSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbWebhooksCreate__",
+		Func: func(e *RecordEvent) error {
+			enqueueWebhookDeliveries(e.App, e.Record, WebhookEventCreate, nil)
+
+			return e.Next()
+		},
+		Priority: 999,
+	})
+
+	app.OnRecordAfterUpdateSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbWebhooksUpdate__",
+		Func: func(e *RecordEvent) error {
+			enqueueWebhookDeliveries(e.App, e.Record, WebhookEventUpdate, nil)
+
+			return e.Next()
+		},
+		Priority: 999,
+	})
+
+	// the delete webhook filters are evaluated before the record deletion
+	// and the matching webhooks are delivered only after a successful delete
+	app.OnRecordDelete().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbWebhooksDelete__",
+		Func: func(e *RecordEvent) error {
+			webhooks := findMatchingWebhooks(e.App, e.Record, WebhookEventDelete)
+			if len(webhooks) > 0 {
+				e.Record.SetRaw(pendingDeleteWebhooksKey, webhooks)
+			}
+
+			err := e.Next()
+			if err != nil {
+				e.Record.SetRaw(pendingDeleteWebhooksKey, nil)
+			}
+
+			return err
+		},
+		Priority: -999,
+	})
+
+	app.OnRecordAfterDeleteSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbWebhooksDeleteSuccess__",
+		Func: func(e *RecordEvent) error {
+			if webhooks, _ := e.Record.GetRaw(pendingDeleteWebhooksKey).([]*Webhook); len(webhooks) > 0 {
+				e.Record.SetRaw(pendingDeleteWebhooksKey, nil)
+				enqueueWebhookDeliveries(e.App, e.Record, WebhookEventDelete, webhooks)
+			}
+
+			return e.Next()
+		},
+		Priority: 999,
+	})
+
+	app.OnRecordAfterDeleteError().Bind(&hook.Handler[*RecordErrorEvent]{
+		Id: "__pbWebhooksDeleteError__",
+		Func: func(e *RecordErrorEvent) error {
+			e.Record.SetRaw(pendingDeleteWebhooksKey, nil)
+
+			return e.Next()
+		},
+		Priority: 999,
+	})
+
+	// periodically cleanup the old webhook delivery log entries
+	app.Cron().Add("__pbWebhookLogsCleanup__", "30 */6 * * *", func() {
+		err := app.DeleteOldWebhookLogs(time.Now().Add(-webhookLogsMaxAge))
+		if err != nil {
+			app.Logger().Warn("Failed to delete old webhook logs", "error", err)
+		}
+	})
+}
+
+// findMatchingWebhooks returns the enabled webhooks of the record collection
+// that are subscribed for the specified event and whose filter matches the record.
+func findMatchingWebhooks(app App, record *Record, event string) []*Webhook {
+	collection := record.Collection()
+	if collection.Name == CollectionNameWebhooks || collection.IsView() {
+		return nil
+	}
+
+	if _, err := app.FindCachedCollectionByNameOrId(CollectionNameWebhooks); err != nil {
+		return nil // e.g. not migrated yet
+	}
+
+	records, err := app.FindAllRecords(CollectionNameWebhooks, dbx.HashExp{
+		"collectionRef": collection.Id,
+		"enabled":       true,
+	})
+	if err != nil {
+		app.Logger().Warn("Failed to load the record webhooks", "error", err, "collectionName", collection.Name)
+		return nil
+	}
+
+	result := make([]*Webhook, 0, len(records))
+
+	for _, r := range records {
+		webhook := &Webhook{Record: r}
+
+		if !webhook.HasEvent(event) {
+			continue
+		}
+
+		if filter := webhook.Filter(); filter != "" {
+			// note: nil requestInfo to evaluate the filter as system (aka. not tenant scoped)
+			ok, err := app.CanAccessRecord(record, nil, &filter)
+			if err != nil {
+				app.Logger().Warn("Failed to evaluate the webhook filter", "error", err, "webhookId", webhook.Id)
+				continue
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		result = append(result, webhook)
+	}
+
+	return result
+}
+
+// enqueueWebhookDeliveries enqueues a delivery job for each of the provided webhooks.
+//
+// If webhooks is nil, the matching record webhooks are loaded with [findMatchingWebhooks].
+func enqueueWebhookDeliveries(app App, record *Record, event string, webhooks []*Webhook) {
+	if webhooks == nil {
+		webhooks = findMatchingWebhooks(app, record, event)
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	rawRecord, err := json.Marshal(record)
+	if err != nil {
+		app.Logger().Warn("Failed to serialize the webhook record", "error", err, "recordId", record.Id)
+		return
+	}
+
+	body, err := json.Marshal(WebhookPayload{
+		Event:          event,
+		CollectionId:   record.Collection().Id,
+		CollectionName: record.Collection().Name,
+		Record:         rawRecord,
+	})
+	if err != nil {
+		app.Logger().Warn("Failed to serialize the webhook payload", "error", err, "recordId", record.Id)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		_, err := app.EnqueueJob(webhookDeliveryJobName, webhookDelivery{
+			WebhookId: webhook.Id,
+			RecordId:  record.Id,
+			Event:     event,
+			Body:      body,
+		}, nil)
+		if err != nil {
+			app.Logger().Warn(
+				"Failed to enqueue webhook delivery",
+				"error", err,
+				"webhookId", webhook.Id,
+				"recordId", record.Id,
+				"event", event,
+			)
+		}
+	}
+}
+
+// deliverWebhook is the webhook delivery job handler.
+//
+// It sends the signed delivery request and stores its outcome in the webhook logs.
+// Returning an error lets the job queue retry the delivery with backoff.
+func (app *BaseApp) deliverWebhook(ctx context.Context, job *Job) error {
+	delivery := webhookDelivery{}
+	if err := job.UnmarshalPayload(&delivery); err != nil {
+		return err
+	}
+
+	record, err := app.FindRecordById(CollectionNameWebhooks, delivery.WebhookId)
+	if err != nil {
+		return nil // the webhook was deleted in the meantime
+	}
+
+	webhook := &Webhook{Record: record}
+	if !webhook.Enabled() {
+		return nil
+	}
+
+	start := time.Now()
+
+	status, sendErr := sendWebhookRequest(ctx, webhook, job.Id, delivery)
+
+	payload := WebhookPayload{}
+	_ = json.Unmarshal(delivery.Body, &payload)
+
+	log := &WebhookLog{
+		WebhookRef: webhook.Id,
+		DeliveryId: job.Id,
+		Event:      delivery.Event,
+		Collection: payload.CollectionName,
+		RecordRef:  delivery.RecordId,
+		URL:        webhook.URL(),
+		Status:     status,
+		Attempt:    job.Attempts,
+		Duration:   float64(time.Since(start)) / float64(time.Millisecond),
+		Created:    types.NowDateTime(),
+	}
+	log.Id = GenerateDefaultRandomId()
+	if sendErr != nil {
+		log.Error = sendErr.Error()
+	}
+
+	if err := app.AuxSave(log); err != nil {
+		app.Logger().Warn("Failed to save webhook log", "error", err, "webhookId", webhook.Id)
+	}
+
+	return sendErr
+}
+
+// sendWebhookRequest sends a single webhook delivery request and
+// returns its response status code.
+//
+// Non-2xx responses are reported as error.
+func sendWebhookRequest(ctx context.Context, webhook *Webhook, deliveryId string, delivery webhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL(), bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PocketBase-Webhooks")
+	req.Header.Set(WebhookHeaderId, webhook.Id)
+	req.Header.Set(WebhookHeaderEvent, delivery.Event)
+	req.Header.Set(WebhookHeaderDelivery, deliveryId)
+	req.Header.Set(WebhookHeaderTimestamp, timestamp)
+	if secret := webhook.Secret(); secret != "" {
+		req.Header.Set(WebhookHeaderSignature, WebhookSignature(secret, timestamp, delivery.Body))
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, webhookMaxErrorBodySize))
+		return res.StatusCode, fmt.Errorf("unexpected response status %d: %s", res.StatusCode, bytes.TrimSpace(resBody))
+	}
+
+	// drain the body to allow the connection reuse
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<20))
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,369 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestWebhookValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view1, err := app.FindCollectionByNameOrId("view1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhooks, err := app.FindCollectionByNameOrId(core.CollectionNameWebhooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		collectionRef  string
+		events         []string
+		filter         string
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			"",
+			[]string{"collectionRef", "events"},
+		},
+		{
+			"missing collection",
+			"missing",
+			[]string{core.WebhookEventCreate},
+			"",
+			[]string{"collectionRef"},
+		},
+		{
+			"view collection",
+			view1.Id,
+			[]string{core.WebhookEventCreate},
+			"",
+			[]string{"collectionRef"},
+		},
+		{
+			"webhooks collection",
+			webhooks.Id,
+			[]string{core.WebhookEventCreate},
+			"",
+			[]string{"collectionRef"},
+		},
+		{
+			"invalid event",
+			demo2.Id,
+			[]string{"invalid"},
+			"",
+			[]string{"events"},
+		},
+		{
+			"invalid filter",
+			demo2.Id,
+			[]string{core.WebhookEventCreate},
+			"missing = 1",
+			[]string{"filter"},
+		},
+		{
+			"valid filter",
+			demo2.Id,
+			[]string{core.WebhookEventCreate, core.WebhookEventDelete},
+			"active = true && title != ''",
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			webhook := core.NewWebhook(app)
+			webhook.SetURL("https://example.com")
+			webhook.SetCollectionRef(s.collectionRef)
+			webhook.SetEvents(s.events)
+			webhook.SetFilter(s.filter)
+
+			tests.TestValidationErrors(t, app.Validate(webhook), s.expectedErrors)
+		})
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	type receivedRequest struct {
+		headers http.Header
+		body    []byte
+	}
+
+	var mu sync.Mutex
+	var received []receivedRequest
+	failStatus := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		received = append(received, receivedRequest{headers: r.Header.Clone(), body: body})
+		status := failStatus
+		mu.Unlock()
+
+		if status > 0 {
+			w.WriteHeader(status)
+			w.Write([]byte("test_failure"))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhook := core.NewWebhook(app)
+	webhook.SetName("test")
+	webhook.SetURL(server.URL)
+	webhook.SetCollectionRef(demo2.Id)
+	webhook.SetEvents([]string{core.WebhookEventCreate, core.WebhookEventDelete})
+	webhook.SetFilter("active = true")
+	webhook.SetSecret("test_secret")
+	webhook.SetEnabled(true)
+	if err := app.Save(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled webhooks must be ignored
+	disabled := core.NewWebhook(app)
+	disabled.SetURL(server.URL)
+	disabled.SetCollectionRef(demo2.Id)
+	disabled.SetEvents([]string{core.WebhookEventCreate})
+	if err := app.Save(disabled); err != nil {
+		t.Fatal(err)
+	}
+
+	processAndCollect := func(t *testing.T) []receivedRequest {
+		if err := app.ProcessJobs(); err != nil {
+			t.Fatal(err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		result := received
+		received = nil
+
+		return result
+	}
+
+	t.Run("non-matching filter", func(t *testing.T) {
+		record := core.NewRecord(demo2)
+		record.Set("title", "inactive")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if reqs := processAndCollect(t); len(reqs) != 0 {
+			t.Fatalf("Expected no deliveries, got %d", len(reqs))
+		}
+	})
+
+	t.Run("not subscribed event", func(t *testing.T) {
+		record, err := app.FindRecordById(demo2, "achvryl401bhse3")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		record.Set("title", "updated")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if reqs := processAndCollect(t); len(reqs) != 0 {
+			t.Fatalf("Expected no deliveries, got %d", len(reqs))
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		record := core.NewRecord(demo2)
+		record.Set("title", "active")
+		record.Set("active", true)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		reqs := processAndCollect(t)
+		if len(reqs) != 1 {
+			t.Fatalf("Expected 1 delivery, got %d", len(reqs))
+		}
+
+		req := reqs[0]
+
+		if v := req.headers.Get(core.WebhookHeaderId); v != webhook.Id {
+			t.Fatalf("Expected webhook id header %q, got %q", webhook.Id, v)
+		}
+
+		if v := req.headers.Get(core.WebhookHeaderEvent); v != core.WebhookEventCreate {
+			t.Fatalf("Expected event header %q, got %q", core.WebhookEventCreate, v)
+		}
+
+		if v := req.headers.Get(core.WebhookHeaderDelivery); v == "" {
+			t.Fatal("Expected non-empty delivery header")
+		}
+
+		expectedSignature := core.WebhookSignature("test_secret", req.headers.Get(core.WebhookHeaderTimestamp), req.body)
+		if v := req.headers.Get(core.WebhookHeaderSignature); v != expectedSignature {
+			t.Fatalf("Expected signature %q, got %q", expectedSignature, v)
+		}
+
+		payload := core.WebhookPayload{}
+		if err := json.Unmarshal(req.body, &payload); err != nil {
+			t.Fatal(err)
+		}
+
+		if payload.Event != core.WebhookEventCreate || payload.CollectionId != demo2.Id || payload.CollectionName != demo2.Name {
+			t.Fatalf("Unexpected payload %s", req.body)
+		}
+
+		if !strings.Contains(string(payload.Record), `"id":"`+record.Id+`"`) {
+			t.Fatalf("Expected the payload record to be %q, got %s", record.Id, payload.Record)
+		}
+
+		log := &core.WebhookLog{}
+		err := app.AuxModelQuery(log).AndWhere(dbx.HashExp{"recordRef": record.Id}).One(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if log.WebhookRef != webhook.Id || log.Status != http.StatusNoContent || log.Error != "" || log.Attempt != 1 {
+			t.Fatalf("Unexpected webhook log %#v", log)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		record, err := app.FindRecordById(demo2, "0yxhwia2amd8gec")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// rolled back delete
+		txErr := app.RunInTransaction(func(txApp core.App) error {
+			if err := txApp.Delete(record); err != nil {
+				return err
+			}
+			return errors.New("rollback")
+		})
+		if txErr == nil {
+			t.Fatal("Expected rollback error")
+		}
+
+		if reqs := processAndCollect(t); len(reqs) != 0 {
+			t.Fatalf("Expected no deliveries after rollback, got %d", len(reqs))
+		}
+
+		if v := record.GetRaw("@pbInternal_pendingDeleteWebhooks"); v != nil {
+			t.Fatalf("Expected the pending delete webhooks to be cleared, got %v", v)
+		}
+
+		if err := app.Delete(record); err != nil {
+			t.Fatal(err)
+		}
+
+		reqs := processAndCollect(t)
+		if len(reqs) != 1 {
+			t.Fatalf("Expected 1 delivery, got %d", len(reqs))
+		}
+
+		if v := reqs[0].headers.Get(core.WebhookHeaderEvent); v != core.WebhookEventDelete {
+			t.Fatalf("Expected event header %q, got %q", core.WebhookEventDelete, v)
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		mu.Lock()
+		failStatus = http.StatusInternalServerError
+		mu.Unlock()
+
+		record := core.NewRecord(demo2)
+		record.Set("title", "failure")
+		record.Set("active", true)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if reqs := processAndCollect(t); len(reqs) != 1 {
+			t.Fatalf("Expected 1 delivery, got %d", len(reqs))
+		}
+
+		log := &core.WebhookLog{}
+		err := app.AuxModelQuery(log).AndWhere(dbx.HashExp{"recordRef": record.Id}).One(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if log.Status != http.StatusInternalServerError || !strings.Contains(log.Error, "test_failure") {
+			t.Fatalf("Unexpected webhook log %#v", log)
+		}
+
+		job, err := app.FindJobById(log.DeliveryId)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job.Status != core.JobStatusPending || job.Attempts != 1 {
+			t.Fatalf("Expected the delivery job to be rescheduled, got status %q and %d attempts", job.Status, job.Attempts)
+		}
+	})
+}
+
+func TestWebhooksDeleteWithCollection(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_webhooks")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	webhook := core.NewWebhook(app)
+	webhook.SetURL("https://example.com")
+	webhook.SetCollectionRef(collection.Id)
+	webhook.SetEvents([]string{core.WebhookEventCreate})
+	if err := app.Save(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordById(core.CollectionNameWebhooks, webhook.Id); err == nil {
+		t.Fatal("Expected the webhook to be deleted")
+	}
+}
@@ -111,6 +111,16 @@ func (form *RecordUpsert) Load(data map[string]any) {
 			continue
 		}
 
+		// skip the read-only database computed fields (eg. formula)
+		if g, ok := form.record.Collection().Fields.GetByName(k).(core.GeneratedValuer); ok && g.IsGenerated() {
+			continue
+		}
+
+		// skip the read-only runtime resolved fields (eg. backRelation)
+		if v, ok := form.record.Collection().Fields.GetByName(k).(core.VirtualValuer); ok && v.IsVirtual() {
+			continue
+		}
+
 		// set only known collection fields
 		field := form.record.SetIfFieldExists(k, v)
 
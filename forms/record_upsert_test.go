@@ -887,6 +887,39 @@ func TestRecordUpsertSubmitSuccess(t *testing.T) {
 	testFilesCount(t, testApp, record, 2) // the file + attrs
 }
 
+func TestRecordUpsertSubmitFormulaField(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	col := core.NewBaseCollection("test_formula")
+	col.Fields.Add(
+		&core.NumberField{Name: "qty"},
+		&core.NumberField{Name: "price"},
+		&core.FormulaField{Name: "total", Expression: "qty * price", ReturnType: core.FormulaReturnTypeNumber},
+	)
+	if err := testApp.Save(col); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(col)
+
+	form := forms.NewRecordUpsert(testApp, record)
+	form.GrantSuperuserAccess()
+	form.Load(map[string]any{
+		"qty":   3,
+		"price": 5,
+		"total": 999, // should be ignored
+	})
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Expected Submit success, got error: %v", err)
+	}
+
+	if v := record.GetFloat("total"); v != 15 {
+		t.Fatalf("Expected the computed total %v, got %v", 15, v)
+	}
+}
+
 func TestRecordUpsertPasswordsSync(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
@@ -7,6 +7,7 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/mails"
+	"golang.org/x/text/language"
 )
 
 const (
@@ -24,6 +25,7 @@ type TestEmailSend struct {
 	Email      string `form:"email" json:"email"`
 	Template   string `form:"template" json:"template"`
 	Collection string `form:"collection" json:"collection"` // optional, fallbacks to _superusers
+	Locale     string `form:"locale" json:"locale"`         // optional, the localized template variant to send
 }
 
 // NewTestEmailSend creates and initializes new TestEmailSend form.
@@ -56,9 +58,23 @@ func (form *TestEmailSend) Validate() error {
 				TestTemplateAuthAlert,
 			),
 		),
+		validation.Field(&form.Locale, validation.Length(0, 35), validation.By(checkLocale)),
 	)
 }
 
+func checkLocale(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := language.Parse(v); err != nil {
+		return validation.NewError("validation_invalid_locale", "Invalid BCP 47 language tag.")
+	}
+
+	return nil
+}
+
 func (form *TestEmailSend) checkAuthCollection(value any) error {
 	v, _ := value.(string)
 	if v == "" {
@@ -99,17 +115,22 @@ func (form *TestEmailSend) Submit() error {
 	record.RefreshTokenKey()
 	record.SetEmail(form.Email)
 
+	var locales []string
+	if form.Locale != "" {
+		locales = append(locales, form.Locale)
+	}
+
 	switch form.Template {
 	case TestTemplateVerification:
-		return mails.SendRecordVerification(form.app, record)
+		return mails.SendRecordVerification(form.app, record, locales...)
 	case TestTemplatePasswordReset:
-		return mails.SendRecordPasswordReset(form.app, record)
+		return mails.SendRecordPasswordReset(form.app, record, locales...)
 	case TestTemplateEmailChange:
-		return mails.SendRecordChangeEmail(form.app, record, form.Email)
+		return mails.SendRecordChangeEmail(form.app, record, form.Email, locales...)
 	case TestTemplateOTP:
-		return mails.SendRecordOTP(form.app, record, "_PB_TEST_OTP_ID_", "123456")
+		return mails.SendRecordOTP(form.app, record, "_PB_TEST_OTP_ID_", "123456", locales...)
 	case TestTemplateAuthAlert:
-		return mails.SendRecordAuthAlert(form.app, record)
+		return mails.SendRecordAuthAlert(form.app, record, locales...)
 	default:
 		return errors.New("unknown template " + form.Template)
 	}
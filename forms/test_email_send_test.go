@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/tests"
 )
@@ -94,3 +95,54 @@ func TestEmailSendValidateAndSubmit(t *testing.T) {
 		})
 	}
 }
+
+func TestEmailSendLocale(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		locale          string
+		expectedSubject string
+		expectedErrors  []string
+	}{
+		{"invalid locale", "", []string{"locale"}},
+		{"", "Verify your acme_test email", nil},
+		{"fr", "Verify your acme_test email", nil},
+		{"de-AT", "de_subject", nil},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.locale, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			users, err := app.FindCollectionByNameOrId("users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			users.VerificationTemplate.Locales = map[string]core.LocalizedEmailTemplate{
+				"de": {Subject: "de_subject", Body: "de_body"},
+			}
+			if err := app.Save(users); err != nil {
+				t.Fatal(err)
+			}
+
+			form := forms.NewTestEmailSend(app)
+			form.Email = "test@example.com"
+			form.Template = forms.TestTemplateVerification
+			form.Collection = "users"
+			form.Locale = s.locale
+
+			result := form.Submit()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+
+			if len(s.expectedErrors) > 0 {
+				return
+			}
+
+			if subject := app.TestMailer.LastMessage().Subject; subject != s.expectedSubject {
+				t.Fatalf("Expected subject %q, got %q", s.expectedSubject, subject)
+			}
+		})
+	}
+}
@@ -12,10 +12,48 @@ import (
 )
 
 // SendRecordAuthAlert sends a new device login alert to the specified auth record.
-func SendRecordAuthAlert(app core.App, authRecord *core.Record) error {
+//
+// The optional requestLocales (ex. from the Accept-Language header) are used
+// for picking the localized template variant (see [core.EmailLocaleConfig]).
+func SendRecordAuthAlert(app core.App, authRecord *core.Record, requestLocales ...string) error {
+	mailClient := app.NewMailClient()
+
+	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().AuthAlert.EmailTemplate, nil, requestLocales)
+	if err != nil {
+		return err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: authRecord.Email()}},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.App = app
+	event.Mailer = mailClient
+	event.Message = message
+	event.Record = authRecord
+
+	return app.OnMailerRecordAuthAlertSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		return e.Mailer.Send(e.Message)
+	})
+}
+
+// SendRecordAuthLockoutAlert sends a temporary lockout alert to the specified auth record.
+//
+// The optional requestLocales (ex. from the Accept-Language header) are used
+// for picking the localized template variant (see [core.EmailLocaleConfig]).
+func SendRecordAuthLockoutAlert(app core.App, authRecord *core.Record, lockout *core.AuthLockout, requestLocales ...string) error {
 	mailClient := app.NewMailClient()
 
-	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().AuthAlert.EmailTemplate, nil)
+	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().AuthAlert.LockoutEmailTemplate, map[string]any{
+		core.EmailPlaceholderLockedUntil: lockout.LockedUntil.String(),
+	}, requestLocales)
 	if err != nil {
 		return err
 	}
@@ -35,6 +73,9 @@ func SendRecordAuthAlert(app core.App, authRecord *core.Record) error {
 	event.Mailer = mailClient
 	event.Message = message
 	event.Record = authRecord
+	event.Meta = map[string]any{
+		"lockout": lockout,
+	}
 
 	return app.OnMailerRecordAuthAlertSend().Trigger(event, func(e *core.MailerRecordEvent) error {
 		return e.Mailer.Send(e.Message)
@@ -44,13 +85,16 @@ func SendRecordAuthAlert(app core.App, authRecord *core.Record) error {
 // SendRecordOTP sends OTP email to the specified auth record.
 //
 // This method will also update the "sentTo" field of the related OTP record to the mail sent To address (if the OTP exists and not already assigned).
-func SendRecordOTP(app core.App, authRecord *core.Record, otpId string, pass string) error {
+//
+// The optional requestLocales (ex. from the Accept-Language header) are used
+// for picking the localized template variant (see [core.EmailLocaleConfig]).
+func SendRecordOTP(app core.App, authRecord *core.Record, otpId string, pass string, requestLocales ...string) error {
 	mailClient := app.NewMailClient()
 
 	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().OTP.EmailTemplate, map[string]any{
 		core.EmailPlaceholderOTPId: otpId,
 		core.EmailPlaceholderOTP:   pass,
-	})
+	}, requestLocales)
 	if err != nil {
 		return err
 	}
@@ -118,7 +162,10 @@ func SendRecordOTP(app core.App, authRecord *core.Record, otpId string, pass str
 }
 
 // SendRecordPasswordReset sends a password reset request email to the specified auth record.
-func SendRecordPasswordReset(app core.App, authRecord *core.Record) error {
+//
+// The optional requestLocales (ex. from the Accept-Language header) are used
+// for picking the localized template variant (see [core.EmailLocaleConfig]).
+func SendRecordPasswordReset(app core.App, authRecord *core.Record, requestLocales ...string) error {
 	token, tokenErr := authRecord.NewPasswordResetToken()
 	if tokenErr != nil {
 		return tokenErr
@@ -128,7 +175,7 @@ func SendRecordPasswordReset(app core.App, authRecord *core.Record) error {
 
 	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().ResetPasswordTemplate, map[string]any{
 		core.EmailPlaceholderToken: token,
-	})
+	}, requestLocales)
 	if err != nil {
 		return err
 	}
@@ -156,7 +203,10 @@ func SendRecordPasswordReset(app core.App, authRecord *core.Record) error {
 }
 
 // SendRecordVerification sends a verification request email to the specified auth record.
-func SendRecordVerification(app core.App, authRecord *core.Record) error {
+//
+// The optional requestLocales (ex. from the Accept-Language header) are used
+// for picking the localized template variant (see [core.EmailLocaleConfig]).
+func SendRecordVerification(app core.App, authRecord *core.Record, requestLocales ...string) error {
 	token, tokenErr := authRecord.NewVerificationToken()
 	if tokenErr != nil {
 		return tokenErr
@@ -166,7 +216,7 @@ func SendRecordVerification(app core.App, authRecord *core.Record) error {
 
 	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().VerificationTemplate, map[string]any{
 		core.EmailPlaceholderToken: token,
-	})
+	}, requestLocales)
 	if err != nil {
 		return err
 	}
@@ -194,7 +244,10 @@ func SendRecordVerification(app core.App, authRecord *core.Record) error {
 }
 
 // SendRecordChangeEmail sends a change email confirmation email to the specified auth record.
-func SendRecordChangeEmail(app core.App, authRecord *core.Record, newEmail string) error {
+//
+// The optional requestLocales (ex. from the Accept-Language header) are used
+// for picking the localized template variant (see [core.EmailLocaleConfig]).
+func SendRecordChangeEmail(app core.App, authRecord *core.Record, newEmail string, requestLocales ...string) error {
 	token, tokenErr := authRecord.NewEmailChangeToken(newEmail)
 	if tokenErr != nil {
 		return tokenErr
@@ -204,7 +257,7 @@ func SendRecordChangeEmail(app core.App, authRecord *core.Record, newEmail strin
 
 	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().ConfirmEmailChangeTemplate, map[string]any{
 		core.EmailPlaceholderToken: token,
-	})
+	}, requestLocales)
 	if err != nil {
 		return err
 	}
@@ -241,12 +294,19 @@ var nonescapeTypes = []string{
 	core.FieldTypeNumber,
 }
 
+// resolveEmailTemplate picks the localized emailTemplate variant for the
+// auth record (see [core.EmailLocaleConfig]) and resolves its placeholders.
 func resolveEmailTemplate(
 	app core.App,
 	authRecord *core.Record,
 	emailTemplate core.EmailTemplate,
 	placeholders map[string]any,
+	requestLocales []string,
 ) (subject string, body string, err error) {
+	emailTemplate = emailTemplate.Localize(
+		authRecord.Collection().EmailLocale.Locales(authRecord, requestLocales...)...,
+	)
+
 	if placeholders == nil {
 		placeholders = map[string]any{}
 	}
@@ -4,9 +4,12 @@ import (
 	"html"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/mails"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestSendRecordAuthAlert(t *testing.T) {
@@ -42,6 +45,37 @@ func TestSendRecordAuthAlert(t *testing.T) {
 	}
 }
 
+func TestSendRecordAuthLockoutAlert(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.FindFirstRecordByData("users", "email", "test@example.com")
+
+	lockout := core.NewAuthLockout(user)
+	lockout.LockedUntil = types.NowDateTime().Add(time.Hour)
+
+	err := mails.SendRecordAuthLockoutAlert(testApp, user, lockout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	expectedParts := []string{
+		"Your " + testApp.Settings().Meta.AppName + " account has been temporary locked until " + lockout.LockedUntil.String(),
+		"too many failed login attempts",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(testApp.TestMailer.LastMessage().HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, testApp.TestMailer.LastMessage().HTML)
+		}
+	}
+}
+
 func TestSendRecordPasswordReset(t *testing.T) {
 	t.Parallel()
 
@@ -166,3 +200,63 @@ func TestSendRecordOTP(t *testing.T) {
 		}
 	}
 }
+
+func TestSendRecordVerificationLocalized(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	users, err := testApp.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Fields.Add(&core.TextField{Name: "locale"})
+	users.EmailLocale.Field = "locale"
+	users.EmailLocale.Fallbacks = []string{"pt"}
+	users.VerificationTemplate.Locales = map[string]core.LocalizedEmailTemplate{
+		"de": {Subject: "de_subject", Body: "de_body {APP_NAME}"},
+		"pt": {Subject: "pt_subject", Body: "pt_body {APP_NAME}"},
+	}
+	if err := testApp.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name            string
+		recordLocale    string
+		requestLocales  []string
+		expectedSubject string
+	}{
+		{"fallback locale", "", nil, "pt_subject"},
+		{"request locale", "", []string{"fr", "de-AT"}, "de_subject"},
+		{"record locale with priority over the request one", "de", []string{"pt"}, "de_subject"},
+		{"unknown record locale", "fr", []string{"fr"}, "pt_subject"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user, err := testApp.FindFirstRecordByData("users", "email", "test@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			user.Set("locale", s.recordLocale)
+
+			err = mails.SendRecordVerification(testApp, user, s.requestLocales...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			message := testApp.TestMailer.LastMessage()
+
+			if message.Subject != s.expectedSubject {
+				t.Fatalf("Expected subject %q, got %q", s.expectedSubject, message.Subject)
+			}
+
+			expectedBody := strings.TrimSuffix(s.expectedSubject, "_subject") + "_body " + testApp.Settings().Meta.AppName
+			if !strings.Contains(message.HTML, expectedBody) {
+				t.Fatalf("Expected %q in\n%s", expectedBody, message.HTML)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// create the _apiKeys system collection (if not already)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		if txApp.HasTable(core.CollectionNameAPIKeys) {
+			return nil // already exists
+		}
+
+		return createAPIKeysCollection(txApp)
+	}, func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNameAPIKeys)
+		if err != nil {
+			return nil // already deleted
+		}
+
+		// system collections can't be deleted with the regular Delete() method
+		collection.System = false
+
+		return txApp.Delete(collection)
+	})
+}
+
+func createAPIKeysCollection(txApp core.App) error {
+	col := core.NewBaseCollection(core.CollectionNameAPIKeys)
+	col.System = true
+
+	ownerRule := "@request.auth.id != '' && recordRef = @request.auth.id && collectionRef = @request.auth.collectionId"
+	col.ListRule = types.Pointer(ownerRule)
+	col.ViewRule = types.Pointer(ownerRule)
+	col.CreateRule = types.Pointer(ownerRule + " && @request.body.lastUsed:isset = false")
+	col.UpdateRule = types.Pointer(ownerRule +
+		" && @request.body.collectionRef:isset = false" +
+		" && @request.body.recordRef:isset = false" +
+		" && @request.body.keyHash:isset = false" +
+		" && @request.body.lastUsed:isset = false")
+	col.DeleteRule = types.Pointer(ownerRule)
+
+	col.Fields.Add(&core.TextField{
+		Name:     "collectionRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "recordRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:   "name",
+		System: true,
+		Max:    255,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "keyHash",
+		System:   true,
+		Hidden:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.JSONField{
+		Name:    "scopes",
+		System:  true,
+		MaxSize: 10000,
+	})
+	col.Fields.Add(&core.DateField{
+		Name:   "expires",
+		System: true,
+	})
+	col.Fields.Add(&core.DateField{
+		Name:   "lastUsed",
+		System: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "created",
+		System:   true,
+		OnCreate: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "updated",
+		System:   true,
+		OnCreate: true,
+		OnUpdate: true,
+	})
+	col.AddIndex("idx_apiKeys_keyHash", true, "keyHash", "")
+	col.AddIndex("idx_apiKeys_collectionRef_recordRef", false, "collectionRef, recordRef", "")
+
+	return txApp.Save(col)
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the _passwordHistory system collection (if not already)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		if txApp.HasTable(core.CollectionNamePasswordHistory) {
+			return nil // already exists
+		}
+
+		return createPasswordHistoryCollection(txApp)
+	}, func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNamePasswordHistory)
+		if err != nil {
+			return nil // already deleted
+		}
+
+		// system collections can't be deleted with the regular Delete() method
+		collection.System = false
+
+		return txApp.Delete(collection)
+	})
+}
+
+func createPasswordHistoryCollection(txApp core.App) error {
+	col := core.NewBaseCollection(core.CollectionNamePasswordHistory)
+	col.System = true
+
+	// superusers only
+	col.ListRule = nil
+	col.ViewRule = nil
+	col.CreateRule = nil
+	col.UpdateRule = nil
+	col.DeleteRule = nil
+
+	col.Fields.Add(&core.TextField{
+		Name:     "collectionRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "recordRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "field",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "hash",
+		System:   true,
+		Hidden:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "created",
+		System:   true,
+		OnCreate: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "updated",
+		System:   true,
+		OnCreate: true,
+		OnUpdate: true,
+	})
+	col.AddIndex("idx_passwordHistory_collectionRef_recordRef_field", false, "collectionRef, recordRef, field", "")
+
+	return txApp.Save(col)
+}
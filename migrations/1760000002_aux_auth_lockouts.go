@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the auxiliary _authLockouts table (if not already)
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.AuxDB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_authLockouts}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]     TEXT DEFAULT "" NOT NULL,
+					[[failures]]      INTEGER DEFAULT 0 NOT NULL,
+					[[lockouts]]      INTEGER DEFAULT 0 NOT NULL,
+					[[lockedUntil]]   TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_authLockouts_collectionRef_recordRef on {{_authLockouts}} ([[collectionRef]], [[recordRef]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.AuxDB().DropTable(core.AuthLockoutsTableName).Execute()
+			return err
+		},
+		ReapplyCondition: func(txApp core.App, runner *core.MigrationsRunner, fileName string) (bool, error) {
+			// reapply only if the _authLockouts table doesn't exist
+			exists := txApp.AuxHasTable(core.AuthLockoutsTableName)
+			return !exists, nil
+		},
+	})
+}
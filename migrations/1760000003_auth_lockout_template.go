@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// set the default lockout email template of the existing auth collections
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		collections, err := txApp.FindAllCollections(core.CollectionTypeAuth)
+		if err != nil {
+			return err
+		}
+
+		defaultTemplate := core.NewAuthCollection("dummy").AuthAlert.LockoutEmailTemplate
+
+		for _, collection := range collections {
+			if collection.AuthAlert.LockoutEmailTemplate.Subject != "" || collection.AuthAlert.LockoutEmailTemplate.Body != "" {
+				continue // already set
+			}
+
+			collection.AuthAlert.LockoutEmailTemplate = defaultTemplate
+
+			if err := txApp.SaveNoValidate(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil)
+}
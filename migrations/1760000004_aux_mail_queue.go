@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the auxiliary _mailQueue and _mailLog tables (if not already)
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.AuxDB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_mailQueue}} (
+					[[id]]          TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[message]]     JSON DEFAULT "{}" NOT NULL,
+					[[attempts]]    INTEGER DEFAULT 0 NOT NULL,
+					[[nextAttempt]] TEXT DEFAULT "" NOT NULL,
+					[[created]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_mailQueue_nextAttempt on {{_mailQueue}} ([[nextAttempt]]);
+
+				CREATE TABLE IF NOT EXISTS {{_mailLog}} (
+					[[id]]      TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[mailRef]] TEXT DEFAULT "" NOT NULL,
+					[[to]]      TEXT DEFAULT "" NOT NULL,
+					[[subject]] TEXT DEFAULT "" NOT NULL,
+					[[status]]  TEXT DEFAULT "" NOT NULL,
+					[[attempt]] INTEGER DEFAULT 0 NOT NULL,
+					[[error]]   TEXT DEFAULT "" NOT NULL,
+					[[created]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_mailLog_mailRef on {{_mailLog}} ([[mailRef]]);
+				CREATE INDEX IF NOT EXISTS idx_mailLog_status on {{_mailLog}} ([[status]]);
+				CREATE INDEX IF NOT EXISTS idx_mailLog_created on {{_mailLog}} ([[created]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			if _, err := txApp.AuxDB().DropTable(core.MailLogsTableName).Execute(); err != nil {
+				return err
+			}
+
+			_, err := txApp.AuxDB().DropTable(core.QueuedMailsTableName).Execute()
+			return err
+		},
+		ReapplyCondition: func(txApp core.App, runner *core.MigrationsRunner, fileName string) (bool, error) {
+			// reapply only if any of the tables doesn't exist
+			exists := txApp.AuxHasTable(core.QueuedMailsTableName) && txApp.AuxHasTable(core.MailLogsTableName)
+			return !exists, nil
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// create the _pushSubscriptions system collection (if not already)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		if txApp.HasTable(core.CollectionNamePushSubscriptions) {
+			return nil // already exists
+		}
+
+		return createPushSubscriptionsCollection(txApp)
+	}, func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNamePushSubscriptions)
+		if err != nil {
+			return nil // already deleted
+		}
+
+		// system collections can't be deleted with the regular Delete() method
+		collection.System = false
+
+		return txApp.Delete(collection)
+	})
+}
+
+func createPushSubscriptionsCollection(txApp core.App) error {
+	col := core.NewBaseCollection(core.CollectionNamePushSubscriptions)
+	col.System = true
+
+	ownerRule := "@request.auth.id != '' && recordRef = @request.auth.id && collectionRef = @request.auth.collectionId"
+	col.ListRule = types.Pointer(ownerRule)
+	col.ViewRule = types.Pointer(ownerRule)
+	col.CreateRule = types.Pointer(ownerRule)
+	col.UpdateRule = types.Pointer(ownerRule +
+		" && @request.body.collectionRef:isset = false" +
+		" && @request.body.recordRef:isset = false")
+	col.DeleteRule = types.Pointer(ownerRule)
+
+	col.Fields.Add(&core.TextField{
+		Name:     "collectionRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "recordRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.URLField{
+		Name:     "endpoint",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "p256dh",
+		System:   true,
+		Required: true,
+		Max:      255,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "auth",
+		System:   true,
+		Required: true,
+		Max:      255,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:   "userAgent",
+		System: true,
+		Max:    500,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "created",
+		System:   true,
+		OnCreate: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "updated",
+		System:   true,
+		OnCreate: true,
+		OnUpdate: true,
+	})
+	col.AddIndex("idx_pushSubscriptions_unique_endpoint", true, "collectionRef, recordRef, endpoint", "")
+
+	return txApp.Save(col)
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the auxiliary _jobs table (if not already)
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.AuxDB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_jobs}} (
+					[[id]]          TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[name]]        TEXT DEFAULT "" NOT NULL,
+					[[payload]]     JSON DEFAULT "null" NOT NULL,
+					[[status]]      TEXT DEFAULT "pending" NOT NULL,
+					[[priority]]    INTEGER DEFAULT 0 NOT NULL,
+					[[uniqueKey]]   TEXT DEFAULT "" NOT NULL,
+					[[attempts]]    INTEGER DEFAULT 0 NOT NULL,
+					[[maxAttempts]] INTEGER DEFAULT 1 NOT NULL,
+					[[runAt]]       TEXT DEFAULT "" NOT NULL,
+					[[lastError]]   TEXT DEFAULT "" NOT NULL,
+					[[created]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_jobs_status_runAt on {{_jobs}} ([[status]], [[runAt]]);
+				CREATE INDEX IF NOT EXISTS idx_jobs_name on {{_jobs}} ([[name]]);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_active_uniqueKey on {{_jobs}} ([[uniqueKey]])
+					WHERE [[uniqueKey]] != '' AND [[status]] IN ('pending', 'running');
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.AuxDB().DropTable(core.JobsTableName).Execute()
+			return err
+		},
+		ReapplyCondition: func(txApp core.App, runner *core.MigrationsRunner, fileName string) (bool, error) {
+			// reapply only if the table doesn't exist
+			return !txApp.AuxHasTable(core.JobsTableName), nil
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the _webhooks system collection (if not already)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		if txApp.HasTable(core.CollectionNameWebhooks) {
+			return nil // already exists
+		}
+
+		return createWebhooksCollection(txApp)
+	}, func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNameWebhooks)
+		if err != nil {
+			return nil // already deleted
+		}
+
+		// system collections can't be deleted with the regular Delete() method
+		collection.System = false
+
+		return txApp.Delete(collection)
+	})
+}
+
+func createWebhooksCollection(txApp core.App) error {
+	col := core.NewBaseCollection(core.CollectionNameWebhooks)
+	col.System = true
+
+	// superusers only
+	col.ListRule = nil
+	col.ViewRule = nil
+	col.CreateRule = nil
+	col.UpdateRule = nil
+	col.DeleteRule = nil
+
+	col.Fields.Add(&core.TextField{
+		Name:   "name",
+		System: true,
+		Max:    255,
+	})
+	col.Fields.Add(&core.URLField{
+		Name:     "url",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "collectionRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.SelectField{
+		Name:      "events",
+		System:    true,
+		Required:  true,
+		MaxSelect: 3,
+		Values: []string{
+			core.WebhookEventCreate,
+			core.WebhookEventUpdate,
+			core.WebhookEventDelete,
+		},
+	})
+	col.Fields.Add(&core.TextField{
+		Name:   "filter",
+		System: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:   "secret",
+		System: true,
+		Hidden: true,
+	})
+	col.Fields.Add(&core.BoolField{
+		Name:   "enabled",
+		System: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "created",
+		System:   true,
+		OnCreate: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "updated",
+		System:   true,
+		OnCreate: true,
+		OnUpdate: true,
+	})
+	col.AddIndex("idx_webhooks_collectionRef", false, "collectionRef", "")
+
+	return txApp.Save(col)
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the auxiliary _webhookLogs table (if not already)
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.AuxDB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_webhookLogs}} (
+					[[id]]         TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[webhookRef]] TEXT DEFAULT "" NOT NULL,
+					[[deliveryId]] TEXT DEFAULT "" NOT NULL,
+					[[event]]      TEXT DEFAULT "" NOT NULL,
+					[[collection]] TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]  TEXT DEFAULT "" NOT NULL,
+					[[url]]        TEXT DEFAULT "" NOT NULL,
+					[[status]]     INTEGER DEFAULT 0 NOT NULL,
+					[[error]]      TEXT DEFAULT "" NOT NULL,
+					[[attempt]]    INTEGER DEFAULT 0 NOT NULL,
+					[[duration]]   REAL DEFAULT 0 NOT NULL,
+					[[created]]    TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_webhookLogs_webhookRef on {{_webhookLogs}} ([[webhookRef]]);
+				CREATE INDEX IF NOT EXISTS idx_webhookLogs_deliveryId on {{_webhookLogs}} ([[deliveryId]]);
+				CREATE INDEX IF NOT EXISTS idx_webhookLogs_created on {{_webhookLogs}} ([[created]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.AuxDB().DropTable(core.WebhookLogsTableName).Execute()
+			return err
+		},
+		ReapplyCondition: func(txApp core.App, runner *core.MigrationsRunner, fileName string) (bool, error) {
+			// reapply only if the table doesn't exist
+			return !txApp.AuxHasTable(core.WebhookLogsTableName), nil
+		},
+	})
+}
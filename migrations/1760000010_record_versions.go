@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// create the _recordVersions system collection (if not already)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		if txApp.HasTable(core.CollectionNameRecordVersions) {
+			return nil // already exists
+		}
+
+		return createRecordVersionsCollection(txApp)
+	}, func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNameRecordVersions)
+		if err != nil {
+			return nil // already deleted
+		}
+
+		// system collections can't be deleted with the regular Delete() method
+		collection.System = false
+
+		return txApp.Delete(collection)
+	})
+}
+
+func createRecordVersionsCollection(txApp core.App) error {
+	col := core.NewBaseCollection(core.CollectionNameRecordVersions)
+	col.System = true
+
+	// superusers only
+	col.ListRule = nil
+	col.ViewRule = nil
+	col.CreateRule = nil
+	col.UpdateRule = nil
+	col.DeleteRule = nil
+
+	col.Fields.Add(&core.TextField{
+		Name:     "collectionRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:     "recordRef",
+		System:   true,
+		Required: true,
+	})
+	col.Fields.Add(&core.NumberField{
+		Name:     "version",
+		System:   true,
+		Required: true,
+		OnlyInt:  true,
+	})
+	col.Fields.Add(&core.SelectField{
+		Name:      "action",
+		System:    true,
+		Required:  true,
+		MaxSelect: 1,
+		Values: []string{
+			core.RecordVersionActionUpdate,
+			core.RecordVersionActionDelete,
+		},
+	})
+	col.Fields.Add(&core.JSONField{
+		Name:   "data",
+		System: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:   "actorCollectionRef",
+		System: true,
+	})
+	col.Fields.Add(&core.TextField{
+		Name:   "actorRef",
+		System: true,
+	})
+	col.Fields.Add(&core.AutodateField{
+		Name:     "created",
+		System:   true,
+		OnCreate: true,
+	})
+	col.AddIndex("idx_recordVersions_collectionRef_recordRef_version", true, "collectionRef, recordRef, version", "")
+
+	return txApp.Save(col)
+}
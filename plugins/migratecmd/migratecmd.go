@@ -6,13 +6,13 @@
 // Example usage:
 //
 //	migratecmd.MustRegister(app, app.RootCmd, migratecmd.Config{
-	//		TemplateLang: migratecmd.TemplateLangGo, // default to migratecmd.TemplateLangGo
+//		TemplateLang: migratecmd.TemplateLangJS, // default to migratecmd.TemplateLangGo
 //		Automigrate:  true,
 //		Dir:          "/custom/migrations/dir", // optional template migrations path; default to "pb_migrations" (for JS) and "migrations" (for Go)
 //	})
 //
 //	Note: To allow running JS migrations you'll need to enable first
-.
+//	[jsvm.MustRegister()].
 package migratecmd
 
 import (
@@ -33,15 +33,22 @@ import (
 type Config struct {
 	// Dir specifies the directory with the user defined migrations.
 	//
-	// If not set it fallbacks to a relative "pb_data/../migrations" directory.
+	// If not set it fallbacks to a relative "pb_data/../pb_migrations" (for js)
+	// or "pb_data/../migrations" (for go) directory.
 	Dir string
 
 	// Automigrate specifies whether to enable automigrations.
 	Automigrate bool
 
 	// TemplateLang specifies the template language to use when
-	// generating migrations - go (default).
+	// generating migrations - js or go (default).
 	TemplateLang string
+
+	// SchemaFile specifies the declarative collections schema file (JSON or YAML)
+	// used by the "migrate plan" and "migrate apply" commands.
+	//
+	// If not set it fallbacks to a relative "pb_data/../pb_schema.json" file.
+	SchemaFile string
 }
 
 // MustRegister registers the migratecmd plugin to the provided app instance
@@ -65,7 +72,15 @@ func Register(app core.App, rootCmd *cobra.Command, config Config) error {
 	}
 
 	if p.config.Dir == "" {
-		template, templateErr = p.goBlankTemplate()
+		if p.config.TemplateLang == TemplateLangJS {
+			p.config.Dir = filepath.Join(p.app.DataDir(), "../pb_migrations")
+		} else {
+			p.config.Dir = filepath.Join(p.app.DataDir(), "../migrations")
+		}
+	}
+
+	if p.config.SchemaFile == "" {
+		p.config.SchemaFile = filepath.Join(p.app.DataDir(), "../pb_schema.json")
 	}
 
 	// attach the migrate command
@@ -95,13 +110,15 @@ func (p *plugin) createCommand() *cobra.Command {
 - create name   - creates new blank migration template file
 - collections   - creates new migration file with snapshot of the local collections configuration
 - history-sync  - ensures that the _migrations history table doesn't have references to deleted migration files
+- plan [file]   - prints the changes required to sync the collections with the declarative schema file
+- apply [file]  - applies the declarative schema file changes in a single transaction
 `
 
 	command := &cobra.Command{
 		Use:          "migrate",
 		Short:        "Executes app DB migration scripts",
 		Long:         cmdDesc,
-		ValidArgs:    []string{"up", "down", "create", "collections"},
+		ValidArgs:    []string{"up", "down", "create", "collections", "plan", "apply"},
 		SilenceUsage: true,
 		RunE: func(command *cobra.Command, args []string) error {
 			cmd := ""
@@ -118,6 +135,16 @@ func (p *plugin) createCommand() *cobra.Command {
 				if _, err := p.migrateCollectionsHandler(args[1:], true); err != nil {
 					return err
 				}
+			case "plan":
+				plan, err := p.migratePlanHandler(args[1:])
+				if err != nil {
+					return err
+				}
+				fmt.Print(plan.String())
+			case "apply":
+				if _, err := p.migrateApplyHandler(args[1:], true); err != nil {
+					return err
+				}
 			default:
 				// note: system migrations are always applied as part of the bootstrap process
 				var list = core.MigrationsList{}
@@ -161,7 +188,11 @@ func (p *plugin) migrateCreateHandler(template string, args []string, interactiv
 	// get default create template
 	if template == "" {
 		var templateErr error
-		template, templateErr = p.goBlankTemplate()
+		if p.config.TemplateLang == TemplateLangJS {
+			template, templateErr = p.jsBlankTemplate()
+		} else {
+			template, templateErr = p.goBlankTemplate()
+		}
 		if templateErr != nil {
 			return "", fmt.Errorf("failed to resolve create template: %v", templateErr)
 		}
@@ -195,10 +226,61 @@ func (p *plugin) migrateCollectionsHandler(args []string, interactive bool) (str
 
 	var template string
 	var templateErr error
+	if p.config.TemplateLang == TemplateLangJS {
+		template, templateErr = p.jsSnapshotTemplate(collections)
+	} else {
 		template, templateErr = p.goSnapshotTemplate(collections)
+	}
 	if templateErr != nil {
 		return "", fmt.Errorf("failed to resolve template: %v", templateErr)
 	}
 
 	return p.migrateCreateHandler(template, createArgs, interactive)
 }
+
+func (p *plugin) migratePlanHandler(args []string) (*SchemaPlan, error) {
+	schemaFile := p.config.SchemaFile
+	if len(args) > 0 {
+		schemaFile = args[0]
+	}
+
+	schema, err := LoadSchemaFile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSchemaPlan(p.app, schema)
+}
+
+func (p *plugin) migrateApplyHandler(args []string, interactive bool) (*SchemaPlan, error) {
+	plan, err := p.migratePlanHandler(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if interactive {
+		fmt.Print(plan.String())
+	}
+
+	if !plan.HasChanges() {
+		return plan, nil
+	}
+
+	if interactive {
+		confirm := osutils.YesNoPrompt("Do you really want to apply the above schema changes?", false)
+		if !confirm {
+			fmt.Println("The command has been cancelled")
+			return nil, nil
+		}
+	}
+
+	if err := plan.Apply(p.app); err != nil {
+		return nil, fmt.Errorf("failed to apply the schema changes: %w", err)
+	}
+
+	if interactive {
+		fmt.Println("Successfully applied the schema changes")
+	}
+
+	return plan, nil
+}
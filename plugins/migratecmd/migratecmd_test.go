@@ -32,7 +32,11 @@ migrate((app) => {
         "body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
         "subject": "Login from a new location"
       },
-      "enabled": true
+      "enabled": true,
+      "lockoutEmailTemplate": {
+        "body": "<p>Hello,</p>\n<p>Your {APP_NAME} account has been temporary locked until {LOCKED_UNTIL} because of too many failed login attempts.</p>\n<p>If this wasn't you, someone may be trying to guess your password and you should consider changing it once the lock expires.</p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "subject": "Your account has been temporary locked"
+      }
     },
     "authRule": "",
     "authToken": {
@@ -42,14 +46,20 @@ migrate((app) => {
       "body": "<p>Hello,</p>\n<p>Click on the button below to confirm your new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Confirm new email</a>\n</p>\n<p><i>If you didn't ask to change your email address, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
       "subject": "Confirm your {APP_NAME} new email address"
     },
+    "constraints": null,
     "createRule": null,
     "deleteRule": null,
     "emailChangeToken": {
       "duration": 1800
     },
+    "emailLocale": {
+      "fallbacks": [],
+      "field": ""
+    },
     "fields": [
       {
         "autogeneratePattern": "[a-z0-9]{15}",
+        "encrypted": false,
         "hidden": false,
         "id": "text@TEST_RANDOM",
         "max": 15,
@@ -64,19 +74,28 @@ migrate((app) => {
       },
       {
         "cost": 0,
+        "disallowBreached": false,
         "hidden": true,
+        "historySize": 0,
         "id": "password@TEST_RANDOM",
         "max": 0,
+        "maxAge": 0,
         "min": 8,
+        "minStrength": 0,
         "name": "password",
         "pattern": "",
         "presentable": false,
+        "requireDigit": false,
+        "requireLowercase": false,
+        "requireSymbol": false,
+        "requireUppercase": false,
         "required": true,
         "system": true,
         "type": "password"
       },
       {
         "autogeneratePattern": "[a-zA-Z0-9]{50}",
+        "encrypted": false,
         "hidden": true,
         "id": "text@TEST_RANDOM",
         "max": 60,
@@ -158,7 +177,10 @@ migrate((app) => {
       "enabled": true,
       "identityFields": [
         "email"
-      ]
+      ],
+      "lockoutDuration": 900,
+      "lockoutMaxDuration": 0,
+      "lockoutThreshold": 0
     },
     "passwordResetToken": {
       "duration": 1800
@@ -167,7 +189,22 @@ migrate((app) => {
       "body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
       "subject": "Reset your {APP_NAME} password"
     },
+    "saml": {
+      "emailAttribute": "",
+      "enabled": false,
+      "entityId": "",
+      "idpCertificate": "",
+      "idpEntityId": "",
+      "idpSSOURL": "",
+      "mappedFields": null
+    },
+    "softDelete": {
+      "enabled": false,
+      "retention": 0
+    },
     "system": true,
+    "tenantField": "",
+    "tenantGuestAccess": false,
     "type": "auth",
     "updateRule": null,
     "verificationTemplate": {
@@ -177,6 +214,7 @@ migrate((app) => {
     "verificationToken": {
       "duration": 259200
     },
+    "versioning": false,
     "viewRule": "id = \"1\""
   });
 
@@ -208,7 +246,11 @@ func init() {
 					"body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 					"subject": "Login from a new location"
 				},
-				"enabled": true
+				"enabled": true,
+				"lockoutEmailTemplate": {
+					"body": "<p>Hello,</p>\n<p>Your {APP_NAME} account has been temporary locked until {LOCKED_UNTIL} because of too many failed login attempts.</p>\n<p>If this wasn't you, someone may be trying to guess your password and you should consider changing it once the lock expires.</p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"subject": "Your account has been temporary locked"
+				}
 			},
 			"authRule": "",
 			"authToken": {
@@ -218,14 +260,20 @@ func init() {
 				"body": "<p>Hello,</p>\n<p>Click on the button below to confirm your new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Confirm new email</a>\n</p>\n<p><i>If you didn't ask to change your email address, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 				"subject": "Confirm your {APP_NAME} new email address"
 			},
+			"constraints": null,
 			"createRule": null,
 			"deleteRule": null,
 			"emailChangeToken": {
 				"duration": 1800
 			},
+			"emailLocale": {
+				"fallbacks": [],
+				"field": ""
+			},
 			"fields": [
 				{
 					"autogeneratePattern": "[a-z0-9]{15}",
+					"encrypted": false,
 					"hidden": false,
 					"id": "text@TEST_RANDOM",
 					"max": 15,
@@ -240,19 +288,28 @@ func init() {
 				},
 				{
 					"cost": 0,
+					"disallowBreached": false,
 					"hidden": true,
+					"historySize": 0,
 					"id": "password@TEST_RANDOM",
 					"max": 0,
+					"maxAge": 0,
 					"min": 8,
+					"minStrength": 0,
 					"name": "password",
 					"pattern": "",
 					"presentable": false,
+					"requireDigit": false,
+					"requireLowercase": false,
+					"requireSymbol": false,
+					"requireUppercase": false,
 					"required": true,
 					"system": true,
 					"type": "password"
 				},
 				{
 					"autogeneratePattern": "[a-zA-Z0-9]{50}",
+					"encrypted": false,
 					"hidden": true,
 					"id": "text@TEST_RANDOM",
 					"max": 60,
@@ -334,7 +391,10 @@ func init() {
 				"enabled": true,
 				"identityFields": [
 					"email"
-				]
+				],
+				"lockoutDuration": 900,
+				"lockoutMaxDuration": 0,
+				"lockoutThreshold": 0
 			},
 			"passwordResetToken": {
 				"duration": 1800
@@ -343,7 +403,22 @@ func init() {
 				"body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 				"subject": "Reset your {APP_NAME} password"
 			},
+			"saml": {
+				"emailAttribute": "",
+				"enabled": false,
+				"entityId": "",
+				"idpCertificate": "",
+				"idpEntityId": "",
+				"idpSSOURL": "",
+				"mappedFields": null
+			},
+			"softDelete": {
+				"enabled": false,
+				"retention": 0
+			},
 			"system": true,
+			"tenantField": "",
+			"tenantGuestAccess": false,
 			"type": "auth",
 			"updateRule": null,
 			"verificationTemplate": {
@@ -353,6 +428,7 @@ func init() {
 			"verificationToken": {
 				"duration": 259200
 			},
+			"versioning": false,
 			"viewRule": "id = \"1\""
 		}` + "`" + `
 
@@ -473,7 +549,11 @@ migrate((app) => {
         "body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
         "subject": "Login from a new location"
       },
-      "enabled": true
+      "enabled": true,
+      "lockoutEmailTemplate": {
+        "body": "<p>Hello,</p>\n<p>Your {APP_NAME} account has been temporary locked until {LOCKED_UNTIL} because of too many failed login attempts.</p>\n<p>If this wasn't you, someone may be trying to guess your password and you should consider changing it once the lock expires.</p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "subject": "Your account has been temporary locked"
+      }
     },
     "authRule": "",
     "authToken": {
@@ -483,14 +563,20 @@ migrate((app) => {
       "body": "<p>Hello,</p>\n<p>Click on the button below to confirm your new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Confirm new email</a>\n</p>\n<p><i>If you didn't ask to change your email address, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
       "subject": "Confirm your {APP_NAME} new email address"
     },
+    "constraints": null,
     "createRule": null,
     "deleteRule": null,
     "emailChangeToken": {
       "duration": 1800
     },
+    "emailLocale": {
+      "fallbacks": [],
+      "field": ""
+    },
     "fields": [
       {
         "autogeneratePattern": "[a-z0-9]{15}",
+        "encrypted": false,
         "hidden": false,
         "id": "text@TEST_RANDOM",
         "max": 15,
@@ -505,19 +591,28 @@ migrate((app) => {
       },
       {
         "cost": 0,
+        "disallowBreached": false,
         "hidden": true,
+        "historySize": 0,
         "id": "password@TEST_RANDOM",
         "max": 0,
+        "maxAge": 0,
         "min": 8,
+        "minStrength": 0,
         "name": "password",
         "pattern": "",
         "presentable": false,
+        "requireDigit": false,
+        "requireLowercase": false,
+        "requireSymbol": false,
+        "requireUppercase": false,
         "required": true,
         "system": true,
         "type": "password"
       },
       {
         "autogeneratePattern": "[a-zA-Z0-9]{50}",
+        "encrypted": false,
         "hidden": true,
         "id": "text@TEST_RANDOM",
         "max": 60,
@@ -599,7 +694,10 @@ migrate((app) => {
       "enabled": true,
       "identityFields": [
         "email"
-      ]
+      ],
+      "lockoutDuration": 900,
+      "lockoutMaxDuration": 0,
+      "lockoutThreshold": 0
     },
     "passwordResetToken": {
       "duration": 1800
@@ -608,7 +706,22 @@ migrate((app) => {
       "body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
       "subject": "Reset your {APP_NAME} password"
     },
+    "saml": {
+      "emailAttribute": "",
+      "enabled": false,
+      "entityId": "",
+      "idpCertificate": "",
+      "idpEntityId": "",
+      "idpSSOURL": "",
+      "mappedFields": null
+    },
+    "softDelete": {
+      "enabled": false,
+      "retention": 0
+    },
     "system": false,
+    "tenantField": "",
+    "tenantGuestAccess": false,
     "type": "auth",
     "updateRule": null,
     "verificationTemplate": {
@@ -618,6 +731,7 @@ migrate((app) => {
     "verificationToken": {
       "duration": 259200
     },
+    "versioning": false,
     "viewRule": "id = \"1\""
   });
 
@@ -652,7 +766,11 @@ func init() {
 					"body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 					"subject": "Login from a new location"
 				},
-				"enabled": true
+				"enabled": true,
+				"lockoutEmailTemplate": {
+					"body": "<p>Hello,</p>\n<p>Your {APP_NAME} account has been temporary locked until {LOCKED_UNTIL} because of too many failed login attempts.</p>\n<p>If this wasn't you, someone may be trying to guess your password and you should consider changing it once the lock expires.</p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"subject": "Your account has been temporary locked"
+				}
 			},
 			"authRule": "",
 			"authToken": {
@@ -662,14 +780,20 @@ func init() {
 				"body": "<p>Hello,</p>\n<p>Click on the button below to confirm your new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Confirm new email</a>\n</p>\n<p><i>If you didn't ask to change your email address, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 				"subject": "Confirm your {APP_NAME} new email address"
 			},
+			"constraints": null,
 			"createRule": null,
 			"deleteRule": null,
 			"emailChangeToken": {
 				"duration": 1800
 			},
+			"emailLocale": {
+				"fallbacks": [],
+				"field": ""
+			},
 			"fields": [
 				{
 					"autogeneratePattern": "[a-z0-9]{15}",
+					"encrypted": false,
 					"hidden": false,
 					"id": "text@TEST_RANDOM",
 					"max": 15,
@@ -684,19 +808,28 @@ func init() {
 				},
 				{
 					"cost": 0,
+					"disallowBreached": false,
 					"hidden": true,
+					"historySize": 0,
 					"id": "password@TEST_RANDOM",
 					"max": 0,
+					"maxAge": 0,
 					"min": 8,
+					"minStrength": 0,
 					"name": "password",
 					"pattern": "",
 					"presentable": false,
+					"requireDigit": false,
+					"requireLowercase": false,
+					"requireSymbol": false,
+					"requireUppercase": false,
 					"required": true,
 					"system": true,
 					"type": "password"
 				},
 				{
 					"autogeneratePattern": "[a-zA-Z0-9]{50}",
+					"encrypted": false,
 					"hidden": true,
 					"id": "text@TEST_RANDOM",
 					"max": 60,
@@ -778,7 +911,10 @@ func init() {
 				"enabled": true,
 				"identityFields": [
 					"email"
-				]
+				],
+				"lockoutDuration": 900,
+				"lockoutMaxDuration": 0,
+				"lockoutThreshold": 0
 			},
 			"passwordResetToken": {
 				"duration": 1800
@@ -787,7 +923,22 @@ func init() {
 				"body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 				"subject": "Reset your {APP_NAME} password"
 			},
+			"saml": {
+				"emailAttribute": "",
+				"enabled": false,
+				"entityId": "",
+				"idpCertificate": "",
+				"idpEntityId": "",
+				"idpSSOURL": "",
+				"mappedFields": null
+			},
+			"softDelete": {
+				"enabled": false,
+				"retention": 0
+			},
 			"system": false,
+			"tenantField": "",
+			"tenantGuestAccess": false,
 			"type": "auth",
 			"updateRule": null,
 			"verificationTemplate": {
@@ -797,6 +948,7 @@ func init() {
 			"verificationToken": {
 				"duration": 259200
 			},
+			"versioning": false,
 			"viewRule": "id = \"1\""
 		}` + "`" + `
 
@@ -923,6 +1075,7 @@ migrate((app) => {
   // add field
   collection.fields.addAt(8, new Field({
     "autogeneratePattern": "",
+    "encrypted": false,
     "hidden": false,
     "id": "f4_id",
     "max": 0,
@@ -1054,6 +1207,7 @@ func init() {
 		// add field
 		if err := collection.Fields.AddMarshaledJSONAt(8, []byte(` + "`" + `{
 			"autogeneratePattern": "",
+			"encrypted": false,
 			"hidden": false,
 			"id": "f4_id",
 			"max": 0,
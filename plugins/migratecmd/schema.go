@@ -0,0 +1,406 @@
+package migratecmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SchemaActionCreate = "create"
+	SchemaActionUpdate = "update"
+	SchemaActionDelete = "delete"
+)
+
+// SchemaChange describes a single planned collection change.
+type SchemaChange struct {
+	// Action is one of the SchemaAction* constants.
+	Action string `json:"action"`
+
+	Collection string `json:"collection"`
+
+	// Details lists the human readable field, index, rule and option changes
+	// (it is empty for create and delete actions).
+	Details []string `json:"details"`
+}
+
+// SchemaPlan describes the changes required to sync the app
+// collections with a declarative collections schema.
+type SchemaPlan struct {
+	Changes []*SchemaChange `json:"changes"`
+
+	// Warnings lists the planned changes that could result in data loss.
+	Warnings []string `json:"warnings"`
+
+	schema []map[string]any
+}
+
+// LoadSchemaFile loads the declarative collections schema from the specified JSON or YAML file.
+//
+// The file is expected to contain an array of collections in the
+// same format as the one used by the Dashboard collections import/export.
+//
+// Files with ".yaml" or ".yml" extension are parsed as YAML, otherwise as JSON.
+func LoadSchemaFile(path string) ([]map[string]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		raw, err = yamlToJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schema file %q: %w", path, err)
+		}
+	}
+
+	schema := []map[string]any{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file %q: %w", path, err)
+	}
+
+	return schema, nil
+}
+
+// yamlToJSON converts the raw YAML document into JSON so that both
+// schema formats are normalized the same way (e.g. numbers as float64).
+func yamlToJSON(raw []byte) ([]byte, error) {
+	var data any
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(data)
+}
+
+// NewSchemaPlan diffs the provided declarative collections schema
+// against the current app collections and returns the planned changes.
+//
+// Non-system collections and fields that are missing from the schema
+// are planned for deletion (the same as [core.App.ImportCollections] with deleteMissing).
+func NewSchemaPlan(app core.App, schema []map[string]any) (*SchemaPlan, error) {
+	if len(schema) == 0 {
+		// prevent accidentally deleting all collections
+		return nil, errors.New("no collections in the schema")
+	}
+
+	plan := &SchemaPlan{schema: schema}
+
+	existingCollections, err := app.FindAllCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]struct{}{}
+
+	for _, data := range schema {
+		old, new, err := resolveSchemaCollection(app, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if old == nil {
+			plan.Changes = append(plan.Changes, &SchemaChange{
+				Action:     SchemaActionCreate,
+				Collection: new.Name,
+			})
+			continue
+		}
+
+		found[old.Id] = struct{}{}
+
+		if err := plan.diffCollections(old, new); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range existingCollections {
+		if _, ok := found[c.Id]; ok || c.System {
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, &SchemaChange{
+			Action:     SchemaActionDelete,
+			Collection: c.Name,
+		})
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("collection %q and all of its records will be deleted", c.Name))
+	}
+
+	return plan, nil
+}
+
+// HasChanges reports whether the plan has at least one collection change.
+func (p *SchemaPlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// Apply executes the planned changes in a single transaction
+// by importing the plan schema with [core.App.ImportCollections].
+func (p *SchemaPlan) Apply(app core.App) error {
+	return app.ImportCollections(p.schema, true)
+}
+
+// String returns a human readable representation of the plan.
+func (p *SchemaPlan) String() string {
+	if !p.HasChanges() {
+		return "No schema changes."
+	}
+
+	var sb strings.Builder
+
+	for _, change := range p.Changes {
+		switch change.Action {
+		case SchemaActionCreate:
+			sb.WriteString("+ create collection ")
+		case SchemaActionDelete:
+			sb.WriteString("- delete collection ")
+		default:
+			sb.WriteString("~ update collection ")
+		}
+		sb.WriteString(fmt.Sprintf("%q\n", change.Collection))
+
+		for _, detail := range change.Details {
+			sb.WriteString("    ")
+			sb.WriteString(detail)
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(p.Warnings) > 0 {
+		sb.WriteString("\nWarnings:\n")
+		for _, w := range p.Warnings {
+			sb.WriteString("  ! ")
+			sb.WriteString(w)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// resolveSchemaCollection returns the existing collection (if any) matching the schema data
+// and the collection that will result from the schema import.
+//
+// It mirrors the [core.App.ImportCollections] normalizations with deleteMissing enabled.
+func resolveSchemaCollection(app core.App, data map[string]any) (*core.Collection, *core.Collection, error) {
+	identifier := cast.ToString(data["id"])
+	if identifier == "" {
+		identifier = cast.ToString(data["name"])
+	}
+
+	old, err := app.FindCollectionByNameOrId(identifier)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+
+	if old == nil {
+		new := &core.Collection{}
+		if err := unmarshalSchemaData(data, new); err != nil {
+			return nil, nil, err
+		}
+		return nil, new, nil
+	}
+
+	// refetch for deep copy
+	new, err := app.FindCollectionByNameOrId(old.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ensure that the fields will be cleared
+	if data["fields"] == nil {
+		data = maps.Clone(data)
+		data["fields"] = []map[string]any{}
+	}
+
+	if err := unmarshalSchemaData(data, new); err != nil {
+		return nil, nil, err
+	}
+
+	// preserve the system fields
+	for _, f := range old.Fields {
+		if !f.GetSystem() || new.Fields.GetById(f.GetId()) != nil {
+			continue
+		}
+
+		found := new.Fields.GetByName(f.GetName())
+		if found != nil && found.Type() == f.Type() {
+			found.SetId(f.GetId())
+		}
+		new.Fields.Add(f)
+	}
+
+	return old, new, nil
+}
+
+func unmarshalSchemaData(data map[string]any, collection *core.Collection) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, collection); err != nil {
+		return fmt.Errorf("failed to load schema collection %q: %w", cast.ToString(data["name"]), err)
+	}
+
+	return nil
+}
+
+func (p *SchemaPlan) diffCollections(old, new *core.Collection) error {
+	change := &SchemaChange{Action: SchemaActionUpdate, Collection: old.Name}
+
+	if old.Name != new.Name {
+		change.Details = append(change.Details, fmt.Sprintf("~ rename to %q", new.Name))
+	}
+
+	if old.Type != new.Type {
+		change.Details = append(change.Details, fmt.Sprintf("~ type %s -> %s", old.Type, new.Type))
+		p.Warnings = append(p.Warnings, fmt.Sprintf("collection %q type changes from %s to %s", old.Name, old.Type, new.Type))
+	}
+
+	// fields
+	// ---------------------------------------------------------------
+	for _, oldField := range old.Fields {
+		newField := new.Fields.GetById(oldField.GetId())
+		if newField == nil {
+			change.Details = append(change.Details, fmt.Sprintf("- field %q (%s)", oldField.GetName(), oldField.Type()))
+			p.Warnings = append(p.Warnings, fmt.Sprintf("field %q and its data will be deleted", old.Name+"."+oldField.GetName()))
+			continue
+		}
+
+		if oldField.GetName() != newField.GetName() {
+			change.Details = append(change.Details, fmt.Sprintf("~ field %q renamed to %q", oldField.GetName(), newField.GetName()))
+		}
+
+		if oldField.Type() != newField.Type() {
+			change.Details = append(change.Details, fmt.Sprintf("~ field %q type %s -> %s", newField.GetName(), oldField.Type(), newField.Type()))
+			p.Warnings = append(p.Warnings, fmt.Sprintf(
+				"field %q type changes from %s to %s and its existing values may not be preserved",
+				old.Name+"."+newField.GetName(), oldField.Type(), newField.Type(),
+			))
+			continue
+		}
+
+		oldMap, err := toMap(oldField)
+		if err != nil {
+			return err
+		}
+
+		newMap, err := toMap(newField)
+		if err != nil {
+			return err
+		}
+
+		if keys := sortedKeys(diffMaps(oldMap, newMap, "id", "name")); len(keys) > 0 {
+			change.Details = append(change.Details, fmt.Sprintf("~ field %q options: %s", newField.GetName(), strings.Join(keys, ", ")))
+		}
+	}
+
+	for _, newField := range new.Fields {
+		if old.Fields.GetById(newField.GetId()) == nil {
+			change.Details = append(change.Details, fmt.Sprintf("+ field %q (%s)", newField.GetName(), newField.Type()))
+		}
+	}
+
+	// indexes
+	// ---------------------------------------------------------------
+	for _, idx := range old.Indexes {
+		if !slices.Contains(new.Indexes, idx) {
+			change.Details = append(change.Details, "- index "+idx)
+		}
+	}
+
+	for _, idx := range new.Indexes {
+		if !slices.Contains(old.Indexes, idx) {
+			change.Details = append(change.Details, "+ index "+idx)
+		}
+	}
+
+	// rules
+	// ---------------------------------------------------------------
+	rules := []struct {
+		name string
+		old  *string
+		new  *string
+	}{
+		{"listRule", old.ListRule, new.ListRule},
+		{"viewRule", old.ViewRule, new.ViewRule},
+		{"createRule", old.CreateRule, new.CreateRule},
+		{"updateRule", old.UpdateRule, new.UpdateRule},
+		{"deleteRule", old.DeleteRule, new.DeleteRule},
+	}
+	if new.IsAuth() {
+		rules = append(rules, []struct {
+			name string
+			old  *string
+			new  *string
+		}{
+			{"authRule", old.AuthRule, new.AuthRule},
+			{"manageRule", old.ManageRule, new.ManageRule},
+		}...)
+	}
+
+	for _, rule := range rules {
+		if formatRule(rule.old) != formatRule(rule.new) {
+			change.Details = append(change.Details, fmt.Sprintf("~ %s %s -> %s", rule.name, formatRule(rule.old), formatRule(rule.new)))
+		}
+	}
+
+	// other options
+	// ---------------------------------------------------------------
+	oldMap, err := toMap(old)
+	if err != nil {
+		return err
+	}
+
+	newMap, err := toMap(new)
+	if err != nil {
+		return err
+	}
+
+	optionKeys := sortedKeys(diffMaps(
+		oldMap, newMap,
+		"id", "name", "type", "fields", "indexes", "created", "updated",
+		"listRule", "viewRule", "createRule", "updateRule", "deleteRule", "authRule", "manageRule",
+	))
+	if len(optionKeys) > 0 {
+		change.Details = append(change.Details, "~ options: "+strings.Join(optionKeys, ", "))
+	}
+
+	if len(change.Details) > 0 {
+		p.Changes = append(p.Changes, change)
+	}
+
+	return nil
+}
+
+// formatRule returns a printable representation of a collection rule.
+func formatRule(rule *string) string {
+	if rule == nil {
+		return "null"
+	}
+
+	return fmt.Sprintf("%q", *rule)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,252 @@
+package migratecmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/plugins/migratecmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestLoadSchemaFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	validFile := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(validFile, []byte(`[{"name":"a"},{"name":"b"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidFile := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalidFile, []byte(`{"name":"a"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	validYAMLFile := filepath.Join(dir, "valid.yaml")
+	if err := os.WriteFile(validYAMLFile, []byte("- name: a\n  fields:\n    - name: title\n      max: 10\n- name: b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	validYMLFile := filepath.Join(dir, "valid.YML")
+	if err := os.WriteFile(validYMLFile, []byte(`[{name: a}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidYAMLFile := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalidYAMLFile, []byte("name: a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name          string
+		file          string
+		expectedTotal int
+		expectError   bool
+	}{
+		{"missing file", filepath.Join(dir, "missing.json"), 0, true},
+		{"non-array json", invalidFile, 0, true},
+		{"valid file", validFile, 2, false},
+		{"non-array yaml", invalidYAMLFile, 0, true},
+		{"valid yaml file", validYAMLFile, 2, false},
+		{"valid yml file (case insensitive extension)", validYMLFile, 1, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			schema, err := migratecmd.LoadSchemaFile(s.file)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if len(schema) != s.expectedTotal {
+				t.Fatalf("Expected %d collections, got %d", s.expectedTotal, len(schema))
+			}
+		})
+	}
+
+	// yaml values must be normalized the same way as the json ones
+	yamlSchema, err := migratecmd.LoadSchemaFile(validYAMLFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, _ := yamlSchema[0]["fields"].([]any)
+	if len(fields) != 1 {
+		t.Fatalf("Expected 1 field, got %v", yamlSchema[0]["fields"])
+	}
+	field, _ := fields[0].(map[string]any)
+	if v, ok := field["max"].(float64); !ok || v != 10 {
+		t.Fatalf("Expected max float64(10), got %T(%v)", field["max"], field["max"])
+	}
+}
+
+func TestNewSchemaPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty schema", func(t *testing.T) {
+		app, _ := tests.NewTestApp()
+		defer app.Cleanup()
+
+		if _, err := migratecmd.NewSchemaPlan(app, nil); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		app, _ := tests.NewTestApp()
+		defer app.Cleanup()
+
+		plan, err := migratecmd.NewSchemaPlan(app, exportSchema(t, app))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if plan.HasChanges() {
+			t.Fatalf("Expected no changes, got\n%s", plan)
+		}
+
+		if str := plan.String(); str != "No schema changes." {
+			t.Fatalf("Expected no changes message, got %q", str)
+		}
+	})
+
+	t.Run("with changes", func(t *testing.T) {
+		app, _ := tests.NewTestApp()
+		defer app.Cleanup()
+
+		schema := exportSchema(t, app)
+
+		// remove demo5 and update demo2
+		schema = slices.DeleteFunc(schema, func(c map[string]any) bool {
+			return c["name"] == "demo5"
+		})
+		for _, c := range schema {
+			if c["name"] != "demo2" {
+				continue
+			}
+
+			c["listRule"] = "active = true"
+			c["indexes"] = []string{"CREATE INDEX idx_demo2_title ON demo2 (title)"}
+
+			fields := c["fields"].([]any)
+			fields = slices.DeleteFunc(fields, func(f any) bool {
+				return f.(map[string]any)["name"] == "active"
+			})
+			for _, f := range fields {
+				if f.(map[string]any)["name"] == "title" {
+					f.(map[string]any)["max"] = 100
+				}
+			}
+			fields = append(fields, map[string]any{"name": "description", "type": "text"})
+			c["fields"] = fields
+		}
+		schema = append(schema, map[string]any{"name": "new_collection", "type": "base"})
+
+		plan, err := migratecmd.NewSchemaPlan(app, schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		str := plan.String()
+
+		expectedParts := []string{
+			`~ update collection "demo2"`,
+			`- field "active" (bool)`,
+			`~ field "title" options: max`,
+			`+ field "description" (text)`,
+			`+ index CREATE INDEX idx_demo2_title ON demo2 (title)`,
+			`~ listRule "" -> "active = true"`,
+			`+ create collection "new_collection"`,
+			`- delete collection "demo5"`,
+			`! field "demo2.active" and its data will be deleted`,
+			`! collection "demo5" and all of its records will be deleted`,
+		}
+		for _, part := range expectedParts {
+			if !strings.Contains(str, part) {
+				t.Errorf("Missing %q in\n%s", part, str)
+			}
+		}
+
+		if total := len(plan.Changes); total != 3 {
+			t.Fatalf("Expected 3 changes, got %d", total)
+		}
+
+		if total := len(plan.Warnings); total != 2 {
+			t.Fatalf("Expected 2 warnings, got %d", total)
+		}
+	})
+}
+
+func TestSchemaPlanApply(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	schema := exportSchema(t, app)
+	for _, c := range schema {
+		if c["name"] == "demo2" {
+			c["fields"] = append(c["fields"].([]any), map[string]any{"name": "description", "type": "text"})
+		}
+	}
+	schema = append(schema, map[string]any{"name": "new_collection", "type": "base"})
+
+	plan, err := migratecmd.NewSchemaPlan(app, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := plan.Apply(app); err != nil {
+		t.Fatal(err)
+	}
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if demo2.Fields.GetByName("description") == nil {
+		t.Fatal("Expected the demo2.description field to be created")
+	}
+
+	if _, err := app.FindCollectionByNameOrId("new_collection"); err != nil {
+		t.Fatalf("Expected new_collection to be created, got %v", err)
+	}
+
+	// replan with the same schema
+	plan, err = migratecmd.NewSchemaPlan(app, exportSchema(t, app))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.HasChanges() {
+		t.Fatalf("Expected no changes after apply, got\n%s", plan)
+	}
+}
+
+// exportSchema returns the current app collections in the declarative schema format.
+func exportSchema(t testing.TB, app core.App) []map[string]any {
+	collections, err := app.FindAllCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := json.Marshal(collections)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := []map[string]any{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	return schema
+}
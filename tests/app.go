@@ -509,6 +509,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnMailReceived().Bind(&hook.Handler[*core.MailReceivedEvent]{
+		Func: func(e *core.MailReceivedEvent) error {
+			t.registerEventCall("OnMailReceived")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnMailerRecordAuthAlertSend().Bind(&hook.Handler[*core.MailerRecordEvent]{
 		Func: func(e *core.MailerRecordEvent) error {
 			t.registerEventCall("OnMailerRecordAuthAlertSend")
@@ -637,6 +645,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnRecordAuthWithSAMLRequest().Bind(&hook.Handler[*core.RecordAuthWithSAMLRequestEvent]{
+		Func: func(e *core.RecordAuthWithSAMLRequestEvent) error {
+			t.registerEventCall("OnRecordAuthWithSAMLRequest")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnRecordAuthRefreshRequest().Bind(&hook.Handler[*core.RecordAuthRefreshRequestEvent]{
 		Func: func(e *core.RecordAuthRefreshRequestEvent) error {
 			t.registerEventCall("OnRecordAuthRefreshRequest")
@@ -709,6 +725,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnRecordAuthLockout().Bind(&hook.Handler[*core.RecordAuthLockoutEvent]{
+		Func: func(e *core.RecordAuthLockoutEvent) error {
+			t.registerEventCall("OnRecordAuthLockout")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnRecordsListRequest().Bind(&hook.Handler[*core.RecordsListRequestEvent]{
 		Func: func(e *core.RecordsListRequestEvent) error {
 			t.registerEventCall("OnRecordsListRequest")
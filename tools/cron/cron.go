@@ -22,6 +22,7 @@ type Cron struct {
 	ticker     *time.Ticker
 	startTimer *time.Timer
 	tickerDone chan bool
+	onResult   func(result *RunResult)
 	jobs       []*Job
 	interval   time.Duration
 	mux        sync.RWMutex
@@ -43,6 +44,9 @@ func New() *Cron {
 
 // SetInterval changes the current cron tick interval
 // (it usually should be >= 1 minute).
+//
+// Note that while there are registered jobs with seconds precision schedule,
+// the cron ticks every second (if the interval is larger).
 func (c *Cron) SetInterval(d time.Duration) {
 	// update interval
 	c.mux.Lock()
@@ -57,6 +61,8 @@ func (c *Cron) SetInterval(d time.Duration) {
 }
 
 // SetTimezone changes the current cron tick timezone.
+//
+// Jobs with explicit schedule timezone (eg. "TZ=Europe/Sofia 0 9 * * *") are not affected.
 func (c *Cron) SetTimezone(l *time.Location) {
 	c.mux.Lock()
 	defer c.mux.Unlock()
@@ -71,6 +77,21 @@ func (c *Cron) MustAdd(jobId string, cronExpr string, run func()) {
 	}
 }
 
+// SetResultHandler registers a function that is called after each
+// cron job run (including the skipped ones), eg. to persist the run history.
+//
+// The handler is applied to both the existing and the newly added jobs.
+func (c *Cron) SetResultHandler(fn func(result *RunResult)) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.onResult = fn
+
+	for _, j := range c.jobs {
+		j.onResult = fn
+	}
+}
+
 // Add registers a single cron job.
 //
 // If there is already a job with the provided id, then the old job
@@ -83,24 +104,54 @@ func (c *Cron) Add(jobId string, cronExpr string, fn func()) error {
 		return errors.New("failed to add new cron job: fn must be non-nil function")
 	}
 
+	return c.addJob(jobId, cronExpr, &Job{fn: fn})
+}
+
+// MustAddWithOptions is similar to AddWithOptions() but panic on failure.
+func (c *Cron) MustAddWithOptions(jobId string, cronExpr string, fn JobFunc, options JobOptions) {
+	if err := c.AddWithOptions(jobId, cronExpr, fn, options); err != nil {
+		panic(err)
+	}
+}
+
+// AddWithOptions registers a single context aware cron job with custom run options
+// (no overlapping runs, max run time, jitter).
+//
+// If there is already a job with the provided id, then the old job
+// will be replaced with the new one.
+func (c *Cron) AddWithOptions(jobId string, cronExpr string, fn JobFunc, options JobOptions) error {
+	if fn == nil {
+		return errors.New("failed to add new cron job: fn must be non-nil function")
+	}
+
+	if options.Timeout < 0 || options.Jitter < 0 {
+		return errors.New("failed to add new cron job: timeout and jitter must be non-negative")
+	}
+
+	return c.addJob(jobId, cronExpr, &Job{ctxFn: fn, options: options})
+}
+
+// addJob initializes and registers the provided job
+// (replacing the existing one with the same id, if any).
+func (c *Cron) addJob(jobId string, cronExpr string, job *Job) error {
 	schedule, err := NewSchedule(cronExpr)
 	if err != nil {
 		return fmt.Errorf("failed to add new cron job: %w", err)
 	}
 
-	c.mux.Lock()
-	defer c.mux.Unlock()
+	job.id = jobId
+	job.schedule = schedule
+	job.state = &jobState{}
 
-	// remove previous (if any)
-	c.jobs = slices.DeleteFunc(c.jobs, func(j *Job) bool {
-		return j.Id() == jobId
-	})
+	c.updateJobs(func() {
+		// remove previous (if any)
+		c.jobs = slices.DeleteFunc(c.jobs, func(j *Job) bool {
+			return j.Id() == jobId
+		})
 
-	// add new
-	c.jobs = append(c.jobs, &Job{
-		id:       jobId,
-		fn:       fn,
-		schedule: schedule,
+		// add new
+		job.onResult = c.onResult
+		c.jobs = append(c.jobs, job)
 	})
 
 	return nil
@@ -108,24 +159,52 @@ func (c *Cron) Add(jobId string, cronExpr string, fn func()) error {
 
 // Remove removes a single cron job by its id.
 func (c *Cron) Remove(jobId string) {
-	c.mux.Lock()
-	defer c.mux.Unlock()
-
-	if c.jobs == nil {
-		return // nothing to remove
-	}
+	c.updateJobs(func() {
+		if c.jobs == nil {
+			return // nothing to remove
+		}
 
-	c.jobs = slices.DeleteFunc(c.jobs, func(j *Job) bool {
-		return j.Id() == jobId
+		c.jobs = slices.DeleteFunc(c.jobs, func(j *Job) bool {
+			return j.Id() == jobId
+		})
 	})
 }
 
 // RemoveAll removes all registered cron jobs.
 func (c *Cron) RemoveAll() {
+	c.updateJobs(func() {
+		c.jobs = []*Job{}
+	})
+}
+
+// updateJobs executes the provided jobs list modification under lock
+// and restarts the ticker if the tick interval has changed as a result
+// (eg. after adding the first job with seconds precision schedule).
+func (c *Cron) updateJobs(fn func()) {
 	c.mux.Lock()
-	defer c.mux.Unlock()
+	oldInterval := c.tickInterval()
+	fn()
+	newInterval := c.tickInterval()
+	isActive := c.ticker != nil || c.startTimer != nil
+	c.mux.Unlock()
 
-	c.jobs = []*Job{}
+	if isActive && oldInterval != newInterval {
+		c.Start()
+	}
+}
+
+// tickInterval returns the actual cron tick interval, aka. the
+// configured one or 1s if there are jobs with seconds precision schedule.
+//
+// Must be called under lock.
+func (c *Cron) tickInterval() time.Duration {
+	if c.interval > time.Second && slices.ContainsFunc(c.jobs, func(j *Job) bool {
+		return j.schedule.HasSeconds()
+	}) {
+		return time.Second
+	}
+
+	return c.interval
 }
 
 // Total returns the current total number of registered cron jobs.
@@ -176,15 +255,17 @@ func (c *Cron) Stop() {
 func (c *Cron) Start() {
 	c.Stop()
 
-	// delay the ticker to start at 00 of 1 c.interval duration
+	c.mux.Lock()
+	interval := c.tickInterval()
+
+	// delay the ticker to start at 00 of 1 interval duration
 	now := time.Now()
-	next := now.Add(c.interval).Truncate(c.interval)
+	next := now.Add(interval).Truncate(interval)
 	delay := next.Sub(now)
 
-	c.mux.Lock()
 	c.startTimer = time.AfterFunc(delay, func() {
 		c.mux.Lock()
-		c.ticker = time.NewTicker(c.interval)
+		c.ticker = time.NewTicker(interval)
 		c.mux.Unlock()
 
 		// run immediately at 00
@@ -218,11 +299,35 @@ func (c *Cron) runDue(t time.Time) {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
 
-	moment := NewMoment(t.In(c.timezone))
+	// the tick interval was reduced because of a seconds precision schedule
+	// so the regular schedules should be checked only once per minute
+	secondsTick := c.tickInterval() < c.interval
+	minute := t.Truncate(time.Minute).Unix()
 
 	for _, j := range c.jobs {
+		location := j.schedule.Location()
+		if location == nil {
+			location = c.timezone
+		}
+
+		moment := NewMoment(t.In(location))
+
+		if j.schedule.HasSeconds() {
+			// prevent running the same job more than once within the same second
+			// (could happen with custom sub-second cron interval)
+			if j.state.lastDueSecond.Swap(t.Unix()) == t.Unix() {
+				continue
+			}
+		} else if secondsTick {
+			// check on the first tick of each new minute
+			// (not necessarily the :00 one in case of a delayed tick)
+			if j.state.lastDueMinute.Swap(minute) == minute {
+				continue
+			}
+		}
+
 		if j.schedule.IsDue(moment) {
-			go j.Run()
+			go j.runScheduled()
 		}
 	}
 }
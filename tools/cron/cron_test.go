@@ -1,8 +1,11 @@
 package cron
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"slices"
+	"sync"
 	"testing"
 	"time"
 )
@@ -159,6 +162,71 @@ func TestCronMustAdd(t *testing.T) {
 	}
 }
 
+func TestCronAddWithOptions(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := c.AddWithOptions("test0", "* * * * *", nil, JobOptions{}); err == nil {
+		t.Fatal("Expected nil function error")
+	}
+
+	if err := c.AddWithOptions("test1", "invalid", noop, JobOptions{}); err == nil {
+		t.Fatal("Expected invalid cron expression error")
+	}
+
+	if err := c.AddWithOptions("test2", "* * * * *", noop, JobOptions{Timeout: -1}); err == nil {
+		t.Fatal("Expected negative timeout error")
+	}
+
+	if err := c.AddWithOptions("test3", "* * * * *", noop, JobOptions{Jitter: -1}); err == nil {
+		t.Fatal("Expected negative jitter error")
+	}
+
+	options := JobOptions{NoOverlap: true, Timeout: time.Minute, Jitter: time.Second}
+
+	if err := c.AddWithOptions("test4", "* * * * *", noop, options); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := c.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("Expected 1 job, got %d", len(jobs))
+	}
+
+	if jobs[0].Options() != options {
+		t.Fatalf("Expected options %v, got %v", options, jobs[0].Options())
+	}
+}
+
+func TestCronSetResultHandler(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	c.Add("before", "* * * * *", func() {})
+
+	var results []string
+	c.SetResultHandler(func(r *RunResult) {
+		results = append(results, r.JobId+":"+r.Status)
+	})
+
+	c.AddWithOptions("after", "* * * * *", func(ctx context.Context) error {
+		return errors.New("test")
+	}, JobOptions{})
+
+	for _, j := range c.Jobs() {
+		j.Run()
+	}
+
+	expected := []string{"before:" + RunStatusSuccess, "after:" + RunStatusError}
+	if !slices.Equal(results, expected) {
+		t.Fatalf("Expected results %v, got %v", expected, results)
+	}
+}
+
 func TestCronRemoveAll(t *testing.T) {
 	t.Parallel()
 
@@ -250,6 +318,97 @@ func TestCronJobs(t *testing.T) {
 	}
 }
 
+func TestCronTickInterval(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	if v := c.tickInterval(); v != time.Minute {
+		t.Fatalf("Expected default tick interval 1m, got %v", v)
+	}
+
+	c.MustAdd("minutes", "* * * * *", func() {})
+
+	if v := c.tickInterval(); v != time.Minute {
+		t.Fatalf("Expected tick interval 1m, got %v", v)
+	}
+
+	c.MustAdd("seconds", "*/5 * * * * *", func() {})
+
+	if v := c.tickInterval(); v != time.Second {
+		t.Fatalf("Expected adapted tick interval 1s, got %v", v)
+	}
+
+	// smaller custom interval shouldn't be changed
+	c.SetInterval(500 * time.Millisecond)
+	if v := c.tickInterval(); v != 500*time.Millisecond {
+		t.Fatalf("Expected tick interval 500ms, got %v", v)
+	}
+
+	c.SetInterval(time.Minute)
+
+	c.Remove("seconds")
+
+	if v := c.tickInterval(); v != time.Minute {
+		t.Fatalf("Expected restored tick interval 1m, got %v", v)
+	}
+}
+
+func TestCronRunDue(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+
+	add := func(id string, expr string) {
+		c.MustAdd(id, expr, func() {
+			mu.Lock()
+			calls[id]++
+			mu.Unlock()
+		})
+	}
+
+	add("minutes", "0 10 * * *")
+	add("seconds", "*/30 0 10 * * *")
+	add("tokyo", "TZ=Asia/Tokyo 0 19 * * *") // 10:00 UTC
+	add("delayed", "1 10 * * *")
+
+	for _, date := range []string{
+		"2024-01-01 10:00:00",
+		"2024-01-01 10:00:00", // same second
+		"2024-01-01 10:00:15",
+		"2024-01-01 10:00:30",
+		"2024-01-01 10:01:01", // missed :00 tick
+		"2024-01-01 10:01:02",
+	} {
+		tm, err := time.Parse(time.DateTime, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.runDue(tm)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expected := map[string]int{
+		"minutes": 1,
+		"seconds": 2,
+		"tokyo":   1,
+		"delayed": 1,
+	}
+
+	for id, total := range expected {
+		if calls[id] != total {
+			t.Fatalf("Expected %d %q calls, got %d (%v)", total, id, calls[id], calls)
+		}
+	}
+}
+
 func TestCronStartStop(t *testing.T) {
 	t.Parallel()
 
@@ -1,12 +1,74 @@
 package cron
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Job run statuses.
+const (
+	RunStatusSuccess = "success"
+	RunStatusError   = "error"
+	RunStatusTimeout = "timeout"
+	RunStatusSkipped = "skipped"
+)
+
+// JobFunc defines a context aware cron job function.
+//
+// The provided ctx is canceled when the job exceeds its max run time (see [JobOptions.Timeout]).
+type JobFunc func(ctx context.Context) error
+
+// JobOptions defines the optional cron job run settings.
+type JobOptions struct {
+	// NoOverlap skips the job run if the previous one hasn't completed yet.
+	NoOverlap bool
+
+	// Timeout is the max job run duration after which the job context is canceled
+	// (0 means no timeout).
+	Timeout time.Duration
+
+	// Jitter is the max random delay applied before each scheduled job run
+	// (useful to spread the load of jobs with the same schedule).
+	Jitter time.Duration
+}
+
+// RunResult describes the outcome of a single cron job run.
+type RunResult struct {
+	Start  time.Time
+	End    time.Time
+	Error  error
+	JobId  string
+	Status string
+}
 
 // Job defines a single registered cron job.
 type Job struct {
 	fn       func()
+	ctxFn    JobFunc // context aware job function (see [Cron.AddWithOptions])
 	schedule *Schedule
+	onResult func(result *RunResult)
+	state    *jobState
 	id       string
+	options  JobOptions
+}
+
+// jobState holds the mutable cron job run state
+// (it is referenced by pointer so that the Job could be copied by value).
+type jobState struct {
+	running atomic.Int32
+
+	// lastDueSecond is the unix timestamp of the last due check
+	// of a seconds precision schedule
+	lastDueSecond atomic.Int64
+
+	// lastDueMinute is the unix timestamp of the last due check minute
+	// of a regular schedule while the cron ticks every second
+	lastDueMinute atomic.Int64
 }
 
 // Id returns the cron job id.
@@ -19,10 +81,96 @@ func (j *Job) Expression() string {
 	return j.schedule.rawExpr
 }
 
+// Options returns the cron job run options.
+func (j *Job) Options() JobOptions {
+	return j.options
+}
+
+// IsRunning reports whether the cron job is currently running.
+func (j *Job) IsRunning() bool {
+	return j.state != nil && j.state.running.Load() > 0
+}
+
 // Run runs the cron job function.
+//
+// If the job has the NoOverlap option and it is already running, the run is skipped.
 func (j *Job) Run() {
-	if j.fn != nil {
-		j.fn()
+	if j.fn == nil && j.ctxFn == nil {
+		return
+	}
+
+	result := &RunResult{
+		JobId: j.id,
+		Start: time.Now(),
+	}
+
+	if j.state != nil {
+		if j.state.running.Add(1) > 1 && j.options.NoOverlap {
+			j.state.running.Add(-1)
+
+			result.End = result.Start
+			result.Status = RunStatusSkipped
+			j.report(result)
+
+			return
+		}
+		defer j.state.running.Add(-1)
+	}
+
+	ctx := context.Background()
+	if j.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.options.Timeout)
+		defer cancel()
+	}
+
+	err := j.call(ctx)
+
+	result.End = time.Now()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Status = RunStatusTimeout
+		result.Error = errors.Join(err, ctx.Err())
+	case err != nil:
+		result.Status = RunStatusError
+		result.Error = err
+	default:
+		result.Status = RunStatusSuccess
+	}
+
+	j.report(result)
+}
+
+// runScheduled runs the cron job after its random jitter delay (if any).
+func (j *Job) runScheduled() {
+	if j.options.Jitter > 0 {
+		time.Sleep(rand.N(j.options.Jitter))
+	}
+
+	j.Run()
+}
+
+// call calls the job function and normalizes its panics to errors.
+func (j *Job) call(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cron job panic: %v", r)
+		}
+	}()
+
+	if j.ctxFn != nil {
+		return j.ctxFn(ctx)
+	}
+
+	j.fn()
+
+	return nil
+}
+
+func (j *Job) report(result *RunResult) {
+	if j.onResult != nil {
+		j.onResult(result)
 	}
 }
 
@@ -32,9 +180,17 @@ func (j Job) MarshalJSON() ([]byte, error) {
 	plain := struct {
 		Id         string `json:"id"`
 		Expression string `json:"expression"`
+		NoOverlap  bool   `json:"noOverlap"`
+		Timeout    int64  `json:"timeout"`
+		Jitter     int64  `json:"jitter"`
+		Running    bool   `json:"running"`
 	}{
 		Id:         j.Id(),
 		Expression: j.Expression(),
+		NoOverlap:  j.options.NoOverlap,
+		Timeout:    int64(j.options.Timeout / time.Second),
+		Jitter:     int64(j.options.Jitter / time.Second),
+		Running:    j.IsRunning(),
 	}
 
 	return json.Marshal(plain)
@@ -1,8 +1,13 @@
 package cron
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestJobId(t *testing.T) {
@@ -41,11 +46,16 @@ func TestJobRun(t *testing.T) {
 
 	j1 := Job{}
 	j2 := Job{fn: func() { calls += "2" }}
+	j3 := Job{ctxFn: func(ctx context.Context) error {
+		calls += "3"
+		return nil
+	}}
 
 	j1.Run()
 	j2.Run()
+	j3.Run()
 
-	expected := "2"
+	expected := "23"
 	if calls != expected {
 		t.Fatalf("Expected calls %q, got %q", expected, calls)
 	}
@@ -57,15 +67,161 @@ func TestJobMarshalJSON(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	j := Job{id: "test_id", schedule: s}
+	j := Job{id: "test_id", schedule: s, options: JobOptions{NoOverlap: true, Timeout: 90 * time.Second, Jitter: 5 * time.Second}}
 
-	raw, err := json.Marshal(j)
-	if err != nil {
-		t.Fatal(err)
+	expected := `{"id":"test_id","expression":"1 2 3 4 5","noOverlap":true,"timeout":90,"jitter":5,"running":false}`
+
+	// both value and pointer
+	for _, v := range []any{j, &j} {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if str := string(raw); str != expected {
+			t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+		}
+	}
+}
+
+func TestJobRunResult(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		fn             JobFunc
+		options        JobOptions
+		expectedStatus string
+		expectedError  bool
+	}{
+		{
+			"success",
+			func(ctx context.Context) error { return nil },
+			JobOptions{},
+			RunStatusSuccess,
+			false,
+		},
+		{
+			"error",
+			func(ctx context.Context) error { return errors.New("test") },
+			JobOptions{},
+			RunStatusError,
+			true,
+		},
+		{
+			"panic",
+			func(ctx context.Context) error { panic("test") },
+			JobOptions{},
+			RunStatusError,
+			true,
+		},
+		{
+			"timeout",
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			JobOptions{Timeout: 10 * time.Millisecond},
+			RunStatusTimeout,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var result *RunResult
+
+			j := Job{
+				id:       s.name,
+				ctxFn:    s.fn,
+				options:  s.options,
+				onResult: func(r *RunResult) { result = r },
+			}
+
+			j.Run()
+
+			if result == nil {
+				t.Fatal("Expected run result, got nil")
+			}
+
+			if result.JobId != s.name {
+				t.Fatalf("Expected JobId %q, got %q", s.name, result.JobId)
+			}
+
+			if result.Status != s.expectedStatus {
+				t.Fatalf("Expected status %q, got %q", s.expectedStatus, result.Status)
+			}
+
+			hasErr := result.Error != nil
+			if hasErr != s.expectedError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectedError, hasErr, result.Error)
+			}
+
+			if result.End.Before(result.Start) {
+				t.Fatalf("Expected End %v to be after Start %v", result.End, result.Start)
+			}
+
+			if j.IsRunning() {
+				t.Fatal("Expected the job to be no longer running")
+			}
+		})
 	}
+}
 
-	expected := `{"id":"test_id","expression":"1 2 3 4 5"}`
-	if str := string(raw); str != expected {
-		t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+func TestJobRunNoOverlap(t *testing.T) {
+	t.Parallel()
+
+	for _, noOverlap := range []bool{false, true} {
+		t.Run(fmt.Sprintf("noOverlap_%v", noOverlap), func(t *testing.T) {
+			var mu sync.Mutex
+			statuses := map[string]int{}
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+
+			j := Job{
+				id:    "test",
+				state: &jobState{},
+				ctxFn: func(ctx context.Context) error {
+					started <- struct{}{}
+					<-release
+					return nil
+				},
+				options: JobOptions{NoOverlap: noOverlap},
+				onResult: func(r *RunResult) {
+					mu.Lock()
+					statuses[r.Status]++
+					mu.Unlock()
+				},
+			}
+
+			var wg sync.WaitGroup
+			wg.Go(j.Run)
+
+			<-started
+
+			if !j.IsRunning() {
+				t.Fatal("Expected the job to be running")
+			}
+
+			if noOverlap {
+				j.Run() // should be skipped without blocking
+			} else {
+				wg.Go(j.Run)
+				<-started
+			}
+
+			close(release)
+			wg.Wait()
+
+			expectedSuccess, expectedSkipped := 2, 0
+			if noOverlap {
+				expectedSuccess, expectedSkipped = 1, 1
+			}
+
+			if statuses[RunStatusSuccess] != expectedSuccess || statuses[RunStatusSkipped] != expectedSkipped {
+				t.Fatalf("Expected %d success and %d skipped runs, got %v", expectedSuccess, expectedSkipped, statuses)
+			}
+		})
 	}
 }
@@ -10,37 +10,87 @@ import (
 
 // Moment represents a parsed single time moment.
 type Moment struct {
+	Second    int `json:"second"`
 	Minute    int `json:"minute"`
 	Hour      int `json:"hour"`
 	Day       int `json:"day"`
 	Month     int `json:"month"`
+	Year      int `json:"year"`
 	DayOfWeek int `json:"dayOfWeek"`
 }
 
 // NewMoment creates a new Moment from the specified time.
 func NewMoment(t time.Time) *Moment {
 	return &Moment{
+		Second:    t.Second(),
 		Minute:    t.Minute(),
 		Hour:      t.Hour(),
 		Day:       t.Day(),
 		Month:     int(t.Month()),
+		Year:      t.Year(),
 		DayOfWeek: int(t.Weekday()),
 	}
 }
 
+// daysInMonth returns the number of days in the moment month.
+func (m *Moment) daysInMonth() int {
+	return time.Date(m.Year, time.Month(m.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekdayOf returns the day of the week of the specified day from the moment month.
+func (m *Moment) weekdayOf(day int) time.Weekday {
+	return time.Date(m.Year, time.Month(m.Month), day, 0, 0, 0, 0, time.UTC).Weekday()
+}
+
 // Schedule stores parsed information for each time component when a cron job should run.
 type Schedule struct {
+	// Seconds is nil for the regular 5 segments (aka. minutes precision) expressions.
+	Seconds    map[int]struct{} `json:"seconds,omitempty"`
 	Minutes    map[int]struct{} `json:"minutes"`
 	Hours      map[int]struct{} `json:"hours"`
 	Days       map[int]struct{} `json:"days"`
 	Months     map[int]struct{} `json:"months"`
 	DaysOfWeek map[int]struct{} `json:"daysOfWeek"`
 
+	location *time.Location
+
+	// day of the month modifiers
+	lastDay         bool             // L
+	lastWeekday     bool             // LW
+	nearestWeekdays map[int]struct{} // nW
+
+	// day of the week modifiers
+	lastDaysOfWeek map[int]struct{}    // nL
+	nthDaysOfWeek  map[[2]int]struct{} // n#k -> [n, k]
+
 	rawExpr string
 }
 
+// HasSeconds reports whether the schedule has a seconds segment
+// (aka. it could be due more than once per minute).
+func (s *Schedule) HasSeconds() bool {
+	return s.Seconds != nil
+}
+
+// Location returns the schedule specific timezone (if any).
+//
+// Returns nil if the schedule has no explicit timezone and
+// the Cron timezone should be used.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
 // IsDue checks whether the provided Moment satisfies the current Schedule.
+//
+// Note that the seconds of the Moment are checked only
+// if the Schedule has a seconds segment.
 func (s *Schedule) IsDue(m *Moment) bool {
+	if s.Seconds != nil {
+		if _, ok := s.Seconds[m.Second]; !ok {
+			return false
+		}
+	}
+
 	if _, ok := s.Minutes[m.Minute]; !ok {
 		return false
 	}
@@ -49,11 +99,11 @@ func (s *Schedule) IsDue(m *Moment) bool {
 		return false
 	}
 
-	if _, ok := s.Days[m.Day]; !ok {
+	if !s.isDayDue(m) {
 		return false
 	}
 
-	if _, ok := s.DaysOfWeek[m.DayOfWeek]; !ok {
+	if !s.isDayOfWeekDue(m) {
 		return false
 	}
 
@@ -64,6 +114,79 @@ func (s *Schedule) IsDue(m *Moment) bool {
 	return true
 }
 
+func (s *Schedule) isDayDue(m *Moment) bool {
+	if _, ok := s.Days[m.Day]; ok {
+		return true
+	}
+
+	if !s.lastDay && !s.lastWeekday && len(s.nearestWeekdays) == 0 {
+		return false
+	}
+
+	lastDay := m.daysInMonth()
+
+	if s.lastDay && m.Day == lastDay {
+		return true
+	}
+
+	if s.lastWeekday {
+		day := lastDay
+		switch m.weekdayOf(day) {
+		case time.Saturday:
+			day -= 1
+		case time.Sunday:
+			day -= 2
+		}
+		if m.Day == day {
+			return true
+		}
+	}
+
+	for day := range s.nearestWeekdays {
+		if day > lastDay {
+			continue // nonexisting day for the current month
+		}
+
+		// find the nearest weekday without leaving the current month
+		switch m.weekdayOf(day) {
+		case time.Saturday:
+			if day == 1 {
+				day += 2
+			} else {
+				day -= 1
+			}
+		case time.Sunday:
+			if day == lastDay {
+				day -= 2
+			} else {
+				day += 1
+			}
+		}
+
+		if m.Day == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schedule) isDayOfWeekDue(m *Moment) bool {
+	if _, ok := s.DaysOfWeek[m.DayOfWeek]; ok {
+		return true
+	}
+
+	if _, ok := s.lastDaysOfWeek[m.DayOfWeek]; ok && m.Day+7 > m.daysInMonth() {
+		return true
+	}
+
+	if _, ok := s.nthDaysOfWeek[[2]int{m.DayOfWeek, (m.Day-1)/7 + 1}]; ok {
+		return true
+	}
+
+	return false
+}
+
 var macros = map[string]string{
 	"@yearly":   "0 0 1 1 *",
 	"@annually": "0 0 1 1 *",
@@ -78,6 +201,8 @@ var macros = map[string]string{
 //
 // A cron expression could be a macro OR 5 segments separated by space,
 // representing: minute, hour, day of the month, month and day of the week.
+// An optional 6th leading segment could be used to specify the seconds,
+// eg. "*/10 * * * * *" (aka. every 10 seconds).
 //
 // The following segment formats are supported:
 //   - wildcard: *
@@ -85,20 +210,60 @@ var macros = map[string]string{
 //   - step:     */n or 1-30/n
 //   - list:     1,2,3,10-20/n
 //
+// The day of the month segment additionally supports:
+//   - L:  the last day of the month
+//   - LW: the last weekday (Mon-Fri) of the month
+//   - nW: the nearest weekday (Mon-Fri) to the n-th day of the month
+//
+// The day of the week segment additionally supports:
+//   - nL:  the last n-th day of the week of the month (eg. 5L - the last Friday)
+//   - n#k: the k-th n-th day of the week of the month (eg. 2#2 - the second Tuesday)
+//
 // The following macros are supported:
 //   - @yearly (or @annually)
 //   - @monthly
 //   - @weekly
 //   - @daily (or @midnight)
 //   - @hourly
+//
+// The expression could be also prefixed with "TZ=<IANA name>" or "CRON_TZ=<IANA name>"
+// to evaluate the schedule in a specific timezone, eg. "TZ=Europe/Sofia 0 9 * * *".
 func NewSchedule(cronExpr string) (*Schedule, error) {
+	rawExpr := cronExpr
+
+	var location *time.Location
+	if strings.HasPrefix(cronExpr, "TZ=") || strings.HasPrefix(cronExpr, "CRON_TZ=") {
+		tz, rest, _ := strings.Cut(cronExpr, " ")
+		_, tzName, _ := strings.Cut(tz, "=")
+
+		loc, err := time.LoadLocation(tzName)
+		if err != nil || tzName == "" {
+			return nil, fmt.Errorf("invalid cron expression timezone %q", tzName)
+		}
+
+		location = loc
+		cronExpr = strings.TrimSpace(rest)
+	}
+
 	if v, ok := macros[cronExpr]; ok {
 		cronExpr = v
 	}
 
 	segments := strings.Split(cronExpr, " ")
-	if len(segments) != 5 {
-		return nil, errors.New("invalid cron expression - must be a valid macro or to have exactly 5 space separated segments")
+
+	var seconds map[int]struct{}
+	switch len(segments) {
+	case 5:
+		// no seconds
+	case 6:
+		var err error
+		seconds, err = parseCronSegment(segments[0], 0, 59)
+		if err != nil {
+			return nil, err
+		}
+		segments = segments[1:]
+	default:
+		return nil, errors.New("invalid cron expression - must be a valid macro or to have exactly 5 (or 6 with seconds) space separated segments")
 	}
 
 	minutes, err := parseCronSegment(segments[0], 0, 59)
@@ -111,29 +276,113 @@ func NewSchedule(cronExpr string) (*Schedule, error) {
 		return nil, err
 	}
 
-	days, err := parseCronSegment(segments[2], 1, 31)
-	if err != nil {
+	schedule := &Schedule{
+		Seconds:  seconds,
+		Minutes:  minutes,
+		Hours:    hours,
+		location: location,
+		rawExpr:  rawExpr,
+	}
+
+	if err := schedule.parseDaysSegment(segments[2]); err != nil {
 		return nil, err
 	}
 
-	months, err := parseCronSegment(segments[3], 1, 12)
+	schedule.Months, err = parseCronSegment(segments[3], 1, 12)
 	if err != nil {
 		return nil, err
 	}
 
-	daysOfWeek, err := parseCronSegment(segments[4], 0, 6)
-	if err != nil {
+	if err := schedule.parseDaysOfWeekSegment(segments[4]); err != nil {
 		return nil, err
 	}
 
-	return &Schedule{
-		Minutes:    minutes,
-		Hours:      hours,
-		Days:       days,
-		Months:     months,
-		DaysOfWeek: daysOfWeek,
-		rawExpr:    cronExpr,
-	}, nil
+	return schedule, nil
+}
+
+// parseDaysSegment parses the day of the month segment
+// (including its L, LW and nW modifiers).
+func (s *Schedule) parseDaysSegment(segment string) error {
+	var regular []string
+
+	for _, p := range strings.Split(segment, ",") {
+		switch {
+		case p == "L":
+			s.lastDay = true
+		case p == "LW":
+			s.lastWeekday = true
+		case strings.HasSuffix(p, "W"):
+			day, err := strconv.Atoi(strings.TrimSuffix(p, "W"))
+			if err != nil || day < 1 || day > 31 {
+				return fmt.Errorf("invalid day of the month modifier %q - must be in the format nW where n is between 1 and 31", p)
+			}
+			if s.nearestWeekdays == nil {
+				s.nearestWeekdays = map[int]struct{}{}
+			}
+			s.nearestWeekdays[day] = struct{}{}
+		default:
+			regular = append(regular, p)
+		}
+	}
+
+	s.Days = map[int]struct{}{}
+	if len(regular) == 0 {
+		return nil
+	}
+
+	days, err := parseCronSegment(strings.Join(regular, ","), 1, 31)
+	if err != nil {
+		return err
+	}
+	s.Days = days
+
+	return nil
+}
+
+// parseDaysOfWeekSegment parses the day of the week segment
+// (including its nL and n#k modifiers).
+func (s *Schedule) parseDaysOfWeekSegment(segment string) error {
+	var regular []string
+
+	for _, p := range strings.Split(segment, ",") {
+		switch {
+		case strings.HasSuffix(p, "L"):
+			weekday, err := strconv.Atoi(strings.TrimSuffix(p, "L"))
+			if err != nil || weekday < 0 || weekday > 6 {
+				return fmt.Errorf("invalid day of the week modifier %q - must be in the format nL where n is between 0 and 6", p)
+			}
+			if s.lastDaysOfWeek == nil {
+				s.lastDaysOfWeek = map[int]struct{}{}
+			}
+			s.lastDaysOfWeek[weekday] = struct{}{}
+		case strings.Contains(p, "#"):
+			rawWeekday, rawNth, _ := strings.Cut(p, "#")
+			weekday, err1 := strconv.Atoi(rawWeekday)
+			nth, err2 := strconv.Atoi(rawNth)
+			if err1 != nil || err2 != nil || weekday < 0 || weekday > 6 || nth < 1 || nth > 5 {
+				return fmt.Errorf("invalid day of the week modifier %q - must be in the format n#k where n is between 0 and 6 and k is between 1 and 5", p)
+			}
+			if s.nthDaysOfWeek == nil {
+				s.nthDaysOfWeek = map[[2]int]struct{}{}
+			}
+			s.nthDaysOfWeek[[2]int{weekday, nth}] = struct{}{}
+		default:
+			regular = append(regular, p)
+		}
+	}
+
+	s.DaysOfWeek = map[int]struct{}{}
+	if len(regular) == 0 {
+		return nil
+	}
+
+	daysOfWeek, err := parseCronSegment(strings.Join(regular, ","), 0, 6)
+	if err != nil {
+		return err
+	}
+	s.DaysOfWeek = daysOfWeek
+
+	return nil
 }
 
 // parseCronSegment parses a single cron expression segment and
@@ -12,13 +12,21 @@ import (
 func TestNewMoment(t *testing.T) {
 	t.Parallel()
 
-	date, err := time.Parse("2006-01-02 15:04", "2023-05-09 15:20")
+	date, err := time.Parse("2006-01-02 15:04:05", "2023-05-09 15:20:07")
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	m := cron.NewMoment(date)
 
+	if m.Second != 7 {
+		t.Fatalf("Expected m.Second %d, got %d", 7, m.Second)
+	}
+
+	if m.Year != 2023 {
+		t.Fatalf("Expected m.Year %d, got %d", 2023, m.Year)
+	}
+
 	if m.Minute != 20 {
 		t.Fatalf("Expected m.Minute %d, got %d", 20, m.Minute)
 	}
@@ -59,7 +67,89 @@ func TestNewSchedule(t *testing.T) {
 			"",
 		},
 		{
-			"* * * * * *",
+			"* * * * * * *",
+			true,
+			"",
+		},
+		{
+			"TZ=invalid * * * * *",
+			true,
+			"",
+		},
+		{
+			"TZ= * * * * *",
+			true,
+			"",
+		},
+		{
+			"*/15 0 0 1 1 *",
+			false,
+			`{"seconds":{"0":{},"15":{},"30":{},"45":{}},"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{}},"months":{"1":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+		{
+			"60 * * * * *",
+			true,
+			"",
+		},
+		{
+			"TZ=Asia/Tokyo 0 0 1 1 *",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{}},"months":{"1":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+		{
+			"CRON_TZ=Asia/Tokyo @yearly",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{}},"months":{"1":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+
+		// day of the month and day of the week modifiers
+		{
+			"0 0 L 1 *",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{},"months":{"1":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+		{
+			"0 0 1,15W,LW 1 *",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{}},"months":{"1":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+		{
+			"0 0 * 1 5L,2#2",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"24":{},"25":{},"26":{},"27":{},"28":{},"29":{},"3":{},"30":{},"31":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"months":{"1":{}},"daysOfWeek":{}}`,
+		},
+		{
+			"0 0 32W * *",
+			true,
+			"",
+		},
+		{
+			"0 0 W * *",
+			true,
+			"",
+		},
+		{
+			"0 0 * * 7L",
+			true,
+			"",
+		},
+		{
+			"0 0 * * 1#6",
+			true,
+			"",
+		},
+		{
+			"0 0 * * 1#0",
+			true,
+			"",
+		},
+		{
+			"0 0 * * L",
+			true,
+			"",
+		},
+		{
+			"0 0 * * 1#",
 			true,
 			"",
 		},
@@ -278,6 +368,111 @@ func TestNewSchedule(t *testing.T) {
 	}
 }
 
+func TestScheduleHasSecondsAndLocation(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		cronExpr         string
+		expectSeconds    bool
+		expectedLocation string
+	}{
+		{"* * * * *", false, ""},
+		{"@daily", false, ""},
+		{"* * * * * *", true, ""},
+		{"TZ=Asia/Tokyo * * * * *", false, "Asia/Tokyo"},
+		{"CRON_TZ=Europe/Sofia */5 * * * * *", true, "Europe/Sofia"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.cronExpr, func(t *testing.T) {
+			schedule, err := cron.NewSchedule(s.cronExpr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := schedule.HasSeconds(); v != s.expectSeconds {
+				t.Fatalf("Expected HasSeconds %v, got %v", s.expectSeconds, v)
+			}
+
+			var location string
+			if loc := schedule.Location(); loc != nil {
+				location = loc.String()
+			}
+
+			if location != s.expectedLocation {
+				t.Fatalf("Expected location %q, got %q", s.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestScheduleIsDueModifiers(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		cronExpr string
+		date     string
+		expected bool
+	}{
+		// seconds
+		{"*/15 * * * * *", "2024-01-01 10:00:15", true},
+		{"*/15 * * * * *", "2024-01-01 10:00:16", false},
+		{"* * * * *", "2024-01-01 10:00:16", true}, // seconds are not checked
+
+		// L
+		{"0 0 L * *", "2024-02-29 00:00:00", true},
+		{"0 0 L * *", "2024-02-28 00:00:00", false},
+		{"0 0 L * *", "2023-02-28 00:00:00", true},
+		{"0 0 1,L * *", "2024-04-01 00:00:00", true},
+		{"0 0 1,L * *", "2024-04-30 00:00:00", true},
+
+		// LW (2024-03-31 is Sunday, 2024-08-31 is Saturday)
+		{"0 0 LW * *", "2024-03-29 00:00:00", true},
+		{"0 0 LW * *", "2024-03-31 00:00:00", false},
+		{"0 0 LW * *", "2024-08-30 00:00:00", true},
+		{"0 0 LW * *", "2024-07-31 00:00:00", true},
+
+		// nW (2024-06-15 is Saturday, 2024-06-01 is Saturday, 2024-06-30 is Sunday)
+		{"0 0 15W * *", "2024-06-14 00:00:00", true},
+		{"0 0 15W * *", "2024-06-15 00:00:00", false},
+		{"0 0 1W * *", "2024-06-03 00:00:00", true},
+		{"0 0 30W * *", "2024-06-28 00:00:00", true},
+		{"0 0 31W * *", "2024-06-28 00:00:00", false}, // nonexisting day
+		{"0 0 10W * *", "2024-06-10 00:00:00", true},  // already a weekday
+
+		// nL (the last Friday of 2024-01 is 2024-01-26)
+		{"0 0 * * 5L", "2024-01-26 00:00:00", true},
+		{"0 0 * * 5L", "2024-01-19 00:00:00", false},
+		{"0 0 * * 5L", "2024-01-25 00:00:00", false},
+
+		// n#k (the second Tuesday of 2024-01 is 2024-01-09)
+		{"0 0 * * 2#2", "2024-01-09 00:00:00", true},
+		{"0 0 * * 2#2", "2024-01-02 00:00:00", false},
+		{"0 0 * * 2#2", "2024-01-16 00:00:00", false},
+		{"0 0 * * 1,2#2", "2024-01-15 00:00:00", true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d-%s-%s", i, s.cronExpr, s.date), func(t *testing.T) {
+			schedule, err := cron.NewSchedule(s.cronExpr)
+			if err != nil {
+				t.Fatalf("Unexpected cron error: %v", err)
+			}
+
+			date, err := time.Parse(time.DateTime, s.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := schedule.IsDue(cron.NewMoment(date))
+
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
 func TestScheduleIsDue(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultHTTPTimeout is the request timeout of the default HTTP-API mailers client.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// defaultHTTPClient is used by the HTTP-API mailers when no custom
+// HTTP client is set (unlike [http.DefaultClient] it has a timeout so
+// that an unresponsive API can't block the mail sending indefinitely).
+var defaultHTTPClient = &http.Client{Timeout: DefaultHTTPTimeout}
+
+// maxErrorBodySize is the max number of bytes to read from the HTTP-API error response body.
+const maxErrorBodySize = 1 << 16
+
+// HTTPError defines an error returned by the HTTP-API mailers
+// for non-2xx responses.
+type HTTPError struct {
+	Raw    []byte
+	Status int
+}
+
+// Error implements the std error interface.
+func (err *HTTPError) Error() string {
+	msg := fmt.Sprintf("mail API responded with status %d", err.Status)
+
+	if raw := strings.TrimSpace(string(err.Raw)); raw != "" {
+		msg += ": " + raw
+	}
+
+	return msg
+}
+
+// sendHTTPRequest sends the provided request and normalizes
+// all non-2xx responses to HTTPError.
+func sendHTTPRequest(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+		return &HTTPError{Status: resp.StatusCode, Raw: raw}
+	}
+
+	// drain the body to allow connection reuse
+	_, err = io.Copy(io.Discard, resp.Body)
+
+	return err
+}
+
+// apiURL joins the base endpoint with the provided path.
+func apiURL(endpoint string, path string) string {
+	return strings.TrimRight(endpoint, "/") + "/" + strings.TrimLeft(path, "/")
+}
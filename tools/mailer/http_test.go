@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendHTTPRequestTimeout(t *testing.T) {
+	// note: not parallel because the default client timeout is temporarily changed
+
+	if defaultHTTPClient == http.DefaultClient || defaultHTTPClient.Timeout != DefaultHTTPTimeout {
+		t.Fatalf("Expected a custom default client with %v timeout, got %v", DefaultHTTPTimeout, defaultHTTPClient.Timeout)
+	}
+
+	originalTimeout := defaultHTTPClient.Timeout
+	defaultHTTPClient.Timeout = 50 * time.Millisecond
+	defer func() {
+		defaultHTTPClient.Timeout = originalTimeout
+	}()
+
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		// nil -> the default client
+		done <- sendHTTPRequest(nil, req)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Expected timeout error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the request to be canceled after the default client timeout")
+	}
+}
@@ -2,11 +2,15 @@ package mailer
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/mail"
+	"strings"
 
+	"github.com/domodwyer/mailyak/v3"
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
 )
 
 // Message defines a generic email message struct.
@@ -70,3 +74,92 @@ func detectReaderMimeType(r io.Reader) (io.Reader, string, error) {
 
 	return io.MultiReader(readCopy, r), mime.String(), nil
 }
+
+// fillMailYak populates the provided mailyak instance with the message data.
+func fillMailYak(yak *mailyak.MailYak, m *Message) error {
+	if m.From.Name != "" {
+		yak.FromName(m.From.Name)
+	}
+	yak.From(m.From.Address)
+	yak.Subject(m.Subject)
+	yak.HTML().Set(m.HTML)
+
+	if m.Text == "" {
+		// try to generate a plain text version of the HTML
+		if plain, err := html2Text(m.HTML); err == nil {
+			yak.Plain().Set(plain)
+		}
+	} else {
+		yak.Plain().Set(m.Text)
+	}
+
+	if len(m.To) > 0 {
+		yak.To(addressesToStrings(m.To, true)...)
+	}
+
+	if len(m.Bcc) > 0 {
+		yak.Bcc(addressesToStrings(m.Bcc, true)...)
+	}
+
+	if len(m.Cc) > 0 {
+		yak.Cc(addressesToStrings(m.Cc, true)...)
+	}
+
+	// add regular attachements (if any)
+	for name, data := range m.Attachments {
+		r, mime, err := detectReaderMimeType(data)
+		if err != nil {
+			return err
+		}
+		yak.AttachWithMimeType(name, r, mime)
+	}
+
+	// add inline attachments (if any)
+	for name, data := range m.InlineAttachments {
+		r, mime, err := detectReaderMimeType(data)
+		if err != nil {
+			return err
+		}
+		yak.AttachInlineWithMimeType(name, r, mime)
+	}
+
+	// add custom headers (if any)
+	var hasMessageId bool
+	for k, v := range m.Headers {
+		if strings.EqualFold(k, "Message-ID") {
+			hasMessageId = true
+		}
+		yak.AddHeader(k, v)
+	}
+	if !hasMessageId {
+		// add a default message id if missing
+		fromParts := strings.Split(m.From.Address, "@")
+		if len(fromParts) == 2 {
+			yak.AddHeader("Message-ID", fmt.Sprintf("<%s@%s>",
+				security.PseudorandomString(15),
+				fromParts[1],
+			))
+		}
+	}
+
+	return nil
+}
+
+// buildMIMEMessage serializes the provided message as raw RFC 5322 MIME message
+// (usually used by the HTTP-API mailers that accept "raw" messages).
+//
+// Note that the Bcc recipients are not included in the message headers.
+func buildMIMEMessage(m *Message) ([]byte, error) {
+	yak := mailyak.New("", nil)
+
+	if err := fillMailYak(yak, m); err != nil {
+		return nil, err
+	}
+
+	buf, err := yak.MimeBuf()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
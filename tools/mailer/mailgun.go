@@ -0,0 +1,95 @@
+package mailer
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"slices"
+
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+var _ Mailer = (*MailgunClient)(nil)
+
+// MailgunClient defines a mail client that sends emails via the
+// Mailgun HTTP API (messages.mime endpoint).
+type MailgunClient struct {
+	onSend *hook.Hook[*SendEvent]
+
+	// HTTPClient is an optional custom HTTP client to use
+	// (if not set, defaults to a client with [DefaultHTTPTimeout]).
+	HTTPClient *http.Client
+
+	// Endpoint is an optional custom API base url
+	// (if not set, defaults to "https://api.mailgun.net").
+	Endpoint string
+
+	// Domain is the Mailgun sending domain.
+	Domain string
+
+	// APIKey is the Mailgun private API key.
+	APIKey string
+}
+
+// OnSend implements [mailer.SendInterceptor] interface.
+func (c *MailgunClient) OnSend() *hook.Hook[*SendEvent] {
+	if c.onSend == nil {
+		c.onSend = &hook.Hook[*SendEvent]{}
+	}
+	return c.onSend
+}
+
+// Send implements [mailer.Mailer] interface.
+func (c *MailgunClient) Send(m *Message) error {
+	if c.onSend != nil {
+		return c.onSend.Trigger(&SendEvent{Message: m}, func(e *SendEvent) error {
+			return c.send(e.Message)
+		})
+	}
+
+	return c.send(m)
+}
+
+func (c *MailgunClient) send(m *Message) error {
+	raw, err := buildMIMEMessage(m)
+	if err != nil {
+		return err
+	}
+
+	body := new(bytes.Buffer)
+	mp := multipart.NewWriter(body)
+
+	// the "to" field of the mime endpoint specifies all message recipients
+	recipients := addressesToStrings(slices.Concat(m.To, m.Cc, m.Bcc), true)
+	for _, r := range recipients {
+		if err := mp.WriteField("to", r); err != nil {
+			return err
+		}
+	}
+
+	fw, err := mp.CreateFormFile("message", "message.mime")
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(raw); err != nil {
+		return err
+	}
+
+	if err := mp.Close(); err != nil {
+		return err
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.mailgun.net"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, apiURL(endpoint, "/v3/"+c.Domain+"/messages.mime"), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mp.FormDataContentType())
+	req.SetBasicAuth("api", c.APIKey)
+
+	return sendHTTPRequest(c.HTTPClient, req)
+}
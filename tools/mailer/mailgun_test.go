@@ -0,0 +1,93 @@
+package mailer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMailgunClientSend(t *testing.T) {
+	t.Parallel()
+
+	var lastReq *http.Request
+	var lastMessage string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/{domain}/messages.mime", func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f, _, err := r.FormFile("message")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+
+		raw, _ := io.ReadAll(f)
+		lastMessage = string(raw)
+
+		if r.PathValue("domain") == "invalid.example.com" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"id":"test","message":"Queued. Thank you."}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &MailgunClient{
+		Endpoint: server.URL,
+		Domain:   "mg.example.com",
+		APIKey:   "test_key",
+	}
+
+	err := client.Send(&Message{
+		From:        mail.Address{Address: "from@example.com"},
+		To:          []mail.Address{{Address: "to@example.com"}},
+		Cc:          []mail.Address{{Address: "cc@example.com"}},
+		Bcc:         []mail.Address{{Address: "bcc@example.com"}},
+		Subject:     "Test subject",
+		Text:        "Test",
+		Attachments: map[string]io.Reader{"a.txt": strings.NewReader("abc")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lastReq.URL.Path != "/v3/mg.example.com/messages.mime" {
+		t.Fatalf("Unexpected request path %q", lastReq.URL.Path)
+	}
+
+	username, password, _ := lastReq.BasicAuth()
+	if username != "api" || password != "test_key" {
+		t.Fatalf("Unexpected basic auth %q:%q", username, password)
+	}
+
+	expectedTo := []string{"to@example.com", "cc@example.com", "bcc@example.com"}
+	if !slices.Equal(lastReq.MultipartForm.Value["to"], expectedTo) {
+		t.Fatalf("Expected recipients %v, got %v", expectedTo, lastReq.MultipartForm.Value["to"])
+	}
+
+	for _, part := range []string{"Subject: Test subject", "CC: cc@example.com", `filename="a.txt"`} {
+		if !strings.Contains(lastMessage, part) {
+			t.Fatalf("Expected %q in the raw message:\n%s", part, lastMessage)
+		}
+	}
+
+	// error response
+	client.Domain = "invalid.example.com"
+	if err := client.Send(&Message{To: []mail.Address{{Address: "to@example.com"}}}); err == nil {
+		t.Fatal("Expected error response")
+	}
+}
@@ -0,0 +1,239 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxMessagePartsDepth is the max allowed nesting of the multipart message parts.
+const maxMessagePartsDepth = 10
+
+// ParseMessage parses the provided raw RFC 5322 message (ex. an inbound reply).
+//
+// The message parts are walked recursively - the first inline text/plain
+// and text/html parts are loaded as Message.Text and Message.HTML and
+// the remaining parts with filename are loaded as Message.Attachments
+// (or as Message.InlineAttachments if they have a Content-ID and inline disposition).
+//
+// All top level headers (including From, To, Subject, Message-Id, etc.)
+// are also loaded in Message.Headers using their canonical key format.
+//
+// The text parts and the encoded-word headers are converted to UTF-8
+// based on their charset (parts with unknown charset are returned as they are).
+func ParseMessage(r io.Reader) (*Message, error) {
+	raw, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Message{
+		Headers:           map[string]string{},
+		Attachments:       map[string]io.Reader{},
+		InlineAttachments: map[string]io.Reader{},
+	}
+
+	decoder := &mime.WordDecoder{CharsetReader: charsetReader}
+
+	for k, v := range raw.Header {
+		if len(v) == 0 {
+			continue
+		}
+
+		decoded, err := decoder.DecodeHeader(v[0])
+		if err != nil {
+			decoded = v[0]
+		}
+
+		m.Headers[textproto.CanonicalMIMEHeaderKey(k)] = decoded
+	}
+
+	m.Subject = m.Headers["Subject"]
+
+	from, err := parseAddressListHeader(decoder, raw.Header, "From")
+	if err != nil {
+		return nil, fmt.Errorf("invalid From header: %w", err)
+	}
+	if len(from) > 0 {
+		m.From = from[0]
+	}
+
+	m.To, err = parseAddressListHeader(decoder, raw.Header, "To")
+	if err != nil {
+		return nil, fmt.Errorf("invalid To header: %w", err)
+	}
+
+	m.Cc, err = parseAddressListHeader(decoder, raw.Header, "Cc")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Cc header: %w", err)
+	}
+
+	m.Bcc, err = parseAddressListHeader(decoder, raw.Header, "Bcc")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Bcc header: %w", err)
+	}
+
+	err = parseMessagePart(m, textproto.MIMEHeader(raw.Header), raw.Body, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func parseAddressListHeader(decoder *mime.WordDecoder, header mail.Header, key string) ([]mail.Address, error) {
+	value := header.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parser := mail.AddressParser{WordDecoder: decoder}
+
+	list, err := parser.ParseList(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]mail.Address, len(list))
+	for i, addr := range list {
+		result[i] = *addr
+	}
+
+	return result, nil
+}
+
+func parseMessagePart(m *Message, header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxMessagePartsDepth {
+		return errors.New("too deeply nested message parts")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+
+			err = parseMessagePart(m, part.Header, part, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	body = decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body)
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if decoded, err := (&mime.WordDecoder{CharsetReader: charsetReader}).DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+
+	// text body
+	if filename == "" && disposition != "attachment" && (mediaType == "text/plain" || mediaType == "text/html") {
+		if r, err := charsetReader(params["charset"], body); err == nil {
+			body = r
+		}
+
+		switch {
+		case mediaType == "text/plain" && m.Text == "":
+			text, err := io.ReadAll(body)
+			m.Text = string(text)
+			return err
+		case mediaType == "text/html" && m.HTML == "":
+			html, err := io.ReadAll(body)
+			m.HTML = string(html)
+			return err
+		}
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	contentId := strings.Trim(header.Get("Content-Id"), "<> ")
+
+	if filename == "" {
+		filename = contentId
+	}
+	if filename == "" {
+		filename = "attachment"
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			filename += exts[0]
+		}
+	}
+
+	if contentId != "" && disposition != "attachment" {
+		m.InlineAttachments[uniqueAttachmentName(m.InlineAttachments, filename)] = bytes.NewReader(data)
+	} else {
+		m.Attachments[uniqueAttachmentName(m.Attachments, filename)] = bytes.NewReader(data)
+	}
+
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// charsetReader returns a reader that converts the input from the
+// specified charset to UTF-8.
+//
+// It returns an error if the charset is not supported.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return input, nil
+	}
+
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+
+	return encoding.NewDecoder().Reader(input), nil
+}
+
+// uniqueAttachmentName returns the provided name or a prefixed version
+// of it if the name already exists in the attachments map.
+func uniqueAttachmentName(attachments map[string]io.Reader, name string) string {
+	result := name
+
+	for i := 2; ; i++ {
+		if _, ok := attachments[result]; !ok {
+			return result
+		}
+		result = fmt.Sprintf("%d_%s", i, name)
+	}
+}
@@ -0,0 +1,275 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	t.Parallel()
+
+	raw := strings.Join([]string{
+		`From: "John Doe" <john@example.com>`,
+		`To: support@example.com, "Jane" <jane@example.com>`,
+		`Cc: cc@example.com`,
+		`Subject: =?UTF-8?Q?Re:_Caf=C3=A9?=`,
+		`Message-ID: <abc@example.com>`,
+		`In-Reply-To: <xyz@example.com>`,
+		`MIME-Version: 1.0`,
+		`Content-Type: multipart/mixed; boundary="mixed"`,
+		``,
+		`--mixed`,
+		`Content-Type: multipart/alternative; boundary="alt"`,
+		``,
+		`--alt`,
+		`Content-Type: text/plain; charset=UTF-8`,
+		`Content-Transfer-Encoding: quoted-printable`,
+		``,
+		`Hello caf=C3=A9`,
+		`--alt`,
+		`Content-Type: text/html; charset=UTF-8`,
+		``,
+		`<p>Hello</p>`,
+		`--alt--`,
+		``,
+		`--mixed`,
+		`Content-Type: text/plain; name="a.txt"`,
+		`Content-Disposition: attachment; filename="a.txt"`,
+		`Content-Transfer-Encoding: base64`,
+		``,
+		`YWJj`,
+		`--mixed`,
+		`Content-Type: text/plain`,
+		`Content-Disposition: attachment; filename="a.txt"`,
+		``,
+		`def`,
+		`--mixed`,
+		`Content-Type: image/png`,
+		`Content-Disposition: inline`,
+		`Content-ID: <logo>`,
+		``,
+		`png`,
+		`--mixed`,
+		`Content-Type: application/pdf`,
+		`Content-Disposition: attachment`,
+		``,
+		`pdf`,
+		`--mixed--`,
+		``,
+	}, "\r\n")
+
+	m, err := ParseMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.From.String() != `"John Doe" <john@example.com>` {
+		t.Fatalf("Unexpected From %q", m.From.String())
+	}
+
+	expectedTo := []mail.Address{{Address: "support@example.com"}, {Name: "Jane", Address: "jane@example.com"}}
+	if len(m.To) != len(expectedTo) || m.To[0] != expectedTo[0] || m.To[1] != expectedTo[1] {
+		t.Fatalf("Expected To %v, got %v", expectedTo, m.To)
+	}
+
+	if len(m.Cc) != 1 || m.Cc[0].Address != "cc@example.com" {
+		t.Fatalf("Unexpected Cc %v", m.Cc)
+	}
+
+	if m.Subject != "Re: Café" {
+		t.Fatalf("Unexpected Subject %q", m.Subject)
+	}
+
+	if m.Headers["Message-Id"] != "<abc@example.com>" || m.Headers["In-Reply-To"] != "<xyz@example.com>" {
+		t.Fatalf("Unexpected headers %v", m.Headers)
+	}
+
+	if m.Text != "Hello café" {
+		t.Fatalf("Unexpected Text %q", m.Text)
+	}
+
+	if m.HTML != "<p>Hello</p>" {
+		t.Fatalf("Unexpected HTML %q", m.HTML)
+	}
+
+	expectedAttachments := map[string]string{
+		"a.txt":          "abc",
+		"2_a.txt":        "def",
+		"attachment.pdf": "pdf",
+	}
+	assertReadersMap(t, m.Attachments, expectedAttachments)
+
+	assertReadersMap(t, m.InlineAttachments, map[string]string{"logo": "png"})
+}
+
+func TestParseMessageSinglePart(t *testing.T) {
+	t.Parallel()
+
+	raw := "From: john@example.com\r\nTo: support@example.com\r\nSubject: test\r\n\r\nHello"
+
+	m, err := ParseMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Text != "Hello" || m.HTML != "" || len(m.Attachments) != 0 {
+		t.Fatalf("Unexpected message %#v", m)
+	}
+}
+
+func TestParseMessageCharsets(t *testing.T) {
+	t.Parallel()
+
+	raw := strings.Join([]string{
+		`From: =?windows-1251?B?yOLg7Q==?= <ivan@example.com>`,
+		`To: support@example.com`,
+		`Subject: =?koi8-r?B?8NLJ18XU?=`,
+		`Content-Type: multipart/mixed; boundary=b1`,
+		``,
+		`--b1`,
+		`Content-Type: text/plain; charset=ISO-8859-1`,
+		`Content-Transfer-Encoding: quoted-printable`,
+		``,
+		`Caf=E9`,
+		`--b1`,
+		`Content-Type: text/html; charset=windows-1251`,
+		``,
+		"<p>\xc7\xe4\xf0\xe0\xe2\xe5\xe9</p>",
+		`--b1`,
+		`Content-Type: text/plain; charset=windows-1251`,
+		`Content-Disposition: attachment; filename="=?ISO-8859-2?Q?=BElu=BB.txt?="`,
+		``,
+		"\xc7\xe4",
+		`--b1--`,
+	}, "\r\n")
+
+	m, err := ParseMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.From.Name != "Иван" || m.From.Address != "ivan@example.com" {
+		t.Fatalf("Expected From %q, got %q", "Иван <ivan@example.com>", m.From.String())
+	}
+
+	if m.Subject != "Привет" {
+		t.Fatalf("Expected Subject %q, got %q", "Привет", m.Subject)
+	}
+
+	if m.Text != "Café" {
+		t.Fatalf("Expected Text %q, got %q", "Café", m.Text)
+	}
+
+	if m.HTML != "<p>Здравей</p>" {
+		t.Fatalf("Expected HTML %q, got %q", "<p>Здравей</p>", m.HTML)
+	}
+
+	// attachments content is not converted
+	assertReadersMap(t, m.Attachments, map[string]string{"žluť.txt": "\xc7\xe4"})
+
+	t.Run("unknown charset", func(t *testing.T) {
+		m, err := ParseMessage(strings.NewReader("From: john@example.com\r\nContent-Type: text/plain; charset=unknown\r\n\r\nHello\xff"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Text != "Hello\xff" {
+			t.Fatalf("Expected the text to be returned as it is, got %q", m.Text)
+		}
+	})
+}
+
+func TestParseMessageRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	original := &Message{
+		From:              mail.Address{Name: "Sender", Address: "from@example.com"},
+		To:                []mail.Address{{Address: "to@example.com"}},
+		Subject:           "Round trip",
+		HTML:              "<p>Test</p>",
+		Text:              "Test",
+		Attachments:       map[string]io.Reader{"a.txt": strings.NewReader("abc")},
+		InlineAttachments: map[string]io.Reader{"b.txt": strings.NewReader("def")},
+	}
+
+	raw, err := buildMIMEMessage(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ParseMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.From != original.From || m.Subject != original.Subject || m.HTML != original.HTML || m.Text != original.Text {
+		t.Fatalf("Unexpected parsed message %#v", m)
+	}
+
+	assertReadersMap(t, m.Attachments, map[string]string{"a.txt": "abc"})
+	assertReadersMap(t, m.InlineAttachments, map[string]string{"b.txt": "def"})
+}
+
+func TestParseMessageErrors(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name string
+		raw  string
+	}{
+		{"empty", ""},
+		{"invalid From", "From: invalid\r\n\r\nHello"},
+		{
+			"unterminated multipart",
+			"From: john@example.com\r\nContent-Type: multipart/mixed; boundary=b\r\n\r\n--b\r\n\r\nHello",
+		},
+		{"too deeply nested", nestedMultipartMessage(15)},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if _, err := ParseMessage(strings.NewReader(s.raw)); err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func nestedMultipartMessage(depth int) string {
+	var raw strings.Builder
+
+	raw.WriteString("From: john@example.com\r\nContent-Type: multipart/mixed; boundary=b0\r\n\r\n")
+
+	for i := 1; i <= depth; i++ {
+		fmt.Fprintf(&raw, "--b%d\r\nContent-Type: multipart/mixed; boundary=b%d\r\n\r\n", i-1, i)
+	}
+
+	return raw.String()
+}
+
+func assertReadersMap(t *testing.T, readers map[string]io.Reader, expected map[string]string) {
+	t.Helper()
+
+	if len(readers) != len(expected) {
+		t.Fatalf("Expected %d items, got %d (%v)", len(expected), len(readers), readers)
+	}
+
+	for name, content := range expected {
+		r, ok := readers[name]
+		if !ok {
+			t.Fatalf("Missing %q", name)
+		}
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != content {
+			t.Fatalf("Expected %q content %q, got %q", name, content, data)
+		}
+	}
+}
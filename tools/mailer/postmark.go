@@ -0,0 +1,153 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+var _ Mailer = (*PostmarkClient)(nil)
+
+// PostmarkClient defines a mail client that sends emails via the
+// Postmark HTTP API (or any other API with compatible "/email" endpoint).
+type PostmarkClient struct {
+	onSend *hook.Hook[*SendEvent]
+
+	// HTTPClient is an optional custom HTTP client to use
+	// (if not set, defaults to a client with [DefaultHTTPTimeout]).
+	HTTPClient *http.Client
+
+	// Endpoint is an optional custom API base url
+	// (if not set, defaults to "https://api.postmarkapp.com").
+	Endpoint string
+
+	// ServerToken is the Postmark server API token.
+	ServerToken string
+}
+
+// OnSend implements [mailer.SendInterceptor] interface.
+func (c *PostmarkClient) OnSend() *hook.Hook[*SendEvent] {
+	if c.onSend == nil {
+		c.onSend = &hook.Hook[*SendEvent]{}
+	}
+	return c.onSend
+}
+
+// Send implements [mailer.Mailer] interface.
+func (c *PostmarkClient) Send(m *Message) error {
+	if c.onSend != nil {
+		return c.onSend.Trigger(&SendEvent{Message: m}, func(e *SendEvent) error {
+			return c.send(e.Message)
+		})
+	}
+
+	return c.send(m)
+}
+
+type postmarkHeader struct {
+	Name  string
+	Value string
+}
+
+type postmarkAttachment struct {
+	Name        string
+	Content     []byte // []byte is serialized as base64 string
+	ContentType string
+	ContentID   string `json:",omitempty"`
+}
+
+type postmarkMessage struct {
+	From        string
+	To          string
+	Cc          string               `json:",omitempty"`
+	Bcc         string               `json:",omitempty"`
+	Subject     string               `json:",omitempty"`
+	HtmlBody    string               `json:",omitempty"`
+	TextBody    string               `json:",omitempty"`
+	Headers     []postmarkHeader     `json:",omitempty"`
+	Attachments []postmarkAttachment `json:",omitempty"`
+}
+
+func (c *PostmarkClient) send(m *Message) error {
+	payload := postmarkMessage{
+		From:     m.From.String(),
+		To:       strings.Join(addressesToStrings(m.To, true), ","),
+		Cc:       strings.Join(addressesToStrings(m.Cc, true), ","),
+		Bcc:      strings.Join(addressesToStrings(m.Bcc, true), ","),
+		Subject:  m.Subject,
+		HtmlBody: m.HTML,
+		TextBody: m.Text,
+	}
+
+	if payload.TextBody == "" && m.HTML != "" {
+		// try to generate a plain text version of the HTML
+		if plain, err := html2Text(m.HTML); err == nil {
+			payload.TextBody = plain
+		}
+	}
+
+	for k, v := range m.Headers {
+		payload.Headers = append(payload.Headers, postmarkHeader{Name: k, Value: v})
+	}
+
+	for name, data := range m.Attachments {
+		attachment, err := newPostmarkAttachment(name, data, false)
+		if err != nil {
+			return err
+		}
+		payload.Attachments = append(payload.Attachments, attachment)
+	}
+
+	for name, data := range m.InlineAttachments {
+		attachment, err := newPostmarkAttachment(name, data, true)
+		if err != nil {
+			return err
+		}
+		payload.Attachments = append(payload.Attachments, attachment)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.postmarkapp.com"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, apiURL(endpoint, "/email"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", c.ServerToken)
+
+	return sendHTTPRequest(c.HTTPClient, req)
+}
+
+func newPostmarkAttachment(name string, data io.Reader, inline bool) (postmarkAttachment, error) {
+	attachment := postmarkAttachment{Name: name}
+
+	r, mime, err := detectReaderMimeType(data)
+	if err != nil {
+		return attachment, err
+	}
+	attachment.ContentType = mime
+
+	attachment.Content, err = io.ReadAll(r)
+	if err != nil {
+		return attachment, err
+	}
+
+	if inline {
+		attachment.ContentID = "cid:" + name
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,94 @@
+package mailer
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestPostmarkClientSend(t *testing.T) {
+	t.Parallel()
+
+	var lastReq *http.Request
+	var lastPayload postmarkMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+		lastPayload = postmarkMessage{}
+
+		if err := json.NewDecoder(r.Body).Decode(&lastPayload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Header.Get("X-Postmark-Server-Token") != "test_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"ErrorCode":10,"Message":"invalid token"}`))
+			return
+		}
+
+		w.Write([]byte(`{"ErrorCode":0,"Message":"OK"}`))
+	}))
+	defer server.Close()
+
+	client := &PostmarkClient{
+		Endpoint:    server.URL,
+		ServerToken: "test_token",
+	}
+
+	err := client.Send(&Message{
+		From:              mail.Address{Name: "Sender", Address: "from@example.com"},
+		To:                []mail.Address{{Address: "to1@example.com"}, {Name: "B", Address: "to2@example.com"}},
+		Bcc:               []mail.Address{{Address: "bcc@example.com"}},
+		Subject:           "Test subject",
+		HTML:              "<p>Test</p>",
+		Headers:           map[string]string{"X-Test": "123"},
+		Attachments:       map[string]io.Reader{"a.txt": strings.NewReader("abc")},
+		InlineAttachments: map[string]io.Reader{"b.txt": strings.NewReader("def")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lastReq.Method != http.MethodPost || lastReq.URL.Path != "/email" {
+		t.Fatalf("Unexpected request %s %s", lastReq.Method, lastReq.URL.Path)
+	}
+
+	if lastPayload.From != `"Sender" <from@example.com>` {
+		t.Fatalf("Unexpected From %q", lastPayload.From)
+	}
+	if lastPayload.To != `to1@example.com,"B" <to2@example.com>` {
+		t.Fatalf("Unexpected To %q", lastPayload.To)
+	}
+	if lastPayload.Bcc != "bcc@example.com" || lastPayload.Cc != "" {
+		t.Fatalf("Unexpected Bcc/Cc %q/%q", lastPayload.Bcc, lastPayload.Cc)
+	}
+	if lastPayload.HtmlBody != "<p>Test</p>" || lastPayload.TextBody != "Test" {
+		t.Fatalf("Unexpected body %q/%q", lastPayload.HtmlBody, lastPayload.TextBody)
+	}
+	if len(lastPayload.Headers) != 1 || lastPayload.Headers[0].Name != "X-Test" || lastPayload.Headers[0].Value != "123" {
+		t.Fatalf("Unexpected headers %v", lastPayload.Headers)
+	}
+
+	attachments := map[string]postmarkAttachment{}
+	for _, a := range lastPayload.Attachments {
+		attachments[a.Name] = a
+	}
+	if a := attachments["a.txt"]; string(a.Content) != "abc" || a.ContentID != "" || !strings.HasPrefix(a.ContentType, "text/plain") {
+		t.Fatalf("Unexpected attachment %v", a)
+	}
+	if a := attachments["b.txt"]; string(a.Content) != "def" || a.ContentID != "cid:b.txt" {
+		t.Fatalf("Unexpected inline attachment %v", a)
+	}
+
+	// error response
+	client.ServerToken = "invalid"
+	err = client.Send(&Message{To: []mail.Address{{Address: "to@example.com"}}})
+	if err == nil || !strings.Contains(err.Error(), "invalid token") {
+		t.Fatalf("Expected invalid token error, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/mail"
+)
+
+var _ Mailer = (*QueueMailer)(nil)
+
+// Queue defines a persistent messages queue interface.
+type Queue interface {
+	// Enqueue stores the provided message for later delivery.
+	Enqueue(message *Message) error
+}
+
+// QueueMailer implements [mailer.Mailer] interface and defines a mail
+// client that enqueues the messages in the provided Queue instead of
+// sending them inline.
+//
+// The actual delivery is left to the Queue implementation
+// (usually a background worker with retries).
+type QueueMailer struct {
+	Queue Queue
+}
+
+// Send implements [mailer.Mailer] interface.
+func (c *QueueMailer) Send(m *Message) error {
+	return c.Queue.Enqueue(m)
+}
+
+// -------------------------------------------------------------------
+
+// encodedMessage is the serializable representation of [Message]
+// with fully loaded attachments.
+type encodedMessage struct {
+	From              mail.Address      `json:"from"`
+	To                []mail.Address    `json:"to"`
+	Bcc               []mail.Address    `json:"bcc"`
+	Cc                []mail.Address    `json:"cc"`
+	Subject           string            `json:"subject"`
+	HTML              string            `json:"html"`
+	Text              string            `json:"text"`
+	Headers           map[string]string `json:"headers"`
+	Attachments       map[string][]byte `json:"attachments"`
+	InlineAttachments map[string][]byte `json:"inlineAttachments"`
+}
+
+// EncodeMessage serializes the provided message into JSON
+// (including its attachments) so that it can be persisted and
+// later restored with [DecodeMessage].
+//
+// Note that the message attachment readers are fully consumed.
+func EncodeMessage(m *Message) ([]byte, error) {
+	attachments, err := readAttachments(m.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	inlineAttachments, err := readAttachments(m.InlineAttachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encodedMessage{
+		From:              m.From,
+		To:                m.To,
+		Bcc:               m.Bcc,
+		Cc:                m.Cc,
+		Subject:           m.Subject,
+		HTML:              m.HTML,
+		Text:              m.Text,
+		Headers:           m.Headers,
+		Attachments:       attachments,
+		InlineAttachments: inlineAttachments,
+	})
+}
+
+// DecodeMessage restores a message serialized with [EncodeMessage].
+func DecodeMessage(data []byte) (*Message, error) {
+	encoded := encodedMessage{}
+
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		From:              encoded.From,
+		To:                encoded.To,
+		Bcc:               encoded.Bcc,
+		Cc:                encoded.Cc,
+		Subject:           encoded.Subject,
+		HTML:              encoded.HTML,
+		Text:              encoded.Text,
+		Headers:           encoded.Headers,
+		Attachments:       attachmentReaders(encoded.Attachments),
+		InlineAttachments: attachmentReaders(encoded.InlineAttachments),
+	}, nil
+}
+
+func readAttachments(attachments map[string]io.Reader) (map[string][]byte, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string][]byte, len(attachments))
+
+	for name, r := range attachments {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = data
+	}
+
+	return result, nil
+}
+
+func attachmentReaders(attachments map[string][]byte) map[string]io.Reader {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	result := make(map[string]io.Reader, len(attachments))
+
+	for name, data := range attachments {
+		result[name] = bytes.NewReader(data)
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+package mailer_test
+
+import (
+	"errors"
+	"io"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+type testQueue struct {
+	messages []*mailer.Message
+	err      error
+}
+
+func (q *testQueue) Enqueue(m *mailer.Message) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	q.messages = append(q.messages, m)
+
+	return nil
+}
+
+func TestQueueMailerSend(t *testing.T) {
+	t.Parallel()
+
+	queue := &testQueue{}
+	client := &mailer.QueueMailer{Queue: queue}
+
+	message := &mailer.Message{Subject: "test"}
+
+	if err := client.Send(message); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queue.messages) != 1 || queue.messages[0] != message {
+		t.Fatalf("Expected the message to be enqueued, got %v", queue.messages)
+	}
+
+	queue.err = errors.New("test")
+
+	if err := client.Send(message); err == nil {
+		t.Fatal("Expected the queue error to be returned")
+	}
+}
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	t.Parallel()
+
+	message := &mailer.Message{
+		From:              mail.Address{Name: "Sender", Address: "sender@example.com"},
+		To:                []mail.Address{{Address: "to@example.com"}},
+		Bcc:               []mail.Address{{Address: "bcc@example.com"}},
+		Cc:                []mail.Address{{Address: "cc@example.com"}},
+		Subject:           "Test subject",
+		HTML:              "<p>Test html</p>",
+		Text:              "Test text",
+		Headers:           map[string]string{"X-Test": "123"},
+		Attachments:       map[string]io.Reader{"a.txt": strings.NewReader("abc")},
+		InlineAttachments: map[string]io.Reader{"b.txt": strings.NewReader("def")},
+	}
+
+	encoded, err := mailer.EncodeMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := mailer.DecodeMessage(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.From != message.From ||
+		decoded.Subject != message.Subject ||
+		decoded.HTML != message.HTML ||
+		decoded.Text != message.Text ||
+		decoded.Headers["X-Test"] != "123" ||
+		len(decoded.To) != 1 || decoded.To[0] != message.To[0] ||
+		len(decoded.Bcc) != 1 || decoded.Bcc[0] != message.Bcc[0] ||
+		len(decoded.Cc) != 1 || decoded.Cc[0] != message.Cc[0] {
+		t.Fatalf("The decoded message doesn't match the original one:\n%#v", decoded)
+	}
+
+	attachments := map[string]map[string]io.Reader{
+		"abc": decoded.Attachments,
+		"def": decoded.InlineAttachments,
+	}
+	for expected, items := range attachments {
+		if len(items) != 1 {
+			t.Fatalf("Expected 1 attachment, got %d", len(items))
+		}
+
+		for _, r := range items {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != expected {
+				t.Fatalf("Expected attachment %q, got %q", expected, data)
+			}
+		}
+	}
+}
+
+func TestDecodeMessageInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := mailer.DecodeMessage([]byte("invalid")); err == nil {
+		t.Fatal("Expected decode error")
+	}
+}
@@ -0,0 +1,179 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+var _ Mailer = (*SESClient)(nil)
+
+const (
+	sesService       = "ses"
+	sesSignAlgorithm = "AWS4-HMAC-SHA256"
+	sesDateFormat    = "20060102T150405Z"
+)
+
+// SESClient defines a mail client that sends emails via the
+// Amazon SES v2 HTTP API (SendEmail with raw content).
+type SESClient struct {
+	onSend *hook.Hook[*SendEvent]
+
+	// HTTPClient is an optional custom HTTP client to use
+	// (if not set, defaults to a client with [DefaultHTTPTimeout]).
+	HTTPClient *http.Client
+
+	// Endpoint is an optional custom API base url
+	// (if not set, defaults to "https://email.{Region}.amazonaws.com").
+	Endpoint string
+
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// OnSend implements [mailer.SendInterceptor] interface.
+func (c *SESClient) OnSend() *hook.Hook[*SendEvent] {
+	if c.onSend == nil {
+		c.onSend = &hook.Hook[*SendEvent]{}
+	}
+	return c.onSend
+}
+
+// Send implements [mailer.Mailer] interface.
+func (c *SESClient) Send(m *Message) error {
+	if c.onSend != nil {
+		return c.onSend.Trigger(&SendEvent{Message: m}, func(e *SendEvent) error {
+			return c.send(e.Message)
+		})
+	}
+
+	return c.send(m)
+}
+
+func (c *SESClient) send(m *Message) error {
+	raw, err := buildMIMEMessage(m)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"FromEmailAddress": m.From.String(),
+		"Destination": map[string]any{
+			"ToAddresses":  addressesToStrings(m.To, true),
+			"CcAddresses":  addressesToStrings(m.Cc, true),
+			"BccAddresses": addressesToStrings(m.Bcc, true),
+		},
+		"Content": map[string]any{
+			"Raw": map[string]any{
+				"Data": raw, // []byte is serialized as base64 string
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://email." + c.Region + ".amazonaws.com"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, apiURL(endpoint, "/v2/email/outbound-emails"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	c.sign(req, body, time.Now().UTC())
+
+	return sendHTTPRequest(c.HTTPClient, req)
+}
+
+// sign signs the provided request per AWS Signature v4.
+//
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+func (c *SESClient) sign(req *http.Request, body []byte, now time.Time) {
+	dateTime := now.Format(sesDateFormat)
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", dateTime)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+
+	// canonical headers
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+
+	signedHeaders := make([]string, 0, len(headers))
+	for k := range headers {
+		signedHeaders = append(signedHeaders, k)
+	}
+	slices.Sort(signedHeaders)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range signedHeaders {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHashHex,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := strings.Join([]string{date, c.Region, sesService, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		sesSignAlgorithm,
+		dateTime,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := sesHMAC([]byte("AWS4"+c.SecretKey), date)
+	signingKey = sesHMAC(signingKey, c.Region)
+	signingKey = sesHMAC(signingKey, sesService)
+	signingKey = sesHMAC(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(sesHMAC(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sesSignAlgorithm,
+		c.AccessKey,
+		scope,
+		strings.Join(signedHeaders, ";"),
+		signature,
+	))
+}
+
+func sesHMAC(key []byte, content string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
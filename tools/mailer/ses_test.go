@@ -0,0 +1,170 @@
+package mailer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSESClientSend(t *testing.T) {
+	t.Parallel()
+
+	var lastReq *http.Request
+	var lastBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+		lastBody, _ = io.ReadAll(r.Body)
+
+		if strings.Contains(string(lastBody), "fail@example.com") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"test_error"}`))
+			return
+		}
+
+		w.Write([]byte(`{"MessageId":"test"}`))
+	}))
+	defer server.Close()
+
+	client := &SESClient{
+		Endpoint:  server.URL,
+		Region:    "eu-west-1",
+		AccessKey: "test_access",
+		SecretKey: "test_secret",
+	}
+
+	err := client.Send(&Message{
+		From:    mail.Address{Name: "Sender", Address: "from@example.com"},
+		To:      []mail.Address{{Address: "to@example.com"}},
+		Bcc:     []mail.Address{{Address: "bcc@example.com"}},
+		Subject: "Test subject",
+		HTML:    "<p>Test</p>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lastReq.Method != http.MethodPost || lastReq.URL.Path != "/v2/email/outbound-emails" {
+		t.Fatalf("Unexpected request %s %s", lastReq.Method, lastReq.URL.Path)
+	}
+
+	auth := lastReq.Header.Get("Authorization")
+	expectedAuthParts := []string{
+		"AWS4-HMAC-SHA256 ",
+		"Credential=test_access/",
+		"/eu-west-1/ses/aws4_request",
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date",
+		"Signature=",
+	}
+	for _, part := range expectedAuthParts {
+		if !strings.Contains(auth, part) {
+			t.Fatalf("Expected %q in the Authorization header, got %q", part, auth)
+		}
+	}
+
+	payload := struct {
+		FromEmailAddress string
+		Destination      struct {
+			ToAddresses  []string
+			BccAddresses []string
+		}
+		Content struct {
+			Raw struct {
+				Data []byte
+			}
+		}
+	}{}
+	if err := json.Unmarshal(lastBody, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.FromEmailAddress != `"Sender" <from@example.com>` {
+		t.Fatalf("Unexpected FromEmailAddress %q", payload.FromEmailAddress)
+	}
+	if len(payload.Destination.ToAddresses) != 1 || len(payload.Destination.BccAddresses) != 1 {
+		t.Fatalf("Unexpected destination %v", payload.Destination)
+	}
+
+	raw := string(payload.Content.Raw.Data)
+	for _, part := range []string{"Subject: Test subject", "To: to@example.com", "<p>Test</p>"} {
+		if !strings.Contains(raw, part) {
+			t.Fatalf("Expected %q in the raw message:\n%s", part, raw)
+		}
+	}
+	if strings.Contains(raw, "bcc@example.com") {
+		t.Fatalf("Expected the Bcc recipients to not be included in the raw message:\n%s", raw)
+	}
+
+	// error response
+	err = client.Send(&Message{To: []mail.Address{{Address: "fail@example.com"}}})
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected HTTPError, got %v", err)
+	}
+	if httpErr.Status != http.StatusBadRequest || !strings.Contains(httpErr.Error(), "test_error") {
+		t.Fatalf("Unexpected HTTPError %v", httpErr)
+	}
+}
+
+func TestSESClientSign(t *testing.T) {
+	t.Parallel()
+
+	client := &SESClient{Region: "us-east-1", AccessKey: "AKID", SecretKey: "SECRET"}
+
+	scenarios := []struct {
+		name string
+		body string
+	}{
+		{"empty body", ""},
+		{"non-empty body", `{"test":123}`},
+	}
+
+	signatures := map[string]bool{}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "https://email.us-east-1.amazonaws.com/v2/email/outbound-emails", strings.NewReader(s.body))
+
+			now := mustParseTime(t, "2024-01-02T03:04:05Z")
+
+			client.sign(req, []byte(s.body), now)
+
+			if v := req.Header.Get("X-Amz-Date"); v != "20240102T030405Z" {
+				t.Fatalf("Unexpected X-Amz-Date %q", v)
+			}
+
+			auth := req.Header.Get("Authorization")
+			if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20240102/us-east-1/ses/aws4_request, ") {
+				t.Fatalf("Unexpected Authorization header %q", auth)
+			}
+
+			// the same request must be signed the same way
+			req2, _ := http.NewRequest(http.MethodPost, req.URL.String(), strings.NewReader(s.body))
+			client.sign(req2, []byte(s.body), now)
+			if auth != req2.Header.Get("Authorization") {
+				t.Fatalf("Expected deterministic signature, got\n%s\n%s", auth, req2.Header.Get("Authorization"))
+			}
+
+			signatures[auth] = true
+		})
+	}
+
+	if len(signatures) != len(scenarios) {
+		t.Fatalf("Expected different signatures for the different payloads")
+	}
+}
+
+func mustParseTime(t testing.TB, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/domodwyer/mailyak/v3"
 	"github.com/pocketbase/pocketbase/tools/hook"
-	"github.com/pocketbase/pocketbase/tools/security"
 )
 
 var _ Mailer = (*SMTPClient)(nil)
@@ -86,69 +85,8 @@ func (c *SMTPClient) send(m *Message) error {
 		yak.LocalName(c.LocalName)
 	}
 
-	if m.From.Name != "" {
-		yak.FromName(m.From.Name)
-	}
-	yak.From(m.From.Address)
-	yak.Subject(m.Subject)
-	yak.HTML().Set(m.HTML)
-
-	if m.Text == "" {
-		// try to generate a plain text version of the HTML
-		if plain, err := html2Text(m.HTML); err == nil {
-			yak.Plain().Set(plain)
-		}
-	} else {
-		yak.Plain().Set(m.Text)
-	}
-
-	if len(m.To) > 0 {
-		yak.To(addressesToStrings(m.To, true)...)
-	}
-
-	if len(m.Bcc) > 0 {
-		yak.Bcc(addressesToStrings(m.Bcc, true)...)
-	}
-
-	if len(m.Cc) > 0 {
-		yak.Cc(addressesToStrings(m.Cc, true)...)
-	}
-
-	// add regular attachements (if any)
-	for name, data := range m.Attachments {
-		r, mime, err := detectReaderMimeType(data)
-		if err != nil {
-			return err
-		}
-		yak.AttachWithMimeType(name, r, mime)
-	}
-
-	// add inline attachments (if any)
-	for name, data := range m.InlineAttachments {
-		r, mime, err := detectReaderMimeType(data)
-		if err != nil {
-			return err
-		}
-		yak.AttachInlineWithMimeType(name, r, mime)
-	}
-
-	// add custom headers (if any)
-	var hasMessageId bool
-	for k, v := range m.Headers {
-		if strings.EqualFold(k, "Message-ID") {
-			hasMessageId = true
-		}
-		yak.AddHeader(k, v)
-	}
-	if !hasMessageId {
-		// add a default message id if missing
-		fromParts := strings.Split(m.From.Address, "@")
-		if len(fromParts) == 2 {
-			yak.AddHeader("Message-ID", fmt.Sprintf("<%s@%s>",
-				security.PseudorandomString(15),
-				fromParts[1],
-			))
-		}
+	if err := fillMailYak(yak, m); err != nil {
+		return err
 	}
 
 	return yak.Send()
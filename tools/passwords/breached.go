@@ -0,0 +1,250 @@
+package passwords
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BreachedPrefixLength is the length of the SHA-1 hash prefix used for
+// the k-anonymity range lookups (the same as in the HIBP Pwned Passwords API).
+const BreachedPrefixLength = 5
+
+// BreachedHashParts returns the uppercase hex encoded SHA-1 hash of the
+// provided password split into a k-anonymity range prefix and suffix.
+func BreachedHashParts(password string) (prefix string, suffix string) {
+	sum := sha1.Sum([]byte(password))
+
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	return hash[:BreachedPrefixLength], hash[BreachedPrefixLength:]
+}
+
+// BreachedCount returns the number of times the provided password
+// was found in the HIBP formatted source (0 if not found).
+//
+// Only the SHA-1 hash prefix of the password is used to load
+// the matching range and the suffix comparison is done in memory.
+//
+// See [BreachedRange] for the supported source formats.
+func BreachedCount(source string, password string) (int, error) {
+	prefix, suffix := BreachedHashParts(password)
+
+	hashes, err := BreachedRange(source, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	return hashes[suffix], nil
+}
+
+// BreachedRange loads all hash suffixes and their breach counts
+// matching the specified 5 characters SHA-1 hash prefix.
+//
+// The source could be either:
+//   - a directory with HIBP range files, each named after the hash prefix
+//     (with or without ".txt" extension) and containing "SUFFIX:COUNT" lines
+//     (this is the format of the official HIBP range API and downloader)
+//   - a single file with "HASH:COUNT" lines ordered by hash
+//     (this is the format of the HIBP "ordered by hash" SHA-1 dump)
+//
+// The sorted single file is searched with binary search so it is
+// not loaded in memory and could be arbitrary large.
+func BreachedRange(source string, prefix string) (map[string]int, error) {
+	prefix = strings.ToUpper(prefix)
+
+	if len(prefix) != BreachedPrefixLength || !isHex(prefix) {
+		return nil, fmt.Errorf("invalid hash prefix %q", prefix)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return loadRangeFile(source, prefix)
+	}
+
+	return searchSortedFile(source, info.Size(), prefix)
+}
+
+func loadRangeFile(dir string, prefix string) (map[string]int, error) {
+	f, err := os.Open(filepath.Join(dir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(filepath.Join(dir, prefix))
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]int{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	result := map[string]int{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		suffix, count, ok := parseHashLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		// allow range files with full hashes
+		if len(suffix) == 40 {
+			if !strings.HasPrefix(suffix, prefix) {
+				continue
+			}
+			suffix = suffix[BreachedPrefixLength:]
+		}
+
+		result[suffix] = count
+	}
+
+	return result, scanner.Err()
+}
+
+func searchSortedFile(path string, size int64, prefix string) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// find the offset of the first line with hash >= prefix
+	var lo, hi int64 = 0, size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		lineStart, line, err := readLineAt(f, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		if lineStart >= size || line == "" || strings.ToUpper(line[:min(len(line), BreachedPrefixLength)]) >= prefix {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	start, _, err := readLineAt(f, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	result := map[string]int{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		hash, count, ok := parseHashLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		if !strings.HasPrefix(hash, prefix) {
+			if hash > prefix {
+				break
+			}
+			continue
+		}
+
+		result[hash[BreachedPrefixLength:]] = count
+	}
+
+	return result, scanner.Err()
+}
+
+// readLineAt returns the start offset and the content of the first
+// full line that begins at or after the specified offset.
+func readLineAt(f *os.File, offset int64) (int64, string, error) {
+	start := offset
+
+	// move to the beginning of the next line unless we are already at one
+	if offset > 0 {
+		prev := make([]byte, 1)
+		if _, err := f.ReadAt(prev, offset-1); err != nil {
+			return 0, "", err
+		}
+
+		if prev[0] != '\n' {
+			buf := make([]byte, 128)
+			for {
+				n, err := f.ReadAt(buf, start)
+				if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+					start += int64(i) + 1
+					break
+				}
+				start += int64(n)
+				if err == io.EOF {
+					return start, "", nil
+				}
+				if err != nil {
+					return 0, "", err
+				}
+			}
+		}
+	}
+
+	buf := make([]byte, 128)
+	n, err := f.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	return start, strings.TrimSpace(string(line)), nil
+}
+
+// parseHashLine parses a single "HASH:COUNT" line.
+func parseHashLine(line string) (string, int, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", 0, false
+	}
+
+	hash, rawCount, _ := strings.Cut(line, ":")
+
+	hash = strings.ToUpper(hash)
+	if !isHex(hash) {
+		return "", 0, false
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(rawCount))
+	if err != nil || count <= 0 {
+		count = 1
+	}
+
+	return hash, count, true
+}
+
+func isHex(str string) bool {
+	if str == "" {
+		return false
+	}
+
+	for _, c := range str {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'F') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+package passwords_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/passwords"
+)
+
+func TestBreachedHashParts(t *testing.T) {
+	t.Parallel()
+
+	prefix, suffix := passwords.BreachedHashParts("password")
+
+	// sha1("password") = 5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8
+	if prefix != "5BAA6" {
+		t.Fatalf("Expected prefix %q, got %q", "5BAA6", prefix)
+	}
+
+	if suffix != "1E4C9B93F3F0682250B6CF8331B7EE68FD8" {
+		t.Fatalf("Expected suffix %q, got %q", "1E4C9B93F3F0682250B6CF8331B7EE68FD8", suffix)
+	}
+}
+
+func TestBreachedCountSortedFile(t *testing.T) {
+	t.Parallel()
+
+	breached := map[string]int{
+		"password": 100,
+		"123456":   200,
+		"qwerty":   3,
+	}
+
+	// generate a sorted HIBP-like file with filler hashes around the breached ones
+	lines := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		prefix, suffix := passwords.BreachedHashParts("filler" + strconv.Itoa(i))
+		lines = append(lines, prefix+suffix+":"+strconv.Itoa(i+1))
+	}
+	for password, count := range breached {
+		prefix, suffix := passwords.BreachedHashParts(password)
+		lines = append(lines, prefix+suffix+":"+strconv.Itoa(count))
+	}
+	slices.Sort(lines)
+
+	file := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		password string
+		expected int
+	}{
+		{"password", 100},
+		{"123456", 200},
+		{"qwerty", 3},
+		{"filler0", 1},
+		{"filler999", 1000},
+		{"not_breached_password", 0},
+		{"", 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.password, func(t *testing.T) {
+			count, err := passwords.BreachedCount(file, s.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != s.expected {
+				t.Fatalf("Expected count %d, got %d", s.expected, count)
+			}
+		})
+	}
+}
+
+func TestBreachedCountRangeDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	prefix, suffix := passwords.BreachedHashParts("password")
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\n" + suffix + ":3861493\n"
+	if err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// range file without extension
+	prefix2, suffix2 := passwords.BreachedHashParts("123456")
+	if err := os.WriteFile(filepath.Join(dir, prefix2), []byte(suffix2+":5"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		password string
+		expected int
+	}{
+		{"password", 3861493},
+		{"123456", 5},
+		{"not_breached_password", 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.password, func(t *testing.T) {
+			count, err := passwords.BreachedCount(dir, s.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != s.expected {
+				t.Fatalf("Expected count %d, got %d", s.expected, count)
+			}
+		})
+	}
+}
+
+func TestBreachedRangeErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	if _, err := passwords.BreachedRange(dir, "ABC"); err == nil {
+		t.Fatal("Expected invalid prefix length error")
+	}
+
+	if _, err := passwords.BreachedRange(dir, "XYZ12"); err == nil {
+		t.Fatal("Expected invalid prefix characters error")
+	}
+
+	if _, err := passwords.BreachedRange(filepath.Join(dir, "missing"), "ABCDE"); err == nil {
+		t.Fatal("Expected missing source error")
+	}
+
+	result, err := passwords.BreachedRange(dir, "abcde")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 0 {
+		t.Fatalf("Expected empty range, got %v", result)
+	}
+}
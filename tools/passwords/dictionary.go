@@ -0,0 +1,48 @@
+package passwords
+
+// commonPasswords is a small set of the most frequently used passwords
+// (all lowercased) that are always considered as very weak.
+var commonPasswords = toSet([]string{
+	"123456", "123456789", "12345678", "12345", "1234567", "1234567890", "123123", "111111",
+	"000000", "654321", "666666", "121212", "112233", "123321", "159753", "987654321",
+	"password", "password1", "password123", "passw0rd", "p@ssw0rd", "pass123", "passpass",
+	"qwerty", "qwerty123", "qwertyuiop", "1q2w3e4r", "1q2w3e4r5t", "1qaz2wsx", "zaq12wsx",
+	"asdfgh", "asdfghjkl", "zxcvbnm", "qazwsx", "abc123", "abcd1234", "a1b2c3", "aa123456",
+	"iloveyou", "admin", "admin123", "administrator", "root", "toor", "letmein", "welcome",
+	"welcome1", "login", "master", "monkey", "dragon", "football", "baseball", "basketball",
+	"soccer", "hockey", "superman", "batman", "starwars", "pokemon", "princess", "sunshine",
+	"shadow", "michael", "jennifer", "jordan", "charlie", "thomas", "hunter", "hunter2",
+	"trustno1", "whatever", "freedom", "secret", "changeme", "default", "guest", "test",
+	"test123", "testing", "qwe123", "killer", "ninja", "mustang", "access", "flower",
+	"lovely", "loveme", "hello", "hello123", "computer", "internet", "google", "samsung",
+	"master123", "summer", "winter", "autumn", "spring", "chocolate", "cheese", "pepper",
+	"ginger", "cookie", "butterfly", "purple", "orange", "yellow", "silver", "golden",
+	"matrix", "mercedes", "ferrari", "porsche", "liverpool", "chelsea", "arsenal", "barcelona",
+})
+
+// commonWords is a small dictionary of common words and password
+// components (all lowercased) that are considered guessable.
+var commonWords = []string{
+	"password", "passwd", "pass", "admin", "user", "login", "welcome", "secret",
+	"qwerty", "love", "hello", "test", "master", "dragon", "monkey", "shadow",
+	"sunshine", "princess", "football", "baseball", "soccer", "hockey", "summer", "winter",
+	"spring", "autumn", "january", "february", "march", "april", "june", "july",
+	"august", "september", "october", "november", "december", "monday", "tuesday", "wednesday",
+	"thursday", "friday", "saturday", "sunday", "google", "apple", "microsoft", "facebook",
+	"company", "change", "letmein", "trust", "freedom", "whatever", "computer", "internet",
+	"correct", "horse", "battery", "staple", "orange", "purple", "yellow", "black",
+	"white", "green", "silver", "golden", "flower", "cookie", "cheese", "chocolate",
+	"angel", "baby", "star", "super", "hunter", "killer", "ninja", "pokemon",
+	"batman", "superman", "starwars", "matrix", "jordan", "michael", "charlie", "thomas",
+	"jennifer", "jessica", "ashley", "daniel", "robert", "william", "david", "richard",
+}
+
+func toSet(items []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(items))
+
+	for _, item := range items {
+		result[item] = struct{}{}
+	}
+
+	return result
+}
@@ -0,0 +1,261 @@
+package passwords
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Strength scores, loosely following the zxcvbn scale.
+const (
+	StrengthVeryWeak   = 0 // too guessable (eg. common password)
+	StrengthWeak       = 1 // very guessable (eg. short or repeated patterns)
+	StrengthFair       = 2 // somewhat guessable
+	StrengthStrong     = 3 // safely unguessable
+	StrengthVeryStrong = 4 // very unguessable
+)
+
+// strengthBits defines the min estimated entropy bits for each score above [StrengthVeryWeak]
+// (roughly 10^3, 10^6, 10^8 and 10^10 guesses).
+var strengthBits = []float64{10, 20, 26.6, 33.2}
+
+// keyboardRows contains the common keyboard layouts rows used for the sequences detection.
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"qwertzuiop",
+	"yxcvbnm",
+	"azertyuiop",
+	"wxcvbn",
+}
+
+// leetReplacer normalizes the most common "l33t" substitutions.
+var leetReplacer = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"7", "t",
+	"8", "b",
+	"9", "g",
+	"@", "a",
+	"$", "s",
+	"!", "i",
+	"+", "t",
+)
+
+// Strength returns a zxcvbn-style estimated strength score of the
+// provided password in the range [StrengthVeryWeak]-[StrengthVeryStrong].
+//
+// The estimation starts from the brute force entropy of the used
+// character classes and penalizes common passwords and dictionary words,
+// repeated characters, alphabetical, numeric and keyboard sequences,
+// and the optional user specific inputs (eg. email, username, name).
+func Strength(password string, userInputs ...string) int {
+	bits := EntropyBits(password, userInputs...)
+
+	score := StrengthVeryWeak
+	for i, min := range strengthBits {
+		if bits >= min {
+			score = i + 1
+		}
+	}
+
+	return score
+}
+
+// EntropyBits returns the estimated entropy bits of the provided password.
+//
+// See [Strength] for more details.
+func EntropyBits(password string, userInputs ...string) float64 {
+	chars := []rune(password)
+	if len(chars) == 0 {
+		return 0
+	}
+
+	lower := strings.ToLower(password)
+	normalized := leetReplacer.Replace(lower)
+
+	if _, ok := commonPasswords[lower]; ok {
+		return 0
+	}
+	if _, ok := commonPasswords[normalized]; ok {
+		return 0
+	}
+
+	charBits := math.Log2(float64(charsetSize(chars)))
+
+	// mark the characters that are part of a guessable pattern
+	// (the first character of each pattern is still counted with its full weight)
+	guessable := make([]bool, len(chars))
+
+	markRepeatsAndSequences(chars, guessable)
+	markYears(chars, guessable)
+
+	lowerChars := []rune(lower)
+	normalizedChars := []rune(normalized)
+
+	for _, row := range keyboardRows {
+		markSubstrings(lowerChars, guessable, row, 3)
+		markSubstrings(lowerChars, guessable, reverse(row), 3)
+	}
+
+	dictionary := make([]string, 0, len(commonWords)+len(userInputs))
+	dictionary = append(dictionary, commonWords...)
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+
+		// also check the local part of the email addresses
+		if local, _, ok := strings.Cut(input, "@"); ok {
+			input = local
+		}
+
+		if len([]rune(input)) >= 3 {
+			dictionary = append(dictionary, input)
+		}
+	}
+
+	for _, word := range dictionary {
+		markWord(lowerChars, guessable, word)
+		markWord(normalizedChars, guessable, word)
+	}
+
+	var bits float64
+	for _, g := range guessable {
+		if g {
+			bits += 1
+		} else {
+			bits += charBits
+		}
+	}
+
+	return bits
+}
+
+func charsetSize(chars []rune) int {
+	var hasLower, hasUpper, hasDigit, hasSymbol, hasOther bool
+
+	for _, c := range chars {
+		switch {
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c < unicode.MaxASCII && unicode.IsPrint(c):
+			hasSymbol = true
+		default:
+			hasOther = true
+		}
+	}
+
+	size := 0
+	if hasLower {
+		size += 26
+	}
+	if hasUpper {
+		size += 26
+	}
+	if hasDigit {
+		size += 10
+	}
+	if hasSymbol {
+		size += 33
+	}
+	if hasOther {
+		size += 100
+	}
+
+	return max(size, 2)
+}
+
+// markRepeatsAndSequences marks as guessable the characters that
+// repeat or continue an ascending/descending sequence (eg. "aaa", "abc", "321").
+func markRepeatsAndSequences(chars []rune, guessable []bool) {
+	for i := 1; i < len(chars); i++ {
+		prev := unicode.ToLower(chars[i-1])
+		curr := unicode.ToLower(chars[i])
+
+		if curr == prev {
+			guessable[i] = true
+			continue
+		}
+
+		diff := curr - prev
+		if diff != 1 && diff != -1 {
+			continue
+		}
+
+		// require at least 3 consecutive sequence characters
+		if i >= 2 && unicode.ToLower(chars[i-1])-unicode.ToLower(chars[i-2]) == diff {
+			guessable[i-1] = true
+			guessable[i] = true
+		}
+	}
+}
+
+// markYears marks as guessable the recent years (eg. "1987", "2024").
+func markYears(chars []rune, guessable []bool) {
+	for i := 0; i+4 <= len(chars); i++ {
+		if (chars[i] == '1' && chars[i+1] == '9') || (chars[i] == '2' && chars[i+1] == '0') {
+			if unicode.IsDigit(chars[i+2]) && unicode.IsDigit(chars[i+3]) {
+				for k := i + 1; k < i+4; k++ {
+					guessable[k] = true
+				}
+			}
+		}
+	}
+}
+
+// markSubstrings marks as guessable the password parts that are
+// substrings of the provided pattern with at least minLength characters.
+func markSubstrings(chars []rune, guessable []bool, pattern string, minLength int) {
+	patternChars := []rune(pattern)
+
+	for i := 0; i < len(chars); i++ {
+		for j := 0; j < len(patternChars); j++ {
+			n := 0
+			for i+n < len(chars) && j+n < len(patternChars) && chars[i+n] == patternChars[j+n] {
+				n++
+			}
+
+			if n >= minLength {
+				for k := i + 1; k < i+n; k++ {
+					guessable[k] = true
+				}
+			}
+		}
+	}
+}
+
+// markWord marks as guessable all occurrences of the provided word.
+func markWord(chars []rune, guessable []bool, word string) {
+	wordChars := []rune(word)
+	if len(wordChars) == 0 || len(wordChars) > len(chars) {
+		return
+	}
+
+	for i := 0; i+len(wordChars) <= len(chars); i++ {
+		if string(chars[i:i+len(wordChars)]) != word {
+			continue
+		}
+
+		for k := i + 1; k < i+len(wordChars); k++ {
+			guessable[k] = true
+		}
+	}
+}
+
+func reverse(str string) string {
+	chars := []rune(str)
+
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+
+	return string(chars)
+}
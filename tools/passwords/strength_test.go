@@ -0,0 +1,44 @@
+package passwords_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/passwords"
+)
+
+func TestStrength(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		password   string
+		userInputs []string
+		expected   int
+	}{
+		{"", nil, passwords.StrengthVeryWeak},
+		{"123456", nil, passwords.StrengthVeryWeak},
+		{"password", nil, passwords.StrengthVeryWeak},
+		{"P@ssw0rd", nil, passwords.StrengthVeryWeak},
+		{"qwerty123", nil, passwords.StrengthVeryWeak},
+		{"aaaaaaaaaa", nil, passwords.StrengthWeak},
+		{"abcdefghij", nil, passwords.StrengthWeak},
+		{"asdfghjkl;", nil, passwords.StrengthWeak},
+		{"x7Kp", nil, passwords.StrengthFair},
+		{"johnsmith2000", []string{"johnsmith@example.com"}, passwords.StrengthFair},
+		{"johnsmith2000", nil, passwords.StrengthVeryStrong},
+		{"Summer2024", nil, passwords.StrengthWeak},
+		{"Summer#2024", nil, passwords.StrengthStrong},
+		{"correcthorsebatterystaple", nil, passwords.StrengthVeryStrong},
+		{"Tr0ub4dor&3", nil, passwords.StrengthVeryStrong},
+		{"v9#Lq2!zR", nil, passwords.StrengthVeryStrong},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.password, func(t *testing.T) {
+			result := passwords.Strength(s.password, s.userInputs...)
+
+			if result != s.expected {
+				t.Fatalf("Expected score %d, got %d (%f bits)", s.expected, result, passwords.EntropyBits(s.password, s.userInputs...))
+			}
+		})
+	}
+}
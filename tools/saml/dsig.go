@@ -0,0 +1,244 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const (
+	NamespaceDSig = "http://www.w3.org/2000/09/xmldsig#"
+
+	AlgorithmExcC14N              = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	AlgorithmEnvelopedSignature   = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	AlgorithmDigestSHA256         = "http://www.w3.org/2001/04/xmlenc#sha256"
+	AlgorithmDigestSHA512         = "http://www.w3.org/2001/04/xmlenc#sha512"
+	AlgorithmSignatureRSASHA256   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	AlgorithmSignatureRSASHA512   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	AlgorithmSignatureECDSASHA256 = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+)
+
+// ErrMissingSignature is returned when the verified element doesn't have a signature.
+var ErrMissingSignature = errors.New("missing xml signature")
+
+var digestAlgorithms = map[string]crypto.Hash{
+	AlgorithmDigestSHA256: crypto.SHA256,
+	AlgorithmDigestSHA512: crypto.SHA512,
+}
+
+var signatureAlgorithms = map[string]crypto.Hash{
+	AlgorithmSignatureRSASHA256:   crypto.SHA256,
+	AlgorithmSignatureRSASHA512:   crypto.SHA512,
+	AlgorithmSignatureECDSASHA256: crypto.SHA256,
+}
+
+// verifyEnvelopedSignature verifies the enveloped XML signature of el
+// against the provided trusted certificates.
+//
+// Only the signatures that directly reference el (aka. URI="#el.ID") with
+// the enveloped-signature and exclusive canonicalization transforms are accepted.
+func verifyEnvelopedSignature(el *xmlElement, certs []*x509.Certificate) error {
+	signatures := el.ChildElements(NamespaceDSig, "Signature")
+	if len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+	if len(signatures) > 1 {
+		return errors.New("multiple xml signatures are not supported")
+	}
+	signature := signatures[0]
+
+	id := el.Attr("ID")
+	if id == "" {
+		return errors.New("the signed element must have an ID attribute")
+	}
+
+	// protect against signature wrapping attacks
+	root := el
+	for root.parent != nil {
+		root = root.parent
+	}
+	if len(root.findElementsByID(id)) != 1 {
+		return errors.New("the signed element ID must be unique")
+	}
+
+	signedInfo := signature.ChildElement(NamespaceDSig, "SignedInfo")
+	if signedInfo == nil {
+		return errors.New("missing SignedInfo element")
+	}
+
+	// canonicalization method
+	c14nMethod := signedInfo.ChildElement(NamespaceDSig, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.Attr("Algorithm") != AlgorithmExcC14N {
+		return errors.New("missing or unsupported canonicalization method")
+	}
+
+	// signature method
+	signatureMethod := signedInfo.ChildElement(NamespaceDSig, "SignatureMethod")
+	if signatureMethod == nil {
+		return errors.New("missing SignatureMethod element")
+	}
+	signatureAlg := signatureMethod.Attr("Algorithm")
+	signatureHash, ok := signatureAlgorithms[signatureAlg]
+	if !ok {
+		return errors.New("unsupported signature method " + signatureAlg)
+	}
+
+	// reference
+	references := signedInfo.ChildElements(NamespaceDSig, "Reference")
+	if len(references) != 1 {
+		return errors.New("expected exactly one signature Reference element")
+	}
+	reference := references[0]
+	if reference.Attr("URI") != "#"+id {
+		return errors.New("the signature reference doesn't match the signed element ID")
+	}
+
+	var inclusivePrefixes []string
+	if transforms := reference.ChildElement(NamespaceDSig, "Transforms"); transforms != nil {
+		for _, transform := range transforms.ChildElements(NamespaceDSig, "Transform") {
+			switch transform.Attr("Algorithm") {
+			case AlgorithmEnvelopedSignature:
+				// signature element is always excluded
+			case AlgorithmExcC14N:
+				inclusivePrefixes = readInclusivePrefixes(transform)
+			default:
+				return errors.New("unsupported signature transform " + transform.Attr("Algorithm"))
+			}
+		}
+	}
+
+	digestMethod := reference.ChildElement(NamespaceDSig, "DigestMethod")
+	if digestMethod == nil {
+		return errors.New("missing DigestMethod element")
+	}
+	digestHash, ok := digestAlgorithms[digestMethod.Attr("Algorithm")]
+	if !ok {
+		return errors.New("unsupported digest method " + digestMethod.Attr("Algorithm"))
+	}
+
+	digestValue := reference.ChildElement(NamespaceDSig, "DigestValue")
+	if digestValue == nil {
+		return errors.New("missing DigestValue element")
+	}
+	expectedDigest, err := decodeBase64(digestValue.Text())
+	if err != nil {
+		return errors.New("invalid DigestValue encoding")
+	}
+
+	// compare the referenced element digest
+	canonicalEl, err := el.canonicalize(inclusivePrefixes, signature)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(hashBytes(digestHash, canonicalEl), expectedDigest) != 1 {
+		return errors.New("the signed element digest doesn't match")
+	}
+
+	// verify the SignedInfo signature
+	signatureValue := signature.ChildElement(NamespaceDSig, "SignatureValue")
+	if signatureValue == nil {
+		return errors.New("missing SignatureValue element")
+	}
+	rawSignature, err := decodeBase64(signatureValue.Text())
+	if err != nil {
+		return errors.New("invalid SignatureValue encoding")
+	}
+
+	canonicalSignedInfo, err := signedInfo.canonicalize(readInclusivePrefixes(c14nMethod), nil)
+	if err != nil {
+		return err
+	}
+	signedInfoHash := hashBytes(signatureHash, canonicalSignedInfo)
+
+	for _, cert := range certs {
+		if verifySignature(cert.PublicKey, signatureAlg, signatureHash, signedInfoHash, rawSignature) == nil {
+			return nil
+		}
+	}
+
+	return errors.New("failed to verify the xml signature with the trusted certificate(s)")
+}
+
+func verifySignature(publicKey any, algorithm string, hash crypto.Hash, hashed []byte, signature []byte) error {
+	switch algorithm {
+	case AlgorithmSignatureRSASHA256, AlgorithmSignatureRSASHA512:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("expected RSA public key")
+		}
+		return rsa.VerifyPKCS1v15(key, hash, hashed, signature)
+	case AlgorithmSignatureECDSASHA256:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("expected ECDSA public key")
+		}
+		if ecdsaVerifyXMLSignature(key, hashed, signature) {
+			return nil
+		}
+		return errors.New("invalid ECDSA signature")
+	}
+
+	return errors.New("unsupported signature algorithm")
+}
+
+// ecdsaVerifyXMLSignature verifies ECDSA signature encoded as r||s
+// (as defined in RFC 4050) with fallback to ASN.1 DER encoding.
+func ecdsaVerifyXMLSignature(key *ecdsa.PublicKey, hashed []byte, signature []byte) bool {
+	if len(signature)%2 == 0 {
+		half := len(signature) / 2
+		r := new(big.Int).SetBytes(signature[:half])
+		s := new(big.Int).SetBytes(signature[half:])
+		if ecdsa.Verify(key, hashed, r, s) {
+			return true
+		}
+	}
+
+	var parsed struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(signature, &parsed); err != nil || parsed.R == nil || parsed.S == nil {
+		return false
+	}
+
+	return ecdsa.Verify(key, hashed, parsed.R, parsed.S)
+}
+
+func readInclusivePrefixes(transform *xmlElement) []string {
+	inclusive := transform.ChildElement(AlgorithmExcC14N, "InclusiveNamespaces")
+	if inclusive == nil {
+		return nil
+	}
+
+	return strings.Fields(inclusive.Attr("PrefixList"))
+}
+
+func hashBytes(hash crypto.Hash, data []byte) []byte {
+	switch hash {
+	case crypto.SHA512:
+		sum := sha512.Sum512(data)
+		return sum[:]
+	default:
+		sum := sha256.Sum256(data)
+		return sum[:]
+	}
+}
+
+// decodeBase64 decodes a standard base64 string ignoring any whitespace characters.
+func decodeBase64(str string) ([]byte, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, str)
+
+	return base64.StdEncoding.DecodeString(cleaned)
+}
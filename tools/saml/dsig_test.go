@@ -0,0 +1,220 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSignaturePlaceholder returns the placeholder comment that
+// signTestXML replaces with the signature of the element with the specified id.
+func testSignaturePlaceholder(id string) string {
+	return "<!--SIGNATURE:" + id + "-->"
+}
+
+// newTestCertificate generates a new self-signed certificate for the specified key.
+func newTestCertificate(t testing.TB, key crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test idp"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+// signTestXML replaces the signature placeholder of the element with
+// the specified id with an enveloped signature generated with key.
+func signTestXML(t testing.TB, doc string, id string, key crypto.Signer, signatureAlg string) string {
+	signatureTemplate := `<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` +
+		`<ds:SignedInfo>` +
+		`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"/>` +
+		`<ds:SignatureMethod Algorithm="` + signatureAlg + `"/>` +
+		`<ds:Reference URI="#` + id + `">` +
+		`<ds:Transforms>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"/>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"/>` +
+		`</ds:Transforms>` +
+		`<ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/>` +
+		`<ds:DigestValue>{DIGEST}</ds:DigestValue>` +
+		`</ds:Reference>` +
+		`</ds:SignedInfo>` +
+		`<ds:SignatureValue>{SIGNATURE}</ds:SignatureValue>` +
+		`</ds:Signature>`
+
+	doc = strings.Replace(doc, testSignaturePlaceholder(id), signatureTemplate, 1)
+
+	// digest
+	root, err := parseXML([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	el := root.findElementsByID(id)[0]
+	canonical, err := el.canonicalize(nil, el.ChildElement(NamespaceDSig, "Signature"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := base64.StdEncoding.EncodeToString(hashBytes(crypto.SHA256, canonical))
+	doc = strings.Replace(doc, "{DIGEST}", digest, 1)
+
+	// signature
+	root, err = parseXML([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	el = root.findElementsByID(id)[0]
+	signedInfo := el.ChildElement(NamespaceDSig, "Signature").ChildElement(NamespaceDSig, "SignedInfo")
+	canonical, err = signedInfo.canonicalize(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := signatureAlgorithms[signatureAlg]
+
+	var rawSignature []byte
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, hashBytes(hash, canonical))
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		rawSignature = make([]byte, 2*size)
+		r.FillBytes(rawSignature[:size])
+		s.FillBytes(rawSignature[size:])
+	default:
+		rawSignature, err = key.Sign(rand.Reader, hashBytes(hash, canonical), hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return strings.Replace(doc, "{SIGNATURE}", base64.StdEncoding.EncodeToString(rawSignature), 1)
+}
+
+func TestVerifyEnvelopedSignature(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaCert := newTestCertificate(t, rsaKey)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecCert := newTestCertificate(t, ecKey)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCert := newTestCertificate(t, otherKey)
+
+	template := `<root xmlns="urn:test"><item ID="item1" attr="a">` + testSignaturePlaceholder("item1") + `<value>test</value></item></root>`
+
+	rsaSigned := signTestXML(t, template, "item1", rsaKey, AlgorithmSignatureRSASHA256)
+	ecSigned := signTestXML(t, template, "item1", ecKey, AlgorithmSignatureECDSASHA256)
+
+	scenarios := []struct {
+		name        string
+		doc         string
+		certs       []*x509.Certificate
+		expectError bool
+	}{
+		{
+			"missing signature",
+			strings.Replace(template, testSignaturePlaceholder("item1"), "", 1),
+			[]*x509.Certificate{rsaCert},
+			true,
+		},
+		{
+			"valid RSA signature",
+			rsaSigned,
+			[]*x509.Certificate{rsaCert},
+			false,
+		},
+		{
+			"valid RSA signature with multiple trusted certs",
+			rsaSigned,
+			[]*x509.Certificate{otherCert, rsaCert},
+			false,
+		},
+		{
+			"valid ECDSA signature",
+			ecSigned,
+			[]*x509.Certificate{ecCert},
+			false,
+		},
+		{
+			"untrusted certificate",
+			rsaSigned,
+			[]*x509.Certificate{otherCert},
+			true,
+		},
+		{
+			"modified signed content",
+			strings.Replace(rsaSigned, "<value>test</value>", "<value>test2</value>", 1),
+			[]*x509.Certificate{rsaCert},
+			true,
+		},
+		{
+			"modified signed attribute",
+			strings.Replace(rsaSigned, `attr="a"`, `attr="b"`, 1),
+			[]*x509.Certificate{rsaCert},
+			true,
+		},
+		{
+			"duplicated ID (signature wrapping)",
+			strings.Replace(rsaSigned, "</root>", `<item ID="item1"><value>evil</value></item></root>`, 1),
+			[]*x509.Certificate{rsaCert},
+			true,
+		},
+		{
+			"unsupported digest method",
+			strings.Replace(rsaSigned, AlgorithmDigestSHA256, "http://www.w3.org/2000/09/xmldsig#sha1", 1),
+			[]*x509.Certificate{rsaCert},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			root, err := parseXML([]byte(s.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			item := root.ChildElement("urn:test", "item")
+
+			err = verifyEnvelopedSignature(item, s.certs)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
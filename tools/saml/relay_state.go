@@ -0,0 +1,88 @@
+package saml
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// MaxRelayStateLength is the max allowed RelayState length
+// (see https://docs.oasis-open.org/security/saml/v2.0/saml-bindings-2.0-os.pdf 3.4.3).
+const MaxRelayStateLength = 80
+
+const (
+	relayStateNonceLength     = 10
+	relayStateSignatureLength = 12 // truncated HMAC-SHA256 bytes
+)
+
+// NewRelayState creates a new signed RelayState token that expires
+// after the specified duration.
+//
+// The token has the format "nonce.expiry.data.signature" and allows
+// the SP to verify the IdP response without persisting any request state:
+//   - the returned requestId is derived from the random nonce and it is
+//     expected to be used as AuthnRequest ID (see [ServiceProvider.AuthnRequestURL])
+//   - data is an optional arbitrary string that will be returned with [ParseRelayState]
+//
+// Note that the data is NOT encrypted and returns an error if
+// the token exceeds the [MaxRelayStateLength] limit.
+func NewRelayState(secret string, data string, duration time.Duration) (state string, requestId string, err error) {
+	nonce := security.RandomString(relayStateNonceLength)
+
+	payload := nonce + "." + strconv.FormatInt(time.Now().Add(duration).Unix(), 36) + "." + data
+
+	state = payload + "." + signRelayState(secret, payload)
+	if len(state) > MaxRelayStateLength {
+		return "", "", errors.New("the RelayState data is too long")
+	}
+
+	return state, relayStateRequestId(nonce), nil
+}
+
+// ParseRelayState verifies the signature and the expiration of a
+// RelayState token created with [NewRelayState] and returns the
+// AuthnRequest ID and the data associated with it.
+func ParseRelayState(secret string, state string, now time.Time) (requestId string, data string, err error) {
+	sepIndex := strings.LastIndexByte(state, '.')
+	if sepIndex < 0 {
+		return "", "", errors.New("invalid RelayState format")
+	}
+
+	payload := state[:sepIndex]
+	if !hmac.Equal([]byte(state[sepIndex+1:]), []byte(signRelayState(secret, payload))) {
+		return "", "", errors.New("invalid RelayState signature")
+	}
+
+	parts := strings.SplitN(payload, ".", 3)
+	if len(parts) != 3 || len(parts[0]) != relayStateNonceLength {
+		return "", "", errors.New("invalid RelayState format")
+	}
+
+	expiry, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return "", "", errors.New("invalid RelayState expiry")
+	}
+	if now.Unix() > expiry {
+		return "", "", errors.New("the RelayState has expired")
+	}
+
+	return relayStateRequestId(parts[0]), parts[2], nil
+}
+
+func relayStateRequestId(nonce string) string {
+	// the xs:ID type must not start with a digit
+	return "_" + nonce
+}
+
+func signRelayState(secret string, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:relayStateSignatureLength])
+}
@@ -0,0 +1,83 @@
+package saml
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelayState(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	state, requestId, err := NewRelayState("secret", "test_data", 1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(requestId, "_") {
+		t.Fatalf("Expected the request id to start with underscore, got %q", requestId)
+	}
+
+	if len(state) > MaxRelayStateLength {
+		t.Fatalf("Expected state no longer than %d bytes, got %d (%s)", MaxRelayStateLength, len(state), state)
+	}
+
+	// unique nonce
+	state2, requestId2, err := NewRelayState("secret", "test_data", 1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == state2 || requestId == requestId2 {
+		t.Fatalf("Expected unique states and request ids, got %q (%q) and %q (%q)", state, requestId, state2, requestId2)
+	}
+
+	// max data length (e.g. realtime client id)
+	if _, _, err := NewRelayState("secret", strings.Repeat("a", 40), 1*time.Minute); err != nil {
+		t.Fatalf("Expected 40 characters data to be allowed, got %v", err)
+	}
+	if _, _, err := NewRelayState("secret", strings.Repeat("a", 80), 1*time.Minute); err == nil {
+		t.Fatal("Expected too long data error")
+	}
+
+	expired, _, err := NewRelayState("secret", "test_data", -1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name              string
+		secret            string
+		state             string
+		expectError       bool
+		expectedRequestId string
+		expectedData      string
+	}{
+		{"empty", "secret", "", true, "", ""},
+		{"invalid format", "secret", "abc", true, "", ""},
+		{"different secret", "secret2", state, true, "", ""},
+		{"modified data", "secret", strings.Replace(state, "test_data", "test_datb", 1), true, "", ""},
+		{"expired", "secret", expired, true, "", ""},
+		{"valid", "secret", state, false, requestId, "test_data"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			requestId, data, err := ParseRelayState(s.secret, s.state, now)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if requestId != s.expectedRequestId {
+				t.Fatalf("Expected request id %q, got %q", s.expectedRequestId, requestId)
+			}
+
+			if data != s.expectedData {
+				t.Fatalf("Expected data %q, got %q", s.expectedData, data)
+			}
+		})
+	}
+}
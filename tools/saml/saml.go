@@ -0,0 +1,506 @@
+// Package saml implements a minimal SAML 2.0 Web Browser SSO service provider
+// (HTTP-Redirect AuthnRequests and HTTP-POST signed Responses).
+//
+// Encrypted assertions and signed AuthnRequests are not supported.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	NamespaceProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	NamespaceAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	NamespaceMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	BindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	BindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+
+	StatusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+
+	NameIDFormatUnspecified  = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	NameIDFormatEmailAddress = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIDFormatPersistent   = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+
+	subjectConfirmationBearer = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// ProviderName is the provider identifier used for the SAML linked external auths.
+const ProviderName = "saml"
+
+// DefaultClockSkew is the default allowed time drift between the SP and the IdP.
+const DefaultClockSkew = 3 * time.Minute
+
+// ServiceProvider defines a SAML 2.0 service provider bound to a single identity provider.
+type ServiceProvider struct {
+	// EntityId is the unique SP identifier (usually its metadata url).
+	EntityId string
+
+	// ACSURL is the Assertion Consumer Service endpoint url where
+	// the IdP will POST the SAMLResponse.
+	ACSURL string
+
+	// IdPEntityId is the expected Issuer of the IdP responses.
+	IdPEntityId string
+
+	// IdPSSOURL is the IdP single sign-on url (HTTP-Redirect binding).
+	IdPSSOURL string
+
+	// IdPCertificates is a list with the trusted IdP signing certificates.
+	IdPCertificates []*x509.Certificate
+
+	// ClockSkew is the allowed time drift between the SP and the IdP
+	// (fallbacks to DefaultClockSkew if not set).
+	ClockSkew time.Duration
+}
+
+// Assertion defines the verified SAML assertion data.
+type Assertion struct {
+	// Attributes is a map with the assertion attribute values
+	// keyed by both their Name and FriendlyName (if any).
+	Attributes map[string][]string `json:"attributes"`
+
+	Id           string    `json:"id"`
+	Issuer       string    `json:"issuer"`
+	NameId       string    `json:"nameId"`
+	NameIdFormat string    `json:"nameIdFormat"`
+	SessionIndex string    `json:"sessionIndex"`
+	InResponseTo string    `json:"inResponseTo"`
+	NotOnOrAfter time.Time `json:"notOnOrAfter"`
+}
+
+// Attribute returns the first value of the assertion attribute with
+// the specified name (or empty string if missing).
+func (a *Assertion) Attribute(name string) string {
+	values := a.Attributes[name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// ParseCertificates parses one or more PEM encoded x509 certificates.
+//
+// For convenience, a single raw base64 DER certificate (as commonly
+// exported in the IdP metadata X509Certificate element) is also accepted.
+func ParseCertificates(data string) ([]*x509.Certificate, error) {
+	data = strings.TrimSpace(data)
+	if data == "" {
+		return nil, errors.New("empty certificate data")
+	}
+
+	if !strings.HasPrefix(data, "-----BEGIN") {
+		raw, err := decodeBase64(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate encoding: %w", err)
+		}
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, err
+		}
+		return []*x509.Certificate{cert}, nil
+	}
+
+	var result []*x509.Certificate
+
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cert)
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("no PEM certificate found")
+	}
+
+	return result, nil
+}
+
+// -------------------------------------------------------------------
+
+type metadataDescriptor struct {
+	XMLName  xml.Name                `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityId string                  `xml:"entityID,attr"`
+	SPSSO    metadataSPSSODescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata SPSSODescriptor"`
+}
+
+type metadataSPSSODescriptor struct {
+	AuthnRequestsSigned        bool                `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned       bool                `xml:"WantAssertionsSigned,attr"`
+	ProtocolSupportEnumeration string              `xml:"protocolSupportEnumeration,attr"`
+	NameIDFormats              []string            `xml:"urn:oasis:names:tc:SAML:2.0:metadata NameIDFormat"`
+	ACS                        metadataEndpointACS `xml:"urn:oasis:names:tc:SAML:2.0:metadata AssertionConsumerService"`
+}
+
+type metadataEndpointACS struct {
+	Binding   string `xml:"Binding,attr"`
+	Location  string `xml:"Location,attr"`
+	Index     int    `xml:"index,attr"`
+	IsDefault bool   `xml:"isDefault,attr"`
+}
+
+// Metadata returns the XML metadata document of the service provider.
+func (sp *ServiceProvider) Metadata() ([]byte, error) {
+	descriptor := metadataDescriptor{
+		EntityId: sp.EntityId,
+		SPSSO: metadataSPSSODescriptor{
+			AuthnRequestsSigned:        false,
+			WantAssertionsSigned:       true,
+			ProtocolSupportEnumeration: NamespaceProtocol,
+			NameIDFormats: []string{
+				NameIDFormatPersistent,
+				NameIDFormatEmailAddress,
+				NameIDFormatUnspecified,
+			},
+			ACS: metadataEndpointACS{
+				Binding:   BindingHTTPPost,
+				Location:  sp.ACSURL,
+				Index:     0,
+				IsDefault: true,
+			},
+		},
+	}
+
+	raw, err := xml.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), raw...), nil
+}
+
+// -------------------------------------------------------------------
+
+type authnRequest struct {
+	XMLName                     xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+	Id                          string   `xml:"ID,attr"`
+	Version                     string   `xml:"Version,attr"`
+	IssueInstant                string   `xml:"IssueInstant,attr"`
+	Destination                 string   `xml:"Destination,attr"`
+	AssertionConsumerServiceURL string   `xml:"AssertionConsumerServiceURL,attr"`
+	ProtocolBinding             string   `xml:"ProtocolBinding,attr"`
+	Issuer                      string   `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	NameIDPolicy                struct {
+		AllowCreate bool `xml:"AllowCreate,attr"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:protocol NameIDPolicy"`
+}
+
+// AuthnRequestURL builds a new HTTP-Redirect binding AuthnRequest url
+// to the IdP single sign-on service.
+//
+// If optRequestId is not set, a random request id is generated.
+//
+// It returns the request id so that it could be optionally
+// compared later with the Response InResponseTo attribute.
+func (sp *ServiceProvider) AuthnRequestURL(relayState string, optRequestId ...string) (requestURL string, requestId string, err error) {
+	if sp.IdPSSOURL == "" {
+		return "", "", errors.New("missing IdP SSO url")
+	}
+
+	if len(relayState) > MaxRelayStateLength {
+		return "", "", errors.New("the RelayState must be no more than 80 bytes")
+	}
+
+	requestId = "_" + security.RandomString(32)
+	if len(optRequestId) > 0 && optRequestId[0] != "" {
+		requestId = optRequestId[0]
+	}
+
+	req := authnRequest{
+		Id:                          requestId,
+		Version:                     "2.0",
+		IssueInstant:                time.Now().UTC().Format(time.RFC3339),
+		Destination:                 sp.IdPSSOURL,
+		AssertionConsumerServiceURL: sp.ACSURL,
+		ProtocolBinding:             BindingHTTPPost,
+		Issuer:                      sp.EntityId,
+	}
+	req.NameIDPolicy.AllowCreate = true
+
+	raw, err := xml.Marshal(req)
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := fw.Write(raw); err != nil {
+		return "", "", err
+	}
+	if err := fw.Close(); err != nil {
+		return "", "", err
+	}
+
+	u, err := url.Parse(sp.IdPSSOURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	query := u.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if relayState != "" {
+		query.Set("RelayState", relayState)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), req.Id, nil
+}
+
+// -------------------------------------------------------------------
+
+// ParseResponse decodes and validates the base64 encoded HTTP-POST
+// binding SAMLResponse and returns its verified assertion.
+//
+// Either the Response or the Assertion element must be signed by
+// one of the trusted IdP certificates.
+//
+// If expectedRequestIds is not empty, the Response InResponseTo must
+// match one of the specified ids (otherwise IdP-initiated responses are also allowed).
+func (sp *ServiceProvider) ParseResponse(encodedResponse string, now time.Time, expectedRequestIds ...string) (*Assertion, error) {
+	if len(sp.IdPCertificates) == 0 {
+		return nil, errors.New("missing IdP certificates")
+	}
+
+	raw, err := decodeBase64(encodedResponse)
+	if err != nil {
+		return nil, errors.New("invalid SAMLResponse encoding")
+	}
+
+	root, err := parseXML(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAMLResponse xml: %w", err)
+	}
+
+	if !root.Is(NamespaceProtocol, "Response") {
+		return nil, errors.New("expected SAML Response root element")
+	}
+
+	if v := root.Attr("Version"); v != "2.0" {
+		return nil, errors.New("unsupported SAML version " + v)
+	}
+
+	if destination := root.Attr("Destination"); destination != "" && destination != sp.ACSURL {
+		return nil, errors.New("the Response Destination doesn't match the ACS url")
+	}
+
+	inResponseTo := root.Attr("InResponseTo")
+	if len(expectedRequestIds) > 0 && !slices.Contains(expectedRequestIds, inResponseTo) {
+		return nil, errors.New("unexpected Response InResponseTo value")
+	}
+
+	if issuer := root.ChildElement(NamespaceAssertion, "Issuer"); issuer != nil &&
+		strings.TrimSpace(issuer.Text()) != sp.IdPEntityId {
+		return nil, errors.New("the Response Issuer doesn't match the IdP entity id")
+	}
+
+	status := root.ChildElement(NamespaceProtocol, "Status")
+	if status == nil {
+		return nil, errors.New("missing Response Status element")
+	}
+	statusCode := status.ChildElement(NamespaceProtocol, "StatusCode")
+	if statusCode == nil || statusCode.Attr("Value") != StatusSuccess {
+		return nil, errors.New("the IdP returned an unsuccessful response status")
+	}
+
+	if len(root.ChildElements(NamespaceAssertion, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("encrypted assertions are not supported")
+	}
+
+	assertions := root.ChildElements(NamespaceAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, errors.New("expected exactly one Assertion element")
+	}
+	assertionEl := assertions[0]
+
+	// signature checks
+	responseErr := verifyEnvelopedSignature(root, sp.IdPCertificates)
+	if responseErr != nil && !errors.Is(responseErr, ErrMissingSignature) {
+		return nil, fmt.Errorf("invalid Response signature: %w", responseErr)
+	}
+	assertionErr := verifyEnvelopedSignature(assertionEl, sp.IdPCertificates)
+	if assertionErr != nil && !errors.Is(assertionErr, ErrMissingSignature) {
+		return nil, fmt.Errorf("invalid Assertion signature: %w", assertionErr)
+	}
+	if responseErr != nil && assertionErr != nil {
+		return nil, errors.New("either the Response or the Assertion must be signed")
+	}
+
+	assertion, err := sp.readAssertion(assertionEl, now)
+	if err != nil {
+		return nil, err
+	}
+	assertion.InResponseTo = inResponseTo
+
+	return assertion, nil
+}
+
+func (sp *ServiceProvider) readAssertion(el *xmlElement, now time.Time) (*Assertion, error) {
+	skew := sp.ClockSkew
+	if skew <= 0 {
+		skew = DefaultClockSkew
+	}
+
+	assertion := &Assertion{
+		Id:         el.Attr("ID"),
+		Attributes: map[string][]string{},
+	}
+
+	if assertion.Id == "" {
+		return nil, errors.New("missing Assertion ID")
+	}
+
+	// issuer
+	issuer := el.ChildElement(NamespaceAssertion, "Issuer")
+	if issuer == nil {
+		return nil, errors.New("missing Assertion Issuer")
+	}
+	assertion.Issuer = strings.TrimSpace(issuer.Text())
+	if assertion.Issuer != sp.IdPEntityId {
+		return nil, errors.New("the Assertion Issuer doesn't match the IdP entity id")
+	}
+
+	// subject
+	subject := el.ChildElement(NamespaceAssertion, "Subject")
+	if subject == nil {
+		return nil, errors.New("missing Assertion Subject")
+	}
+	nameId := subject.ChildElement(NamespaceAssertion, "NameID")
+	if nameId == nil || strings.TrimSpace(nameId.Text()) == "" {
+		return nil, errors.New("missing Assertion Subject NameID")
+	}
+	assertion.NameId = strings.TrimSpace(nameId.Text())
+	assertion.NameIdFormat = nameId.Attr("Format")
+
+	var hasValidConfirmation bool
+	for _, confirmation := range subject.ChildElements(NamespaceAssertion, "SubjectConfirmation") {
+		if confirmation.Attr("Method") != subjectConfirmationBearer {
+			continue
+		}
+
+		data := confirmation.ChildElement(NamespaceAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+
+		if recipient := data.Attr("Recipient"); recipient != sp.ACSURL {
+			continue
+		}
+
+		notOnOrAfter, err := parseTime(data.Attr("NotOnOrAfter"))
+		if err != nil || notOnOrAfter.IsZero() || !now.Before(notOnOrAfter.Add(skew)) {
+			continue
+		}
+
+		hasValidConfirmation = true
+		assertion.NotOnOrAfter = notOnOrAfter
+		break
+	}
+	if !hasValidConfirmation {
+		return nil, errors.New("missing or expired bearer SubjectConfirmation")
+	}
+
+	// conditions
+	conditions := el.ChildElement(NamespaceAssertion, "Conditions")
+	if conditions == nil {
+		return nil, errors.New("missing Assertion Conditions")
+	}
+
+	notBefore, err := parseTime(conditions.Attr("NotBefore"))
+	if err != nil {
+		return nil, errors.New("invalid Conditions NotBefore")
+	}
+	if !notBefore.IsZero() && now.Add(skew).Before(notBefore) {
+		return nil, errors.New("the Assertion is not valid yet")
+	}
+
+	notOnOrAfter, err := parseTime(conditions.Attr("NotOnOrAfter"))
+	if err != nil {
+		return nil, errors.New("invalid Conditions NotOnOrAfter")
+	}
+	if !notOnOrAfter.IsZero() {
+		if !now.Before(notOnOrAfter.Add(skew)) {
+			return nil, errors.New("the Assertion has expired")
+		}
+		if notOnOrAfter.Before(assertion.NotOnOrAfter) {
+			assertion.NotOnOrAfter = notOnOrAfter
+		}
+	}
+
+	restrictions := conditions.ChildElements(NamespaceAssertion, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return nil, errors.New("missing Assertion AudienceRestriction")
+	}
+	for _, restriction := range restrictions {
+		var matched bool
+		for _, audience := range restriction.ChildElements(NamespaceAssertion, "Audience") {
+			if strings.TrimSpace(audience.Text()) == sp.EntityId {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, errors.New("the Assertion audience doesn't match the SP entity id")
+		}
+	}
+
+	// authn statement
+	if authn := el.ChildElement(NamespaceAssertion, "AuthnStatement"); authn != nil {
+		assertion.SessionIndex = authn.Attr("SessionIndex")
+	}
+
+	// attributes
+	for _, statement := range el.ChildElements(NamespaceAssertion, "AttributeStatement") {
+		for _, attr := range statement.ChildElements(NamespaceAssertion, "Attribute") {
+			values := []string{}
+			for _, v := range attr.ChildElements(NamespaceAssertion, "AttributeValue") {
+				values = append(values, strings.TrimSpace(v.Text()))
+			}
+
+			if name := attr.Attr("Name"); name != "" {
+				assertion.Attributes[name] = append(assertion.Attributes[name], values...)
+			}
+
+			if friendly := attr.Attr("FriendlyName"); friendly != "" && friendly != attr.Attr("Name") {
+				assertion.Attributes[friendly] = append(assertion.Attributes[friendly], values...)
+			}
+		}
+	}
+
+	return assertion, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,436 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSPEntityId  = "https://sp.example.com/metadata"
+	testACSURL      = "https://sp.example.com/acs"
+	testIdPEntityId = "https://idp.example.com"
+)
+
+type testResponseOptions struct {
+	issuer       string
+	audience     string
+	recipient    string
+	destination  string
+	inResponseTo string
+	status       string
+	notBefore    time.Time
+	notOnOrAfter time.Time
+	signResponse bool
+	signAssert   bool
+}
+
+func newTestResponse(t testing.TB, key *rsa.PrivateKey, opts testResponseOptions) string {
+	if opts.issuer == "" {
+		opts.issuer = testIdPEntityId
+	}
+	if opts.audience == "" {
+		opts.audience = testSPEntityId
+	}
+	if opts.recipient == "" {
+		opts.recipient = testACSURL
+	}
+	if opts.destination == "" {
+		opts.destination = testACSURL
+	}
+	if opts.status == "" {
+		opts.status = StatusSuccess
+	}
+	if opts.notBefore.IsZero() {
+		opts.notBefore = time.Now().Add(-1 * time.Minute)
+	}
+	if opts.notOnOrAfter.IsZero() {
+		opts.notOnOrAfter = time.Now().Add(5 * time.Minute)
+	}
+
+	responseSignature := ""
+	if opts.signResponse {
+		responseSignature = testSignaturePlaceholder("_resp1")
+	}
+
+	assertionSignature := ""
+	if opts.signAssert {
+		assertionSignature = testSignaturePlaceholder("_assert1")
+	}
+
+	doc := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_resp1" Version="2.0" IssueInstant="` + time.Now().UTC().Format(time.RFC3339) + `" Destination="` + opts.destination + `" InResponseTo="` + opts.inResponseTo + `">
+	<saml:Issuer>` + opts.issuer + `</saml:Issuer>` + responseSignature + `
+	<samlp:Status><samlp:StatusCode Value="` + opts.status + `"/></samlp:Status>
+	<saml:Assertion xmlns:xs="http://www.w3.org/2001/XMLSchema" ID="_assert1" Version="2.0" IssueInstant="` + time.Now().UTC().Format(time.RFC3339) + `">
+		<saml:Issuer>` + opts.issuer + `</saml:Issuer>` + assertionSignature + `
+		<saml:Subject>
+			<saml:NameID Format="` + NameIDFormatEmailAddress + `">test@example.com</saml:NameID>
+			<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+				<saml:SubjectConfirmationData NotOnOrAfter="` + opts.notOnOrAfter.UTC().Format(time.RFC3339) + `" Recipient="` + opts.recipient + `"/>
+			</saml:SubjectConfirmation>
+		</saml:Subject>
+		<saml:Conditions NotBefore="` + opts.notBefore.UTC().Format(time.RFC3339) + `" NotOnOrAfter="` + opts.notOnOrAfter.UTC().Format(time.RFC3339) + `">
+			<saml:AudienceRestriction><saml:Audience>` + opts.audience + `</saml:Audience></saml:AudienceRestriction>
+		</saml:Conditions>
+		<saml:AuthnStatement AuthnInstant="` + time.Now().UTC().Format(time.RFC3339) + `" SessionIndex="_session1"/>
+		<saml:AttributeStatement>
+			<saml:Attribute Name="urn:oid:2.5.4.42" FriendlyName="givenName"><saml:AttributeValue xsi:type="xs:string" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">John</saml:AttributeValue></saml:Attribute>
+			<saml:Attribute Name="groups"><saml:AttributeValue>a</saml:AttributeValue><saml:AttributeValue>b</saml:AttributeValue></saml:Attribute>
+		</saml:AttributeStatement>
+	</saml:Assertion>
+</samlp:Response>`
+
+	if opts.signAssert {
+		doc = signTestXML(t, doc, "_assert1", key, AlgorithmSignatureRSASHA256)
+	}
+
+	if opts.signResponse {
+		doc = signTestXML(t, doc, "_resp1", key, AlgorithmSignatureRSASHA256)
+	}
+
+	return doc
+}
+
+func newTestSP(cert *x509.Certificate) *ServiceProvider {
+	return &ServiceProvider{
+		EntityId:        testSPEntityId,
+		ACSURL:          testACSURL,
+		IdPEntityId:     testIdPEntityId,
+		IdPSSOURL:       "https://idp.example.com/sso?a=1",
+		IdPCertificates: []*x509.Certificate{cert},
+	}
+}
+
+func TestParseCertificates(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+
+	pemCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	rawCert := base64.StdEncoding.EncodeToString(cert.Raw)
+
+	scenarios := []struct {
+		name          string
+		data          string
+		expectedCount int
+		expectError   bool
+	}{
+		{"empty", "", 0, true},
+		{"invalid base64", "!!!", 0, true},
+		{"invalid pem", "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----", 0, true},
+		{"raw base64 DER", rawCert[:20] + "\n" + rawCert[20:], 1, false},
+		{"single pem", pemCert, 1, false},
+		{"multiple pem", pemCert + "\n" + pemCert, 2, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			certs, err := ParseCertificates(s.data)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if len(certs) != s.expectedCount {
+				t.Fatalf("Expected %d certificates, got %d", s.expectedCount, len(certs))
+			}
+		})
+	}
+}
+
+func TestServiceProviderMetadata(t *testing.T) {
+	t.Parallel()
+
+	sp := &ServiceProvider{EntityId: testSPEntityId, ACSURL: testACSURL}
+
+	raw, err := sp.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := parseXML(raw)
+	if err != nil {
+		t.Fatalf("Failed to parse the generated metadata: %v", err)
+	}
+
+	if !root.Is(NamespaceMetadata, "EntityDescriptor") || root.Attr("entityID") != testSPEntityId {
+		t.Fatalf("Invalid EntityDescriptor element:\n%s", raw)
+	}
+
+	descriptor := root.ChildElement(NamespaceMetadata, "SPSSODescriptor")
+	if descriptor == nil || descriptor.Attr("WantAssertionsSigned") != "true" {
+		t.Fatalf("Invalid SPSSODescriptor element:\n%s", raw)
+	}
+
+	acs := descriptor.ChildElement(NamespaceMetadata, "AssertionConsumerService")
+	if acs == nil || acs.Attr("Location") != testACSURL || acs.Attr("Binding") != BindingHTTPPost {
+		t.Fatalf("Invalid AssertionConsumerService element:\n%s", raw)
+	}
+}
+
+func TestServiceProviderAuthnRequestURL(t *testing.T) {
+	t.Parallel()
+
+	sp := newTestSP(nil)
+
+	rawURL, requestId, err := sp.AuthnRequestURL("test_state")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requestId == "" {
+		t.Fatal("Expected non-empty request id")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(rawURL, "https://idp.example.com/sso?") || u.Query().Get("a") != "1" {
+		t.Fatalf("Expected the original IdP SSO url and query params to be preserved, got %s", rawURL)
+	}
+
+	if v := u.Query().Get("RelayState"); v != "test_state" {
+		t.Fatalf("Expected RelayState %q, got %q", "test_state", v)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := parseXML(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !root.Is(NamespaceProtocol, "AuthnRequest") {
+		t.Fatalf("Expected AuthnRequest element, got:\n%s", raw)
+	}
+
+	if v := root.Attr("ID"); v != requestId {
+		t.Fatalf("Expected request ID %q, got %q", requestId, v)
+	}
+
+	if v := root.Attr("AssertionConsumerServiceURL"); v != testACSURL {
+		t.Fatalf("Expected ACS url %q, got %q", testACSURL, v)
+	}
+
+	if issuer := root.ChildElement(NamespaceAssertion, "Issuer"); issuer == nil || issuer.Text() != testSPEntityId {
+		t.Fatalf("Expected Issuer %q, got:\n%s", testSPEntityId, raw)
+	}
+
+	// custom request id
+	_, customRequestId, err := sp.AuthnRequestURL("", "_custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customRequestId != "_custom" {
+		t.Fatalf("Expected request id %q, got %q", "_custom", customRequestId)
+	}
+
+	// too long RelayState
+	if _, _, err := sp.AuthnRequestURL(strings.Repeat("a", 81)); err == nil {
+		t.Fatal("Expected error for too long RelayState")
+	}
+
+	// missing IdP SSO url
+	sp.IdPSSOURL = ""
+	if _, _, err := sp.AuthnRequestURL(""); err == nil {
+		t.Fatal("Expected error for missing IdP SSO url")
+	}
+}
+
+func TestServiceProviderParseResponse(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+
+	encode := func(doc string) string {
+		return base64.StdEncoding.EncodeToString([]byte(doc))
+	}
+
+	scenarios := []struct {
+		name        string
+		response    string
+		requestIds  []string
+		expectError bool
+	}{
+		{
+			"invalid base64",
+			"!!!",
+			nil,
+			true,
+		},
+		{
+			"non Response root",
+			encode(`<a/>`),
+			nil,
+			true,
+		},
+		{
+			"unsigned response and assertion",
+			encode(newTestResponse(t, key, testResponseOptions{})),
+			nil,
+			true,
+		},
+		{
+			"signed assertion",
+			encode(newTestResponse(t, key, testResponseOptions{signAssert: true})),
+			nil,
+			false,
+		},
+		{
+			"signed response",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true})),
+			nil,
+			false,
+		},
+		{
+			"signed response and assertion",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, signAssert: true})),
+			nil,
+			false,
+		},
+		{
+			"tampered signed assertion",
+			encode(strings.Replace(
+				newTestResponse(t, key, testResponseOptions{signAssert: true}),
+				">test@example.com<",
+				">admin@example.com<",
+				1,
+			)),
+			nil,
+			true,
+		},
+		{
+			"unsuccessful status",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, status: "urn:oasis:names:tc:SAML:2.0:status:Requester"})),
+			nil,
+			true,
+		},
+		{
+			"different issuer",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, issuer: "https://evil.example.com"})),
+			nil,
+			true,
+		},
+		{
+			"different audience",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, audience: "https://other.example.com"})),
+			nil,
+			true,
+		},
+		{
+			"different recipient",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, recipient: "https://other.example.com/acs"})),
+			nil,
+			true,
+		},
+		{
+			"different destination",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, destination: "https://other.example.com/acs"})),
+			nil,
+			true,
+		},
+		{
+			"expired assertion",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, notOnOrAfter: time.Now().Add(-10 * time.Minute)})),
+			nil,
+			true,
+		},
+		{
+			"not yet valid assertion",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, notBefore: time.Now().Add(10 * time.Minute)})),
+			nil,
+			true,
+		},
+		{
+			"within the clock skew",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, notBefore: time.Now().Add(1 * time.Minute)})),
+			nil,
+			false,
+		},
+		{
+			"unexpected InResponseTo",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, inResponseTo: "_other"})),
+			[]string{"_req1"},
+			true,
+		},
+		{
+			"expected InResponseTo",
+			encode(newTestResponse(t, key, testResponseOptions{signResponse: true, inResponseTo: "_req1"})),
+			[]string{"_req1"},
+			false,
+		},
+	}
+
+	sp := newTestSP(cert)
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assertion, err := sp.ParseResponse(s.response, time.Now(), s.requestIds...)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if assertion.Id != "_assert1" {
+				t.Fatalf("Expected assertion id %q, got %q", "_assert1", assertion.Id)
+			}
+
+			if assertion.NameId != "test@example.com" || assertion.NameIdFormat != NameIDFormatEmailAddress {
+				t.Fatalf("Unexpected NameID %q (%q)", assertion.NameId, assertion.NameIdFormat)
+			}
+
+			if assertion.SessionIndex != "_session1" {
+				t.Fatalf("Expected session index %q, got %q", "_session1", assertion.SessionIndex)
+			}
+
+			if v := assertion.Attribute("givenName"); v != "John" {
+				t.Fatalf("Expected givenName attribute %q, got %q", "John", v)
+			}
+
+			if v := assertion.Attribute("urn:oid:2.5.4.42"); v != "John" {
+				t.Fatalf("Expected urn:oid:2.5.4.42 attribute %q, got %q", "John", v)
+			}
+
+			if v := assertion.Attributes["groups"]; len(v) != 2 || v[0] != "a" || v[1] != "b" {
+				t.Fatalf("Expected groups attribute [a b], got %v", v)
+			}
+
+			if v := assertion.Attribute("missing"); v != "" {
+				t.Fatalf("Expected empty missing attribute, got %q", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,435 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// maxDocumentSize is the max allowed size of a single XML document (~1MB).
+const maxDocumentSize = 1 << 20
+
+// maxDocumentDepth is the max allowed nesting level of a single XML document.
+const maxDocumentDepth = 100
+
+// xmlAttr defines a single non-namespace declaration element attribute.
+type xmlAttr struct {
+	Prefix string
+	Local  string
+	Value  string
+}
+
+// xmlElement is a minimal DOM element that preserves the namespace
+// prefixes as written in the original document (needed for the canonicalization).
+type xmlElement struct {
+	parent   *xmlElement
+	Prefix   string
+	Local    string
+	Attrs    []xmlAttr
+	NSDecls  map[string]string // prefix -> namespace uri ("" for the default namespace)
+	Children []any             // *xmlElement or string (char data)
+}
+
+// parseXML parses the provided raw XML document and returns its root element.
+//
+// DTDs, entity declarations and other directives are not allowed.
+func parseXML(data []byte) (*xmlElement, error) {
+	if len(data) > maxDocumentSize {
+		return nil, errors.New("the xml document is too large")
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var root *xmlElement
+	var current *xmlElement
+	var depth int
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth > maxDocumentDepth {
+				return nil, errors.New("the xml document is too deeply nested")
+			}
+
+			if root != nil && current == nil {
+				return nil, errors.New("the xml document must have a single root element")
+			}
+
+			el := &xmlElement{
+				parent:  current,
+				Prefix:  t.Name.Space,
+				Local:   t.Name.Local,
+				NSDecls: map[string]string{},
+			}
+
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					el.NSDecls[""] = attr.Value
+				case attr.Name.Space == "xmlns":
+					el.NSDecls[attr.Name.Local] = attr.Value
+				default:
+					el.Attrs = append(el.Attrs, xmlAttr{
+						Prefix: attr.Name.Space,
+						Local:  attr.Name.Local,
+						Value:  attr.Value,
+					})
+				}
+			}
+
+			if current == nil {
+				root = el
+			} else {
+				current.Children = append(current.Children, el)
+			}
+
+			current = el
+		case xml.EndElement:
+			depth--
+			if current == nil || current.Prefix != t.Name.Space || current.Local != t.Name.Local {
+				return nil, errors.New("unexpected xml end element " + t.Name.Local)
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.Children = append(current.Children, string(t))
+			} else if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("unexpected char data outside of the root element")
+			}
+		case xml.Directive:
+			return nil, errors.New("xml directives (DTD, entities, etc.) are not allowed")
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("missing xml root element")
+	}
+
+	if current != nil {
+		return nil, errors.New("unclosed xml element " + current.Local)
+	}
+
+	return root, nil
+}
+
+// lookupNS returns the namespace uri associated with the specified
+// prefix in the scope of the current element.
+func (el *xmlElement) lookupNS(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespace, true
+	}
+
+	for e := el; e != nil; e = e.parent {
+		if uri, ok := e.NSDecls[prefix]; ok {
+			return uri, true
+		}
+	}
+
+	// the default namespace is empty if not explicitly declared
+	if prefix == "" {
+		return "", true
+	}
+
+	return "", false
+}
+
+// inScopeNS returns all namespace declarations visible in the scope of the current element.
+func (el *xmlElement) inScopeNS() map[string]string {
+	result := map[string]string{}
+
+	for e := el; e != nil; e = e.parent {
+		for prefix, uri := range e.NSDecls {
+			if _, ok := result[prefix]; !ok {
+				result[prefix] = uri
+			}
+		}
+	}
+
+	return result
+}
+
+// Namespace returns the resolved namespace uri of the element.
+func (el *xmlElement) Namespace() string {
+	uri, _ := el.lookupNS(el.Prefix)
+	return uri
+}
+
+// Is checks whether the element has the specified namespace and local name.
+func (el *xmlElement) Is(namespace string, local string) bool {
+	return el.Local == local && el.Namespace() == namespace
+}
+
+// Attr returns the value of the first unprefixed attribute with the specified name.
+func (el *xmlElement) Attr(name string) string {
+	for _, attr := range el.Attrs {
+		if attr.Prefix == "" && attr.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// ChildElements returns all direct child elements matching the
+// specified namespace and local name.
+func (el *xmlElement) ChildElements(namespace string, local string) []*xmlElement {
+	var result []*xmlElement
+
+	for _, child := range el.Children {
+		if c, ok := child.(*xmlElement); ok && c.Is(namespace, local) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// ChildElement returns the first direct child element matching the
+// specified namespace and local name (or nil if there is no such element).
+func (el *xmlElement) ChildElement(namespace string, local string) *xmlElement {
+	for _, child := range el.Children {
+		if c, ok := child.(*xmlElement); ok && c.Is(namespace, local) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Text returns the concatenated char data of the element direct children.
+func (el *xmlElement) Text() string {
+	var sb strings.Builder
+
+	for _, child := range el.Children {
+		if str, ok := child.(string); ok {
+			sb.WriteString(str)
+		}
+	}
+
+	return sb.String()
+}
+
+// findElementsByID returns all elements in the current tree with the
+// specified "ID" attribute value.
+func (el *xmlElement) findElementsByID(id string) []*xmlElement {
+	var result []*xmlElement
+
+	if el.Attr("ID") == id {
+		result = append(result, el)
+	}
+
+	for _, child := range el.Children {
+		if c, ok := child.(*xmlElement); ok {
+			result = append(result, c.findElementsByID(id)...)
+		}
+	}
+
+	return result
+}
+
+// -------------------------------------------------------------------
+
+// canonicalize serializes the element subtree using the
+// Exclusive XML Canonicalization 1.0 (omits comments) algorithm
+// (https://www.w3.org/TR/xml-exc-c14n/).
+//
+// inclusivePrefixes is the optional InclusiveNamespaces PrefixList
+// ("#default" for the default namespace) and exclude is an optional
+// descendant element to omit from the output (e.g. for the enveloped signature transform).
+func (el *xmlElement) canonicalize(inclusivePrefixes []string, exclude *xmlElement) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := el.writeCanonical(&buf, map[string]string{"": ""}, inclusivePrefixes, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (el *xmlElement) writeCanonical(buf *bytes.Buffer, rendered map[string]string, inclusivePrefixes []string, exclude *xmlElement) error {
+	// collect the visibly utilized prefixes
+	utilized := []string{el.Prefix}
+	for _, attr := range el.Attrs {
+		if attr.Prefix != "" && !slices.Contains(utilized, attr.Prefix) {
+			utilized = append(utilized, attr.Prefix)
+		}
+	}
+
+	// treat the inclusive prefixes as utilized if they are in scope
+	if len(inclusivePrefixes) > 0 {
+		inScope := el.inScopeNS()
+		for _, p := range inclusivePrefixes {
+			if p == "#default" {
+				p = ""
+			}
+			if _, ok := inScope[p]; ok && !slices.Contains(utilized, p) {
+				utilized = append(utilized, p)
+			}
+		}
+	}
+
+	type nsDecl struct {
+		prefix string
+		uri    string
+	}
+
+	var decls []nsDecl
+
+	childRendered := rendered
+
+	for _, prefix := range utilized {
+		if prefix == "xml" {
+			continue
+		}
+
+		uri, ok := el.lookupNS(prefix)
+		if !ok {
+			return fmt.Errorf("undeclared namespace prefix %q", prefix)
+		}
+
+		if current, ok := rendered[prefix]; ok && current == uri {
+			continue // already rendered by an output ancestor
+		}
+
+		decls = append(decls, nsDecl{prefix, uri})
+	}
+
+	if len(decls) > 0 {
+		childRendered = make(map[string]string, len(rendered)+len(decls))
+		for k, v := range rendered {
+			childRendered[k] = v
+		}
+		for _, d := range decls {
+			childRendered[d.prefix] = d.uri
+		}
+	}
+
+	slices.SortFunc(decls, func(a, b nsDecl) int {
+		return strings.Compare(a.prefix, b.prefix)
+	})
+
+	type resolvedAttr struct {
+		xmlAttr
+		namespace string
+	}
+
+	attrs := make([]resolvedAttr, 0, len(el.Attrs))
+	for _, attr := range el.Attrs {
+		var namespace string
+		if attr.Prefix != "" {
+			namespace, _ = el.lookupNS(attr.Prefix)
+		}
+		attrs = append(attrs, resolvedAttr{attr, namespace})
+	}
+
+	slices.SortFunc(attrs, func(a, b resolvedAttr) int {
+		if c := strings.Compare(a.namespace, b.namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Local, b.Local)
+	})
+
+	name := el.Local
+	if el.Prefix != "" {
+		name = el.Prefix + ":" + el.Local
+	}
+
+	buf.WriteByte('<')
+	buf.WriteString(name)
+
+	for _, d := range decls {
+		if d.prefix == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + d.prefix + `="`)
+		}
+		escapeCanonicalAttr(buf, d.uri)
+		buf.WriteByte('"')
+	}
+
+	for _, attr := range attrs {
+		buf.WriteByte(' ')
+		if attr.Prefix != "" {
+			buf.WriteString(attr.Prefix + ":")
+		}
+		buf.WriteString(attr.Local)
+		buf.WriteString(`="`)
+		escapeCanonicalAttr(buf, attr.Value)
+		buf.WriteByte('"')
+	}
+
+	buf.WriteByte('>')
+
+	for _, child := range el.Children {
+		switch c := child.(type) {
+		case string:
+			escapeCanonicalText(buf, c)
+		case *xmlElement:
+			if c == exclude {
+				continue
+			}
+			if err := c.writeCanonical(buf, childRendered, inclusivePrefixes, exclude); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf.WriteString("</" + name + ">")
+
+	return nil
+}
+
+func escapeCanonicalText(buf *bytes.Buffer, str string) {
+	for _, r := range str {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '\r':
+			buf.WriteString("&#xD;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
+
+func escapeCanonicalAttr(buf *bytes.Buffer, str string) {
+	for _, r := range str {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '"':
+			buf.WriteString("&quot;")
+		case '\t':
+			buf.WriteString("&#x9;")
+		case '\n':
+			buf.WriteString("&#xA;")
+		case '\r':
+			buf.WriteString("&#xD;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
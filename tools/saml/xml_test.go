@@ -0,0 +1,183 @@
+package saml
+
+import (
+	"testing"
+)
+
+func TestParseXML(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{"empty", ``, true},
+		{"invalid", `<a>`, true},
+		{"mismatched end tag", `<a></b>`, true},
+		{"multiple roots", `<a></a><b></b>`, true},
+		{"doctype", `<!DOCTYPE a [<!ENTITY x "y">]><a>&x;</a>`, true},
+		{"undeclared entity", `<a>&x;</a>`, true},
+		{"valid", `<?xml version="1.0"?><a xmlns="urn:test"><b>test</b></a>`, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := parseXML([]byte(s.data))
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestXMLElementHelpers(t *testing.T) {
+	t.Parallel()
+
+	root, err := parseXML([]byte(`<a:root xmlns:a="urn:a" xmlns="urn:default" ID="r1">
+		<child ID="c1">hello <b:x xmlns:b="urn:b">ignored</b:x>world</child>
+		<a:child ID="c2"/>
+	</a:root>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !root.Is("urn:a", "root") {
+		t.Fatalf("Expected root to be urn:a root, got %q %q", root.Namespace(), root.Local)
+	}
+
+	if v := root.Attr("ID"); v != "r1" {
+		t.Fatalf("Expected ID r1, got %q", v)
+	}
+
+	defaultChildren := root.ChildElements("urn:default", "child")
+	if len(defaultChildren) != 1 || defaultChildren[0].Attr("ID") != "c1" {
+		t.Fatalf("Expected single c1 default child, got %v", defaultChildren)
+	}
+
+	if text := defaultChildren[0].Text(); text != "hello world" {
+		t.Fatalf("Expected text %q, got %q", "hello world", text)
+	}
+
+	if c := root.ChildElement("urn:a", "child"); c == nil || c.Attr("ID") != "c2" {
+		t.Fatalf("Expected c2 child, got %v", c)
+	}
+
+	if c := root.ChildElement("urn:missing", "child"); c != nil {
+		t.Fatalf("Expected nil child, got %v", c)
+	}
+
+	if found := root.findElementsByID("c1"); len(found) != 1 {
+		t.Fatalf("Expected 1 element with ID c1, got %d", len(found))
+	}
+}
+
+func TestXMLElementCanonicalize(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		data      string
+		path      []string // local names of the nested elements to canonicalize
+		inclusive []string
+		expected  string
+	}{
+		{
+			"exc-c14n spec example",
+			`<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"/></n1:elem2></n0:local>`,
+			[]string{"elem2"},
+			nil,
+			`<n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2>`,
+		},
+		{
+			"unused namespaces are omitted and inherited ones are rendered",
+			`<a:root xmlns:a="urn:a" xmlns:b="urn:b" xmlns:unused="urn:unused"><a:child b:attr="1"><a:nested/></a:child></a:root>`,
+			[]string{"child"},
+			nil,
+			`<a:child xmlns:a="urn:a" xmlns:b="urn:b" b:attr="1"><a:nested></a:nested></a:child>`,
+		},
+		{
+			"inclusive prefixes",
+			`<a:root xmlns:a="urn:a" xmlns:xs="urn:xs"><a:child/></a:root>`,
+			[]string{"child"},
+			[]string{"xs", "missing"},
+			`<a:child xmlns:a="urn:a" xmlns:xs="urn:xs"></a:child>`,
+		},
+		{
+			"attributes sorting and escaping",
+			`<root z="1" b:y="2" a="&quot;&lt;&#9;&#10;" xmlns:b="urn:b" b:a="3">t&amp;&lt;&gt;&#13;"</root>`,
+			nil,
+			nil,
+			`<root xmlns:b="urn:b" a="&quot;&lt;&#x9;&#xA;" z="1" b:a="3" b:y="2">t&amp;&lt;&gt;&#xD;"</root>`,
+		},
+		{
+			"default namespace undeclaration",
+			`<root xmlns="urn:default"><child xmlns=""><nested/></child></root>`,
+			nil,
+			nil,
+			`<root xmlns="urn:default"><child xmlns=""><nested></nested></child></root>`,
+		},
+		{
+			"default namespace of apex",
+			`<root xmlns="urn:default"><child/></root>`,
+			[]string{"child"},
+			nil,
+			`<child xmlns="urn:default"></child>`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			el, err := parseXML([]byte(s.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, local := range s.path {
+				var next *xmlElement
+				for _, c := range el.Children {
+					if child, ok := c.(*xmlElement); ok && child.Local == local {
+						next = child
+						break
+					}
+				}
+				if next == nil {
+					t.Fatalf("Missing element %q", local)
+				}
+				el = next
+			}
+
+			result, err := el.canonicalize(s.inclusive, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(result) != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestXMLElementCanonicalizeExclude(t *testing.T) {
+	t.Parallel()
+
+	root, err := parseXML([]byte(`<root><a/><b/></root>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exclude := root.Children[0].(*xmlElement)
+
+	result, err := root.canonicalize(nil, exclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<root><b></b></root>`
+	if string(result) != expected {
+		t.Fatalf("Expected %s, got %s", expected, result)
+	}
+}
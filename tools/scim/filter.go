@@ -0,0 +1,651 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+)
+
+// List with the supported filter operators.
+const (
+	OpAnd = "and"
+	OpOr  = "or"
+	OpNot = "not"
+
+	OpEqual          = "eq"
+	OpNotEqual       = "ne"
+	OpContains       = "co"
+	OpStartsWith     = "sw"
+	OpEndsWith       = "ew"
+	OpPresent        = "pr"
+	OpGreater        = "gt"
+	OpGreaterOrEqual = "ge"
+	OpLess           = "lt"
+	OpLessOrEqual    = "le"
+)
+
+var compareOperators = map[string]struct{}{
+	OpEqual:          {},
+	OpNotEqual:       {},
+	OpContains:       {},
+	OpStartsWith:     {},
+	OpEndsWith:       {},
+	OpGreater:        {},
+	OpGreaterOrEqual: {},
+	OpLess:           {},
+	OpLessOrEqual:    {},
+}
+
+const maxFilterLength = 4096
+
+// Expr defines a single parsed SCIM filter expression node.
+//
+// Logical nodes (and, or, not) have only Children, while the attribute
+// nodes have only Attr and Value (Value is nil for the "pr" operator).
+//
+// Attr is always normalized to lowercase (without URN schema prefix)
+// and value path filters are flattened, aka. `emails[type eq "work"]`
+// is stored as `emails.type eq "work"`.
+type Expr struct {
+	Value    any     // string, float64, bool or nil
+	Op       string  // one of the Op* constants
+	Attr     string  // attribute path (eg. "username", "name.givenname")
+	Children []*Expr // and/or/not operands
+}
+
+// ParseFilter parses the provided SCIM filter string (RFC 7644 3.4.2.2).
+func ParseFilter(filter string) (*Expr, error) {
+	if len(filter) > maxFilterLength {
+		return nil, errors.New("the filter is too long")
+	}
+
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	expr, err := p.parseOr("")
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.eof() {
+		return nil, fmt.Errorf("unexpected token %q", p.peek().literal)
+	}
+
+	return expr, nil
+}
+
+// Build converts the expression into a [search] filter string and its placeholder params.
+//
+// resolveAttr is used to map each SCIM attribute path to a filter field
+// identifier and it is expected to return an error for unsupported attributes.
+func (e *Expr) Build(resolveAttr func(attr string) (string, error)) (string, dbx.Params, error) {
+	params := dbx.Params{}
+
+	result, err := e.build(resolveAttr, params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return result, params, nil
+}
+
+func (e *Expr) build(resolveAttr func(attr string) (string, error), params dbx.Params) (string, error) {
+	switch e.Op {
+	case OpAnd, OpOr:
+		parts := make([]string, 0, len(e.Children))
+		for _, child := range e.Children {
+			part, err := child.build(resolveAttr, params)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+
+		joinOp := " && "
+		if e.Op == OpOr {
+			joinOp = " || "
+		}
+
+		return "(" + strings.Join(parts, joinOp) + ")", nil
+	case OpNot:
+		// the filter grammar doesn't have a NOT operator so we invert
+		// the child expression by applying De Morgan's laws
+		if len(e.Children) != 1 {
+			return "", errors.New("invalid not expression")
+		}
+		return e.Children[0].negate().build(resolveAttr, params)
+	}
+
+	field, err := resolveAttr(e.Attr)
+	if err != nil {
+		return "", err
+	}
+
+	if e.Op == OpPresent {
+		return "(" + field + " != '' && " + field + " != null)", nil
+	}
+
+	if e.Op == "!"+OpPresent {
+		return "(" + field + " = '' || " + field + " = null)", nil
+	}
+
+	var value string
+	switch v := e.Value.(type) {
+	case nil:
+		value = "null"
+	case bool:
+		value = strconv.FormatBool(v)
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		name := "scim" + strconv.Itoa(len(params))
+		value = "{:" + name + "}"
+
+		switch strings.TrimPrefix(e.Op, "!") {
+		case OpContains:
+			params[name] = "%" + escapeLike(v) + "%"
+		case OpStartsWith:
+			params[name] = escapeLike(v) + "%"
+		case OpEndsWith:
+			params[name] = "%" + escapeLike(v)
+		default:
+			params[name] = v
+		}
+	default:
+		return "", fmt.Errorf("unsupported filter value %v", v)
+	}
+
+	var op string
+	switch e.Op {
+	case OpEqual, "!" + OpNotEqual:
+		op = "="
+	case OpNotEqual, "!" + OpEqual:
+		op = "!="
+	case OpContains, OpStartsWith, OpEndsWith:
+		op = "~"
+	case "!" + OpContains, "!" + OpStartsWith, "!" + OpEndsWith:
+		op = "!~"
+	case OpGreater, "!" + OpLessOrEqual:
+		op = ">"
+	case OpGreaterOrEqual, "!" + OpLess:
+		op = ">="
+	case OpLess, "!" + OpGreaterOrEqual:
+		op = "<"
+	case OpLessOrEqual, "!" + OpGreater:
+		op = "<="
+	default:
+		return "", fmt.Errorf("unsupported filter operator %q", e.Op)
+	}
+
+	return field + " " + op + " " + value, nil
+}
+
+// negate returns a new inverted copy of the current expression.
+//
+// Negated attribute operators are prefixed with "!" and are
+// meant to be used only internally by the build method.
+func (e *Expr) negate() *Expr {
+	switch e.Op {
+	case OpAnd, OpOr:
+		negated := &Expr{Op: OpAnd}
+		if e.Op == OpAnd {
+			negated.Op = OpOr
+		}
+		for _, child := range e.Children {
+			negated.Children = append(negated.Children, child.negate())
+		}
+		return negated
+	case OpNot:
+		return e.Children[0]
+	}
+
+	negated := *e
+	if strings.HasPrefix(e.Op, "!") {
+		negated.Op = e.Op[1:]
+	} else {
+		negated.Op = "!" + e.Op
+	}
+
+	return &negated
+}
+
+// Match reports whether the expression is satisfied by the attribute
+// values returned by getValue (usually used to evaluate PATCH value path filters).
+//
+// String comparisons are case-insensitive.
+func (e *Expr) Match(getValue func(attr string) any) bool {
+	switch e.Op {
+	case OpAnd:
+		for _, child := range e.Children {
+			if !child.Match(getValue) {
+				return false
+			}
+		}
+		return true
+	case OpOr:
+		for _, child := range e.Children {
+			if child.Match(getValue) {
+				return true
+			}
+		}
+		return false
+	case OpNot:
+		return len(e.Children) == 1 && !e.Children[0].Match(getValue)
+	}
+
+	value := getValue(e.Attr)
+
+	if e.Op == OpPresent {
+		return value != nil && value != ""
+	}
+
+	switch expected := e.Value.(type) {
+	case string:
+		actual, ok := value.(string)
+		if !ok {
+			return e.Op == OpNotEqual
+		}
+		return compareStrings(e.Op, strings.ToLower(actual), strings.ToLower(expected))
+	case float64:
+		var actual float64
+		switch v := value.(type) {
+		case float64:
+			actual = v
+		case int:
+			actual = float64(v)
+		default:
+			return e.Op == OpNotEqual
+		}
+		return compareNumbers(e.Op, actual, expected)
+	default: // bool and nil
+		switch e.Op {
+		case OpEqual:
+			return value == expected
+		case OpNotEqual:
+			return value != expected
+		}
+	}
+
+	return false
+}
+
+func compareStrings(op string, a, b string) bool {
+	switch op {
+	case OpEqual:
+		return a == b
+	case OpNotEqual:
+		return a != b
+	case OpContains:
+		return strings.Contains(a, b)
+	case OpStartsWith:
+		return strings.HasPrefix(a, b)
+	case OpEndsWith:
+		return strings.HasSuffix(a, b)
+	case OpGreater:
+		return a > b
+	case OpGreaterOrEqual:
+		return a >= b
+	case OpLess:
+		return a < b
+	case OpLessOrEqual:
+		return a <= b
+	}
+
+	return false
+}
+
+func compareNumbers(op string, a, b float64) bool {
+	switch op {
+	case OpEqual:
+		return a == b
+	case OpNotEqual:
+		return a != b
+	case OpGreater:
+		return a > b
+	case OpGreaterOrEqual:
+		return a >= b
+	case OpLess:
+		return a < b
+	case OpLessOrEqual:
+		return a <= b
+	}
+
+	return false
+}
+
+func escapeLike(str string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(str)
+}
+
+// normalizeAttr lowercases the attribute path and strips its URN schema prefix
+// (eg. "urn:ietf:params:scim:schemas:core:2.0:User:userName" -> "username").
+func normalizeAttr(attr string) string {
+	if strings.HasPrefix(strings.ToLower(attr), "urn:") {
+		// the attribute names can't contain ":" so everything after the last one is the attribute path
+		attr = attr[strings.LastIndex(attr, ":")+1:]
+	}
+
+	return strings.ToLower(attr)
+}
+
+// -------------------------------------------------------------------
+
+const (
+	tokenWord   = "word"
+	tokenString = "string"
+	tokenLParen = "("
+	tokenRParen = ")"
+	tokenLBrack = "["
+	tokenRBrack = "]"
+)
+
+type token struct {
+	kind    string
+	literal string
+}
+
+func tokenize(str string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(str); {
+		c := str[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, token{kind: string(c), literal: string(c)})
+			i++
+		case c == '"':
+			// find the closing quote (skipping the escaped characters)
+			end := i + 1
+			for ; end < len(str); end++ {
+				if str[end] == '\\' {
+					end++
+					continue
+				}
+				if str[end] == '"' {
+					break
+				}
+			}
+			if end >= len(str) {
+				return nil, errors.New("unterminated string value")
+			}
+
+			var value string
+			if err := json.Unmarshal([]byte(str[i:end+1]), &value); err != nil {
+				return nil, fmt.Errorf("invalid string value %s", str[i:end+1])
+			}
+
+			tokens = append(tokens, token{kind: tokenString, literal: value})
+			i = end + 1
+		default:
+			end := i
+			for end < len(str) && !strings.ContainsRune(" \t\n\r()[]\"", rune(str[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, literal: str[i:end]})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.eof() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && strings.EqualFold(t.literal, keyword)
+}
+
+func (p *parser) parseOr(attrPrefix string) (*Expr, error) {
+	left, err := p.parseAnd(attrPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isKeyword(OpOr) {
+		return left, nil
+	}
+
+	expr := &Expr{Op: OpOr, Children: []*Expr{left}}
+	for p.isKeyword(OpOr) {
+		p.next()
+
+		right, err := p.parseAnd(attrPrefix)
+		if err != nil {
+			return nil, err
+		}
+		expr.Children = append(expr.Children, right)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseAnd(attrPrefix string) (*Expr, error) {
+	left, err := p.parseUnary(attrPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isKeyword(OpAnd) {
+		return left, nil
+	}
+
+	expr := &Expr{Op: OpAnd, Children: []*Expr{left}}
+	for p.isKeyword(OpAnd) {
+		p.next()
+
+		right, err := p.parseUnary(attrPrefix)
+		if err != nil {
+			return nil, err
+		}
+		expr.Children = append(expr.Children, right)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseUnary(attrPrefix string) (*Expr, error) {
+	if p.eof() {
+		return nil, errors.New("unexpected end of filter")
+	}
+
+	if p.isKeyword(OpNot) {
+		p.next()
+
+		if p.next().kind != tokenLParen {
+			return nil, errors.New("expected ( after not")
+		}
+
+		child, err := p.parseOr(attrPrefix)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next().kind != tokenRParen {
+			return nil, errors.New("missing closing )")
+		}
+
+		return &Expr{Op: OpNot, Children: []*Expr{child}}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+
+		expr, err := p.parseOr(attrPrefix)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next().kind != tokenRParen {
+			return nil, errors.New("missing closing )")
+		}
+
+		return expr, nil
+	}
+
+	attrToken := p.next()
+	if attrToken.kind != tokenWord {
+		return nil, fmt.Errorf("expected attribute path, got %q", attrToken.literal)
+	}
+	attr := attrPrefix + normalizeAttr(attrToken.literal)
+
+	// value path filter (eg. emails[type eq "work"])
+	if p.peek().kind == tokenLBrack {
+		if attrPrefix != "" {
+			return nil, errors.New("nested value path filters are not supported")
+		}
+
+		p.next()
+
+		expr, err := p.parseOr(attr + ".")
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next().kind != tokenRBrack {
+			return nil, errors.New("missing closing ]")
+		}
+
+		return expr, nil
+	}
+
+	opToken := p.next()
+	op := strings.ToLower(opToken.literal)
+	if opToken.kind != tokenWord {
+		return nil, fmt.Errorf("expected operator after %q", attrToken.literal)
+	}
+
+	if op == OpPresent {
+		return &Expr{Op: OpPresent, Attr: attr}, nil
+	}
+
+	if _, ok := compareOperators[op]; !ok {
+		return nil, fmt.Errorf("unsupported operator %q", opToken.literal)
+	}
+
+	valueToken := p.next()
+
+	var value any
+	switch valueToken.kind {
+	case tokenString:
+		value = valueToken.literal
+	case tokenWord:
+		switch strings.ToLower(valueToken.literal) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			num, err := strconv.ParseFloat(valueToken.literal, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid comparison value %q", valueToken.literal)
+			}
+			value = num
+		}
+	default:
+		return nil, fmt.Errorf("missing comparison value for %q", attrToken.literal)
+	}
+
+	return &Expr{Op: op, Attr: attr, Value: value}, nil
+}
+
+// -------------------------------------------------------------------
+
+// Path defines a parsed PATCH operation path (RFC 7644 3.5.2).
+//
+// Example: `members[value eq "abc"]` -> Attr: "members", Filter: `members.value eq "abc"`
+type Path struct {
+	Filter  *Expr
+	Attr    string // normalized attribute name
+	SubAttr string // normalized sub-attribute name (if any)
+}
+
+// ParsePath parses a single PATCH operation path.
+func ParsePath(path string) (*Path, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("empty path")
+	}
+
+	result := &Path{}
+
+	if start := strings.Index(path, "["); start > 0 {
+		end := strings.LastIndex(path, "]")
+		if end < start {
+			return nil, errors.New("missing closing ]")
+		}
+
+		filter, err := ParseFilter(path[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		result.Filter = filter
+		result.Attr = normalizeAttr(path[:start])
+
+		rest := path[end+1:]
+		if rest != "" {
+			if !strings.HasPrefix(rest, ".") || len(rest) == 1 {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			result.SubAttr = strings.ToLower(rest[1:])
+		}
+
+		return result, nil
+	}
+
+	attr := normalizeAttr(path)
+	if before, after, ok := strings.Cut(attr, "."); ok {
+		result.Attr = before
+		result.SubAttr = after
+	} else {
+		result.Attr = attr
+	}
+
+	if result.Attr == "" || strings.ContainsAny(result.Attr, " ()]\"") {
+		return nil, fmt.Errorf("invalid path %q", path)
+	}
+
+	return result, nil
+}
+
+// FullAttr returns the dotted attribute path (eg. "name.givenname").
+func (p *Path) FullAttr() string {
+	if p.SubAttr == "" {
+		return p.Attr
+	}
+
+	return p.Attr + "." + p.SubAttr
+}
@@ -0,0 +1,245 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/scim"
+)
+
+func testResolveAttr(attr string) (string, error) {
+	switch attr {
+	case "username":
+		return "username", nil
+	case "emails.value", "emails":
+		return "email", nil
+	case "emails.type":
+		return "emailType", nil
+	case "active":
+		return "verified", nil
+	case "meta.lastmodified":
+		return "updated", nil
+	}
+
+	return "", errors.New("unsupported " + attr)
+}
+
+func TestParseFilterAndBuild(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		filter         string
+		expectError    bool
+		expectedFilter string
+		expectedParams string
+	}{
+		{"empty", ``, true, "", ""},
+		{"missing value", `userName eq`, true, "", ""},
+		{"invalid operator", `userName like "a"`, true, "", ""},
+		{"unclosed string", `userName eq "a`, true, "", ""},
+		{"unclosed group", `(userName eq "a"`, true, "", ""},
+		{"trailing tokens", `userName eq "a" "b"`, true, "", ""},
+		{"unsupported attribute", `title eq "a"`, true, "", ""},
+		{"too long", `userName eq "` + strings.Repeat("a", 5000) + `"`, true, "", ""},
+		{
+			"eq with case-insensitive operator and attribute",
+			`UserName EQ "test@example.com"`,
+			false,
+			`username = {:scim0}`,
+			`{"scim0":"test@example.com"}`,
+		},
+		{
+			"urn prefixed attribute",
+			`urn:ietf:params:scim:schemas:core:2.0:User:userName ne "a"`,
+			false,
+			`username != {:scim0}`,
+			`{"scim0":"a"}`,
+		},
+		{
+			"like operators with escaped wildcards",
+			`userName co "a%" and userName sw "b" and userName ew "c"`,
+			false,
+			`(username ~ {:scim0} && username ~ {:scim1} && username ~ {:scim2})`,
+			`{"scim0":"%a\\%%","scim1":"b%","scim2":"%c"}`,
+		},
+		{
+			"literals and comparison operators",
+			`active eq true or meta.lastModified gt "2024-01-01" or meta.lastModified le 5`,
+			false,
+			`(verified = true || updated > {:scim0} || updated <= 5)`,
+			`{"scim0":"2024-01-01"}`,
+		},
+		{
+			"presence",
+			`emails pr`,
+			false,
+			`(email != '' && email != null)`,
+			`{}`,
+		},
+		{
+			"precedence and grouping",
+			`userName eq "a" or userName eq "b" and (active eq false or emails pr)`,
+			false,
+			`(username = {:scim0} || (username = {:scim1} && (verified = false || (email != '' && email != null))))`,
+			`{"scim0":"a","scim1":"b"}`,
+		},
+		{
+			"not with De Morgan",
+			`not (userName eq "a" and emails co "b")`,
+			false,
+			`(username != {:scim0} || email !~ {:scim1})`,
+			`{"scim0":"a","scim1":"%b%"}`,
+		},
+		{
+			"double negation",
+			`not (not (emails pr))`,
+			false,
+			`(email != '' && email != null)`,
+			`{}`,
+		},
+		{
+			"value path",
+			`emails[type eq "work" and value ew "@example.com"]`,
+			false,
+			`(emailType = {:scim0} && email ~ {:scim1})`,
+			`{"scim0":"work","scim1":"%@example.com"}`,
+		},
+		{
+			"escaped quotes",
+			`userName eq "a\"b"`,
+			false,
+			`username = {:scim0}`,
+			`{"scim0":"a\"b"}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var filter string
+			var paramsRaw []byte
+
+			expr, err := scim.ParseFilter(s.filter)
+			if err == nil {
+				var params any
+				filter, params, err = expr.Build(testResolveAttr)
+				paramsRaw, _ = json.Marshal(params)
+			}
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if filter != s.expectedFilter {
+				t.Fatalf("Expected filter\n%s\ngot\n%s", s.expectedFilter, filter)
+			}
+
+			if string(paramsRaw) != s.expectedParams {
+				t.Fatalf("Expected params\n%s\ngot\n%s", s.expectedParams, paramsRaw)
+			}
+		})
+	}
+}
+
+func TestExprMatch(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"members.value":   "AbC",
+		"members.display": "",
+		"members.count":   float64(5),
+	}
+
+	getValue := func(attr string) any {
+		return values[attr]
+	}
+
+	scenarios := []struct {
+		filter   string
+		expected bool
+	}{
+		{`members[value eq "abc"]`, true},
+		{`members[value eq "abd"]`, false},
+		{`members[value ne "abd"]`, true},
+		{`members[value sw "a"]`, true},
+		{`members[value ew "c"]`, true},
+		{`members[value co "b"]`, true},
+		{`members[value co "x"]`, false},
+		{`members[value pr]`, true},
+		{`members[display pr]`, false},
+		{`members[missing pr]`, false},
+		{`members[count gt 4 and count le 5]`, true},
+		{`members[count lt 5]`, false},
+		{`members[value eq "x" or count ge 5]`, true},
+		{`not (members[value eq "abc"])`, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter, func(t *testing.T) {
+			expr, err := scim.ParseFilter(s.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result := expr.Match(getValue); result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		path            string
+		expectError     bool
+		expectedAttr    string
+		expectedSubAttr string
+		expectedFilter  bool
+	}{
+		{"", true, "", "", false},
+		{"members[value eq", true, "", "", false},
+		{"members[value eq \"a\"]x", true, "", "", false},
+		{"invalid attr", true, "", "", false},
+		{"userName", false, "username", "", false},
+		{"name.givenName", false, "name", "givenname", false},
+		{"urn:ietf:params:scim:schemas:core:2.0:User:displayName", false, "displayname", "", false},
+		{`members[value eq "a"]`, false, "members", "", true},
+		{`emails[type eq "work"].value`, false, "emails", "value", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.path, func(t *testing.T) {
+			path, err := scim.ParsePath(s.path)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if path.Attr != s.expectedAttr {
+				t.Fatalf("Expected Attr %q, got %q", s.expectedAttr, path.Attr)
+			}
+
+			if path.SubAttr != s.expectedSubAttr {
+				t.Fatalf("Expected SubAttr %q, got %q", s.expectedSubAttr, path.SubAttr)
+			}
+
+			if hasFilter := path.Filter != nil; hasFilter != s.expectedFilter {
+				t.Fatalf("Expected hasFilter %v, got %v", s.expectedFilter, hasFilter)
+			}
+		})
+	}
+}
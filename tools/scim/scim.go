@@ -0,0 +1,129 @@
+// Package scim implements helpers for building SCIM 2.0
+// (RFC 7643 and RFC 7644) provisioning endpoints.
+package scim
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ContentType is the SCIM protocol media type.
+const ContentType = "application/scim+json"
+
+// List with the supported SCIM schema and message URNs.
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// List with the standard error scimType values (RFC 7644 3.12).
+const (
+	ErrorTypeInvalidFilter = "invalidFilter"
+	ErrorTypeTooMany       = "tooMany"
+	ErrorTypeUniqueness    = "uniqueness"
+	ErrorTypeMutability    = "mutability"
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+	ErrorTypeInvalidPath   = "invalidPath"
+	ErrorTypeNoTarget      = "noTarget"
+	ErrorTypeInvalidValue  = "invalidValue"
+)
+
+// List with the supported PATCH operations.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+)
+
+// Error defines a SCIM error response.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// Meta defines the common resource meta attribute.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// ListResponse defines a SCIM list (aka. query) response.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	Resources    []any    `json:"Resources"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+}
+
+// NewListResponse creates a new ListResponse for the provided resources.
+func NewListResponse(resources []any, totalResults int, startIndex int) *ListResponse {
+	if resources == nil {
+		resources = []any{}
+	}
+
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		Resources:    resources,
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+	}
+}
+
+// PatchOperation defines a single PATCH request operation.
+type PatchOperation struct {
+	Value any    `json:"value"`
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+}
+
+// PatchRequest defines a SCIM PATCH request body (RFC 7644 3.5.2).
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// Validate checks whether the PATCH request has the expected schema
+// and valid operations.
+//
+// Note that it also normalizes the operation names to lowercase
+// since some IdPs (eg. Azure AD) send them capitalized.
+func (r *PatchRequest) Validate() error {
+	if !slices.Contains(r.Schemas, SchemaPatchOp) {
+		return errors.New("missing " + SchemaPatchOp + " schema")
+	}
+
+	if len(r.Operations) == 0 {
+		return errors.New("at least one operation is required")
+	}
+
+	for i, op := range r.Operations {
+		r.Operations[i].Op = strings.ToLower(op.Op)
+
+		switch r.Operations[i].Op {
+		case PatchOpAdd, PatchOpReplace:
+			if op.Value == nil {
+				return errors.New("missing " + r.Operations[i].Op + " operation value")
+			}
+		case PatchOpRemove:
+			if op.Path == "" {
+				return errors.New("missing remove operation path")
+			}
+		default:
+			return errors.New("unsupported operation " + op.Op)
+		}
+	}
+
+	return nil
+}
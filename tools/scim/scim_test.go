@@ -0,0 +1,112 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/scim"
+)
+
+func TestNewListResponse(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		resources []any
+		expected  string
+	}{
+		{
+			"nil resources",
+			nil,
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"Resources":[],"totalResults":10,"startIndex":1,"itemsPerPage":0}`,
+		},
+		{
+			"non-empty resources",
+			[]any{map[string]any{"id": "a"}, map[string]any{"id": "b"}},
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"Resources":[{"id":"a"},{"id":"b"}],"totalResults":10,"startIndex":1,"itemsPerPage":2}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			raw, err := json.Marshal(scim.NewListResponse(s.resources, 10, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestPatchRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		body        string
+		expectError bool
+		expectedOps []string
+	}{
+		{
+			"missing schema",
+			`{"Operations":[{"op":"add","value":{"a":1}}]}`,
+			true,
+			nil,
+		},
+		{
+			"no operations",
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[]}`,
+			true,
+			nil,
+		},
+		{
+			"unsupported operation",
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"move","path":"a"}]}`,
+			true,
+			nil,
+		},
+		{
+			"add without value",
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"add","path":"a"}]}`,
+			true,
+			nil,
+		},
+		{
+			"remove without path",
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"remove"}]}`,
+			true,
+			nil,
+		},
+		{
+			"valid operations with mixed case",
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"Replace","value":{"active":false}},{"op":"ADD","path":"members","value":[{"value":"a"}]},{"op":"remove","path":"members"}]}`,
+			false,
+			[]string{"replace", "add", "remove"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			req := &scim.PatchRequest{}
+			if err := json.Unmarshal([]byte(s.body), req); err != nil {
+				t.Fatal(err)
+			}
+
+			err := req.Validate()
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			for i, op := range s.expectedOps {
+				if req.Operations[i].Op != op {
+					t.Fatalf("Expected operation %d to be %q, got %q", i, op, req.Operations[i].Op)
+				}
+			}
+		})
+	}
+}
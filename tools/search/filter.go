@@ -173,6 +173,15 @@ func buildResolversExpr(
 ) (dbx.Expression, error) {
 	var expr dbx.Expression
 
+	if !isLikeOp(op) {
+		if err := normalizeOperandParam(left.ParamNormalizer, right); err != nil {
+			return nil, err
+		}
+		if err := normalizeOperandParam(right.ParamNormalizer, left); err != nil {
+			return nil, err
+		}
+	}
+
 	switch op {
 	case fexpr.SignEq, fexpr.SignAnyEq:
 		expr = resolveEqualExpr(true, left, right)
@@ -405,6 +414,38 @@ func resolveEqualExpr(equal bool, left, right *ResolverResult) dbx.Expression {
 	)
 }
 
+func isLikeOp(op fexpr.SignOp) bool {
+	switch op {
+	case fexpr.SignLike, fexpr.SignAnyLike, fexpr.SignNlike, fexpr.SignAnyNlike:
+		return true
+	}
+
+	return false
+}
+
+// normalizeOperandParam replaces the operand single placeholder param
+// value with the one returned by the normalizer (if any).
+func normalizeOperandParam(normalizer func(value any) (any, error), operand *ResolverResult) error {
+	if normalizer == nil || operand.ParamNormalizer != nil || len(operand.Params) != 1 {
+		return nil
+	}
+
+	for name, value := range operand.Params {
+		if operand.Identifier != "{:"+name+"}" {
+			return nil // not a plain placeholder (eg. LOWER({:a}))
+		}
+
+		normalized, err := normalizer(value)
+		if err != nil {
+			return err
+		}
+
+		operand.Params = dbx.Params{name: normalized}
+	}
+
+	return nil
+}
+
 func hasEmptyParamValue(result *ResolverResult) bool {
 	for _, p := range result.Params {
 		switch v := p.(type) {
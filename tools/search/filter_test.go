@@ -339,3 +339,74 @@ func TestLikeParamsWrapping(t *testing.T) {
 		t.Fatalf("Expected query \n%s, \ngot \n%s", expectedQuery, calledQueries[0])
 	}
 }
+
+// normalizerResolver resolves every field as plain column
+// with ParamNormalizer that doubles the other operand param value.
+type normalizerResolver struct{}
+
+func (r *normalizerResolver) UpdateQuery(query *dbx.SelectQuery) error {
+	return nil
+}
+
+func (r *normalizerResolver) Resolve(field string) (*search.ResolverResult, error) {
+	return &search.ResolverResult{
+		Identifier: "[[" + field + "]]",
+		ParamNormalizer: func(value any) (any, error) {
+			v, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("unsupported value %v", value)
+			}
+			return v * 2, nil
+		},
+	}, nil
+}
+
+func TestFilterDataBuildExprParamNormalizer(t *testing.T) {
+	scenarios := []struct {
+		filter         search.FilterData
+		expectError    bool
+		expectedParams []any
+	}{
+		{"a > 1", false, []any{2.0}},
+		{"1 <= a", false, []any{2.0}},
+		{"a ?= 2 && a != 3", false, []any{4.0, 6.0}},
+		{"a = b", false, nil},
+		{"a ~ 'test'", false, []any{"%test%"}}, // not applied for like operators
+		{"a = 'test'", true, nil},
+	}
+
+	for _, s := range scenarios {
+		t.Run(string(s.filter), func(t *testing.T) {
+			expr, err := s.filter.BuildExpr(&normalizerResolver{})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			params := dbx.Params{}
+			expr.Build(&dbx.DB{}, params)
+
+			if len(params) != len(s.expectedParams) {
+				t.Fatalf("Expected %d params, got %v", len(s.expectedParams), params)
+			}
+
+			for _, expected := range s.expectedParams {
+				var found bool
+				for _, v := range params {
+					if v == expected {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("Missing expected param %v in %v", expected, params)
+				}
+			}
+		})
+	}
+}
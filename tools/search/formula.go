@@ -0,0 +1,679 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+	"github.com/spf13/cast"
+)
+
+// FormulaData is a computed value expression following the filter grammar
+// extended with arithmetic operators and a small set of scalar functions.
+//
+// The literals, identifiers, comparison operators (including the "?="
+// any-match variants) and the "&&"/"||" joins are tokenized with the filter
+// scanner and the comparisons are compiled with the same rules as in
+// [FilterData] (eg. comparing with an empty string also matches NULL and
+// "a ~ 'b'" auto wraps the text literal with "%").
+//
+// Supported operands:
+//   - field identifiers (eg. "price"; only direct field references are allowed)
+//   - number literals (eg. 10, 1.5)
+//   - text literals (eg. 'abc', "abc")
+//   - true, false and null (if not resolved as field)
+//
+// Supported operators (in order of precedence):
+//   - unary "-"
+//   - "*", "/", "%"
+//   - "+", "-"
+//   - "=", "!=", "<", "<=", ">", ">=", "~", "!~" (and their "?" variants)
+//   - "&&"
+//   - "||"
+//
+// Supported functions:
+//
+//	concat(a, b, ...), lower(a), upper(a), trim(a), length(a),
+//	abs(a), round(a[, digits]), min(a, b, ...), max(a, b, ...),
+//	coalesce(a, b, ...), if(condition, a, b)
+//
+// and the [TokenFunctions] filter functions (eg. geoDistance(lonA, latA, lonB, latB)).
+//
+// Because the formula must compile to a deterministic single row value,
+// it differs from the filter grammar in the following:
+//   - the "@" macros (eg. @now), @request.* and @collection.* identifiers are not allowed
+//   - relation paths (eg. "author.name") and field modifiers (eg. "tags:length") are not allowed
+//   - the any-match "?" operators behave the same as their regular counterpart
+//   - "/" is a division (always with real result) and not a comment start; use "//" for comments
+//
+// Example:
+//
+//	var formula FormulaData = "qty * price - discount"
+type FormulaData string
+
+// formulaFunctions lists the supported formula functions with their min and max (-1 for variadic) arguments.
+var formulaFunctions = map[string][2]int{
+	"concat":   {2, -1},
+	"lower":    {1, 1},
+	"upper":    {1, 1},
+	"trim":     {1, 1},
+	"length":   {1, 1},
+	"abs":      {1, 1},
+	"round":    {1, 2},
+	"min":      {2, -1},
+	"max":      {2, -1},
+	"coalesce": {2, -1},
+	"if":       {3, 3},
+}
+
+// maxFormulaLength is the max allowed formula expression string length.
+const maxFormulaLength = 2000
+
+// BuildSQL compiles the formula into a standalone deterministic SQL expression
+// with inlined literal values, making it suitable for generated columns and table constraints.
+//
+// resolveIdentifier is invoked for each field identifier and must return its SQL column expression
+// (or an error if the identifier is not allowed).
+func (f FormulaData) BuildSQL(resolveIdentifier func(name string) (string, error)) (string, error) {
+	raw := strings.TrimSpace(string(f))
+	if raw == "" {
+		return "", errors.New("empty formula expression")
+	}
+
+	if len(raw) > maxFormulaLength {
+		return "", fmt.Errorf("the formula expression must be no more than %d characters", maxFormulaLength)
+	}
+
+	tokens, err := tokenizeFormula(raw)
+	if err != nil {
+		return "", err
+	}
+
+	p := &formulaParser{tokens: tokens, resolveIdentifier: resolveIdentifier}
+
+	result, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+
+	if t := p.peek(); t.Type != fexpr.TokenEOF {
+		return "", fmt.Errorf("unexpected %q", t.Literal)
+	}
+
+	return inlineFormulaParams(result.Identifier, result.Params), nil
+}
+
+// Identifiers returns the list of unique field identifiers used in the formula expression.
+//
+// The true, false and null literals are not included.
+func (f FormulaData) Identifiers() ([]string, error) {
+	result := []string{}
+
+	_, err := f.BuildSQL(func(name string) (string, error) {
+		if _, ok := normalizedIdentifiers[strings.ToLower(name)]; ok {
+			return "", errors.New("literal")
+		}
+
+		for _, existing := range result {
+			if existing == name {
+				return name, nil
+			}
+		}
+
+		result = append(result, name)
+
+		return name, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// -------------------------------------------------------------------
+
+// formulaTokenOperator is the token type of the formula specific
+// arithmetic operators and punctuation ("+", "-", "*", "/", "%", "(", ")" and ",").
+const formulaTokenOperator fexpr.TokenType = "formula_operator"
+
+// tokenizeFormula splits raw by the formula specific operators and
+// tokenizes the remaining parts with the filter [fexpr.Scanner].
+func tokenizeFormula(raw string) ([]fexpr.Token, error) {
+	tokens := []fexpr.Token{}
+
+	var chunk strings.Builder
+
+	flush := func() error {
+		if chunk.Len() == 0 {
+			return nil
+		}
+
+		scanner := fexpr.NewScanner([]byte(chunk.String()))
+		chunk.Reset()
+
+		for {
+			t, err := scanner.Scan()
+			if err != nil {
+				return err
+			}
+
+			switch t.Type {
+			case fexpr.TokenEOF:
+				return nil
+			case fexpr.TokenWS, fexpr.TokenComment:
+				continue
+			case fexpr.TokenIdentifier, fexpr.TokenNumber, fexpr.TokenText, fexpr.TokenSign, fexpr.TokenJoin:
+				tokens = append(tokens, t)
+			default:
+				return fmt.Errorf("unexpected %q", t.Literal)
+			}
+		}
+	}
+
+	rs := []rune(raw)
+
+	var quote, prev rune
+
+	for i := 0; i < len(rs); i++ {
+		ch := rs[i]
+
+		// quoted text (following the fexpr escaping rules)
+		if quote != 0 {
+			if ch == quote && prev != '\\' {
+				quote = 0
+			}
+			prev = ch
+			chunk.WriteRune(ch)
+			continue
+		}
+
+		switch {
+		case ch == '\'' || ch == '"':
+			quote = ch
+			prev = 0
+			chunk.WriteRune(ch)
+		case ch == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			// skip the comment until the end of the line
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+			chunk.WriteRune(' ')
+		case strings.ContainsRune("+-*/%(),", ch):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, fexpr.Token{Type: formulaTokenOperator, Literal: string(ch)})
+		default:
+			chunk.WriteRune(ch)
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	tokens = append(tokens, fexpr.Token{Type: fexpr.TokenEOF})
+
+	return tokens, nil
+}
+
+// -------------------------------------------------------------------
+
+// formulaParser compiles the formula tokens into SQL.
+//
+// The parse results are [ResolverResult] values that are either
+// a single placeholder param (for the text and number literals)
+// or a param-less SQL expression (aka. with already inlined params).
+type formulaParser struct {
+	tokens            []fexpr.Token
+	pos               int
+	depth             int
+	totalParams       int
+	resolveIdentifier func(name string) (string, error)
+}
+
+// maxFormulaDepth limits the nested groups and function calls.
+const maxFormulaDepth = 50
+
+func (p *formulaParser) peek() fexpr.Token {
+	return p.tokens[p.pos]
+}
+
+func (p *formulaParser) next() fexpr.Token {
+	t := p.tokens[p.pos]
+	if t.Type != fexpr.TokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *formulaParser) is(tokenType fexpr.TokenType, literals ...string) bool {
+	t := p.peek()
+	if t.Type != tokenType {
+		return false
+	}
+
+	for _, v := range literals {
+		if t.Literal == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *formulaParser) expectOperator(value string) error {
+	if !p.is(formulaTokenOperator, value) {
+		t := p.peek()
+		if t.Type == fexpr.TokenEOF {
+			return fmt.Errorf("expected %q, got end of expression", value)
+		}
+		return fmt.Errorf("expected %q, got %q", value, t.Literal)
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *formulaParser) parseOr() (*ResolverResult, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(fexpr.TokenJoin, "||") {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = p.compose("(%s OR %s)", left, right)
+	}
+
+	return left, nil
+}
+
+func (p *formulaParser) parseAnd() (*ResolverResult, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(fexpr.TokenJoin, "&&") {
+		p.next()
+
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+
+		left = p.compose("(%s AND %s)", left, right)
+	}
+
+	return left, nil
+}
+
+func (p *formulaParser) parseComparison() (*ResolverResult, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().Type != fexpr.TokenSign {
+		return left, nil
+	}
+
+	op := fexpr.SignOp(p.next().Literal)
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	// reuse the filter operators semantic
+	expr, err := buildResolversExpr(left, op, right)
+	if err != nil {
+		return nil, err
+	}
+
+	params := dbx.Params{}
+	sql := expr.Build(nil, params)
+
+	return &ResolverResult{Identifier: "(" + inlineFormulaParams(sql, params) + ")"}, nil
+}
+
+func (p *formulaParser) parseAdditive() (*ResolverResult, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(formulaTokenOperator, "+", "-") {
+		op := p.next().Literal
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		left = p.compose("(%s %s %s)", left, &ResolverResult{Identifier: op}, right)
+	}
+
+	return left, nil
+}
+
+func (p *formulaParser) parseMultiplicative() (*ResolverResult, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(formulaTokenOperator, "*", "/", "%") {
+		op := p.next().Literal
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		if op == "/" {
+			// avoid the SQLite integer division
+			left = p.compose("(CAST(%s AS REAL) / %s)", left, right)
+		} else {
+			left = p.compose("(%s %s %s)", left, &ResolverResult{Identifier: op}, right)
+		}
+	}
+
+	return left, nil
+}
+
+func (p *formulaParser) parseUnary() (*ResolverResult, error) {
+	if p.is(formulaTokenOperator, "-") {
+		p.next()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return p.compose("(-%s)", operand), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (*ResolverResult, error) {
+	t := p.next()
+
+	switch t.Type {
+	case fexpr.TokenNumber:
+		return p.param(cast.ToFloat64(t.Literal)), nil
+	case fexpr.TokenText:
+		return p.param(t.Literal), nil
+	case fexpr.TokenIdentifier:
+		if p.is(formulaTokenOperator, "(") {
+			return p.parseFunction(t.Literal)
+		}
+
+		return p.resolve(t.Literal)
+	case formulaTokenOperator:
+		if t.Literal == "(" {
+			p.depth++
+			if p.depth > maxFormulaDepth {
+				return nil, errors.New("the formula expression is too deeply nested")
+			}
+
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+
+			p.depth--
+
+			return p.compose("(%s)", inner), nil
+		}
+
+		return nil, fmt.Errorf("unexpected %q", t.Literal)
+	case fexpr.TokenEOF:
+		return nil, errors.New("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.Literal)
+	}
+}
+
+// resolve resolves a single field identifier.
+//
+// Similar to the filter, true, false and null are treated as literals
+// only if they can't be resolved as field.
+func (p *formulaParser) resolve(name string) (*ResolverResult, error) {
+	if strings.HasPrefix(name, "@") {
+		return nil, fmt.Errorf("%q - the formula expression doesn't support @ macros and identifiers", name)
+	}
+
+	if strings.ContainsAny(name, ".:") {
+		return nil, fmt.Errorf("%q - the formula expression doesn't support relation paths and field modifiers", name)
+	}
+
+	var sql string
+	var err error
+	if p.resolveIdentifier == nil {
+		err = fmt.Errorf("unknown identifier %q", name)
+	} else {
+		sql, err = p.resolveIdentifier(name)
+	}
+
+	if err != nil || sql == "" {
+		if v, ok := normalizedIdentifiers[strings.ToLower(name)]; ok {
+			return &ResolverResult{Identifier: v}, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("unknown identifier %q", name)
+		}
+		return nil, err
+	}
+
+	return &ResolverResult{Identifier: sql}, nil
+}
+
+func (p *formulaParser) parseFunction(name string) (*ResolverResult, error) {
+	if fn, ok := TokenFunctions[name]; ok {
+		return p.parseTokenFunction(name, fn)
+	}
+
+	name = strings.ToLower(name)
+
+	limits, ok := formulaFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	args, err := p.parseFunctionArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) < limits[0] || (limits[1] >= 0 && len(args) > limits[1]) {
+		return nil, fmt.Errorf("invalid number of %s() arguments", name)
+	}
+
+	switch name {
+	case "concat":
+		return p.compose("("+strings.Repeat("%s || ", len(args)-1)+"%s)", args...), nil
+	case "if":
+		return p.compose("(CASE WHEN %s THEN %s ELSE %s END)", args...), nil
+	default:
+		return p.compose(name+"("+strings.Repeat("%s, ", len(args)-1)+"%s)", args...), nil
+	}
+}
+
+// parseTokenFunction evaluates one of the [TokenFunctions].
+//
+// Similar to the filter, their arguments must be plain identifiers, numbers or text literals.
+func (p *formulaParser) parseTokenFunction(name string, fn func(
+	argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error),
+	args ...fexpr.Token,
+) (*ResolverResult, error)) (*ResolverResult, error) {
+	p.next() // "("
+
+	args := []fexpr.Token{}
+	if !p.is(formulaTokenOperator, ")") {
+		for {
+			t := p.next()
+			switch t.Type {
+			case fexpr.TokenIdentifier, fexpr.TokenNumber, fexpr.TokenText:
+				args = append(args, t)
+			default:
+				return nil, fmt.Errorf("%s() arguments must be identifiers, numbers or text literals", name)
+			}
+
+			if !p.is(formulaTokenOperator, ",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if err := p.expectOperator(")"); err != nil {
+		return nil, err
+	}
+
+	result, err := fn(func(t fexpr.Token) (*ResolverResult, error) {
+		switch t.Type {
+		case fexpr.TokenIdentifier:
+			return p.resolve(t.Literal)
+		case fexpr.TokenNumber:
+			return p.param(cast.ToFloat64(t.Literal)), nil
+		default:
+			return p.param(t.Literal), nil
+		}
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResolverResult{
+		Identifier: inlineFormulaParams(result.Identifier, result.Params),
+		NoCoalesce: result.NoCoalesce,
+	}, nil
+}
+
+func (p *formulaParser) parseFunctionArgs() ([]*ResolverResult, error) {
+	p.next() // "("
+
+	p.depth++
+	if p.depth > maxFormulaDepth {
+		return nil, errors.New("the formula expression is too deeply nested")
+	}
+
+	args := []*ResolverResult{}
+	if !p.is(formulaTokenOperator, ")") {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if !p.is(formulaTokenOperator, ",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if err := p.expectOperator(")"); err != nil {
+		return nil, err
+	}
+
+	p.depth--
+
+	return args, nil
+}
+
+// param returns a new single placeholder param result.
+func (p *formulaParser) param(value any) *ResolverResult {
+	p.totalParams++
+
+	placeholder := "f" + strconv.Itoa(p.totalParams)
+
+	return &ResolverResult{
+		Identifier: "{:" + placeholder + "}",
+		Params:     dbx.Params{placeholder: value},
+	}
+}
+
+// compose formats the operands SQL (with inlined params) according to the specified format.
+func (p *formulaParser) compose(format string, operands ...*ResolverResult) *ResolverResult {
+	args := make([]any, len(operands))
+	for i, operand := range operands {
+		args[i] = inlineFormulaParams(operand.Identifier, operand.Params)
+	}
+
+	return &ResolverResult{Identifier: fmt.Sprintf(format, args...)}
+}
+
+// inlineFormulaParams replaces the sql "{:name}" placeholders with their SQL literal param value.
+func inlineFormulaParams(sql string, params dbx.Params) string {
+	for name, value := range params {
+		var literal string
+
+		switch v := value.(type) {
+		case nil:
+			literal = "NULL"
+		case string:
+			literal = quoteFormulaText(v)
+		case bool:
+			literal = "0"
+			if v {
+				literal = "1"
+			}
+		default:
+			literal = strconv.FormatFloat(cast.ToFloat64(v), 'f', -1, 64)
+		}
+
+		sql = strings.ReplaceAll(sql, "{:"+name+"}", literal)
+	}
+
+	return sql
+}
+
+// quoteFormulaText returns a single quoted SQL string literal.
+//
+// The "{" and "[" characters are inlined with char() calls to prevent
+// accidentally matching the dbx table/column/param placeholders.
+func quoteFormulaText(str string) string {
+	var sb strings.Builder
+
+	var hasChars bool
+
+	sb.WriteString("'")
+	for _, ch := range str {
+		switch ch {
+		case '\'':
+			sb.WriteString("''")
+		case '{', '[':
+			hasChars = true
+			sb.WriteString("' || char(" + strconv.Itoa(int(ch)) + ") || '")
+		default:
+			sb.WriteRune(ch)
+		}
+	}
+	sb.WriteString("'")
+
+	if hasChars {
+		return "(" + sb.String() + ")"
+	}
+
+	return sb.String()
+}
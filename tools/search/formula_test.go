@@ -0,0 +1,122 @@
+package search_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func TestFormulaDataBuildSQL(t *testing.T) {
+	t.Parallel()
+
+	resolver := func(name string) (string, error) {
+		switch name {
+		case "unknown", "null", "true", "false":
+			return "", errors.New("unknown field")
+		}
+		return "[[" + name + "]]", nil
+	}
+
+	scenarios := []struct {
+		formula     search.FormulaData
+		expectError bool
+		expected    string
+	}{
+		// invalid
+		{"", true, ""},
+		{"   ", true, ""},
+		{"a +", true, ""},
+		{"(a + b", true, ""},
+		{"a + b)", true, ""},
+		{"a b", true, ""},
+		{"@request.auth.id", true, ""},
+		{"a.b", true, ""},
+		{"'abc", true, ""},
+		{"1.2.3", true, ""},
+		{"unknown + 1", true, ""},
+		{"missing(a)", true, ""},
+		{"lower(a, b)", true, ""},
+		{"concat(a)", true, ""},
+		{"if(a, b)", true, ""},
+		{"a = b = c", true, ""},
+		{search.FormulaData(strings.Repeat("(", 51) + "1" + strings.Repeat(")", 51)), true, ""},
+		{search.FormulaData(strings.Repeat("a", 2001)), true, ""},
+
+		// valid
+		{"1", false, "1"},
+		{"qty * price", false, "([[qty]] * [[price]])"},
+		{"qty * price - discount", false, "(([[qty]] * [[price]]) - [[discount]])"},
+		{"qty * (price - discount)", false, "([[qty]] * (([[price]] - [[discount]])))"},
+		{"-a + 2.5 % b", false, "((-[[a]]) + (2.5 % [[b]]))"},
+		{"a / 2", false, "(CAST([[a]] AS REAL) / 2)"},
+		{"a = null", false, "(([[a]] = '' OR [[a]] IS NULL))"},
+		{"a != true", false, "([[a]] IS NOT 1)"},
+		{"a = ''", false, "(([[a]] = '' OR [[a]] IS NULL))"},
+		{"a = b", false, "(COALESCE([[a]], '') = COALESCE([[b]], ''))"},
+		{"a ?= 1", false, "([[a]] = 1)"},
+		{"a + 1 >= b", false, "(([[a]] + 1) >= [[b]])"},
+		{"a > 1 && b <= 2 || c", false, "((([[a]] > 1) AND ([[b]] <= 2)) OR [[c]])"},
+		{"a ~ 'te%st'", false, `([[a]] LIKE 'te%st' ESCAPE '\')`},
+		{"a ~ 'te_st'", false, `([[a]] LIKE '%te\_st%' ESCAPE '\')`},
+		{"a !~ 'test'", false, `([[a]] NOT LIKE '%test%' ESCAPE '\')`},
+		{"a ~ b", false, `([[a]] LIKE ('%' || [[b]] || '%') ESCAPE '\')`},
+		{"a * 2 // comment (\n + 1", false, "(([[a]] * 2) + 1)"},
+		{"a * 2 // + 1", false, "([[a]] * 2)"},
+		{"geoDistance(a, b, 1, 2) < 10", false, "((6371 * acos(cos(radians([[b]])) * cos(radians(2)) * cos(radians(1) - radians([[a]])) + sin(radians([[b]])) * sin(radians(2)))) < 10)"},
+
+		// filter grammar divergences
+		{"@now", true, ""},
+		{"a = @request.auth.id", true, ""},
+		{"@collection.demo.id", true, ""},
+		{"a:length", true, ""},
+		{"a:lower = 'x'", true, ""},
+		{"geoDistance(a + 1, b, 1, 2)", true, ""},
+		{"a ?= b", false, "(COALESCE([[a]], '') = COALESCE([[b]], ''))"},
+		{"a / 2 / 4", false, "(CAST((CAST([[a]] AS REAL) / 2) AS REAL) / 4)"},
+
+		{`'it''s' = "a\"b"`, true, ""},
+		{`'it\'s' = "a\"b"`, false, `('it''s' = 'a"b')`},
+		{"'{:a} [[b]]'", false, "('' || char(123) || ':a} ' || char(91) || '' || char(91) || 'b]]')"},
+		{"concat(first, ' ', last)", false, "([[first]] || ' ' || [[last]])"},
+		{"UPPER(trim(name))", false, "upper(trim([[name]]))"},
+		{"round(a / b, 2)", false, "round((CAST([[a]] AS REAL) / [[b]]), 2)"},
+		{"coalesce(a, b, 0)", false, "coalesce([[a]], [[b]], 0)"},
+		{"if(stock > 0, 'yes', 'no')", false, "(CASE WHEN ([[stock]] > 0) THEN 'yes' ELSE 'no' END)"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%.50s", s.formula), func(t *testing.T) {
+			result, err := s.formula.BuildSQL(resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormulaDataIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	identifiers, err := search.FormulaData("a * b + if(c, a, lower('d'))").Identifiers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "a,b,c"
+	if v := strings.Join(identifiers, ","); v != expected {
+		t.Fatalf("Expected %q, got %q", expected, v)
+	}
+
+	if _, err := search.FormulaData("a +").Identifiers(); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
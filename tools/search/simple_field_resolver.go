@@ -31,6 +31,13 @@ type ResolverResult struct {
 	// AfterBuild is an optional function that will be called after building
 	// and combining the result of both resolved operands/sides in a single expression.
 	AfterBuild func(expr dbx.Expression) dbx.Expression
+
+	// ParamNormalizer is an optional function that will be used to normalize
+	// the single param value of the other operand when it is a plain placeholder
+	// (ex. to convert a decimal literal into its sortable db representation).
+	//
+	// It is applied only for the =, !=, <, <=, >, >= operators (and their "any" variants).
+	ParamNormalizer func(value any) (any, error)
 }
 
 // FieldResolver defines an interface for managing search fields.
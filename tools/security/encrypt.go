@@ -5,6 +5,7 @@ import (
 	"crypto/cipher"
 	crand "crypto/rand"
 	"encoding/base64"
+	"errors"
 	"io"
 )
 
@@ -12,6 +13,17 @@ import (
 //
 // This method uses AES-256-GCM block cypher mode.
 func Encrypt(data []byte, key string) (string, error) {
+	return EncryptWithAAD(data, key, nil)
+}
+
+// EncryptWithAAD encrypts "data" with the specified "key" (must be valid 32 char AES key)
+// and authenticates (but doesn't encrypt) the provided "additionalData".
+//
+// The same additionalData must be provided on [DecryptWithAAD]
+// (it could be used to bind the cipher text to a specific context).
+//
+// This method uses AES-256-GCM block cypher mode.
+func EncryptWithAAD(data []byte, key string, additionalData []byte) (string, error) {
 	block, err := aes.NewCipher([]byte(key))
 	if err != nil {
 		return "", err
@@ -29,7 +41,7 @@ func Encrypt(data []byte, key string) (string, error) {
 		return "", err
 	}
 
-	cipherByte := gcm.Seal(nonce, nonce, data, nil)
+	cipherByte := gcm.Seal(nonce, nonce, data, additionalData)
 
 	result := base64.StdEncoding.EncodeToString(cipherByte)
 
@@ -40,6 +52,14 @@ func Encrypt(data []byte, key string) (string, error) {
 //
 // This method uses AES-256-GCM block cypher mode.
 func Decrypt(cipherText string, key string) ([]byte, error) {
+	return DecryptWithAAD(cipherText, key, nil)
+}
+
+// DecryptWithAAD decrypts encrypted text with key (must be valid 32 chars AES key)
+// and verifies that it was encrypted with the same "additionalData" (see [EncryptWithAAD]).
+//
+// This method uses AES-256-GCM block cypher mode.
+func DecryptWithAAD(cipherText string, key string, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher([]byte(key))
 	if err != nil {
 		return nil, err
@@ -57,6 +77,10 @@ func Decrypt(cipherText string, key string) ([]byte, error) {
 		return nil, err
 	}
 
+	if len(cipherByte) < nonceSize {
+		return nil, errors.New("cipher text is too short")
+	}
+
 	nonce, cipherByteClean := cipherByte[:nonceSize], cipherByte[nonceSize:]
-	return gcm.Open(nil, nonce, cipherByteClean, nil)
+	return gcm.Open(nil, nonce, cipherByteClean, additionalData)
 }
@@ -78,3 +78,42 @@ func TestDecrypt(t *testing.T) {
 		})
 	}
 }
+
+func TestEncryptDecryptWithAAD(t *testing.T) {
+	key := "abcdabcdabcdabcdabcdabcdabcdabcd"
+
+	encrypted, err := security.EncryptWithAAD([]byte("123"), key, []byte("aad1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		aad         []byte
+		expectError bool
+	}{
+		{"nil aad", nil, true},
+		{"different aad", []byte("aad2"), true},
+		{"matching aad", []byte("aad1"), false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := security.DecryptWithAAD(encrypted, key, s.aad)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && string(result) != "123" {
+				t.Fatalf("Expected %q, got %q", "123", result)
+			}
+		})
+	}
+
+	// too short cipher text
+	if _, err := security.DecryptWithAAD("YWJj", key, nil); err == nil {
+		t.Fatal("Expected too short cipher text error")
+	}
+}
@@ -0,0 +1,143 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// recordSize is the "rs" value of the aes128gcm content coding header
+	// (the entire payload is always encrypted as a single record).
+	recordSize = 4096
+
+	// headerSize is the size of the aes128gcm content coding header
+	// (salt + rs + idlen + 65 bytes uncompressed ephemeral public key).
+	headerSize = 16 + 4 + 1 + 65
+
+	// MaxPayloadSize is the max supported plain payload size in bytes
+	// (the 4096 bytes push message limit without the encryption overhead).
+	MaxPayloadSize = recordSize - headerSize - 16 - 1
+)
+
+// ErrPayloadTooLarge is returned when the plain push payload exceeds [MaxPayloadSize].
+var ErrPayloadTooLarge = fmt.Errorf("the push payload must be no more than %d bytes", MaxPayloadSize)
+
+// Encrypt encrypts the provided payload for the specified subscription
+// following the RFC 8291 "aes128gcm" Web Push message encryption.
+//
+// The returned bytes are the full push message body
+// (aka. the content coding header followed by the single encrypted record).
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublic, authSecret, err := parseSubscriptionKeys(sub.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// ephemeral application server key pair
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encryptWithKey(uaPublic, authSecret, asPrivate, salt, payload)
+}
+
+// CheckSubscriptionKeys checks whether the provided subscription keys
+// are a valid base64 encoded P-256 public key and authentication secret.
+func CheckSubscriptionKeys(keys SubscriptionKeys) error {
+	_, _, err := parseSubscriptionKeys(keys)
+
+	return err
+}
+
+func parseSubscriptionKeys(keys SubscriptionKeys) (*ecdh.PublicKey, []byte, error) {
+	uaPublicRaw, err := decodeBase64(keys.P256dh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh key encoding: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+
+	authSecret, err := decodeBase64(keys.Auth)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid auth secret encoding: %w", err)
+	}
+	if len(authSecret) != 16 {
+		return nil, nil, errors.New("the auth secret must be 16 bytes")
+	}
+
+	return uaPublic, authSecret, nil
+}
+
+// encryptWithKey encrypts the payload with the provided ephemeral
+// application server key and salt (see [Encrypt]).
+func encryptWithKey(uaPublic *ecdh.PublicKey, authSecret []byte, asPrivate *ecdh.PrivateKey, salt []byte, payload []byte) ([]byte, error) {
+	uaPublicRaw := uaPublic.Bytes()
+	asPublicRaw := asPrivate.PublicKey().Bytes()
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// key_info = "WebPush: info" || 0x00 || ua_public || as_public
+	keyInfo := make([]byte, 0, 14+len(uaPublicRaw)+len(asPublicRaw))
+	keyInfo = append(keyInfo, "WebPush: info\x00"...)
+	keyInfo = append(keyInfo, uaPublicRaw...)
+	keyInfo = append(keyInfo, asPublicRaw...)
+
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, string(keyInfo), 32)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// the 0x02 delimiter marks the last (and only) record
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+
+	result := make([]byte, headerSize, headerSize+len(plaintext)+gcm.Overhead())
+	copy(result, salt)
+	binary.BigEndian.PutUint32(result[16:], recordSize)
+	result[20] = byte(len(asPublicRaw))
+	copy(result[21:], asPublicRaw)
+
+	return gcm.Seal(result, nonce, plaintext, nil), nil
+}
@@ -0,0 +1,268 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testUserAgent mimics the browser side of a push subscription.
+type testUserAgent struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+}
+
+func newTestUserAgent(t testing.TB) *testUserAgent {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := make([]byte, 16)
+	rand.Read(auth)
+
+	return &testUserAgent{key: key, auth: auth}
+}
+
+func (ua *testUserAgent) subscription(endpoint string) Subscription {
+	return Subscription{
+		Endpoint: endpoint,
+		Keys: SubscriptionKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(ua.key.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(ua.auth),
+		},
+	}
+}
+
+// decrypt decrypts the provided RFC 8291 push message body.
+func (ua *testUserAgent) decrypt(body []byte) ([]byte, error) {
+	if len(body) < headerSize {
+		return nil, errors.New("too short body")
+	}
+
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	if rs != recordSize {
+		return nil, errors.New("unexpected record size")
+	}
+	idlen := int(body[20])
+	asPublicRaw := body[21 : 21+idlen]
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdhSecret, err := ua.key.ECDH(asPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), ua.key.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, asPublicRaw...)
+
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, ua.auth, string(keyInfo), 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, body[21+idlen:], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plaintext) == 0 || plaintext[len(plaintext)-1] != 0x02 {
+		return nil, errors.New("missing last record delimiter")
+	}
+
+	return plaintext[:len(plaintext)-1], nil
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	ua := newTestUserAgent(t)
+
+	scenarios := []struct {
+		name        string
+		sub         Subscription
+		payload     []byte
+		expectError bool
+	}{
+		{
+			"invalid p256dh",
+			Subscription{Keys: SubscriptionKeys{P256dh: "invalid", Auth: ua.subscription("").Keys.Auth}},
+			[]byte("test"),
+			true,
+		},
+		{
+			"missing auth",
+			Subscription{Keys: SubscriptionKeys{P256dh: ua.subscription("").Keys.P256dh}},
+			[]byte("test"),
+			true,
+		},
+		{
+			"too large payload",
+			ua.subscription(""),
+			bytes.Repeat([]byte("a"), MaxPayloadSize+1),
+			true,
+		},
+		{
+			"empty payload",
+			ua.subscription(""),
+			nil,
+			false,
+		},
+		{
+			"max payload",
+			ua.subscription(""),
+			bytes.Repeat([]byte("a"), MaxPayloadSize),
+			false,
+		},
+		{
+			"std base64 padded keys",
+			Subscription{Keys: SubscriptionKeys{
+				P256dh: base64.StdEncoding.EncodeToString(ua.key.PublicKey().Bytes()),
+				Auth:   base64.StdEncoding.EncodeToString(ua.auth),
+			}},
+			[]byte(`{"title":"test"}`),
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			body, err := Encrypt(s.sub, s.payload)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if len(body) > recordSize {
+				t.Fatalf("Expected the encrypted body to be no more than %d bytes, got %d", recordSize, len(body))
+			}
+
+			decrypted, err := ua.decrypt(body)
+			if err != nil {
+				t.Fatalf("Failed to decrypt: %v", err)
+			}
+
+			if !bytes.Equal(decrypted, s.payload) {
+				t.Fatalf("Expected payload %q, got %q", s.payload, decrypted)
+			}
+		})
+	}
+}
+
+func TestEncryptUniqueness(t *testing.T) {
+	t.Parallel()
+
+	sub := newTestUserAgent(t).subscription("")
+
+	a, err := Encrypt(sub, []byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Encrypt(sub, []byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(a, b) || strings.HasPrefix(string(a), string(b[:16])) {
+		t.Fatal("Expected different salt and ciphertext for each message")
+	}
+}
+
+// RFC 8291 Appendix A test vector.
+func TestEncryptWithKeyRFC8291(t *testing.T) {
+	t.Parallel()
+
+	decode := func(str string) []byte {
+		raw, err := decodeBase64(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	uaPublic, err := ecdh.P256().NewPublicKey(decode("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asPrivate, err := ecdh.P256().NewPrivateKey(decode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := encryptWithKey(
+		uaPublic,
+		decode("BTBZMqHH6r4Tts7J_aSIgg"),
+		asPrivate,
+		decode("DGv6ra1nlYgDCS1FRnbzlw"),
+		[]byte("When I grow up, I want to be a watermelon"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+
+	if encoded := base64.RawURLEncoding.EncodeToString(body); encoded != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, encoded)
+	}
+}
+
+func TestCheckSubscriptionKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestUserAgent(t).subscription("").Keys
+
+	scenarios := []struct {
+		name        string
+		keys        SubscriptionKeys
+		expectError bool
+	}{
+		{"empty", SubscriptionKeys{}, true},
+		{"invalid p256dh", SubscriptionKeys{P256dh: "abc", Auth: keys.Auth}, true},
+		{"invalid auth", SubscriptionKeys{P256dh: keys.P256dh, Auth: "abc"}, true},
+		{"valid", keys, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := CheckSubscriptionKeys(s.keys)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package webpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultHTTPTimeout is the request timeout of the default [Client] HTTP client.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// ErrForbiddenEndpoint is returned when the push subscription endpoint
+// is not a public https url (see [ValidateEndpoint]).
+var ErrForbiddenEndpoint = errors.New("the push subscription endpoint must be a public https url")
+
+// ValidateEndpoint checks whether the provided push subscription endpoint
+// is safe to be requested by the server, aka. that it is an https url
+// and its host is not a loopback, private, link-local or other reserved address.
+//
+// Hosts listed in allowedHosts (in the format "host" or "host:port")
+// are trusted and skip the above checks (e.g. a self-hosted push service
+// in the local network).
+//
+// Note that domain names are not resolved here; the default [Client]
+// HTTP client additionally checks the resolved addresses on dial.
+func ValidateEndpoint(endpoint string, allowedHosts []string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return ErrForbiddenEndpoint
+	}
+
+	if isAllowedHost(u.Host, allowedHosts) {
+		return nil
+	}
+
+	if u.Scheme != "https" || u.User != nil {
+		return ErrForbiddenEndpoint
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenEndpoint
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return ErrForbiddenEndpoint
+	}
+
+	return nil
+}
+
+// isAllowedHost reports whether the "host:port" (or just "host") hostPort
+// matches one of the allowedHosts entries.
+func isAllowedHost(hostPort string, allowedHosts []string) bool {
+	if len(allowedHosts) == 0 {
+		return false
+	}
+
+	hostPort = strings.ToLower(hostPort)
+
+	host := hostPort
+	if h, _, err := net.SplitHostPort(hostPort); err == nil {
+		host = h
+	}
+
+	return slices.ContainsFunc(allowedHosts, func(allowed string) bool {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		return allowed == hostPort || strings.Trim(allowed, "[]") == host
+	})
+}
+
+// nat64Prefix is the NAT64 well-known prefix (RFC 6052)
+// which addresses embed an IPv4 address in their last 4 bytes.
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// reservedIPPrefixes is the list of the special-purpose address blocks
+// that are not globally reachable (based on the IANA IPv4 and IPv6
+// Special-Purpose Address Registries).
+//
+// The IPv4-mapped (::ffff:0:0/96) and NAT64 addresses are checked
+// against the IPv4 blocks of their embedded address.
+var reservedIPPrefixes = []netip.Prefix{
+	// IPv4
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("10.0.0.0/8"),      // private-use
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space (CGNAT)
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link local
+	netip.MustParsePrefix("172.16.0.0/12"),   // private-use
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation (TEST-NET-1)
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private-use
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation (TEST-NET-2)
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation (TEST-NET-3)
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved and limited broadcast
+	// IPv6
+	netip.MustParsePrefix("::/96"),          // unspecified, loopback and the deprecated IPv4-compatible addresses
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use IPv4/IPv6 translation
+	netip.MustParsePrefix("100::/64"),       // discard-only
+	netip.MustParsePrefix("2001::/23"),      // IETF protocol assignments (Teredo, benchmarking, ORCHID, etc.)
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+	netip.MustParsePrefix("2002::/16"),      // 6to4
+	netip.MustParsePrefix("3fff::/20"),      // documentation
+	netip.MustParsePrefix("5f00::/16"),      // segment routing (SRv6) SIDs
+	netip.MustParsePrefix("fc00::/7"),       // unique local
+	netip.MustParsePrefix("fe80::/10"),      // link local
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site local
+	netip.MustParsePrefix("ff00::/8"),       // multicast
+}
+
+// isPublicIP reports whether ip is a globally routable unicast address.
+func isPublicIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	if nat64Prefix.Contains(addr) {
+		b := addr.As16()
+		addr = netip.AddrFrom4([4]byte(b[12:]))
+	}
+
+	for _, prefix := range reservedIPPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newDefaultHTTPClient creates the default push service HTTP client.
+//
+// The client has a request timeout, doesn't follow redirects and
+// refuses to connect to non-public addresses (except for the allowedHosts)
+// as protection against requests to internal services via the user
+// submitted push subscription endpoints (aka. SSRF).
+func newDefaultHTTPClient(allowedHosts []string) *http.Client {
+	dialer := &net.Dialer{Timeout: DefaultHTTPTimeout}
+
+	guardedDialer := &net.Dialer{
+		Timeout: DefaultHTTPTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w (resolved to %s)", ErrForbiddenEndpoint, host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if isAllowedHost(addr, allowedHosts) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		return guardedDialer.DialContext(ctx, network, addr)
+	}
+	transport.Proxy = nil // the dial checks are meaningless if the requests go through a proxy
+
+	return &http.Client{
+		Timeout:   DefaultHTTPTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// vapidTokenDuration is the lifetime of the generated VAPID JWT
+// (RFC 8292 recommends no more than 24 hours).
+const vapidTokenDuration = 12 * time.Hour
+
+// GenerateVAPIDKeys generates a new P-256 VAPID key pair and returns
+// the base64 (RawURLEncoding) encoded uncompressed public key
+// and raw private key scalar.
+//
+// The public key is the "applicationServerKey" that should be
+// passed to the browser PushManager.subscribe().
+func GenerateVAPIDKeys() (publicKey string, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	publicKey = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	privateKey = base64.RawURLEncoding.EncodeToString(key.Bytes())
+
+	return publicKey, privateKey, nil
+}
+
+// parseVAPIDPrivateKey decodes the base64 encoded raw P-256 private key scalar.
+func parseVAPIDPrivateKey(privateKey string) (*ecdsa.PrivateKey, error) {
+	raw, err := decodeBase64(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key encoding: %w", err)
+	}
+
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	return key, nil
+}
+
+// CheckVAPIDKeys checks whether the provided base64 encoded
+// VAPID public and private keys form a valid P-256 key pair.
+func CheckVAPIDKeys(publicKey string, privateKey string) error {
+	key, err := parseVAPIDPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	pub, err := decodeBase64(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid VAPID public key encoding: %w", err)
+	}
+
+	expectedPub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(pub, expectedPub) {
+		return errors.New("the VAPID public key doesn't match the private key")
+	}
+
+	return nil
+}
+
+// vapidAuthorization returns the RFC 8292 "Authorization" header value
+// for the push service of the provided subscription endpoint.
+func vapidAuthorization(endpoint string, subject string, publicKey string, privateKey string, now time.Time) (string, error) {
+	if subject == "" {
+		return "", errors.New("missing VAPID subject")
+	}
+
+	// ensure that the public key belongs to the private one
+	// (otherwise the push service will reject the request with 403)
+	if err := CheckVAPIDKeys(publicKey, privateKey); err != nil {
+		return "", err
+	}
+
+	key, err := parseVAPIDPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	// normalize the public key encoding
+	rawPub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", errors.New("invalid push subscription endpoint")
+	}
+
+	claims := jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidTokenDuration).Unix(),
+		"sub": subject,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	if err != nil {
+		return "", err
+	}
+
+	return "vapid t=" + token + ", k=" + base64.RawURLEncoding.EncodeToString(rawPub), nil
+}
+
+// decodeBase64 decodes the provided base64 string
+// regardless of its padding and alphabet (std or url).
+func decodeBase64(str string) ([]byte, error) {
+	str = strings.TrimRight(strings.TrimSpace(str), "=")
+	str = strings.NewReplacer("+", "-", "/", "_").Replace(str)
+
+	return base64.RawURLEncoding.DecodeString(str)
+}
@@ -0,0 +1,188 @@
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestGenerateVAPIDKeys(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawPub, err := decodeBase64(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rawPub) != 65 || rawPub[0] != 0x04 {
+		t.Fatalf("Expected 65 bytes uncompressed public key, got %d bytes", len(rawPub))
+	}
+
+	key, err := parseVAPIDPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPub, _ := key.PublicKey.Bytes()
+	if string(expectedPub) != string(rawPub) {
+		t.Fatal("The generated public key doesn't match the private key")
+	}
+
+	pub2, priv2, _ := GenerateVAPIDKeys()
+	if pub == pub2 || priv == priv2 {
+		t.Fatal("Expected unique key pairs")
+	}
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherPub, _, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	scenarios := []struct {
+		name        string
+		endpoint    string
+		subject     string
+		pub         string
+		priv        string
+		expectError bool
+	}{
+		{"missing subject", "https://push.example.com/abc", "", pub, priv, true},
+		{"invalid private key", "https://push.example.com/abc", "mailto:test@example.com", pub, "invalid", true},
+		{"mismatched public key", "https://push.example.com/abc", "mailto:test@example.com", otherPub, priv, true},
+		{"invalid endpoint", "/abc", "mailto:test@example.com", pub, priv, true},
+		{"valid", "https://push.example.com:8080/abc?q=1", "mailto:test@example.com", pub, priv, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			header, err := vapidAuthorization(s.endpoint, s.subject, s.pub, s.priv, now)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			claims, k := parseTestVAPIDAuthorization(t, header)
+
+			if k != pub {
+				t.Fatalf("Expected k=%q, got %q", pub, k)
+			}
+
+			if claims["aud"] != "https://push.example.com:8080" {
+				t.Fatalf("Expected the endpoint origin as aud, got %v", claims["aud"])
+			}
+
+			if claims["sub"] != s.subject {
+				t.Fatalf("Expected sub %q, got %v", s.subject, claims["sub"])
+			}
+
+			exp, _ := claims.GetExpirationTime()
+			if exp == nil || exp.Unix() != now.Add(vapidTokenDuration).Unix() {
+				t.Fatalf("Unexpected exp claim %v", exp)
+			}
+		})
+	}
+}
+
+// parseTestVAPIDAuthorization verifies the VAPID authorization header
+// signature with its own "k" public key and returns the JWT claims.
+func parseTestVAPIDAuthorization(t testing.TB, header string) (jwt.MapClaims, string) {
+	rest, ok := strings.CutPrefix(header, "vapid ")
+	if !ok {
+		t.Fatalf("Missing vapid scheme in %q", header)
+	}
+
+	var token, k string
+	for _, part := range strings.Split(rest, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "t":
+			token = value
+		case "k":
+			k = value
+		}
+	}
+
+	rawPub, err := decodeBase64(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), rawPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.NewParser(jwt.WithValidMethods([]string{"ES256"})).ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return pubKey, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to verify the VAPID JWT: %v", err)
+	}
+
+	return claims, k
+}
+
+func TestCheckVAPIDKeys(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherPub, otherPriv, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		pub         string
+		priv        string
+		expectError bool
+	}{
+		{"empty", "", "", true},
+		{"invalid private key", pub, "invalid", true},
+		{"invalid public key", "!@#", priv, true},
+		{"mismatched pair 1", otherPub, priv, true},
+		{"mismatched pair 2", pub, otherPriv, true},
+		{"valid pair", pub, priv, false},
+		{"valid pair (padded)", pub + "=", priv + "=", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := CheckVAPIDKeys(s.pub, s.priv)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Package webpush implements a minimal Web Push client with
+// RFC 8291 message encryption, RFC 8292 VAPID authentication
+// and RFC 8030 message delivery.
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxErrorBodySize is the max number of bytes to read from the push service error response body.
+const maxErrorBodySize = 1 << 16
+
+// DefaultTTL is the default push message TTL (in seconds)
+// used when [Options.TTL] is not set.
+const DefaultTTL = 24 * 60 * 60
+
+// Urgency values as defined in RFC 8030 section 5.3.
+const (
+	UrgencyVeryLow = "very-low"
+	UrgencyLow     = "low"
+	UrgencyNormal  = "normal"
+	UrgencyHigh    = "high"
+)
+
+// Subscription defines a browser push subscription
+// (aka. the result of the JS PushSubscription.toJSON()).
+type Subscription struct {
+	Endpoint string           `json:"endpoint"`
+	Keys     SubscriptionKeys `json:"keys"`
+}
+
+// SubscriptionKeys defines the user agent encryption keys of a push subscription.
+type SubscriptionKeys struct {
+	// P256dh is the base64 encoded user agent P-256 ECDH public key.
+	P256dh string `json:"p256dh"`
+
+	// Auth is the base64 encoded user agent authentication secret.
+	Auth string `json:"auth"`
+}
+
+// Options defines the optional RFC 8030 push message delivery options.
+type Options struct {
+	// TTL is the number of seconds the push service should retain
+	// the message in case the user agent is not immediately available
+	// (if zero, fallbacks to [DefaultTTL], negative values are normalized to 0).
+	TTL int
+
+	// Urgency is the message urgency (could be any of the Urgency* constants).
+	Urgency string
+
+	// Topic is an optional identifier that allows replacing
+	// a previously sent but still pending message with the same topic.
+	Topic string
+}
+
+// Client defines a Web Push client authenticated with a VAPID key pair.
+type Client struct {
+	// HTTPClient is the client used for the push service requests.
+	//
+	// If nil, fallbacks to a default client with [DefaultHTTPTimeout]
+	// that refuses to connect to non-public network addresses.
+	HTTPClient *http.Client
+
+	// AllowedHosts is an optional list of trusted push service hosts
+	// (in the format "host" or "host:port") that skip the
+	// endpoint https and public network address checks.
+	AllowedHosts []string
+
+	// Subject is the VAPID contact of the application server
+	// (usually a "mailto:" or "https:" url).
+	Subject string
+
+	// PublicKey is the base64 encoded VAPID public key.
+	PublicKey string
+
+	// PrivateKey is the base64 encoded VAPID private key.
+	PrivateKey string
+
+	defaultHTTPClient     *http.Client
+	defaultHTTPClientOnce sync.Once
+}
+
+// Send encrypts and delivers the payload to the push service
+// of the provided subscription.
+//
+// Non-2xx push service responses are returned as [*ResponseError].
+func (c *Client) Send(ctx context.Context, sub Subscription, payload []byte, opts *Options) error {
+	if sub.Endpoint == "" {
+		return errors.New("missing push subscription endpoint")
+	}
+
+	if err := ValidateEndpoint(sub.Endpoint, c.AllowedHosts); err != nil {
+		return err
+	}
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := vapidAuthorization(sub.Endpoint, c.Subject, c.PublicKey, c.PrivateKey, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	} else if ttl < 0 {
+		ttl = 0
+	}
+
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(ttl))
+	if opts.Urgency != "" {
+		req.Header.Set("Urgency", opts.Urgency)
+	}
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+		return &ResponseError{Status: resp.StatusCode, Raw: raw}
+	}
+
+	// drain the body to allow connection reuse
+	_, err = io.Copy(io.Discard, resp.Body)
+
+	return err
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	c.defaultHTTPClientOnce.Do(func() {
+		c.defaultHTTPClient = newDefaultHTTPClient(c.AllowedHosts)
+	})
+
+	return c.defaultHTTPClient
+}
+
+// ResponseError defines an error returned for non-2xx push service responses.
+type ResponseError struct {
+	Raw    []byte
+	Status int
+}
+
+// Error implements the std error interface.
+func (err *ResponseError) Error() string {
+	msg := fmt.Sprintf("push service responded with status %d", err.Status)
+
+	if raw := strings.TrimSpace(string(err.Raw)); raw != "" {
+		msg += ": " + raw
+	}
+
+	return msg
+}
+
+// IsExpired reports whether the push service responded that
+// the subscription is no longer valid (404 Not Found or 410 Gone)
+// and therefore it should be removed.
+func (err *ResponseError) IsExpired() bool {
+	return err.Status == http.StatusNotFound || err.Status == http.StatusGone
+}
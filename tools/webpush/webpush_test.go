@@ -0,0 +1,150 @@
+package webpush
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientSend(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ua := newTestUserAgent(t)
+
+	var lastReq *http.Request
+	var lastBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+		lastBody, _ = io.ReadAll(r.Body)
+
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte("expired"))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/error":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		Subject:      "mailto:test@example.com",
+		PublicKey:    pub,
+		PrivateKey:   priv,
+		AllowedHosts: []string{server.Listener.Addr().String()},
+	}
+
+	t.Run("missing endpoint", func(t *testing.T) {
+		err := client.Send(context.Background(), ua.subscription(""), []byte("test"), nil)
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("forbidden endpoint", func(t *testing.T) {
+		err := client.Send(context.Background(), ua.subscription("https://127.0.0.1:1/ok"), []byte("test"), nil)
+		if !errors.Is(err, ErrForbiddenEndpoint) {
+			t.Fatalf("Expected ErrForbiddenEndpoint, got %v", err)
+		}
+	})
+
+	t.Run("success with default options", func(t *testing.T) {
+		err := client.Send(context.Background(), ua.subscription(server.URL+"/ok"), []byte("test"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedHeaders := map[string]string{
+			"Content-Encoding": "aes128gcm",
+			"Content-Type":     "application/octet-stream",
+			"Ttl":              "86400",
+			"Urgency":          "",
+			"Topic":            "",
+		}
+		for k, v := range expectedHeaders {
+			if h := lastReq.Header.Get(k); h != v {
+				t.Errorf("Expected %s header %q, got %q", k, v, h)
+			}
+		}
+
+		claims, k := parseTestVAPIDAuthorization(t, lastReq.Header.Get("Authorization"))
+		if k != pub {
+			t.Fatalf("Expected k=%q, got %q", pub, k)
+		}
+		if claims["aud"] != server.URL {
+			t.Fatalf("Expected aud %q, got %v", server.URL, claims["aud"])
+		}
+
+		decrypted, err := ua.decrypt(lastBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decrypted) != "test" {
+			t.Fatalf("Expected payload %q, got %q", "test", decrypted)
+		}
+	})
+
+	t.Run("success with custom options", func(t *testing.T) {
+		err := client.Send(context.Background(), ua.subscription(server.URL+"/ok"), []byte("test"), &Options{
+			TTL:     -1,
+			Urgency: UrgencyHigh,
+			Topic:   "abc",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedHeaders := map[string]string{
+			"Ttl":     "0",
+			"Urgency": "high",
+			"Topic":   "abc",
+		}
+		for k, v := range expectedHeaders {
+			if h := lastReq.Header.Get(k); h != v {
+				t.Errorf("Expected %s header %q, got %q", k, v, h)
+			}
+		}
+	})
+
+	errorScenarios := []struct {
+		path            string
+		expectedStatus  int
+		expectedExpired bool
+	}{
+		{"/gone", http.StatusGone, true},
+		{"/missing", http.StatusNotFound, true},
+		{"/error", http.StatusTooManyRequests, false},
+	}
+
+	for _, s := range errorScenarios {
+		t.Run("error "+s.path, func(t *testing.T) {
+			err := client.Send(context.Background(), ua.subscription(server.URL+s.path), []byte("test"), nil)
+
+			var respErr *ResponseError
+			if !errors.As(err, &respErr) {
+				t.Fatalf("Expected ResponseError, got %v", err)
+			}
+
+			if respErr.Status != s.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", s.expectedStatus, respErr.Status)
+			}
+
+			if respErr.IsExpired() != s.expectedExpired {
+				t.Fatalf("Expected IsExpired %v", s.expectedExpired)
+			}
+		})
+	}
+}